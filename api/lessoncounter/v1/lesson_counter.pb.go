@@ -0,0 +1,896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: lessoncounter/v1/lesson_counter.proto
+
+// API проверки расписания для межсервисных вызовов. Повторяет возможности
+// веб-интерфейса: запуск проверки недели, статус и результаты последней проверки.
+
+package lessoncounterv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StartCheckRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Любая дата недели в формате 2006-01-02.
+	WeekStart string `protobuf:"bytes,1,opt,name=week_start,json=weekStart,proto3" json:"week_start,omitempty"`
+	// Включить в HTML-отчет расписание всех студентов.
+	FullReport    bool `protobuf:"varint,2,opt,name=full_report,json=fullReport,proto3" json:"full_report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartCheckRequest) Reset() {
+	*x = StartCheckRequest{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCheckRequest) ProtoMessage() {}
+
+func (x *StartCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCheckRequest.ProtoReflect.Descriptor instead.
+func (*StartCheckRequest) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StartCheckRequest) GetWeekStart() string {
+	if x != nil {
+		return x.WeekStart
+	}
+	return ""
+}
+
+func (x *StartCheckRequest) GetFullReport() bool {
+	if x != nil {
+		return x.FullReport
+	}
+	return false
+}
+
+type StartCheckResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Неделя проверки в формате 2006-01-02 (понедельник).
+	Week          string `protobuf:"bytes,1,opt,name=week,proto3" json:"week,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartCheckResponse) Reset() {
+	*x = StartCheckResponse{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCheckResponse) ProtoMessage() {}
+
+func (x *StartCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCheckResponse.ProtoReflect.Descriptor instead.
+func (*StartCheckResponse) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartCheckResponse) GetWeek() string {
+	if x != nil {
+		return x.Week
+	}
+	return ""
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{2}
+}
+
+type GetStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsProcessing  bool                   `protobuf:"varint,1,opt,name=is_processing,json=isProcessing,proto3" json:"is_processing,omitempty"`
+	ReportReady   bool                   `protobuf:"varint,2,opt,name=report_ready,json=reportReady,proto3" json:"report_ready,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Progress      string                 `protobuf:"bytes,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	Issues        []*Issue               `protobuf:"bytes,5,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusResponse) ProtoMessage() {}
+
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatusResponse) GetIsProcessing() bool {
+	if x != nil {
+		return x.IsProcessing
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetReportReady() bool {
+	if x != nil {
+		return x.ReportReady
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetProgress() string {
+	if x != nil {
+		return x.Progress
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetIssues() []*Issue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+// Issue — некритичная проблема загрузки данных.
+type Issue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Issue) Reset() {
+	*x = Issue{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Issue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Issue) ProtoMessage() {}
+
+func (x *Issue) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Issue.ProtoReflect.Descriptor instead.
+func (*Issue) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Issue) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Issue) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Issue) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListViolationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Отбор по группе; пусто — все группы.
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// Отбор по студенту; пусто — все студенты.
+	Student       string `protobuf:"bytes,2,opt,name=student,proto3" json:"student,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListViolationsRequest) Reset() {
+	*x = ListViolationsRequest{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListViolationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListViolationsRequest) ProtoMessage() {}
+
+func (x *ListViolationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListViolationsRequest.ProtoReflect.Descriptor instead.
+func (*ListViolationsRequest) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListViolationsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListViolationsRequest) GetStudent() string {
+	if x != nil {
+		return x.Student
+	}
+	return ""
+}
+
+type ListViolationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*Violation           `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListViolationsResponse) Reset() {
+	*x = ListViolationsResponse{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListViolationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListViolationsResponse) ProtoMessage() {}
+
+func (x *ListViolationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListViolationsResponse.ProtoReflect.Descriptor instead.
+func (*ListViolationsResponse) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListViolationsResponse) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type Violation struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StudentName string                 `protobuf:"bytes,2,opt,name=student_name,json=studentName,proto3" json:"student_name,omitempty"`
+	Group       string                 `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	Year        int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	// Дата в формате 2006-01-02.
+	Date string `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	// Вид нарушения: overload, gaps, custom, clash, weekly_overload, consecutive, overlap.
+	Kind  string `protobuf:"bytes,6,opt,name=kind,proto3" json:"kind,omitempty"`
+	Title string `protobuf:"bytes,7,opt,name=title,proto3" json:"title,omitempty"`
+	Hours int32  `protobuf:"varint,8,opt,name=hours,proto3" json:"hours,omitempty"`
+	// Название пользовательского правила для вида custom.
+	Rule          string `protobuf:"bytes,9,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Violation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Violation) GetStudentName() string {
+	if x != nil {
+		return x.StudentName
+	}
+	return ""
+}
+
+func (x *Violation) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Violation) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Violation) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Violation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Violation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Violation) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+func (x *Violation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+type ListLessonsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Отбор по группе; пусто — все группы.
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// Отбор по студенту: его индивидуальные занятия и групповые пары его группы.
+	Student       string `protobuf:"bytes,2,opt,name=student,proto3" json:"student,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLessonsRequest) Reset() {
+	*x = ListLessonsRequest{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLessonsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLessonsRequest) ProtoMessage() {}
+
+func (x *ListLessonsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLessonsRequest.ProtoReflect.Descriptor instead.
+func (*ListLessonsRequest) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListLessonsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListLessonsRequest) GetStudent() string {
+	if x != nil {
+		return x.Student
+	}
+	return ""
+}
+
+type ListLessonsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lessons       []*Lesson              `protobuf:"bytes,1,rep,name=lessons,proto3" json:"lessons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLessonsResponse) Reset() {
+	*x = ListLessonsResponse{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLessonsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLessonsResponse) ProtoMessage() {}
+
+func (x *ListLessonsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLessonsResponse.ProtoReflect.Descriptor instead.
+func (*ListLessonsResponse) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListLessonsResponse) GetLessons() []*Lesson {
+	if x != nil {
+		return x.Lessons
+	}
+	return nil
+}
+
+type Lesson struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Дата в формате 2006-01-02.
+	Date   string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Number int32  `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
+	// Половина пары: 0 — вся пара, 1 или 2 — половина.
+	PairHalf int32 `protobuf:"varint,4,opt,name=pair_half,json=pairHalf,proto3" json:"pair_half,omitempty"`
+	Hours    int32 `protobuf:"varint,5,opt,name=hours,proto3" json:"hours,omitempty"`
+	// Время в формате 15:04; пусто, если неизвестно.
+	StartTime  string   `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime    string   `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Discipline string   `protobuf:"bytes,8,opt,name=discipline,proto3" json:"discipline,omitempty"`
+	Teachers   []string `protobuf:"bytes,9,rep,name=teachers,proto3" json:"teachers,omitempty"`
+	Cabinet    string   `protobuf:"bytes,10,opt,name=cabinet,proto3" json:"cabinet,omitempty"`
+	Group      string   `protobuf:"bytes,11,opt,name=group,proto3" json:"group,omitempty"`
+	Student    string   `protobuf:"bytes,12,opt,name=student,proto3" json:"student,omitempty"`
+	Subgroup   string   `protobuf:"bytes,13,opt,name=subgroup,proto3" json:"subgroup,omitempty"`
+	// Источник: individual, group, imported.
+	Source        string `protobuf:"bytes,14,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lesson) Reset() {
+	*x = Lesson{}
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lesson) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lesson) ProtoMessage() {}
+
+func (x *Lesson) ProtoReflect() protoreflect.Message {
+	mi := &file_lessoncounter_v1_lesson_counter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lesson.ProtoReflect.Descriptor instead.
+func (*Lesson) Descriptor() ([]byte, []int) {
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Lesson) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Lesson) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Lesson) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Lesson) GetPairHalf() int32 {
+	if x != nil {
+		return x.PairHalf
+	}
+	return 0
+}
+
+func (x *Lesson) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+func (x *Lesson) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Lesson) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *Lesson) GetDiscipline() string {
+	if x != nil {
+		return x.Discipline
+	}
+	return ""
+}
+
+func (x *Lesson) GetTeachers() []string {
+	if x != nil {
+		return x.Teachers
+	}
+	return nil
+}
+
+func (x *Lesson) GetCabinet() string {
+	if x != nil {
+		return x.Cabinet
+	}
+	return ""
+}
+
+func (x *Lesson) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Lesson) GetStudent() string {
+	if x != nil {
+		return x.Student
+	}
+	return ""
+}
+
+func (x *Lesson) GetSubgroup() string {
+	if x != nil {
+		return x.Subgroup
+	}
+	return ""
+}
+
+func (x *Lesson) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+var File_lessoncounter_v1_lesson_counter_proto protoreflect.FileDescriptor
+
+const file_lessoncounter_v1_lesson_counter_proto_rawDesc = "" +
+	"\n" +
+	"%lessoncounter/v1/lesson_counter.proto\x12\x10lessoncounter.v1\"S\n" +
+	"\x11StartCheckRequest\x12\x1d\n" +
+	"\n" +
+	"week_start\x18\x01 \x01(\tR\tweekStart\x12\x1f\n" +
+	"\vfull_report\x18\x02 \x01(\bR\n" +
+	"fullReport\"(\n" +
+	"\x12StartCheckResponse\x12\x12\n" +
+	"\x04week\x18\x01 \x01(\tR\x04week\"\x12\n" +
+	"\x10GetStatusRequest\"\xbe\x01\n" +
+	"\x11GetStatusResponse\x12#\n" +
+	"\ris_processing\x18\x01 \x01(\bR\fisProcessing\x12!\n" +
+	"\freport_ready\x18\x02 \x01(\bR\vreportReady\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1a\n" +
+	"\bprogress\x18\x04 \x01(\tR\bprogress\x12/\n" +
+	"\x06issues\x18\x05 \x03(\v2\x17.lessoncounter.v1.IssueR\x06issues\"U\n" +
+	"\x05Issue\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"G\n" +
+	"\x15ListViolationsRequest\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x18\n" +
+	"\astudent\x18\x02 \x01(\tR\astudent\"U\n" +
+	"\x16ListViolationsResponse\x12;\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x1b.lessoncounter.v1.ViolationR\n" +
+	"violations\"\xd0\x01\n" +
+	"\tViolation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fstudent_name\x18\x02 \x01(\tR\vstudentName\x12\x14\n" +
+	"\x05group\x18\x03 \x01(\tR\x05group\x12\x12\n" +
+	"\x04year\x18\x04 \x01(\x05R\x04year\x12\x12\n" +
+	"\x04date\x18\x05 \x01(\tR\x04date\x12\x12\n" +
+	"\x04kind\x18\x06 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05title\x18\a \x01(\tR\x05title\x12\x14\n" +
+	"\x05hours\x18\b \x01(\x05R\x05hours\x12\x12\n" +
+	"\x04rule\x18\t \x01(\tR\x04rule\"D\n" +
+	"\x12ListLessonsRequest\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x18\n" +
+	"\astudent\x18\x02 \x01(\tR\astudent\"I\n" +
+	"\x13ListLessonsResponse\x122\n" +
+	"\alessons\x18\x01 \x03(\v2\x18.lessoncounter.v1.LessonR\alessons\"\xeb\x02\n" +
+	"\x06Lesson\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x16\n" +
+	"\x06number\x18\x03 \x01(\x05R\x06number\x12\x1b\n" +
+	"\tpair_half\x18\x04 \x01(\x05R\bpairHalf\x12\x14\n" +
+	"\x05hours\x18\x05 \x01(\x05R\x05hours\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\tR\aendTime\x12\x1e\n" +
+	"\n" +
+	"discipline\x18\b \x01(\tR\n" +
+	"discipline\x12\x1a\n" +
+	"\bteachers\x18\t \x03(\tR\bteachers\x12\x18\n" +
+	"\acabinet\x18\n" +
+	" \x01(\tR\acabinet\x12\x14\n" +
+	"\x05group\x18\v \x01(\tR\x05group\x12\x18\n" +
+	"\astudent\x18\f \x01(\tR\astudent\x12\x1a\n" +
+	"\bsubgroup\x18\r \x01(\tR\bsubgroup\x12\x16\n" +
+	"\x06source\x18\x0e \x01(\tR\x06source2\xff\x02\n" +
+	"\rLessonCounter\x12W\n" +
+	"\n" +
+	"StartCheck\x12#.lessoncounter.v1.StartCheckRequest\x1a$.lessoncounter.v1.StartCheckResponse\x12T\n" +
+	"\tGetStatus\x12\".lessoncounter.v1.GetStatusRequest\x1a#.lessoncounter.v1.GetStatusResponse\x12c\n" +
+	"\x0eListViolations\x12'.lessoncounter.v1.ListViolationsRequest\x1a(.lessoncounter.v1.ListViolationsResponse\x12Z\n" +
+	"\vListLessons\x12$.lessoncounter.v1.ListLessonsRequest\x1a%.lessoncounter.v1.ListLessonsResponseBGZEgithub.com/Vaflel/lesson-counter/api/lessoncounter/v1;lessoncounterv1b\x06proto3"
+
+var (
+	file_lessoncounter_v1_lesson_counter_proto_rawDescOnce sync.Once
+	file_lessoncounter_v1_lesson_counter_proto_rawDescData []byte
+)
+
+func file_lessoncounter_v1_lesson_counter_proto_rawDescGZIP() []byte {
+	file_lessoncounter_v1_lesson_counter_proto_rawDescOnce.Do(func() {
+		file_lessoncounter_v1_lesson_counter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lessoncounter_v1_lesson_counter_proto_rawDesc), len(file_lessoncounter_v1_lesson_counter_proto_rawDesc)))
+	})
+	return file_lessoncounter_v1_lesson_counter_proto_rawDescData
+}
+
+var file_lessoncounter_v1_lesson_counter_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_lessoncounter_v1_lesson_counter_proto_goTypes = []any{
+	(*StartCheckRequest)(nil),      // 0: lessoncounter.v1.StartCheckRequest
+	(*StartCheckResponse)(nil),     // 1: lessoncounter.v1.StartCheckResponse
+	(*GetStatusRequest)(nil),       // 2: lessoncounter.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),      // 3: lessoncounter.v1.GetStatusResponse
+	(*Issue)(nil),                  // 4: lessoncounter.v1.Issue
+	(*ListViolationsRequest)(nil),  // 5: lessoncounter.v1.ListViolationsRequest
+	(*ListViolationsResponse)(nil), // 6: lessoncounter.v1.ListViolationsResponse
+	(*Violation)(nil),              // 7: lessoncounter.v1.Violation
+	(*ListLessonsRequest)(nil),     // 8: lessoncounter.v1.ListLessonsRequest
+	(*ListLessonsResponse)(nil),    // 9: lessoncounter.v1.ListLessonsResponse
+	(*Lesson)(nil),                 // 10: lessoncounter.v1.Lesson
+}
+var file_lessoncounter_v1_lesson_counter_proto_depIdxs = []int32{
+	4,  // 0: lessoncounter.v1.GetStatusResponse.issues:type_name -> lessoncounter.v1.Issue
+	7,  // 1: lessoncounter.v1.ListViolationsResponse.violations:type_name -> lessoncounter.v1.Violation
+	10, // 2: lessoncounter.v1.ListLessonsResponse.lessons:type_name -> lessoncounter.v1.Lesson
+	0,  // 3: lessoncounter.v1.LessonCounter.StartCheck:input_type -> lessoncounter.v1.StartCheckRequest
+	2,  // 4: lessoncounter.v1.LessonCounter.GetStatus:input_type -> lessoncounter.v1.GetStatusRequest
+	5,  // 5: lessoncounter.v1.LessonCounter.ListViolations:input_type -> lessoncounter.v1.ListViolationsRequest
+	8,  // 6: lessoncounter.v1.LessonCounter.ListLessons:input_type -> lessoncounter.v1.ListLessonsRequest
+	1,  // 7: lessoncounter.v1.LessonCounter.StartCheck:output_type -> lessoncounter.v1.StartCheckResponse
+	3,  // 8: lessoncounter.v1.LessonCounter.GetStatus:output_type -> lessoncounter.v1.GetStatusResponse
+	6,  // 9: lessoncounter.v1.LessonCounter.ListViolations:output_type -> lessoncounter.v1.ListViolationsResponse
+	9,  // 10: lessoncounter.v1.LessonCounter.ListLessons:output_type -> lessoncounter.v1.ListLessonsResponse
+	7,  // [7:11] is the sub-list for method output_type
+	3,  // [3:7] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_lessoncounter_v1_lesson_counter_proto_init() }
+func file_lessoncounter_v1_lesson_counter_proto_init() {
+	if File_lessoncounter_v1_lesson_counter_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lessoncounter_v1_lesson_counter_proto_rawDesc), len(file_lessoncounter_v1_lesson_counter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lessoncounter_v1_lesson_counter_proto_goTypes,
+		DependencyIndexes: file_lessoncounter_v1_lesson_counter_proto_depIdxs,
+		MessageInfos:      file_lessoncounter_v1_lesson_counter_proto_msgTypes,
+	}.Build()
+	File_lessoncounter_v1_lesson_counter_proto = out.File
+	file_lessoncounter_v1_lesson_counter_proto_goTypes = nil
+	file_lessoncounter_v1_lesson_counter_proto_depIdxs = nil
+}
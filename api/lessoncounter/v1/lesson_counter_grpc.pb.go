@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: lessoncounter/v1/lesson_counter.proto
+
+// API проверки расписания для межсервисных вызовов. Повторяет возможности
+// веб-интерфейса: запуск проверки недели, статус и результаты последней проверки.
+
+package lessoncounterv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LessonCounter_StartCheck_FullMethodName     = "/lessoncounter.v1.LessonCounter/StartCheck"
+	LessonCounter_GetStatus_FullMethodName      = "/lessoncounter.v1.LessonCounter/GetStatus"
+	LessonCounter_ListViolations_FullMethodName = "/lessoncounter.v1.LessonCounter/ListViolations"
+	LessonCounter_ListLessons_FullMethodName    = "/lessoncounter.v1.LessonCounter/ListLessons"
+)
+
+// LessonCounterClient is the client API for LessonCounter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LessonCounterClient interface {
+	// StartCheck запускает проверку недели. Одновременно выполняется только одна проверка.
+	StartCheck(ctx context.Context, in *StartCheckRequest, opts ...grpc.CallOption) (*StartCheckResponse, error)
+	// GetStatus возвращает состояние последней проверки.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// ListViolations возвращает нарушения последней проверки.
+	ListViolations(ctx context.Context, in *ListViolationsRequest, opts ...grpc.CallOption) (*ListViolationsResponse, error)
+	// ListLessons возвращает занятия, загруженные при последней проверке.
+	ListLessons(ctx context.Context, in *ListLessonsRequest, opts ...grpc.CallOption) (*ListLessonsResponse, error)
+}
+
+type lessonCounterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLessonCounterClient(cc grpc.ClientConnInterface) LessonCounterClient {
+	return &lessonCounterClient{cc}
+}
+
+func (c *lessonCounterClient) StartCheck(ctx context.Context, in *StartCheckRequest, opts ...grpc.CallOption) (*StartCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartCheckResponse)
+	err := c.cc.Invoke(ctx, LessonCounter_StartCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonCounterClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, LessonCounter_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonCounterClient) ListViolations(ctx context.Context, in *ListViolationsRequest, opts ...grpc.CallOption) (*ListViolationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListViolationsResponse)
+	err := c.cc.Invoke(ctx, LessonCounter_ListViolations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonCounterClient) ListLessons(ctx context.Context, in *ListLessonsRequest, opts ...grpc.CallOption) (*ListLessonsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLessonsResponse)
+	err := c.cc.Invoke(ctx, LessonCounter_ListLessons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LessonCounterServer is the server API for LessonCounter service.
+// All implementations must embed UnimplementedLessonCounterServer
+// for forward compatibility.
+type LessonCounterServer interface {
+	// StartCheck запускает проверку недели. Одновременно выполняется только одна проверка.
+	StartCheck(context.Context, *StartCheckRequest) (*StartCheckResponse, error)
+	// GetStatus возвращает состояние последней проверки.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// ListViolations возвращает нарушения последней проверки.
+	ListViolations(context.Context, *ListViolationsRequest) (*ListViolationsResponse, error)
+	// ListLessons возвращает занятия, загруженные при последней проверке.
+	ListLessons(context.Context, *ListLessonsRequest) (*ListLessonsResponse, error)
+	mustEmbedUnimplementedLessonCounterServer()
+}
+
+// UnimplementedLessonCounterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLessonCounterServer struct{}
+
+func (UnimplementedLessonCounterServer) StartCheck(context.Context, *StartCheckRequest) (*StartCheckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartCheck not implemented")
+}
+func (UnimplementedLessonCounterServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedLessonCounterServer) ListViolations(context.Context, *ListViolationsRequest) (*ListViolationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListViolations not implemented")
+}
+func (UnimplementedLessonCounterServer) ListLessons(context.Context, *ListLessonsRequest) (*ListLessonsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLessons not implemented")
+}
+func (UnimplementedLessonCounterServer) mustEmbedUnimplementedLessonCounterServer() {}
+func (UnimplementedLessonCounterServer) testEmbeddedByValue()                       {}
+
+// UnsafeLessonCounterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LessonCounterServer will
+// result in compilation errors.
+type UnsafeLessonCounterServer interface {
+	mustEmbedUnimplementedLessonCounterServer()
+}
+
+func RegisterLessonCounterServer(s grpc.ServiceRegistrar, srv LessonCounterServer) {
+	// If the following call panics, it indicates UnimplementedLessonCounterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LessonCounter_ServiceDesc, srv)
+}
+
+func _LessonCounter_StartCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonCounterServer).StartCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonCounter_StartCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonCounterServer).StartCheck(ctx, req.(*StartCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonCounter_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonCounterServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonCounter_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonCounterServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonCounter_ListViolations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListViolationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonCounterServer).ListViolations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonCounter_ListViolations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonCounterServer).ListViolations(ctx, req.(*ListViolationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonCounter_ListLessons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLessonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonCounterServer).ListLessons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonCounter_ListLessons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonCounterServer).ListLessons(ctx, req.(*ListLessonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LessonCounter_ServiceDesc is the grpc.ServiceDesc for LessonCounter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LessonCounter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lessoncounter.v1.LessonCounter",
+	HandlerType: (*LessonCounterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartCheck",
+			Handler:    _LessonCounter_StartCheck_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _LessonCounter_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListViolations",
+			Handler:    _LessonCounter_ListViolations_Handler,
+		},
+		{
+			MethodName: "ListLessons",
+			Handler:    _LessonCounter_ListLessons_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lessoncounter/v1/lesson_counter.proto",
+}
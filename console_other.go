@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// hideConsole ничего не делает: вне Windows программа не открывает своё окно консоли
+func hideConsole() {}
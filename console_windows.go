@@ -0,0 +1,9 @@
+package main
+
+import "syscall"
+
+// hideConsole отключает программу от окна консоли, чтобы при работе из значка
+// в области уведомлений оно не оставалось на экране
+func hideConsole() {
+	syscall.NewLazyDLL("kernel32.dll").NewProc("FreeConsole").Call()
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertLevel — важность оповещения
+type AlertLevel string
+
+const (
+	AlertInfo    AlertLevel = "info"    // для сведения: задание выполнено, вышла новая версия
+	AlertWarning AlertLevel = "warning" // данные проверки неполные
+	AlertError   AlertLevel = "error"   // проверка не удалась
+)
+
+// DisplayName возвращает название важности для отображения пользователю
+func (l AlertLevel) DisplayName() string {
+	switch l {
+	case AlertInfo:
+		return "Сведения"
+	case AlertWarning:
+		return "Предупреждение"
+	case AlertError:
+		return "Ошибка"
+	default:
+		return string(l)
+	}
+}
+
+// Alert — оповещение на странице «Оповещения»: важное событие, которое не должно потеряться
+// вместе с прокрученным журналом, пока его не прочтут
+type Alert struct {
+	ID        string
+	CreatedAt time.Time
+	Level     AlertLevel
+	Title     string
+	Text      string
+	Link      string // страница с подробностями; пусто — ссылки нет
+	Read      bool
+}
+
+// alertIssues — категории проблем проверки, о которых создаются оповещения, в порядке вывода,
+// и заголовки оповещений
+var alertIssues = []struct {
+	category IssueCategory
+	title    string
+}{
+	{IssueSourceFailed, "не загрузились источники"},
+	{IssueStaleFile, "устаревшие файлы преподавателей"},
+}
+
+// AlertsFor возвращает оповещения о событии: по одному на каждую категорию alertIssues
+// завершённой проверки, о выполненном задании очереди и о новой версии программы.
+// Остальные события оповещений не создают.
+func AlertsFor(event Event) []Alert {
+	var alerts []Alert
+	switch event.Type {
+	case EventCheckCompleted:
+		for _, kind := range alertIssues {
+			var sources []string
+			for _, issue := range event.Issues {
+				if issue.Category == kind.category {
+					sources = append(sources, issue.Source)
+				}
+			}
+			if len(sources) == 0 {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Level: AlertWarning,
+				Title: fmt.Sprintf("Неделя %s: %s (%d)", event.Week, kind.title, len(sources)),
+				Text:  strings.Join(sources, ", "),
+				Link:  "/",
+			})
+		}
+	case EventJobFinished:
+		job := event.Job
+		if job == nil {
+			return nil
+		}
+		alert := Alert{
+			Level: AlertInfo,
+			Title: fmt.Sprintf("Задание %d: неделя %s проверена", job.ID, job.Week),
+			Link:  "/jobs",
+		}
+		switch {
+		case job.Status == JobFailed:
+			alert.Level = AlertError
+			alert.Title = fmt.Sprintf("Задание %d: проверка недели %s не удалась", job.ID, job.Week)
+			alert.Text = job.Error
+		case len(job.FailedGroups) > 0:
+			alert.Level = AlertWarning
+			alert.Text = "Не загрузились с сайта группы: " + strings.Join(job.FailedGroups, ", ")
+		}
+		alerts = append(alerts, alert)
+	case EventUpdateAvailable:
+		alerts = append(alerts, Alert{
+			Level: AlertInfo,
+			Title: fmt.Sprintf("Доступна новая версия %s", event.Version),
+			Link:  event.URL,
+		})
+	}
+	for i := range alerts {
+		alerts[i].CreatedAt = event.Time
+	}
+	return alerts
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// PairTime — время начала и конца пары в формате "15:04"
+type PairTime struct {
+	Start string
+	End   string
+}
+
+// Validate проверяет формат времени и что пара заканчивается позже, чем начинается
+func (p PairTime) Validate() error {
+	start, err := time.Parse("15:04", p.Start)
+	if err != nil {
+		return fmt.Errorf("неверное время начала %q", p.Start)
+	}
+	end, err := time.Parse("15:04", p.End)
+	if err != nil {
+		return fmt.Errorf("неверное время конца %q", p.End)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("пара заканчивается (%s) не позже, чем начинается (%s)", p.End, p.Start)
+	}
+	return nil
+}
+
+// BellSchedule — расписание звонков: время пар по умолчанию и варианты для отдельных дней недели.
+// В варианте дня достаточно указать только пары, время которых отличается.
+type BellSchedule struct {
+	Default map[int]PairTime
+	Days    map[time.Weekday]map[int]PairTime
+}
+
+// pairsPerDay — количество пар в учебном дне по расписанию звонков; меняется через SetPairsPerDay.
+// Его записывают запуск программы и страницы настроек, а читают проверка порогов и отчеты без
+// результата проверки из разных горутин, поэтому значение атомарное. Проверка сюда не пишет:
+// число пар её звонков возвращается вместе с результатом проверки.
+var pairsPerDay atomic.Int64
+
+func init() {
+	pairsPerDay.Store(int64(DefaultBellSchedule().PairCount()))
+}
+
+// SetPairsPerDay задаёт количество пар в учебном дне: по нему строятся сетка пар отчета
+// и варианты переноса занятий. Неположительные значения игнорируются
+func SetPairsPerDay(n int) {
+	if n > 0 {
+		pairsPerDay.Store(int64(n))
+	}
+}
+
+// PairsPerDay возвращает количество пар в учебном дне
+func PairsPerDay() int {
+	return int(pairsPerDay.Load())
+}
+
+// DefaultBellSchedule возвращает расписание звонков вуза, действовавшее до появления настройки
+func DefaultBellSchedule() BellSchedule {
+	return BellSchedule{
+		Default: map[int]PairTime{
+			1: {"08:30", "10:00"},
+			2: {"10:10", "11:40"},
+			3: {"11:50", "13:20"},
+			4: {"13:40", "15:10"},
+			5: {"15:20", "16:50"},
+			6: {"16:55", "18:25"},
+			7: {"18:30", "20:00"},
+		},
+		Days: map[time.Weekday]map[int]PairTime{},
+	}
+}
+
+// PairTime возвращает время пары с учётом варианта для дня недели
+func (b BellSchedule) PairTime(weekday time.Weekday, number int) (PairTime, bool) {
+	if pair, ok := b.Days[weekday][number]; ok {
+		return pair, true
+	}
+	pair, ok := b.Default[number]
+	return pair, ok
+}
+
+// Times возвращает время начала и конца пары в указанную дату. Для неизвестной пары
+// возвращается начало дня.
+func (b BellSchedule) Times(date time.Time, number int) (time.Time, time.Time) {
+	date = date.In(location)
+	pair, _ := b.PairTime(date.Weekday(), number)
+	return atTime(date, pair.Start), atTime(date, pair.End)
+}
+
+// Numbers возвращает номера пар, для которых задано время, по возрастанию
+func (b BellSchedule) Numbers() []int {
+	seen := make(map[int]bool)
+	for number := range b.Default {
+		seen[number] = true
+	}
+	for _, day := range b.Days {
+		for number := range day {
+			seen[number] = true
+		}
+	}
+	numbers := make([]int, 0, len(seen))
+	for number := range seen {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// PairCount возвращает количество пар в дне — наибольший номер пары, для которой задано время
+// по умолчанию или в варианте дня; 0, если время не задано ни для одной пары
+func (b BellSchedule) PairCount() int {
+	numbers := b.Numbers()
+	if len(numbers) == 0 {
+		return 0
+	}
+	return numbers[len(numbers)-1]
+}
+
+// Validate проверяет время всех пар
+func (b BellSchedule) Validate() error {
+	for number, pair := range b.Default {
+		if err := pair.Validate(); err != nil {
+			return fmt.Errorf("пара %d: %w", number, err)
+		}
+	}
+	for weekday, day := range b.Days {
+		for number, pair := range day {
+			if err := pair.Validate(); err != nil {
+				return fmt.Errorf("%s, пара %d: %w", WeekdayName(weekday), number, err)
+			}
+		}
+	}
+	return nil
+}
+
+// WeekdayName возвращает название дня недели
+func WeekdayName(weekday time.Weekday) string {
+	names := []string{
+		"воскресенье", "понедельник", "вторник",
+		"среда", "четверг", "пятница", "суббота",
+	}
+	return names[weekday]
+}
+
+// atTime возвращает момент времени "15:04" в указанную дату
+func atTime(date time.Time, hm string) time.Time {
+	t, err := time.Parse("15:04", hm)
+	if err != nil {
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location())
+}
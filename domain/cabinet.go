@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CabinetPattern — правило приведения записи кабинета к идентификатору: запись, подходящая
+// под регулярное выражение Match, заменяется шаблоном ID, в котором $1, $2… — группы выражения.
+// Например, Match `^(\d+)\s*\((К\d+)\)$` и ID `$2-$1` превращают "24 (К3)" в "К3-24".
+type CabinetPattern struct {
+	Match string `yaml:"match"`
+	ID    string `yaml:"id"`
+}
+
+// CabinetNormalizer приводит записи кабинетов из разных источников ("К3-24", "24 (К3)",
+// "ауд. 24") к идентификаторам из справочника, чтобы занятия в одном кабинете совпадали
+// при поиске накладок и подсчёте загрузки
+type CabinetNormalizer struct {
+	patterns []*regexp.Regexp
+	ids      []string
+	known    map[string]string // нормализованная запись → идентификатор из справочника
+}
+
+// NewCabinetNormalizer создаёт нормализатор по справочнику кабинетов и правилам приведения.
+// Правила применяются по порядку, срабатывает первое подходящее.
+func NewCabinetNormalizer(cabinets []string, patterns []CabinetPattern) (CabinetNormalizer, error) {
+	n := CabinetNormalizer{known: make(map[string]string)}
+	for _, cabinet := range cabinets {
+		cabinet = strings.Join(strings.Fields(cabinet), " ")
+		if cabinet != "" {
+			n.known[normalizeName(cabinet)] = cabinet
+		}
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern.Match)
+		if err != nil {
+			return CabinetNormalizer{}, fmt.Errorf("правило кабинета %q: %w", pattern.Match, err)
+		}
+		n.patterns = append(n.patterns, re)
+		n.ids = append(n.ids, pattern.ID)
+	}
+	return n, nil
+}
+
+// IsEmpty сообщает, что нет ни справочника, ни правил и приводить записи не к чему
+func (n CabinetNormalizer) IsEmpty() bool {
+	return len(n.known) == 0 && len(n.patterns) == 0
+}
+
+// Normalize возвращает идентификатор кабинета для записи из расписания. Лишние пробелы убираются,
+// затем применяется первое подходящее правило, и результат сверяется со справочником с точностью
+// до регистра, пробелов, тире и букв другой раскладки. Второй результат — найден ли кабинет
+// в справочнике; без справочника он всегда true.
+func (n CabinetNormalizer) Normalize(label string) (string, bool) {
+	cabinet := strings.Join(strings.Fields(label), " ")
+	if cabinet == "" {
+		return "", true
+	}
+	for i, re := range n.patterns {
+		if re.MatchString(cabinet) {
+			cabinet = strings.TrimSpace(re.ReplaceAllString(cabinet, n.ids[i]))
+			break
+		}
+	}
+	if len(n.known) == 0 {
+		return cabinet, true
+	}
+	if id, ok := n.known[normalizeName(cabinet)]; ok {
+		return id, true
+	}
+	return cabinet, false
+}
+
+// Apply приводит кабинеты занятий к идентификаторам и возвращает записи, которых нет в справочнике,
+// без повторов в порядке появления
+func (n CabinetNormalizer) Apply(lessons []Lesson) []string {
+	if n.IsEmpty() {
+		return nil
+	}
+	var unknown []string
+	seen := make(map[string]bool)
+	for i := range lessons {
+		cabinet, ok := n.Normalize(lessons[i].Cabinet)
+		lessons[i].Cabinet = cabinet
+		if !ok && !seen[cabinet] {
+			seen[cabinet] = true
+			unknown = append(unknown, cabinet)
+		}
+	}
+	return unknown
+}
+
+// Known возвращает идентификаторы кабинетов справочника
+func (n CabinetNormalizer) Known() []string {
+	ids := make([]string, 0, len(n.known))
+	for _, id := range n.known {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Cabinet — кабинет из справочника cabinets.yaml
+type Cabinet struct {
+	ID       string // идентификатор, например "К3-24"
+	Type     string // тип кабинета, например "класс фортепиано"; пусто — тип не указан
+	Building string // корпус; если не указан, берётся часть идентификатора до тире ("К3")
+}
+
+// BuildingName возвращает корпус кабинета: указанный явно или часть идентификатора до тире
+func (c Cabinet) BuildingName() string {
+	if c.Building != "" {
+		return c.Building
+	}
+	if i := strings.Index(c.ID, "-"); i > 0 {
+		return c.ID[:i]
+	}
+	return ""
+}
+
+// roomNumber возвращает номер кабинета — последние цифры идентификатора; -1, если цифр нет
+func (c Cabinet) roomNumber() int {
+	end := len(c.ID)
+	start := end
+	for start > 0 && c.ID[start-1] >= '0' && c.ID[start-1] <= '9' {
+		start--
+	}
+	if start == end {
+		return -1
+	}
+	number, err := strconv.Atoi(c.ID[start:end])
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// CabinetConflict — накладка: в одном кабинете в одно время стоят разные занятия
+type CabinetConflict struct {
+	Cabinet     string
+	Time        LessonTime          // пара накладки
+	Lessons     []Lesson            // по одному занятию каждой дисциплины и преподавателя
+	Suggestions []CabinetSuggestion // свободные кабинеты для каждого из занятий
+}
+
+// CabinetSuggestion — свободные кабинеты того же типа, в которые можно перенести занятие
+type CabinetSuggestion struct {
+	Lesson   Lesson
+	Cabinets []string // лучшие варианты первыми
+}
+
+// FindCabinetConflicts находит накладки кабинетов: занятия разных дисциплин или преподавателей,
+// занимающие один кабинет в одну половинку пары. Одно занятие нескольких студентов или групп
+// (совпадают дисциплина и преподаватели) накладкой не считается. Для каждого занятия накладки
+// подбираются свободные в этот слот кабинеты справочника того же типа: сначала в том же корпусе,
+// затем с ближайшим номером. Без справочника накладки находятся, но варианты не предлагаются.
+func FindCabinetConflicts(lessons []Lesson, cabinets []Cabinet) []CabinetConflict {
+	type slotKey struct {
+		cabinet string
+		date    string
+		number  int
+	}
+	type activity struct {
+		lesson Lesson
+		halves map[int]bool
+	}
+
+	slots := make(map[slotKey][]*activity)
+	byCabinetDay := make(map[string]Schedule) // кабинет|дата → занятия, для поиска свободных кабинетов
+	var keys []slotKey
+	for _, lesson := range lessons {
+		cabinet := strings.TrimSpace(lesson.Cabinet)
+		if cabinet == "" {
+			continue
+		}
+		date := lesson.Time.DateString()
+		byCabinetDay[cabinet+"|"+date] = append(byCabinetDay[cabinet+"|"+date], lesson)
+
+		key := slotKey{cabinet, date, lesson.Time.Number}
+		identity := activityIdentity(lesson)
+		var found *activity
+		for _, a := range slots[key] {
+			if activityIdentity(a.lesson) == identity {
+				found = a
+				break
+			}
+		}
+		if found == nil {
+			if len(slots[key]) == 0 {
+				keys = append(keys, key)
+			}
+			found = &activity{lesson: lesson, halves: make(map[int]bool)}
+			slots[key] = append(slots[key], found)
+		}
+		for slot := range (Schedule{lesson}).SlotsOccupied() {
+			found.halves[slot] = true
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		if keys[i].number != keys[j].number {
+			return keys[i].number < keys[j].number
+		}
+		return keys[i].cabinet < keys[j].cabinet
+	})
+
+	registry := make(map[string]Cabinet, len(cabinets))
+	for _, cabinet := range cabinets {
+		registry[cabinet.ID] = cabinet
+	}
+
+	var conflicts []CabinetConflict
+	for _, key := range keys {
+		activities := slots[key]
+		var clashing []Lesson
+		for i, a := range activities {
+			for j, b := range activities {
+				if i != j && overlaps(b.halves, a.lesson.Time) {
+					clashing = append(clashing, a.lesson)
+					break
+				}
+			}
+		}
+		if len(clashing) == 0 {
+			continue
+		}
+
+		conflict := CabinetConflict{Cabinet: key.cabinet, Time: clashing[0].Time, Lessons: clashing}
+		if current, ok := registry[key.cabinet]; ok {
+			for _, lesson := range clashing {
+				free := freeCabinets(current, lesson, cabinets, byCabinetDay)
+				if len(free) > 0 {
+					conflict.Suggestions = append(conflict.Suggestions, CabinetSuggestion{Lesson: lesson, Cabinets: free})
+				}
+			}
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// activityIdentity — дисциплина и преподаватели занятия: занятия с одинаковыми значениями
+// в одном кабинете — это одно совместное занятие
+func activityIdentity(l Lesson) string {
+	return normalizeName(l.Discipline) + "|" + normalizeName(l.TeacherNames())
+}
+
+// freeCabinets подбирает кабинеты справочника того же типа, что и current, свободные во время
+// занятия lesson, — сначала в том же корпусе, затем по близости номера; не более maxAlternatives
+func freeCabinets(current Cabinet, lesson Lesson, cabinets []Cabinet, byCabinetDay map[string]Schedule) []string {
+	var candidates []Cabinet
+	for _, cabinet := range cabinets {
+		if cabinet.ID == current.ID || cabinet.Type != current.Type {
+			continue
+		}
+		if overlaps(byCabinetDay[cabinet.ID+"|"+lesson.Time.DateString()].SlotsOccupied(), lesson.Time) {
+			continue
+		}
+		candidates = append(candidates, cabinet)
+	}
+
+	building, number := current.BuildingName(), current.roomNumber()
+	distance := func(c Cabinet) int {
+		if number < 0 || c.roomNumber() < 0 {
+			return math.MaxInt
+		}
+		d := c.roomNumber() - number
+		if d < 0 {
+			return -d
+		}
+		return d
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		sameI, sameJ := candidates[i].BuildingName() == building, candidates[j].BuildingName() == building
+		if sameI != sameJ {
+			return sameI
+		}
+		if di, dj := distance(candidates[i]), distance(candidates[j]); di != dj {
+			return di < dj
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	var result []string
+	for _, cabinet := range candidates {
+		if len(result) >= maxAlternatives {
+			break
+		}
+		result = append(result, cabinet.ID)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Holiday — каникулы или праздничный период, в который занятий нет
+type Holiday struct {
+	Name  string
+	Start Week
+	End   Week // последняя неделя каникул (включительно)
+}
+
+// Contains проверяет, приходится ли неделя на каникулы
+func (h Holiday) Contains(w Week) bool {
+	return !w.Start().Before(h.Start.Start()) && !w.Start().After(h.End.Start())
+}
+
+// AcademicCalendar — календарь учебного года: первая неделя, семестры и каникулы.
+// Нумерация недель совпадает с нумерацией на сайте расписания: неделя начала
+// учебного года — первая, дальше номера идут подряд, включая каникулы.
+type AcademicCalendar struct {
+	YearStart Week
+	Semesters []Semester
+	Holidays  []Holiday
+}
+
+// IsZero сообщает, что календарь не настроен
+func (c AcademicCalendar) IsZero() bool {
+	return c.YearStart.IsZero()
+}
+
+// WeekNumber возвращает номер недели от начала учебного года (с единицы) или 0,
+// если календарь не настроен или неделя раньше начала года
+func (c AcademicCalendar) WeekNumber(w Week) int {
+	if c.IsZero() || w.Start().Before(c.YearStart.Start()) {
+		return 0
+	}
+	return int(w.Start().Sub(c.YearStart.Start()).Hours()/24/7+0.5) + 1
+}
+
+// WeekByNumber возвращает неделю по номеру от начала учебного года
+func (c AcademicCalendar) WeekByNumber(n int) (Week, error) {
+	if c.IsZero() {
+		return Week{}, fmt.Errorf("учебный календарь не настроен")
+	}
+	if n < 1 {
+		return Week{}, fmt.Errorf("неверный номер недели: %d", n)
+	}
+	return WeekOf(c.YearStart.Start().AddDate(0, 0, 7*(n-1))), nil
+}
+
+// ParseWeek разбирает неделю, введённую пользователем: дату в одном из форматов ParseWeek
+// или номер недели от начала учебного года («неделя 7»). Ошибка перечисляет допустимые форматы.
+func (c AcademicCalendar) ParseWeek(s string) (Week, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Week{}, fmt.Errorf("не указана неделя; %s", weekFormatsHint)
+	}
+	if week, ok := parseWeekDate(s); ok {
+		return week, nil
+	}
+	if n, ok := parseWeekNumber(s); ok {
+		if c.IsZero() {
+			return Week{}, fmt.Errorf("неделю по номеру (%q) можно указать, только если настроен учебный календарь; укажите дату, например 10.02.2025", s)
+		}
+		return c.WeekByNumber(n)
+	}
+	return Week{}, fmt.Errorf("не удалось распознать неделю %q; %s", s, weekFormatsHint)
+}
+
+// CalendarWeek — неделя учебного года для выбора в интерфейсе
+type CalendarWeek struct {
+	Number   int // номер недели от начала учебного года
+	Week     Week
+	Semester string // семестр, в который попадает неделя
+	Holiday  string // название каникул, если неделя на них приходится
+}
+
+// Label возвращает подпись недели, например "Неделя 7 (14.10–20.10), Осень 2024"
+func (w CalendarWeek) Label() string {
+	label := fmt.Sprintf("Неделя %d (%s–%s)", w.Number, w.Week.Start().Format("02.01"), w.Week.End().Format("02.01"))
+	if w.Holiday != "" {
+		return label + ", " + w.Holiday
+	}
+	if w.Semester != "" {
+		return label + ", " + w.Semester
+	}
+	return label
+}
+
+// Weeks перечисляет недели учебного года от первой до конца последнего семестра
+func (c AcademicCalendar) Weeks() []CalendarWeek {
+	if c.IsZero() {
+		return nil
+	}
+
+	last := c.YearStart
+	for _, semester := range c.Semesters {
+		if end := WeekOf(semester.End); end.Start().After(last.Start()) {
+			last = end
+		}
+	}
+
+	var weeks []CalendarWeek
+	for week := c.YearStart; !week.Start().After(last.Start()); week = week.Next() {
+		weeks = append(weeks, c.describe(week))
+	}
+	return weeks
+}
+
+// describe возвращает описание недели: номер, семестр и каникулы
+func (c AcademicCalendar) describe(w Week) CalendarWeek {
+	result := CalendarWeek{Number: c.WeekNumber(w), Week: w}
+	for _, semester := range c.Semesters {
+		if semester.Contains(w.Start()) || semester.Contains(w.End()) {
+			result.Semester = semester.Name
+			break
+		}
+	}
+	for _, holiday := range c.Holidays {
+		if holiday.Contains(w) {
+			result.Holiday = holiday.Name
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// CheckRecord хранит результат одной проверки расписания за неделю
+type CheckRecord struct {
+	Week         Week
+	CheckedAt    time.Time        // время проверки
+	LessonsCount int              // количество загруженных занятий
+	DailyLoad    float64          // средняя дневная нагрузка студента, ак. ч; 0 — в записи не сохранена
+	Completeness int              // полнота данных проверки, % (Completeness.Score); 0 — в записи не сохранена
+	Violations   []Violation      // найденные нарушения
+	Hours        []DeliveredHours // часы индивидуальных занятий по студентам и дисциплинам
+	Groups       []GroupLoad      // нагрузка и окна по группам; нет у проверок прежних версий
+	Resolved     []Violation      // нарушения прежних проверок недели, которых нет в последней
+}
+
+// Supersede возвращает запись повторной проверки недели, в которой нарушения прошлых проверок,
+// исчезнувшие в новой, перенесены в Resolved. Снова появившееся нарушение перестаёт считаться
+// устранённым.
+func (r CheckRecord) Supersede(previous CheckRecord) CheckRecord {
+	current := make(map[string]bool, len(r.Violations))
+	for _, v := range r.Violations {
+		current[v.ID()] = true
+	}
+
+	seen := make(map[string]bool)
+	r.Resolved = nil
+	for _, list := range [][]Violation{previous.Resolved, previous.Violations} {
+		for _, v := range list {
+			id := v.ID()
+			if current[id] || seen[id] {
+				continue
+			}
+			seen[id] = true
+			r.Resolved = append(r.Resolved, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Comment — комментарий к нарушению для обсуждения внутри программы
+type Comment struct {
+	ViolationID string
+	Author      string
+	Text        string
+	CreatedAt   time.Time
+}
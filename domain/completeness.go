@@ -0,0 +1,144 @@
+package domain
+
+import "sort"
+
+// Completeness — полнота данных, по которым выполнена проверка: сколько ожидаемых
+// преподавателей, групп и студентов действительно попали в неё. Отчет без нарушений
+// при неполных данных означает лишь, что нарушений не нашли в загруженном.
+type Completeness struct {
+	TeachersFound    int // преподавателей с занятиями в файлах за неделю
+	TeachersExpected int // преподавателей, приславших файлы расписания
+	GroupsLoaded     int // групп, расписание которых загружено с сайта
+	GroupsExpected   int // групп проверяемых студентов
+	StudentsMatched  int // студентов, для которых найдены занятия
+	StudentsTracked  int // проверяемых студентов
+	// MissingTeachers — ожидаемые преподаватели без занятий за неделю: скорее всего, не прислали
+	// новый файл
+	MissingTeachers []string
+	// ByList — ожидаемые преподаватели взяты из списка ведущих индивидуальные занятия
+	// (ExpectTeachers), а не из файлов расписания
+	ByList bool
+}
+
+// MeasureCompleteness вычисляет полноту данных проверки недели. Ожидаемые преподаватели —
+// все, кто встречается в файлах расписания coverage, в том числе в устаревших; найденными
+// считаются те, у кого есть индивидуальные занятия недели. Группы, которые не загрузились
+// или не найдены на сайте, определяются по проблемам issues.
+func MeasureCompleteness(week Week, students []Student, lessons []Lesson, coverage []FileCoverage, issues []Issue) Completeness {
+	var c Completeness
+
+	teaching := make(map[string]bool)
+	for _, lesson := range lessons {
+		if lesson.Source != SourceIndividual || !week.IsZero() && !week.Contains(lesson.Time.Date) {
+			continue
+		}
+		for _, teacher := range lesson.Teachers {
+			teaching[teacher.Name] = true
+		}
+	}
+	expected := make(map[string]bool)
+	for _, file := range coverage {
+		for _, teacher := range file.Teachers {
+			if teacher == "Unknown" || expected[teacher] {
+				continue
+			}
+			expected[teacher] = true
+			if teaching[teacher] {
+				c.TeachersFound++
+			} else {
+				c.MissingTeachers = append(c.MissingTeachers, teacher)
+			}
+		}
+	}
+	c.TeachersExpected = len(expected)
+	sort.Strings(c.MissingTeachers)
+
+	missingGroups := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Category == IssueSourceFailed || issue.Category == IssueGroupUnknown {
+			missingGroups[issue.Source] = true
+		}
+	}
+	groups := make(map[string]bool)
+	schedule := Schedule(lessons)
+	for _, student := range students {
+		if !groups[student.Group] {
+			groups[student.Group] = true
+			if !missingGroups[student.Group] {
+				c.GroupsLoaded++
+			}
+		}
+		if len(schedule.ForStudent(student)) > 0 {
+			c.StudentsMatched++
+		}
+	}
+	c.GroupsExpected = len(groups)
+	c.StudentsTracked = len(students)
+	return c
+}
+
+// ExpectTeachers заменяет полноту по преподавателям полнотой по списку преподавателей,
+// ведущих индивидуальные занятия: ожидается expected преподавателей, absent из них без занятий
+// на неделе (см. AbsentTeachers)
+func (c *Completeness) ExpectTeachers(expected int, absent []Teacher) {
+	c.ByList = true
+	c.TeachersExpected = expected
+	c.TeachersFound = expected - len(absent)
+	c.MissingTeachers = nil
+	for _, teacher := range absent {
+		c.MissingTeachers = append(c.MissingTeachers, teacher.Name)
+	}
+	sort.Strings(c.MissingTeachers)
+}
+
+// Merge складывает полноту данных нескольких недель, например для отчета за месяц
+func (c Completeness) Merge(other Completeness) Completeness {
+	c.TeachersFound += other.TeachersFound
+	c.TeachersExpected += other.TeachersExpected
+	c.GroupsLoaded += other.GroupsLoaded
+	c.GroupsExpected += other.GroupsExpected
+	c.StudentsMatched += other.StudentsMatched
+	c.StudentsTracked += other.StudentsTracked
+	c.ByList = c.ByList || other.ByList
+	seen := make(map[string]bool, len(c.MissingTeachers))
+	missing := append([]string(nil), c.MissingTeachers...)
+	for _, teacher := range missing {
+		seen[teacher] = true
+	}
+	for _, teacher := range other.MissingTeachers {
+		if !seen[teacher] {
+			seen[teacher] = true
+			missing = append(missing, teacher)
+		}
+	}
+	sort.Strings(missing)
+	c.MissingTeachers = missing
+	return c
+}
+
+// Score возвращает полноту данных в процентах — среднее долей найденных преподавателей,
+// загруженных групп и сопоставленных студентов. Доли, для которых ничего не ожидалось,
+// не учитываются; если не ожидалось ничего, полнота — 100%.
+func (c Completeness) Score() int {
+	var sum float64
+	n := 0
+	for _, part := range [][2]int{
+		{c.TeachersFound, c.TeachersExpected},
+		{c.GroupsLoaded, c.GroupsExpected},
+		{c.StudentsMatched, c.StudentsTracked},
+	} {
+		if part[1] > 0 {
+			sum += float64(part[0]) / float64(part[1])
+			n++
+		}
+	}
+	if n == 0 {
+		return 100
+	}
+	return int(sum / float64(n) * 100)
+}
+
+// IsComplete сообщает, что все ожидаемые преподаватели, группы и студенты есть в проверке
+func (c Completeness) IsComplete() bool {
+	return c.TeachersFound == c.TeachersExpected && c.GroupsLoaded == c.GroupsExpected && c.StudentsMatched == c.StudentsTracked
+}
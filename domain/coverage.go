@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileCoverage описывает, какие даты и каких преподавателей охватывает файл расписания
+type FileCoverage struct {
+	File     string    // имя файла
+	Path     string    // полный путь к файлу
+	Teachers []string  // преподаватели, чьи занятия есть в файле
+	First    time.Time // дата первого занятия в файле
+	Last     time.Time // дата последнего занятия в файле
+}
+
+// CoverageOf вычисляет охват файла по его занятиям
+func CoverageOf(path string, lessons []Lesson) FileCoverage {
+	coverage := FileCoverage{File: filepath.Base(path), Path: path}
+	seen := make(map[string]bool)
+	for _, lesson := range lessons {
+		date := lesson.Time.Date
+		if coverage.First.IsZero() || date.Before(coverage.First) {
+			coverage.First = date
+		}
+		if date.After(coverage.Last) {
+			coverage.Last = date
+		}
+		for _, teacher := range lesson.Teachers {
+			if teacher.Name != "" && !seen[teacher.Name] {
+				seen[teacher.Name] = true
+				coverage.Teachers = append(coverage.Teachers, teacher.Name)
+			}
+		}
+	}
+	sort.Strings(coverage.Teachers)
+	return coverage
+}
+
+// AbsentTeachers возвращает преподавателей из expected, у которых нет ни одного индивидуального
+// занятия на неделе week — ни под основным именем, ни под псевдонимом. Чаще всего это значит,
+// что файл расписания на неделю не прислан.
+func AbsentTeachers(expected []Teacher, lessons []Lesson, week Week) []Teacher {
+	var names []string
+	for _, lesson := range lessons {
+		if lesson.Source != SourceIndividual || !week.Contains(lesson.Time.Date) {
+			continue
+		}
+		for _, teacher := range lesson.Teachers {
+			names = append(names, teacher.Name)
+		}
+	}
+
+	var absent []Teacher
+	for _, teacher := range expected {
+		found := false
+		for _, name := range names {
+			if teacher.Matches(name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			absent = append(absent, teacher)
+		}
+	}
+	return absent
+}
+
+// StaleTeacher — преподаватель, самый свежий файл которого заканчивается раньше проверяемой недели
+type StaleTeacher struct {
+	Teacher string
+	File    string    // самый свежий файл преподавателя
+	Last    time.Time // последняя дата в этом файле
+}
+
+// StaleTeachers находит преподавателей, у которых нет файла с датами проверяемой недели
+// или позже: скорее всего, новый файл не прислали, и их занятия недели пропадут из проверки.
+func StaleTeachers(files []FileCoverage, week Week) []StaleTeacher {
+	latest := make(map[string]FileCoverage)
+	for _, file := range files {
+		for _, teacher := range file.Teachers {
+			if current, ok := latest[teacher]; !ok || file.Last.After(current.Last) {
+				latest[teacher] = file
+			}
+		}
+	}
+
+	var stale []StaleTeacher
+	for teacher, file := range latest {
+		if file.Last.Before(week.Start()) {
+			stale = append(stale, StaleTeacher{Teacher: teacher, File: file.File, Last: file.Last})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Teacher < stale[j].Teacher
+	})
+	return stale
+}
@@ -0,0 +1,35 @@
+package domain
+
+// DepartmentSource определяет, откуда загружается групповое расписание отделения
+type DepartmentSource string
+
+const (
+	DepartmentSourceSite DepartmentSource = "site" // расписание с сайта вуза
+	DepartmentSourceNone DepartmentSource = "none" // групповое расписание не загружается
+)
+
+// DisplayName возвращает название источника для отображения пользователю
+func (s DepartmentSource) DisplayName() string {
+	switch s {
+	case DepartmentSourceSite:
+		return "Сайт вуза"
+	case DepartmentSourceNone:
+		return "Не загружать"
+	default:
+		return string(s)
+	}
+}
+
+// Department содержит информацию об отделении (факультете)
+type Department struct {
+	Name        string           // название, как на сайте и в записях студентов
+	SiteID      string           // идентификатор отделения на сайте; пусто — искать по названию
+	Source      DepartmentSource // источник группового расписания
+	RuleProfile string           // название профиля правил проверки для отделения
+	MaxParallel int              // одновременных запросов к сайту за группами отделения; 0 — только общий предел
+}
+
+// LoadsFromSite сообщает, нужно ли загружать групповое расписание отделения с сайта
+func (d Department) LoadsFromSite() bool {
+	return d.Source == "" || d.Source == DepartmentSourceSite
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// IssueCategory определяет вид некритичной проблемы, возникшей при проверке
+type IssueCategory string
+
+const (
+	IssueFileSkipped      IssueCategory = "file_skipped"      // файл расписания пропущен
+	IssueSourceFailed     IssueCategory = "source_failed"     // источник (например, группа на сайте) не загружен
+	IssueStudentUnmatched IssueCategory = "student_unmatched" // для студента не найдено ни одного занятия
+	IssueRuleInvalid      IssueCategory = "rule_invalid"      // пользовательское правило не удалось разобрать
+	IssueHalvesMerged     IssueCategory = "halves_merged"     // половинки пары с разными данными объединены
+	IssueMergeConflict    IssueCategory = "merge_conflict"    // расхождение в половинках пары, требующее проверки
+	IssueGroupUnknown     IssueCategory = "group_unknown"     // группы студента нет на сайте (вероятно, опечатка)
+	IssueTeacherUnknown   IssueCategory = "teacher_unknown"   // преподавателя из XLS-файла нет на сайте
+	IssueStaleFile        IssueCategory = "stale_file"        // файл преподавателя не охватывает проверяемую неделю
+	IssueCabinetUnknown   IssueCategory = "cabinet_unknown"   // кабинета нет в справочнике кабинетов
+	IssueTeacherAbsent    IssueCategory = "teacher_absent"    // нет занятий преподавателя, чей файл ожидается каждую неделю
+	IssueGroupChanged     IssueCategory = "group_changed"     // расписание группы на сайте изменилось с прошлой загрузки
+	IssueTeacherUnread    IssueCategory = "teacher_unread"    // заголовок преподавателя в XLS-файле не распознан, занятия без имени
+)
+
+// DisplayName возвращает название категории для отображения пользователю
+func (c IssueCategory) DisplayName() string {
+	switch c {
+	case IssueFileSkipped:
+		return "Файл пропущен"
+	case IssueSourceFailed:
+		return "Источник недоступен"
+	case IssueStudentUnmatched:
+		return "Студент без занятий"
+	case IssueRuleInvalid:
+		return "Ошибка в правиле"
+	case IssueHalvesMerged:
+		return "Половинки пары объединены"
+	case IssueMergeConflict:
+		return "Расхождение в половинках пары"
+	case IssueGroupUnknown:
+		return "Группа не найдена"
+	case IssueTeacherUnknown:
+		return "Преподаватель не найден"
+	case IssueStaleFile:
+		return "Устаревший файл"
+	case IssueCabinetUnknown:
+		return "Кабинет не найден"
+	case IssueTeacherAbsent:
+		return "Нет файла преподавателя"
+	case IssueGroupChanged:
+		return "Расписание группы изменилось"
+	case IssueTeacherUnread:
+		return "Преподаватель не распознан"
+	default:
+		return string(c)
+	}
+}
+
+// Informational сообщает, что проблема этой категории — уведомление об изменениях,
+// а не признак неполных данных
+func (c IssueCategory) Informational() bool {
+	return c == IssueGroupChanged
+}
+
+// Issue описывает одну некритичную проблему
+type Issue struct {
+	Category IssueCategory `json:"category"`
+	Source   string        `json:"source"` // файл, группа или студент, к которому относится проблема
+	Message  string        `json:"message"`
+}
+
+// String возвращает текстовое описание проблемы
+func (i Issue) String() string {
+	if i.Source == "" {
+		return fmt.Sprintf("%s: %s", i.Category.DisplayName(), i.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", i.Category.DisplayName(), i.Source, i.Message)
+}
+
+// Diagnostics накапливает некритичные проблемы проверки из всех слоев приложения.
+// Безопасен для использования из нескольких горутин; методы допускают nil-получатель.
+type Diagnostics struct {
+	mu     sync.Mutex
+	issues []Issue
+}
+
+// NewDiagnostics создает пустой набор диагностик
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{}
+}
+
+// Add добавляет проблему указанной категории
+func (d *Diagnostics) Add(category IssueCategory, source, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.issues = append(d.issues, Issue{
+		Category: category,
+		Source:   source,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Issues возвращает копию накопленных проблем
+func (d *Diagnostics) Issues() []Issue {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Issue(nil), d.issues...)
+}
+
+// Len возвращает количество накопленных проблем
+func (d *Diagnostics) Len() int {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.issues)
+}
+
+// Err возвращает накопленные проблемы как ошибку или nil, если проблем нет
+func (d *Diagnostics) Err() error {
+	if d.Len() == 0 {
+		return nil
+	}
+	return d
+}
+
+// Error реализует интерфейс error, перечисляя все проблемы
+func (d *Diagnostics) Error() string {
+	issues := d.Issues()
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "sort"
+
+// GroupDigest — нарушения студентов одной группы за неделю, для куратора
+type GroupDigest struct {
+	Group    string
+	Students []StudentDigest // студенты группы с нарушениями в порядке имён
+	Total    int             // всего нарушений в группе
+}
+
+// StudentDigest — нарушения одного студента в сводке по группе
+type StudentDigest struct {
+	Student    string
+	Year       int
+	Violations []Violation // нарушения по дням недели
+}
+
+// BuildGroupDigest группирует нарушения недели по группам и студентам.
+// Группы и студенты упорядочены по имени, нарушения студента — по дате.
+func BuildGroupDigest(violations []Violation) []GroupDigest {
+	byGroup := make(map[string]map[string]*StudentDigest)
+	for _, v := range violations {
+		students := byGroup[v.Group]
+		if students == nil {
+			students = make(map[string]*StudentDigest)
+			byGroup[v.Group] = students
+		}
+		student := students[v.StudentName]
+		if student == nil {
+			student = &StudentDigest{Student: v.StudentName, Year: v.Year}
+			students[v.StudentName] = student
+		}
+		student.Violations = append(student.Violations, v)
+	}
+
+	digest := make([]GroupDigest, 0, len(byGroup))
+	for group, students := range byGroup {
+		g := GroupDigest{Group: group}
+		for _, student := range students {
+			sort.SliceStable(student.Violations, func(i, j int) bool {
+				return student.Violations[i].Date.Before(student.Violations[j].Date)
+			})
+			g.Students = append(g.Students, *student)
+			g.Total += len(student.Violations)
+		}
+		sort.Slice(g.Students, func(i, j int) bool {
+			return g.Students[i].Student < g.Students[j].Student
+		})
+		digest = append(digest, g)
+	}
+	sort.Slice(digest, func(i, j int) bool {
+		return digest[i].Group < digest[j].Group
+	})
+	return digest
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType определяет тип события проверки расписания
+type EventType string
+
+const (
+	EventCheckStarted    EventType = "check_started"    // проверка запущена
+	EventSourceParsed    EventType = "source_parsed"    // источник расписания загружен
+	EventViolationFound  EventType = "violation_found"  // найдено нарушение
+	EventCheckCompleted  EventType = "check_completed"  // проверка завершена
+	EventJobFinished     EventType = "job_finished"     // задание из очереди выполнено
+	EventUpdateAvailable EventType = "update_available" // вышла новая версия программы
+)
+
+// Event описывает событие, возникшее при проверке расписания
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	Week      Week
+	Source    LessonSource // для EventSourceParsed — загруженный источник
+	Count     int          // количество занятий (EventSourceParsed) или нарушений (EventCheckCompleted)
+	Violation *Violation   // для EventViolationFound — найденное нарушение
+	Err       error        // для EventCheckCompleted — ошибка, если проверка не удалась
+	Issues    []Issue      // для EventCheckCompleted — некритичные проблемы проверки
+	Job       *Job         // для EventJobFinished — выполненное задание
+	Version   string       // для EventUpdateAvailable — новая версия
+	URL       string       // для EventUpdateAvailable — страница выпуска
+}
+
+// EventListener получает события проверки
+type EventListener interface {
+	HandleEvent(event Event)
+}
+
+// EventListenerFunc позволяет использовать функцию как EventListener
+type EventListenerFunc func(event Event)
+
+// HandleEvent вызывает f(event)
+func (f EventListenerFunc) HandleEvent(event Event) {
+	f(event)
+}
+
+// EventBus рассылает события всем подписанным слушателям.
+// Методы допускают nil-получатель, поэтому шину можно не передавать.
+type EventBus struct {
+	mu        sync.RWMutex
+	listeners []EventListener
+}
+
+// NewEventBus создает шину событий с указанными слушателями
+func NewEventBus(listeners ...EventListener) *EventBus {
+	return &EventBus{listeners: listeners}
+}
+
+// Subscribe добавляет слушателя
+func (b *EventBus) Subscribe(listener EventListener) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
+// Unsubscribe удаляет слушателя, добавленного через Subscribe. Слушатель должен быть
+// сравнимым значением (например, указателем)
+func (b *EventBus) Unsubscribe(listener EventListener) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, l := range b.listeners {
+		if l == listener {
+			b.listeners = append(b.listeners[:i:i], b.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish синхронно передает событие всем слушателям. Если время события не задано,
+// подставляется текущее.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.RLock()
+	listeners := append([]EventListener(nil), b.listeners...)
+	b.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener.HandleEvent(event)
+	}
+}
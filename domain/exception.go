@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Exception — допущенное диспетчером исключение: нарушение вида Kind (и правила Rule
+// для пользовательских правил) у студента в указанный день недели не считается
+// нарушением до даты Until включительно
+type Exception struct {
+	StudentName string
+	Kind        ViolationKind
+	Rule        string // название пользовательского правила для ViolationCustom
+	Weekday     time.Weekday
+	Until       time.Time // последний день действия исключения
+	Reason      string
+	ApprovedBy  string
+}
+
+// ExceptionFor создает исключение для повторяющегося нарушения: тот же студент,
+// вид нарушения, правило и день недели
+func ExceptionFor(v Violation, until time.Time) Exception {
+	return Exception{
+		StudentName: v.StudentName,
+		Kind:        v.Kind,
+		Rule:        v.Rule,
+		Weekday:     v.Date.In(location).Weekday(),
+		Until:       until,
+	}
+}
+
+// ID возвращает идентификатор исключения для удаления через интерфейс
+func (e Exception) ID() string {
+	key := fmt.Sprintf("%s|%s|%s|%d|%s", e.StudentName, e.Kind, e.Rule, e.Weekday, e.Until.In(location).Format("2006-01-02"))
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Title возвращает описание исключения для отображения пользователю
+func (e Exception) Title() string {
+	return fmt.Sprintf("%s, %s", Violation{Kind: e.Kind, Rule: e.Rule}.Title(), WeekdayName(e.Weekday))
+}
+
+// Expired сообщает, что срок действия исключения истёк к дате date
+func (e Exception) Expired(date time.Time) bool {
+	return date.In(location).Format("2006-01-02") > e.Until.In(location).Format("2006-01-02")
+}
+
+// Matches сообщает, что исключение покрывает нарушение
+func (e Exception) Matches(v Violation) bool {
+	return e.StudentName == v.StudentName &&
+		e.Kind == v.Kind &&
+		e.Rule == v.Rule &&
+		e.Weekday == v.Date.In(location).Weekday() &&
+		!e.Expired(v.Date)
+}
+
+// ExceptedViolation — нарушение, подавленное допущенным исключением
+type ExceptedViolation struct {
+	Violation Violation
+	Exception Exception
+}
+
+// ApplyExceptions отделяет нарушения, покрытые действующими исключениями, от остальных
+func ApplyExceptions(violations []Violation, exceptions []Exception) ([]Violation, []ExceptedViolation) {
+	if len(exceptions) == 0 {
+		return violations, nil
+	}
+
+	var kept []Violation
+	var excepted []ExceptedViolation
+	for _, v := range violations {
+		matched := false
+		for _, e := range exceptions {
+			if e.Matches(v) {
+				excepted = append(excepted, ExceptedViolation{Violation: v, Exception: e})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			kept = append(kept, v)
+		}
+	}
+	return kept, excepted
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "sort"
+
+// GroupLoad — нагрузка студентов группы за проверенную неделю. Хранятся суммы, а не средние,
+// чтобы показатели нескольких недель можно было сложить.
+type GroupLoad struct {
+	Group       string
+	Year        int
+	Students    int // проверяемых студентов группы
+	StudentDays int // учебных дней студентов группы (день считается у каждого студента)
+	Hours       int // академических часов в эти дни
+	GapHalves   int // окон в эти дни, в половинках пар
+}
+
+// DailyLoad возвращает среднюю дневную нагрузку студента группы, ак. ч
+func (g GroupLoad) DailyLoad() float64 {
+	if g.StudentDays == 0 {
+		return 0
+	}
+	return float64(g.Hours) / float64(g.StudentDays)
+}
+
+// MeasureGroupLoads считает нагрузку и окна студентов по группам так же, как проверка:
+// подгруппы одной пары учитываются один раз
+func MeasureGroupLoads(lessons []Lesson, students []Student) []GroupLoad {
+	schedule := Schedule(lessons)
+	byGroup := make(map[string]*GroupLoad)
+	var groups []string
+	for _, student := range students {
+		load := byGroup[student.Group]
+		if load == nil {
+			load = &GroupLoad{Group: student.Group, Year: student.Year}
+			byGroup[student.Group] = load
+			groups = append(groups, student.Group)
+		}
+		load.Students++
+		for _, day := range schedule.ForStudent(student).MergeSubgroups().ByDay() {
+			load.StudentDays++
+			load.Hours += day.Hours()
+			load.GapHalves += day.Gaps()
+		}
+	}
+
+	sort.Strings(groups)
+	result := make([]GroupLoad, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *byGroup[group])
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// JobStatus — состояние задания на проверку в очереди
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // ожидает выполнения
+	JobRunning   JobStatus = "running"   // выполняется
+	JobDone      JobStatus = "done"      // выполнено
+	JobFailed    JobStatus = "failed"    // завершилось ошибкой, можно повторить
+	JobCancelled JobStatus = "cancelled" // отменено до выполнения
+)
+
+// DisplayName возвращает название состояния для отображения пользователю
+func (s JobStatus) DisplayName() string {
+	switch s {
+	case JobQueued:
+		return "В очереди"
+	case JobRunning:
+		return "Выполняется"
+	case JobDone:
+		return "Выполнено"
+	case JobFailed:
+		return "Ошибка"
+	case JobCancelled:
+		return "Отменено"
+	default:
+		return string(s)
+	}
+}
+
+// Job — задание на проверку недели из очереди
+type Job struct {
+	ID          int64
+	Week        Week
+	FullReport  bool  // отчет с расписанием всех студентов
+	Batch       int64 // первое задание недель, поставленных в очередь вместе; 0 — одиночное задание
+	Status      JobStatus
+	Attempts    int    // количество запусков
+	Error       string // ошибка последнего запуска
+	CreatedAt   time.Time
+	ScheduledAt time.Time // не запускать раньше этого момента
+	StartedAt   time.Time
+	FinishedAt  time.Time
+
+	// FailedGroups — группы, не загрузившиеся с сайта при последнем запуске даже после повторов:
+	// задание выполнено, но отчет по этим группам неполный
+	FailedGroups []string
+}
+
+// Due сообщает, что задание ожидает выполнения и его время наступило
+func (j Job) Due(now time.Time) bool {
+	return j.Status == JobQueued && !j.ScheduledAt.After(now)
+}
+
+// CanCancel сообщает, что задание ещё можно отменить
+func (j Job) CanCancel() bool {
+	return j.Status == JobQueued
+}
+
+// CanRetry сообщает, что задание можно поставить в очередь повторно
+func (j Job) CanRetry() bool {
+	return j.Status == JobFailed || j.Status == JobCancelled
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LessonChanges — различия двух версий расписания группы: добавленные, удалённые
+// и перенесённые занятия
+type LessonChanges struct {
+	Added   []Lesson
+	Removed []Lesson
+	Moved   []LessonMove
+}
+
+// LessonMove — занятие, перенесённое на другое время или в другой кабинет
+type LessonMove struct {
+	From Lesson
+	To   Lesson
+}
+
+// IsEmpty сообщает, что версии расписания совпадают
+func (c LessonChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Moved) == 0
+}
+
+// String перечисляет изменения для сообщения диспетчеру
+func (c LessonChanges) String() string {
+	var parts []string
+	if len(c.Added) > 0 {
+		parts = append(parts, "добавлено: "+describeLessons(c.Added))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, "удалено: "+describeLessons(c.Removed))
+	}
+	if len(c.Moved) > 0 {
+		moves := make([]string, len(c.Moved))
+		for i, move := range c.Moved {
+			moves[i] = fmt.Sprintf("%s → %s", describeLesson(move.From), lessonPlace(move.To))
+		}
+		parts = append(parts, "перенесено: "+strings.Join(moves, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DiffLessons сравнивает прежнюю и новую версии расписания группы. Занятие, исчезнувшее
+// в одном слоте и появившееся в другом (или в другом кабинете) с той же дисциплиной,
+// подгруппой и преподавателями, считается перенесённым.
+func DiffLessons(previous, current []Lesson) LessonChanges {
+	remaining := make(map[string]int)
+	for _, lesson := range previous {
+		remaining[lessonChangeKey(lesson)]++
+	}
+	var added []Lesson
+	for _, lesson := range current {
+		key := lessonChangeKey(lesson)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		added = append(added, lesson)
+	}
+	var removed []Lesson
+	for _, lesson := range previous {
+		key := lessonChangeKey(lesson)
+		if remaining[key] > 0 {
+			remaining[key]--
+			removed = append(removed, lesson)
+		}
+	}
+
+	var changes LessonChanges
+	used := make([]bool, len(added))
+	for _, from := range removed {
+		moved := false
+		for i, to := range added {
+			if !used[i] && lessonIdentity(from) == lessonIdentity(to) {
+				used[i], moved = true, true
+				changes.Moved = append(changes.Moved, LessonMove{From: from, To: to})
+				break
+			}
+		}
+		if !moved {
+			changes.Removed = append(changes.Removed, from)
+		}
+	}
+	for i, lesson := range added {
+		if !used[i] {
+			changes.Added = append(changes.Added, lesson)
+		}
+	}
+	return changes
+}
+
+// lessonIdentity — что определяет занятие независимо от времени и кабинета
+func lessonIdentity(l Lesson) string {
+	return strings.Join([]string{l.Discipline, l.Subgroup, l.TeacherNames()}, "|")
+}
+
+// lessonChangeKey — занятие вместе со временем и кабинетом: совпадение ключей значит,
+// что занятие не менялось
+func lessonChangeKey(l Lesson) string {
+	return fmt.Sprintf("%s|%s|%d|%d|%s", lessonIdentity(l), l.Time.Date.In(location).Format("2006-01-02"), l.Time.Number, l.Time.PairHalf, l.Cabinet)
+}
+
+// describeLessons перечисляет занятия через запятую
+func describeLessons(lessons []Lesson) string {
+	items := make([]string, len(lessons))
+	for i, lesson := range lessons {
+		items[i] = describeLesson(lesson)
+	}
+	return strings.Join(items, ", ")
+}
+
+// describeLesson описывает занятие: дисциплина, подгруппа, время и кабинет
+func describeLesson(l Lesson) string {
+	name := l.Discipline
+	if l.Subgroup != "" {
+		name += " (" + l.Subgroup + ")"
+	}
+	return name + ", " + lessonPlace(l)
+}
+
+// lessonPlace описывает время и кабинет занятия
+func lessonPlace(l Lesson) string {
+	place := l.Time.SlotString()
+	if l.Cabinet != "" {
+		place += ", каб. " + l.Cabinet
+	}
+	return place
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Limits содержит пороги стандартных правил проверки
+type Limits struct {
+	MaxDailyHours    int `yaml:"max_daily_hours"`     // допустимая дневная нагрузка, ак. ч
+	MaxGaps          int `yaml:"max_gaps"`            // допустимые окна, в половинках пар
+	MaxGapsFirstYear int `yaml:"max_gaps_first_year"` // допустимые окна для студентов 1-го курса
+	// MaxConsecutive — допустимые занятия подряд без перерыва, в половинках пар. Незаданный или
+	// нулевой порог WithDefaults заменяет значением по умолчанию; выключается правило в Enabled.
+	MaxConsecutive int `yaml:"max_consecutive"`
+	// MaxWeeklyHours — допустимая недельная нагрузка, ак. ч. Незаданный или нулевой порог
+	// WithDefaults заменяет значением по умолчанию; выключается правило в Enabled.
+	MaxWeeklyHours int `yaml:"max_weekly_hours"`
+	// Years — пороги отдельных курсов; незаданные (нулевые) значения курса берутся из общих порогов
+	Years map[int]YearLimits `yaml:"years,omitempty"`
+	// Enabled включает и выключает стандартные правила (виды из standardRules); не указанное правило включено
+	Enabled map[ViolationKind]bool `yaml:"enabled,omitempty"`
+}
+
+// YearLimits содержит пороги стандартных правил для одного курса
+type YearLimits struct {
+	MaxDailyHours int `yaml:"max_daily_hours,omitempty"` // допустимая дневная нагрузка, ак. ч
+	MaxGaps       int `yaml:"max_gaps,omitempty"`        // допустимые окна, в половинках пар
+}
+
+// standardRules — виды нарушений стандартных правил, которые можно выключить в Enabled
+var standardRules = []ViolationKind{ViolationOverload, ViolationGaps, ViolationClash, ViolationWeeklyOverload, ViolationConsecutive, ViolationOverlap}
+
+// DefaultLimits возвращает пороги, действующие, если они не заданы в rules.yaml
+func DefaultLimits() Limits {
+	return Limits{MaxDailyHours: MaxDailyHours, MaxGaps: 4, MaxGapsFirstYear: 2, MaxWeeklyHours: 54, MaxConsecutive: 10}
+}
+
+// WithDefaults возвращает пороги, в которых незаданные (нулевые) значения заменены значениями по умолчанию
+func (l Limits) WithDefaults() Limits {
+	defaults := DefaultLimits()
+	if l.MaxDailyHours == 0 {
+		l.MaxDailyHours = defaults.MaxDailyHours
+	}
+	if l.MaxGaps == 0 {
+		l.MaxGaps = defaults.MaxGaps
+	}
+	if l.MaxGapsFirstYear == 0 {
+		l.MaxGapsFirstYear = defaults.MaxGapsFirstYear
+	}
+	if l.MaxWeeklyHours == 0 {
+		l.MaxWeeklyHours = defaults.MaxWeeklyHours
+	}
+	if l.MaxConsecutive == 0 {
+		l.MaxConsecutive = defaults.MaxConsecutive
+	}
+	return l
+}
+
+// Validate проверяет, что пороги положительны и не превышают длину учебного дня
+func (l Limits) Validate() error {
+	var errs []error
+	maxHours := PairsPerDay() * 2
+	if l.MaxDailyHours < 1 || l.MaxDailyHours > maxHours {
+		errs = append(errs, fmt.Errorf("дневная нагрузка должна быть от 1 до %d часов, указано %d", maxHours, l.MaxDailyHours))
+	}
+	if l.MaxGaps < 1 || l.MaxGaps > maxHours {
+		errs = append(errs, fmt.Errorf("допустимые окна должны быть от 1 до %d, указано %d", maxHours, l.MaxGaps))
+	}
+	if l.MaxGapsFirstYear < 1 || l.MaxGapsFirstYear > maxHours {
+		errs = append(errs, fmt.Errorf("допустимые окна 1-го курса должны быть от 1 до %d, указано %d", maxHours, l.MaxGapsFirstYear))
+	}
+	if l.MaxConsecutive < 1 || l.MaxConsecutive > maxHours {
+		errs = append(errs, fmt.Errorf("занятия подряд без перерыва должны быть от 1 до %d половинок пар, указано %d", maxHours, l.MaxConsecutive))
+	}
+	if maxWeekly := maxHours * 7; l.MaxWeeklyHours < 1 || l.MaxWeeklyHours > maxWeekly {
+		errs = append(errs, fmt.Errorf("недельная нагрузка должна быть от 1 до %d часов, указано %d", maxWeekly, l.MaxWeeklyHours))
+	}
+	for _, year := range l.years() {
+		limits := l.Years[year]
+		if year < 1 || year > 6 {
+			errs = append(errs, fmt.Errorf("курс в years должен быть от 1 до 6, указано %d", year))
+		}
+		// 0 у курса — порог не задан и берётся из общих
+		if limits.MaxDailyHours < 0 || limits.MaxDailyHours > maxHours {
+			errs = append(errs, fmt.Errorf("дневная нагрузка %d-го курса должна быть от 0 (как у всех курсов) до %d часов, указано %d", year, maxHours, limits.MaxDailyHours))
+		}
+		if limits.MaxGaps < 0 || limits.MaxGaps > maxHours {
+			errs = append(errs, fmt.Errorf("допустимые окна %d-го курса должны быть от 0 (как у всех курсов) до %d, указано %d", year, maxHours, limits.MaxGaps))
+		}
+	}
+	for kind := range l.Enabled {
+		if !slices.Contains(standardRules, kind) {
+			errs = append(errs, fmt.Errorf("неизвестное правило %q в enabled: допустимы %s", kind, standardRuleNames()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MaxGapsFor возвращает допустимые окна для студента курса year: порог курса из Years,
+// а если он не задан — общий порог (для 1-го курса — MaxGapsFirstYear)
+func (l Limits) MaxGapsFor(year int) int {
+	if gaps := l.Years[year].MaxGaps; gaps > 0 {
+		return gaps
+	}
+	if year == 1 {
+		return l.MaxGapsFirstYear
+	}
+	return l.MaxGaps
+}
+
+// MaxDailyHoursFor возвращает допустимую дневную нагрузку для студента курса year:
+// порог курса из Years, а если он не задан — общий порог
+func (l Limits) MaxDailyHoursFor(year int) int {
+	if hours := l.Years[year].MaxDailyHours; hours > 0 {
+		return hours
+	}
+	return l.MaxDailyHours
+}
+
+// IsEnabled сообщает, проверяется ли стандартное правило вида kind (см. standardRules). Правила, не указанные
+// в Enabled, и пользовательские правила включены всегда.
+func (l Limits) IsEnabled(kind ViolationKind) bool {
+	enabled, ok := l.Enabled[kind]
+	return !ok || enabled
+}
+
+// standardRuleNames перечисляет стандартные правила через запятую для сообщений об ошибках
+func standardRuleNames() string {
+	names := make([]string, len(standardRules))
+	for i, kind := range standardRules {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
+
+// years возвращает курсы с собственными порогами по возрастанию
+func (l Limits) years() []int {
+	years := make([]int, 0, len(l.Years))
+	for year := range l.Years {
+		years = append(years, year)
+	}
+	slices.Sort(years)
+	return years
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "fmt"
+
+// MergePolicy определяет, как объединять половинки пары, данные которых расходятся
+// (разные дисциплины, преподаватели или кабинеты)
+type MergePolicy string
+
+const (
+	// MergePolicyJoin объединяет половинки в одну пару, перечисляя различия через "/"
+	MergePolicyJoin MergePolicy = "join"
+	// MergePolicyPreferIndividual не объединяет расходящиеся половинки: каждая остается
+	// отдельным индивидуальным занятием
+	MergePolicyPreferIndividual MergePolicy = "prefer-individual"
+	// MergePolicyWarn объединяет половинки как MergePolicyJoin и отмечает расхождение как предупреждение
+	MergePolicyWarn MergePolicy = "flag-as-warning"
+)
+
+// ParseMergePolicy разбирает название политики объединения. Пустая строка означает MergePolicyJoin
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	switch MergePolicy(s) {
+	case "", MergePolicyJoin:
+		return MergePolicyJoin, nil
+	case MergePolicyPreferIndividual, MergePolicyWarn:
+		return MergePolicy(s), nil
+	default:
+		return "", fmt.Errorf("неизвестная политика объединения %q (допустимо: join, prefer-individual, flag-as-warning)", s)
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang — язык отчета и выгрузок
+type Lang string
+
+const (
+	LangRU Lang = "ru" // русский, язык интерфейса
+	LangEN Lang = "en" // английский, для аккредитации
+)
+
+// ParseLang разбирает код языка из параметра запроса; пустой код — русский
+func ParseLang(code string) (Lang, error) {
+	switch Lang(strings.ToLower(strings.TrimSpace(code))) {
+	case "", LangRU:
+		return LangRU, nil
+	case LangEN:
+		return LangEN, nil
+	default:
+		return LangRU, fmt.Errorf("неизвестный язык %q: доступны ru, en", code)
+	}
+}
+
+// catalogs — каталоги сообщений по языкам. Ключ — русский текст (или строка формата), как он
+// записан в коде и шаблонах, поэтому для русского языка каталог не нужен.
+var catalogs = map[Lang]map[string]string{
+	LangEN: messagesEN,
+}
+
+// T переводит сообщение msgid. Сообщение, которого нет в каталоге, возвращается без перевода.
+func (l Lang) T(msgid string) string {
+	if translated, ok := catalogs[l][msgid]; ok {
+		return translated
+	}
+	return msgid
+}
+
+// Tf переводит строку формата и подставляет в неё аргументы
+func (l Lang) Tf(format string, args ...any) string {
+	return fmt.Sprintf(l.T(format), args...)
+}
+
+// Message — сообщение с подстановками, которое переводится при выводе: Format служит ключом каталога
+type Message struct {
+	Format string
+	Args   []any
+}
+
+// Msg создаёт сообщение со строкой формата format
+func Msg(format string, args ...any) Message {
+	return Message{Format: format, Args: args}
+}
+
+// In возвращает сообщение на языке lang
+func (m Message) In(lang Lang) string {
+	return lang.Tf(m.Format, m.Args...)
+}
+
+// String возвращает сообщение на русском
+func (m Message) String() string {
+	return m.In(LangRU)
+}
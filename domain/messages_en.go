@@ -0,0 +1,164 @@
+package domain
+
+// messagesEN — английский каталог сообщений отчета и выгрузок. Ключи — русские тексты из кода
+// и шаблонов; при изменении русского текста ключ здесь меняется вместе с ним, иначе текст
+// останется без перевода.
+var messagesEN = map[string]string{
+	// Виды нарушений, категории проблем и источники занятий
+	"Превышение нагрузки":           "Daily load exceeded",
+	"Превышение окон":               "Too many gaps",
+	"Нарушение правила":             "Rule violated",
+	"Наложение на групповую пару":   "Overlaps a group class",
+	"Превышение недельной нагрузки": "Weekly load exceeded",
+	"Занятия без перерыва":          "Classes without a break",
+	"Наложение занятий":             "Overlapping lessons",
+	"%s «%s»":                       "%s “%s”",
+	"Файл пропущен":                 "File skipped",
+	"Источник недоступен":           "Source unavailable",
+	"Студент без занятий":           "Student without lessons",
+	"Ошибка в правиле":              "Rule error",
+	"Половинки пары объединены":     "Class halves merged",
+	"Расхождение в половинках пары": "Class halves differ",
+	"Группа не найдена":             "Group not found",
+	"Преподаватель не найден":       "Teacher not found",
+	"Устаревший файл":               "Outdated file",
+	"Кабинет не найден":             "Room not found",
+	"Нет файла преподавателя":       "Teacher file missing",
+	"Расписание группы изменилось":  "Group schedule changed",
+	"Преподаватель не распознан":    "Teacher not recognized",
+	"Индивидуальное расписание":     "Individual schedule",
+	"Групповое расписание":          "Group schedule",
+	"Импорт": "Import",
+	"Неизвестный источник": "Unknown source",
+
+	// Дни недели и месяцы
+	"понедельник":      "Monday",
+	"вторник":          "Tuesday",
+	"среда":            "Wednesday",
+	"четверг":          "Thursday",
+	"пятница":          "Friday",
+	"суббота":          "Saturday",
+	"воскресенье":      "Sunday",
+	"Понедельник":      "Monday",
+	"Вторник":          "Tuesday",
+	"Среда":            "Wednesday",
+	"Четверг":          "Thursday",
+	"Пятница":          "Friday",
+	"Суббота":          "Saturday",
+	"Воскресенье":      "Sunday",
+	"январь":           "January",
+	"февраль":          "February",
+	"март":             "March",
+	"апрель":           "April",
+	"май":              "May",
+	"июнь":             "June",
+	"июль":             "July",
+	"август":           "August",
+	"сентябрь":         "September",
+	"октябрь":          "October",
+	"ноябрь":           "November",
+	"декабрь":          "December",
+	"%s %s, пара %d":   "%s %s, class %d",
+	" (%d-я половина)": " (half %d)",
+
+	// Описания правил в сносках
+	"Дневная нагрузка — сумма академических часов всех занятий студента за день: допускается не более %d ак. ч.":                                            "Daily load is the total academic hours of all of the student's lessons in a day: at most %d academic hours are allowed.",
+	"Окна — свободные половинки пар между первым и последним занятием дня: допускается не более %d, для студентов 1-го курса — не более %d.":                "Gaps are free class halves between the first and the last lesson of the day: at most %d are allowed, at most %d for first-year students.",
+	"Для %d-го курса допускается нагрузка не более %d ак. ч.":                                                                                               "Year %d students may have at most %d academic hours.",
+	"Для %d-го курса допускается окон не более %d.":                                                                                                         "Year %d students may have at most %d gaps.",
+	"Недельная нагрузка — сумма академических часов всех занятий студента за неделю: допускается не более %d ак. ч.":                                        "Weekly load is the total academic hours of all of the student's lessons in a week: at most %d academic hours are allowed.",
+	"Занятия без перерыва — занятые половинки пар подряд, без свободной половинки между ними: допускается не более %d подряд; указано число половинок пар.": "Classes without a break are occupied class halves in a row with no free half between them: at most %d in a row are allowed; the number of class halves is shown.",
+	"Индивидуальное занятие не должно совпадать по времени с групповой парой группы студента; указаны часы наложения.":                                      "An individual lesson must not coincide with a group class of the student's group; the overlapping hours are shown.",
+	"У студента не должно быть двух разных индивидуальных занятий или двух групповых пар в одной половинке пары; указаны часы совпадающих занятий.":         "A student must not have two different individual lessons or two group classes in the same class half; the hours of the coinciding lessons are shown.",
+	"Правило «%s» из rules.yaml нарушается, если для дня студента выполняется условие: %s. Указаны часы за день.":                                           "Rule “%s” from rules.yaml is violated when the student's day matches the condition: %s. The hours for the day are shown.",
+
+	// Отчет о нарушениях
+	"Период: с %s по %s":               "Period: %s to %s",
+	"Скачать занятия недели (JSON)":    "Download week lessons (JSON)",
+	"Сводка по группам":                "Summary by group",
+	"Скачать отчет":                    "Download report",
+	"Скачать отчет на английском":      "Download report in English",
+	"Разослать студентам их нарушения": "Send students their violations",
+	"Студент:":                         "Student:",
+	"Группа:":                          "Group:",
+	"Курс:":                            "Year:",
+	"1 курс":                           "Year 1",
+	"Нарушение:":                       "Violation:",
+	"ак.ч":                             "ac. h",
+	"Обсуждение":                       "Discussion",
+	"Варианты переноса":                "Rescheduling options",
+	"(свободны студент, преподаватель и кабинет):": "(student, teacher and room are free):",
+	"Отлично!": "Great!",
+	"Нарушений в расписании не найдено.":                                "No schedule violations found.",
+	"Нарушений в загруженных данных не найдено,":                        "No violations found in the loaded data,",
+	"но данные неполные (%d%%) — см. итоги проверки.":                   "but the data is incomplete (%d%%), see the check summary.",
+	"Накладки кабинетов":                                                "Room double-bookings",
+	"Кабинет %s: %s":                                                    "Room %s: %s",
+	"Свободные кабинеты того же типа":                                   "Free rooms of the same type",
+	"(сначала в том же корпусе):":                                       "(same building first):",
+	"Свободных кабинетов того же типа в справочнике cabinets.yaml нет.": "There are no free rooms of the same type in cabinets.yaml.",
+	"Допущенные исключения":                                             "Approved exceptions",
+	"Студент":      "Student",
+	"Группа":       "Group",
+	"Нарушение":    "Violation",
+	"Дата":         "Date",
+	"Действует до": "Valid until",
+	"Основание":    "Reason",
+	"Расписание всех студентов": "Schedules of all students",
+	"Нарушения:":                "Violations:",
+	"Нарушений нет":             "No violations",
+	"№":                         "No.",
+	"Преподаватель":             "Teacher",
+	"Дисциплина":                "Subject",
+	"Часы":                      "Hours",
+	"Источник:":                 "Source:",
+	"время:":                    "time:",
+	"%d-я половина пары":        "half %d of the class",
+	"Первый курс":               "First year",
+	"Нормы для 1-го курса строже. Нарушений: %d, студентов: %d.": "First-year limits are stricter. Violations: %d, students: %d.",
+	"Ак.ч":                 "Ac. h",
+	"Индивидуальные, ак.ч": "Individual, ac. h",
+	"Групповые, ак.ч":      "Group, ac. h",
+	"Всего":                "Total",
+	"Итого за неделю":      "Week total",
+	"Пробная проверка: результат не сохранён в историю проверок и не рассылается студентам.": "Dry run: the result is not saved to the check history and is not sent to students.",
+	"Нарушений:":           "Violations:",
+	"студентов:":           "students:",
+	"групп:":               "groups:",
+	"допущено исключений:": "approved exceptions:",
+	"Полнота данных:":      "Data completeness:",
+	"преподаватели с занятиями в файлах: %d из %d":                          "teachers with lessons in files: %d of %d",
+	"группы с сайта: %d из %d":                                              "groups from the website: %d of %d",
+	"студенты с занятиями: %d из %d":                                        "students with lessons: %d of %d",
+	"Нет занятий за проверяемый период у преподавателей:":                   "No lessons in the checked period for teachers:",
+	"Напомнить прислать файлы":                                              "Remind to send files",
+	"Данные неполные, результаты могут быть занижены:":                      "The data is incomplete, results may be understated:",
+	"Данные полные: все источники загружены без замечаний":                  "The data is complete: all sources loaded without issues",
+	"Данные неполные: отчет без нарушений ещё не значит, что нарушений нет": "The data is incomplete: a report without violations does not mean there are none",
+	"Изменения с прошлой загрузки:":                                         "Changes since the last load:",
+	"Подробности — в замечаниях к проверке.":                                "See the check notes for details.",
+	"Не загрузились с сайта группы:":                                        "Groups that failed to load from the website:",
+	"Повторить только неудачные":                                            "Retry failed only",
+
+	// Отчет за месяц
+	"Отчет за период с %s по %s: недель %d, нарушений %d": "Report for %s to %s: %d weeks, %d violations",
+	"Скачать занятия за период (JSON)":                    "Download period lessons (JSON)",
+	"Неделя с %s по %s":                                   "Week %s to %s",
+	"Накладки кабинетов:":                                 "Room double-bookings:",
+	"свободны:":                                           "free:",
+	"Допущенные исключения:":                              "Approved exceptions:",
+
+	// Выгрузки XLSX
+	"Сводка": "Summary",
+	"Сводка нарушений по группам за %s": "Violations by group for %s",
+	"Нарушений не найдено":              "No violations found",
+	"Группа %s: нарушения за %s":        "Group %s: violations for %s",
+	"Курс":            "Year",
+	"День":            "Day",
+	"Всего нарушений": "Total violations",
+	"Табель индивидуальных занятий за %s": "Individual lessons timesheet for %s",
+	"Итого":  "Subtotal",
+	"Табель": "Timesheet",
+	"Отчет о нарушениях расписания":       "Schedule violations report",
+	"Отчет о нарушениях расписания за %s": "Schedule violations report for %s",
+}
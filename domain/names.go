@@ -0,0 +1,137 @@
+package domain
+
+import "strings"
+
+// lookalikes заменяет латинские буквы, похожие на кириллические, и убирает тире и пробелы,
+// в которых чаще всего ошибаются при наборе названий групп
+var lookalikes = strings.NewReplacer(
+	"a", "а", "b", "в", "c", "с", "e", "е", "h", "н", "k", "к", "m", "м",
+	"o", "о", "p", "р", "t", "т", "x", "х", "y", "у",
+	"-", "", "–", "", "—", "", " ", "",
+)
+
+// normalizeName приводит название к виду для нестрогого сравнения
+func normalizeName(name string) string {
+	return lookalikes.Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// ClosestName возвращает название из candidates, похожее на name: совпадающее с точностью
+// до регистра, пробелов и букв другой раскладки или отличающееся не более чем на два символа.
+// Если похожих названий нет, возвращается false.
+func ClosestName(name string, candidates []string) (string, bool) {
+	target := []rune(normalizeName(name))
+	maxDistance := min(2, len(target)/3)
+
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		distance := editDistance(target, []rune(normalizeName(candidate)))
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance возвращает расстояние Левенштейна между строками
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// studentNameParts возвращает фамилию студента в виде для нестрогого сравнения и его инициалы:
+// «Иванов И.И.», «Иванов И. И.» и «Иванов Иван Иванович» дают одно и то же
+func studentNameParts(name string) (string, string) {
+	fields := strings.Fields(strings.NewReplacer(".", " ", "ё", "е", "Ё", "Е").Replace(name))
+	if len(fields) == 0 {
+		return "", ""
+	}
+	var initials []rune
+	for _, field := range fields[1:] {
+		initials = append(initials, []rune(strings.ToLower(field))[0])
+	}
+	return normalizeName(fields[0]), string(initials)
+}
+
+// similarStudentNames сообщает, что имена, вероятно, принадлежат одному студенту: инициалы
+// совпадают (или одни продолжают другие, как «И.» и «И.И.»), а фамилии отличаются не более
+// чем на одну букву
+func similarStudentNames(a, b string) bool {
+	surnameA, initialsA := studentNameParts(a)
+	surnameB, initialsB := studentNameParts(b)
+	if surnameA == "" || surnameB == "" {
+		return false
+	}
+	if !strings.HasPrefix(initialsA, initialsB) && !strings.HasPrefix(initialsB, initialsA) {
+		return false
+	}
+	maxDistance := 0
+	if len([]rune(surnameA)) >= 5 {
+		maxDistance = 1
+	}
+	return editDistance([]rune(surnameA), []rune(surnameB)) <= maxDistance
+}
+
+// DuplicateStudents находит группы записей, которые, вероятно, относятся к одному студенту:
+// одна фамилия с инициалами, записанная по-разному. Записи в группе идут в порядке списка.
+func DuplicateStudents(students []Student) [][]Student {
+	groupOf := make([]int, len(students))
+	for i := range groupOf {
+		groupOf[i] = -1
+	}
+
+	var groups [][]Student
+	for i, student := range students {
+		if groupOf[i] >= 0 {
+			continue
+		}
+		group := []Student{student}
+		for j := i + 1; j < len(students); j++ {
+			if groupOf[j] < 0 && students[j].Name != student.Name && similarStudentNames(student.Name, students[j].Name) {
+				groupOf[j] = len(groups)
+				group = append(group, students[j])
+			}
+		}
+		if len(group) > 1 {
+			groupOf[i] = len(groups)
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// MergeStudent дополняет запись keep данными из записи duplicate, которые в keep не заполнены
+func MergeStudent(keep, duplicate Student) Student {
+	for _, field := range []struct{ target, source *string }{
+		{&keep.Group, &duplicate.Group},
+		{&keep.Department, &duplicate.Department},
+		{&keep.Email, &duplicate.Email},
+		{&keep.Telegram, &duplicate.Telegram},
+		{&keep.CuratorEmail, &duplicate.CuratorEmail},
+	} {
+		if *field.target == "" {
+			*field.target = *field.source
+		}
+	}
+	if keep.Year == 0 {
+		keep.Year = duplicate.Year
+	}
+	return keep
+}
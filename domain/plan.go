@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Semester — учебный семестр с датами начала и окончания (включительно)
+type Semester struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Contains проверяет, попадает ли дата в семестр
+func (s Semester) Contains(date time.Time) bool {
+	day := date.In(location).Format("2006-01-02")
+	return day >= s.Start.In(location).Format("2006-01-02") && day <= s.End.In(location).Format("2006-01-02")
+}
+
+// Progress возвращает долю прошедшего семестра на момент now: от 0 до 1
+func (s Semester) Progress(now time.Time) float64 {
+	total := s.End.Sub(s.Start)
+	if total <= 0 || !now.Before(s.End) {
+		return 1
+	}
+	if now.Before(s.Start) {
+		return 0
+	}
+	return float64(now.Sub(s.Start)) / float64(total)
+}
+
+// PlanEntry — плановое количество часов индивидуальных занятий студента по дисциплине за семестр
+type PlanEntry struct {
+	Student    string
+	Discipline string
+	Semester   string
+	Hours      int
+}
+
+// DeliveredHours — фактические часы индивидуальных занятий студента по дисциплине за неделю
+type DeliveredHours struct {
+	Student    string
+	Discipline string
+	Hours      int
+}
+
+// CountDeliveredHours суммирует часы индивидуальных занятий по студентам и дисциплинам
+func CountDeliveredHours(lessons Schedule) []DeliveredHours {
+	type key struct{ student, discipline string }
+	hours := make(map[key]int)
+	for _, lesson := range lessons {
+		if lesson.Source == SourceGroup || lesson.Student == "" {
+			continue
+		}
+		hours[key{lesson.Student, lesson.Discipline}] += lesson.Time.Hours
+	}
+
+	result := make([]DeliveredHours, 0, len(hours))
+	for k, h := range hours {
+		result = append(result, DeliveredHours{Student: k.student, Discipline: k.discipline, Hours: h})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Student != result[j].Student {
+			return result[i].Student < result[j].Student
+		}
+		return result[i].Discipline < result[j].Discipline
+	})
+	return result
+}
+
+// PlanStatus — результат сравнения фактических часов с планом
+type PlanStatus string
+
+const (
+	PlanOnTrack PlanStatus = "on_track" // часы соответствуют плану на текущую дату
+	PlanUnder   PlanStatus = "under"    // часов меньше, чем положено к текущей дате
+	PlanOver    PlanStatus = "over"     // часов больше, чем положено по плану на семестр
+	PlanUnknown PlanStatus = "unknown"  // семестр плана не найден
+)
+
+// DisplayName возвращает название статуса для отображения пользователю
+func (s PlanStatus) DisplayName() string {
+	switch s {
+	case PlanOnTrack:
+		return "В норме"
+	case PlanUnder:
+		return "Недовыполнение"
+	case PlanOver:
+		return "Перевыполнение"
+	default:
+		return "Семестр не найден"
+	}
+}
+
+// PlanProgress — выполнение одной строки плана
+type PlanProgress struct {
+	PlanEntry
+	Expected int // сколько часов должно быть выдано к текущей дате
+	Actual   int // сколько часов выдано по сохранённым проверкам
+	Status   PlanStatus
+}
+
+// Difference возвращает разницу между фактическими и положенными к текущей дате часами
+func (p PlanProgress) Difference() int {
+	return p.Actual - p.Expected
+}
+
+// BuildPlanReport сопоставляет план с фактическими часами из истории проверок.
+// Фактические часы накапливаются по неделям, попадающим в семестр. К дате now положена
+// доля плана, пропорциональная прошедшей части семестра. Названия дисциплин сравниваются
+// без учёта регистра.
+func BuildPlanReport(semesters []Semester, plan []PlanEntry, records []CheckRecord, now time.Time) []PlanProgress {
+	semesterByName := make(map[string]Semester)
+	for _, semester := range semesters {
+		semesterByName[semester.Name] = semester
+	}
+
+	result := make([]PlanProgress, 0, len(plan))
+	for _, entry := range plan {
+		progress := PlanProgress{PlanEntry: entry, Status: PlanUnknown}
+		semester, ok := semesterByName[entry.Semester]
+		if !ok {
+			result = append(result, progress)
+			continue
+		}
+
+		for _, record := range records {
+			if !semester.Contains(record.Week.Start()) {
+				continue
+			}
+			for _, delivered := range record.Hours {
+				if delivered.Student == entry.Student && sameDiscipline(delivered.Discipline, entry.Discipline) {
+					progress.Actual += delivered.Hours
+				}
+			}
+		}
+
+		progress.Expected = int(float64(entry.Hours)*semester.Progress(now) + 0.5)
+		switch {
+		case progress.Actual > entry.Hours:
+			progress.Status = PlanOver
+		case progress.Actual < progress.Expected:
+			progress.Status = PlanUnder
+		default:
+			progress.Status = PlanOnTrack
+		}
+		result = append(result, progress)
+	}
+	return result
+}
+
+func sameDiscipline(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"log"
+)
+
+// Rule — правило проверки одного дня студента. Validator проверяет каждый день всеми правилами
+// реестра (см. Validator.Rules), поэтому новую проверку достаточно реализовать как Rule и
+// зарегистрировать, не меняя сам обход расписания.
+type Rule interface {
+	// Name возвращает название правила для журнала
+	Name() string
+	// Check проверяет занятия студента за один день и возвращает найденные нарушения
+	Check(student Student, dayLessons Schedule) []Violation
+}
+
+// WeekRule — правило проверки всей недели студента, например недельной нагрузки: такие нарушения
+// не видны ни в одном отдельном дне
+type WeekRule interface {
+	// Name возвращает название правила для журнала
+	Name() string
+	// CheckWeek проверяет занятия студента за неделю week и возвращает найденные нарушения
+	CheckWeek(student Student, week Week, weekLessons Schedule) []Violation
+}
+
+// OverloadRule — дневная нагрузка студента не больше порога его курса
+type OverloadRule struct {
+	Limits Limits
+}
+
+func (OverloadRule) Name() string { return string(ViolationOverload) }
+
+func (r OverloadRule) Check(student Student, dayLessons Schedule) []Violation {
+	if hours := dayLessons.Hours(); hours > r.Limits.MaxDailyHoursFor(student.Year) {
+		return []Violation{dayViolation(student, dayLessons, ViolationOverload, hours)}
+	}
+	return nil
+}
+
+// GapsRule — окон (пустых половинок пар между первым и последним занятием) не больше порога курса
+type GapsRule struct {
+	Limits Limits
+}
+
+func (GapsRule) Name() string { return string(ViolationGaps) }
+
+func (r GapsRule) Check(student Student, dayLessons Schedule) []Violation {
+	if gaps := dayLessons.Gaps(); gaps > r.Limits.MaxGapsFor(student.Year) {
+		return []Violation{dayViolation(student, dayLessons, ViolationGaps, gaps)}
+	}
+	return nil
+}
+
+// ConsecutiveRule — занятых половинок пар подряд, без свободной половинки между ними, не больше порога.
+// Деканат оценивает такие дни отдельно от общей нагрузки: шесть пар подряд тяжелее тех же часов с окнами.
+type ConsecutiveRule struct {
+	Limits Limits
+}
+
+func (ConsecutiveRule) Name() string { return string(ViolationConsecutive) }
+
+func (r ConsecutiveRule) Check(student Student, dayLessons Schedule) []Violation {
+	if r.Limits.MaxConsecutive <= 0 {
+		return nil
+	}
+	if run := dayLessons.LongestRun(); run > r.Limits.MaxConsecutive {
+		return []Violation{dayViolation(student, dayLessons, ViolationConsecutive, run)}
+	}
+	return nil
+}
+
+// ClashRule — индивидуальные занятия не ставятся на обязательные пары группы
+type ClashRule struct{}
+
+func (ClashRule) Name() string { return string(ViolationClash) }
+
+func (ClashRule) Check(student Student, dayLessons Schedule) []Violation {
+	if hours := clashHours(dayLessons); hours > 0 {
+		return []Violation{dayViolation(student, dayLessons, ViolationClash, hours)}
+	}
+	return nil
+}
+
+// clashHours возвращает часы индивидуальных занятий, поставленных на половинки пар,
+// занятые групповым расписанием. Пары "Индивидуальные занятия" в групповое расписание
+// не попадают, поэтому любая групповая пара считается обязательной.
+func clashHours(dayLessons Schedule) int {
+	var individual, group Schedule
+	for _, lesson := range dayLessons {
+		if lesson.Source == SourceGroup {
+			group = append(group, lesson)
+		} else {
+			individual = append(individual, lesson)
+		}
+	}
+	if len(individual) == 0 || len(group) == 0 {
+		return 0
+	}
+
+	groupSlots := group.SlotsOccupied()
+	hours := 0
+	for _, lesson := range individual {
+		for slot := range (Schedule{lesson}).SlotsOccupied() {
+			if groupSlots[slot] {
+				hours += lesson.Time.Hours
+				break
+			}
+		}
+	}
+	return hours
+}
+
+// OverlapRule — у студента нет двух разных занятий в одной половинке пары: например, занятий
+// из файлов двух преподавателей или двух групповых пар. Индивидуальное занятие на групповой паре
+// находит ClashRule, здесь такие пары не учитываются, чтобы не считать наложение дважды.
+type OverlapRule struct{}
+
+func (OverlapRule) Name() string { return string(ViolationOverlap) }
+
+func (OverlapRule) Check(student Student, dayLessons Schedule) []Violation {
+	if hours := overlapHours(dayLessons); hours > 0 {
+		return []Violation{dayViolation(student, dayLessons, ViolationOverlap, hours)}
+	}
+	return nil
+}
+
+// overlapHours возвращает часы занятий, которые занимают хотя бы одну половинку пары вместе
+// с другим занятием того же вида (оба индивидуальные или оба групповые). Записи с одинаковыми
+// дисциплиной и преподавателями — это одно занятие, полученное дважды, а не наложение.
+func overlapHours(dayLessons Schedule) int {
+	hours := 0
+	for i, lesson := range dayLessons {
+		busy := (Schedule{lesson}).SlotsOccupied()
+		for j, other := range dayLessons {
+			if i == j || (lesson.Source == SourceGroup) != (other.Source == SourceGroup) {
+				continue
+			}
+			if activityIdentity(lesson) == activityIdentity(other) {
+				continue
+			}
+			if overlaps(busy, other.Time) {
+				hours += lesson.Time.Hours
+				break
+			}
+		}
+	}
+	return hours
+}
+
+// WeeklyOverloadRule — недельная нагрузка студента не больше порога: ловит хроническую перегрузку,
+// при которой каждый день в отдельности укладывается в дневной порог
+type WeeklyOverloadRule struct {
+	Limits Limits
+}
+
+func (WeeklyOverloadRule) Name() string { return string(ViolationWeeklyOverload) }
+
+func (r WeeklyOverloadRule) CheckWeek(student Student, week Week, weekLessons Schedule) []Violation {
+	if r.Limits.MaxWeeklyHours <= 0 || len(weekLessons) == 0 {
+		return nil
+	}
+	hours := weekLessons.Hours()
+	if hours <= r.Limits.MaxWeeklyHours {
+		return nil
+	}
+	return []Violation{NewViolation(student.Name, student.Group, student.Year, week.Start(), ViolationWeeklyOverload, hours)}
+}
+
+// customRuleCheck — пользовательское правило из rules.yaml в реестре правил
+type customRuleCheck struct {
+	rule CustomRule
+}
+
+func (c customRuleCheck) Name() string { return c.rule.Name }
+
+func (c customRuleCheck) Check(student Student, dayLessons Schedule) []Violation {
+	violated, err := c.rule.Evaluate(dayLessons)
+	if err != nil {
+		log.Printf("Ошибка проверки: %v", err)
+		return nil
+	}
+	if !violated {
+		return nil
+	}
+	violation := dayViolation(student, dayLessons, ViolationCustom, dayLessons.Hours())
+	violation.Rule = c.rule.Name
+	return []Violation{violation}
+}
+
+// dayViolation создаёт нарушение студента за день занятий dayLessons
+func dayViolation(student Student, dayLessons Schedule, kind ViolationKind, hours int) Violation {
+	return NewViolation(student.Name, student.Group, student.Year, dayLessons[0].Time.Date, kind, hours)
+}
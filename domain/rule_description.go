@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"strings"
+)
+
+// RuleDescription — понятное описание правила проверки с его порогами. Печатается в отчете
+// сноской под нарушением, чтобы читатель видел, что именно превышено.
+type RuleDescription struct {
+	Kind  ViolationKind
+	Rule  string    // название пользовательского правила для ViolationCustom
+	Text  string    // описание на русском
+	Parts []Message // предложения описания с порогами, переводятся для отчета на другом языке
+}
+
+// Matches сообщает, что нарушение найдено этим правилом
+func (d RuleDescription) Matches(v Violation) bool {
+	return d.Kind == v.Kind && (d.Kind != ViolationCustom || d.Rule == v.Rule)
+}
+
+// TextIn возвращает описание правила на языке lang
+func (d RuleDescription) TextIn(lang Lang) string {
+	if len(d.Parts) == 0 {
+		return d.Text
+	}
+	sentences := make([]string, 0, len(d.Parts))
+	for _, part := range d.Parts {
+		sentences = append(sentences, part.In(lang))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describe составляет описание правила из предложений
+func describe(kind ViolationKind, rule string, parts ...Message) RuleDescription {
+	d := RuleDescription{Kind: kind, Rule: rule, Parts: parts}
+	d.Text = d.TextIn(LangRU)
+	return d
+}
+
+// Describe возвращает описания включённых стандартных правил с текущими порогами
+func (l Limits) Describe() []RuleDescription {
+	overload := []Message{Msg("Дневная нагрузка — сумма академических часов всех занятий студента за день: допускается не более %d ак. ч.", l.MaxDailyHours)}
+	gaps := []Message{Msg("Окна — свободные половинки пар между первым и последним занятием дня: допускается не более %d, для студентов 1-го курса — не более %d.", l.MaxGaps, l.MaxGapsFirstYear)}
+	for _, year := range l.years() {
+		if hours := l.Years[year].MaxDailyHours; hours > 0 {
+			overload = append(overload, Msg("Для %d-го курса допускается нагрузка не более %d ак. ч.", year, hours))
+		}
+		if limit := l.Years[year].MaxGaps; limit > 0 {
+			gaps = append(gaps, Msg("Для %d-го курса допускается окон не более %d.", year, limit))
+		}
+	}
+	all := []RuleDescription{
+		describe(ViolationOverload, "", overload...),
+		describe(ViolationGaps, "", gaps...),
+		describe(ViolationClash, "", Msg("Индивидуальное занятие не должно совпадать по времени с групповой парой группы студента; указаны часы наложения.")),
+		describe(ViolationOverlap, "", Msg("У студента не должно быть двух разных индивидуальных занятий или двух групповых пар в одной половинке пары; указаны часы совпадающих занятий.")),
+	}
+	if l.MaxConsecutive > 0 {
+		all = append(all, describe(ViolationConsecutive, "", Msg("Занятия без перерыва — занятые половинки пар подряд, без свободной половинки между ними: допускается не более %d подряд; указано число половинок пар.", l.MaxConsecutive)))
+	}
+	if l.MaxWeeklyHours > 0 {
+		all = append(all, describe(ViolationWeeklyOverload, "", Msg("Недельная нагрузка — сумма академических часов всех занятий студента за неделю: допускается не более %d ак. ч.", l.MaxWeeklyHours)))
+	}
+
+	var descriptions []RuleDescription
+	for _, d := range all {
+		if l.IsEnabled(d.Kind) {
+			descriptions = append(descriptions, d)
+		}
+	}
+	return descriptions
+}
+
+// Describe возвращает описание пользовательского правила: его условие из rules.yaml
+func (r CustomRule) Describe() RuleDescription {
+	return describe(ViolationCustom, r.Name,
+		Msg("Правило «%s» из rules.yaml нарушается, если для дня студента выполняется условие: %s. Указаны часы за день.", r.Name, r.Expression))
+}
+
+// DescribeRules возвращает описания всех правил, которыми проверяется расписание
+func (v *Validator) DescribeRules() []RuleDescription {
+	descriptions := v.limits.Describe()
+	for _, rule := range v.customRules {
+		descriptions = append(descriptions, rule.Describe())
+	}
+	return descriptions
+}
@@ -0,0 +1,560 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Небольшой язык выражений для пользовательских правил проверки дня студента.
+// Пример: count(lessons where discipline == "Вокал") > 1 && hours(lessons) >= 8
+//
+// Поддерживаются:
+//   - функции count(lessons [where условие]) и hours(lessons [where условие]);
+//   - поля занятия внутри where: discipline, teacher, cabinet, group, student,
+//     source, number, half, hours;
+//   - сравнения ==, !=, >, >=, <, <=; логические &&, ||, ! (или and, or, not);
+//   - числа, строки в двойных кавычках, true/false и скобки.
+
+// CustomRule — пользовательское правило, заданное выражением над занятиями дня.
+// Правило нарушено, если выражение истинно.
+type CustomRule struct {
+	Name       string // название правила, отображается в отчете
+	Expression string // исходный текст выражения
+	expr       ruleNode
+}
+
+// CompileRule разбирает выражение правила и возвращает готовое к проверке правило. Ошибки типов —
+// нелогическое выражение, сравнение строки с числом, поле занятия вне where — обнаруживаются
+// здесь же, а не при проверке каждого дня.
+func CompileRule(name, expression string) (CustomRule, error) {
+	tokens, err := tokenizeRule(expression)
+	if err != nil {
+		return CustomRule{}, fmt.Errorf("правило %q: %w", name, err)
+	}
+	p := &ruleParser{tokens: tokens}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return CustomRule{}, fmt.Errorf("правило %q: %w", name, err)
+	}
+	if !p.done() {
+		return CustomRule{}, fmt.Errorf("правило %q: лишний текст после выражения: %q", name, p.peek().text)
+	}
+	typ, err := checkRuleTypes(expr, false)
+	if err != nil {
+		return CustomRule{}, fmt.Errorf("правило %q: %w", name, err)
+	}
+	if typ != ruleBool {
+		return CustomRule{}, fmt.Errorf("правило %q: выражение должно быть логическим, получено: %s", name, typ)
+	}
+	return CustomRule{Name: name, Expression: expression, expr: expr}, nil
+}
+
+// Evaluate проверяет правило на занятиях одного дня и возвращает true, если правило нарушено
+func (r CustomRule) Evaluate(day Schedule) (bool, error) {
+	if r.expr == nil {
+		return false, fmt.Errorf("правило %q не скомпилировано", r.Name)
+	}
+	value, err := r.expr.eval(ruleEnv{day: day})
+	if err != nil {
+		return false, fmt.Errorf("правило %q: %w", r.Name, err)
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("правило %q: выражение должно быть логическим, получено %v", r.Name, value)
+	}
+	return result, nil
+}
+
+// ruleEnv — контекст вычисления: занятия дня и текущее занятие внутри where
+type ruleEnv struct {
+	day    Schedule
+	lesson *Lesson
+}
+
+type ruleNode interface {
+	eval(env ruleEnv) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(ruleEnv) (any, error) { return n.value, nil }
+
+type fieldNode struct{ name string }
+
+// lessonFields — поля занятия, доступные внутри where
+var lessonFields = map[string]func(l *Lesson) any{
+	"discipline": func(l *Lesson) any { return l.Discipline },
+	"teacher":    func(l *Lesson) any { return l.TeacherNames() },
+	"cabinet":    func(l *Lesson) any { return l.Cabinet },
+	"group":      func(l *Lesson) any { return l.Group },
+	"student":    func(l *Lesson) any { return l.Student },
+	"source":     func(l *Lesson) any { return string(l.Source) },
+	"number":     func(l *Lesson) any { return float64(l.Time.Number) },
+	"half":       func(l *Lesson) any { return float64(l.Time.PairHalf) },
+	"hours":      func(l *Lesson) any { return float64(l.Time.Hours) },
+}
+
+func (n fieldNode) eval(env ruleEnv) (any, error) {
+	if env.lesson == nil {
+		return nil, fmt.Errorf("поле %s доступно только внутри where", n.name)
+	}
+	return lessonFields[n.name](env.lesson), nil
+}
+
+type notNode struct{ operand ruleNode }
+
+func (n notNode) eval(env ruleEnv) (any, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("оператор ! применим только к логическим значениям")
+	}
+	return !b, nil
+}
+
+type logicNode struct {
+	op          string // "&&" или "||"
+	left, right ruleNode
+}
+
+func (n logicNode) eval(env ruleEnv) (any, error) {
+	left, err := evalBool(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !left {
+		return false, nil
+	}
+	if n.op == "||" && left {
+		return true, nil
+	}
+	return evalBool(n.right, env)
+}
+
+func evalBool(node ruleNode, env ruleEnv) (bool, error) {
+	value, err := node.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("ожидалось логическое значение, получено %v", value)
+	}
+	return b, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right ruleNode
+}
+
+func (n compareNode) eval(env ruleEnv) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("нельзя сравнить число %v с %v", l, right)
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("нельзя сравнить строку %q с %v", l, right)
+		}
+		cmp = strings.Compare(l, r)
+	case bool:
+		r, ok := right.(bool)
+		if !ok || (n.op != "==" && n.op != "!=") {
+			return nil, fmt.Errorf("логические значения можно сравнивать только через == и !=")
+		}
+		if l != r {
+			cmp = 1
+		}
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	default:
+		return cmp <= 0, nil
+	}
+}
+
+// aggregateNode вычисляет count(...) или hours(...) по занятиям дня, удовлетворяющим where
+type aggregateNode struct {
+	fn    string
+	where ruleNode // может быть nil
+}
+
+func (n aggregateNode) eval(env ruleEnv) (any, error) {
+	total := 0.0
+	for i := range env.day {
+		lesson := &env.day[i]
+		if n.where != nil {
+			match, err := evalBool(n.where, ruleEnv{day: env.day, lesson: lesson})
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
+		if n.fn == "count" {
+			total++
+		} else {
+			total += float64(lesson.Time.Hours)
+		}
+	}
+	return total, nil
+}
+
+// ruleType — тип значения выражения правила
+type ruleType string
+
+const (
+	ruleBool   ruleType = "логическое значение"
+	ruleNumber ruleType = "число"
+	ruleString ruleType = "строка"
+)
+
+// typeOf возвращает тип значения, которое вычисляет выражение
+func typeOf(value any) ruleType {
+	switch value.(type) {
+	case bool:
+		return ruleBool
+	case float64:
+		return ruleNumber
+	default:
+		return ruleString
+	}
+}
+
+// checkRuleTypes проверяет типы выражения без вычисления и возвращает тип результата.
+// inWhere — выражение стоит внутри where, где доступны поля занятия.
+func checkRuleTypes(node ruleNode, inWhere bool) (ruleType, error) {
+	switch n := node.(type) {
+	case literalNode:
+		return typeOf(n.value), nil
+	case fieldNode:
+		if !inWhere {
+			return "", fmt.Errorf("поле %s доступно только внутри where", n.name)
+		}
+		return typeOf(lessonFields[n.name](&Lesson{})), nil
+	case notNode:
+		typ, err := checkRuleTypes(n.operand, inWhere)
+		if err != nil {
+			return "", err
+		}
+		if typ != ruleBool {
+			return "", fmt.Errorf("оператор ! применим только к логическим значениям, получено: %s", typ)
+		}
+		return ruleBool, nil
+	case logicNode:
+		for _, operand := range []ruleNode{n.left, n.right} {
+			typ, err := checkRuleTypes(operand, inWhere)
+			if err != nil {
+				return "", err
+			}
+			if typ != ruleBool {
+				return "", fmt.Errorf("оператор %s применим только к логическим значениям, получено: %s", n.op, typ)
+			}
+		}
+		return ruleBool, nil
+	case compareNode:
+		left, err := checkRuleTypes(n.left, inWhere)
+		if err != nil {
+			return "", err
+		}
+		right, err := checkRuleTypes(n.right, inWhere)
+		if err != nil {
+			return "", err
+		}
+		if left != right {
+			return "", fmt.Errorf("нельзя сравнить значения разных типов: %s и %s", left, right)
+		}
+		if left == ruleBool && n.op != "==" && n.op != "!=" {
+			return "", fmt.Errorf("логические значения можно сравнивать только через == и !=")
+		}
+		return ruleBool, nil
+	case aggregateNode:
+		if n.where != nil {
+			typ, err := checkRuleTypes(n.where, true)
+			if err != nil {
+				return "", err
+			}
+			if typ != ruleBool {
+				return "", fmt.Errorf("условие where должно быть логическим, получено: %s", typ)
+			}
+		}
+		return ruleNumber, nil
+	default:
+		return "", fmt.Errorf("неизвестный узел выражения %T", node)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenOp
+	tokenEOF
+)
+
+type ruleToken struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizeRule разбивает выражение на лексемы
+func tokenizeRule(input string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokenNumber, string(runes[start:i])})
+		case r == '"':
+			i++
+			start := i
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("незакрытая строка")
+			}
+			tokens = append(tokens, ruleToken{tokenString, string(runes[start:i])})
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokenIdent, string(runes[start:i])})
+		default:
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				switch two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					tokens = append(tokens, ruleToken{tokenOp, two})
+					i += 2
+					continue
+				}
+			}
+			switch r {
+			case '>', '<', '!', '(', ')':
+				tokens = append(tokens, ruleToken{tokenOp, string(r)})
+				i++
+			default:
+				return nil, fmt.Errorf("неожиданный символ %q", r)
+			}
+		}
+	}
+	return append(tokens, ruleToken{kind: tokenEOF}), nil
+}
+
+// ruleParser — парсер методом рекурсивного спуска
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+func (p *ruleParser) peek() ruleToken { return p.tokens[p.pos] }
+
+func (p *ruleParser) next() ruleToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *ruleParser) done() bool { return p.peek().kind == tokenEOF }
+
+// accept съедает лексему, если она совпадает с одним из вариантов
+func (p *ruleParser) accept(texts ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOp && t.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *ruleParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return fmt.Errorf("ожидалось %q, найдено %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *ruleParser) parseExpr() (ruleNode, error) {
+	return p.parseOr()
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *ruleParser) parseCompare() (ruleNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", ">=", "<=", ">", "<")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *ruleParser) parsePrimary() (ruleNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("неверное число %q", t.text)
+		}
+		return literalNode{value: value}, nil
+	case tokenString:
+		return literalNode{value: t.text}, nil
+	case tokenOp:
+		if t.text != "(" {
+			return nil, fmt.Errorf("неожиданный оператор %q", t.text)
+		}
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "count", "hours":
+			if p.peek().text == "(" {
+				return p.parseAggregate(t.text)
+			}
+		}
+		if _, ok := lessonFields[t.text]; ok {
+			return fieldNode{name: t.text}, nil
+		}
+		return nil, fmt.Errorf("неизвестный идентификатор %q", t.text)
+	default:
+		return nil, fmt.Errorf("неожиданный конец выражения")
+	}
+}
+
+// parseAggregate разбирает count(lessons [where условие]) или hours(...)
+func (p *ruleParser) parseAggregate(fn string) (ruleNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if err := p.expect("lessons"); err != nil {
+		return nil, err
+	}
+	node := aggregateNode{fn: fn}
+	if _, ok := p.accept("where"); ok {
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		node.where = where
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
@@ -0,0 +1,98 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/testsupport"
+)
+
+func TestCustomRuleEvaluate(t *testing.T) {
+	// День: две пары вокала по 2 часа и половина пары фортепиано на 1 час
+	day := domain.Schedule{
+		testsupport.NewLesson().Pair(1).Discipline("Вокал").Teacher("Соколова Е.Н.").Build(),
+		testsupport.NewLesson().Pair(2).Discipline("Вокал").Teacher("Петров А.В.").Build(),
+		testsupport.NewLesson().Pair(3).Half(1).Discipline("Фортепиано").Teacher("Петров А.В.").Build(),
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "количество занятий", expression: "count(lessons) == 3", want: true},
+		{name: "сумма часов", expression: "hours(lessons) >= 6", want: false},
+		{name: "&& связывает сильнее ||", expression: "true || false && false", want: true},
+		{name: "скобки меняют порядок", expression: "(true || false) && false", want: false},
+		{name: "! связывает сильнее &&", expression: "!false && false", want: false},
+		{name: "словесные операторы", expression: "not (false and false) or false", want: true},
+		{name: "вложенные скобки", expression: "((count(lessons) > 2)) && !(hours(lessons) < 5)", want: true},
+		{name: "where по дисциплине", expression: `count(lessons where discipline == "Вокал") == 2`, want: true},
+		{name: "where без совпадений", expression: `count(lessons where discipline == "Сольфеджио") > 0`, want: false},
+		{name: "where по преподавателю", expression: `hours(lessons where teacher == "Петров А.В.") == 3`, want: true},
+		{name: "where с полем hours и логикой", expression: "hours(lessons where number >= 2 && hours < 2) == 1", want: true},
+		{name: "сравнение логических значений", expression: "(count(lessons) > 1) == true", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := domain.CompileRule("правило", tt.expression)
+			if err != nil {
+				t.Fatalf("CompileRule(%q): %v", tt.expression, err)
+			}
+			got, err := rule.Evaluate(day)
+			if err != nil {
+				t.Fatalf("Evaluate(%q): %v", tt.expression, err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) = %v, ожидалось %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomRuleEvaluateUncompiled(t *testing.T) {
+	day := domain.Schedule{testsupport.NewLesson().Discipline("Вокал").Build()}
+	rule := domain.CustomRule{Name: "правило", Expression: "count(lessons) > 0"}
+	if _, err := rule.Evaluate(day); err == nil || !strings.Contains(err.Error(), "не скомпилировано") {
+		t.Errorf("Evaluate без CompileRule: ошибка %v, ожидалась «не скомпилировано»", err)
+	}
+}
+
+func TestCompileRuleErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "незакрытая строка", expression: `count(lessons where discipline == "Вокал) > 0`, wantErr: "незакрытая строка"},
+		{name: "неизвестный идентификатор", expression: "pairs > 1", wantErr: `неизвестный идентификатор "pairs"`},
+		{name: "неизвестное поле в where", expression: "count(lessons where room == 1) > 0", wantErr: `неизвестный идентификатор "room"`},
+		{name: "лишняя скобка", expression: "count(lessons) > 1)", wantErr: "лишний текст после выражения"},
+		{name: "лишнее выражение", expression: "true false", wantErr: "лишний текст после выражения"},
+		{name: "незакрытая скобка", expression: "(true || false", wantErr: `ожидалось ")"`},
+		{name: "агрегат не по занятиям", expression: "count(students) > 0", wantErr: `ожидалось "lessons"`},
+		{name: "оборванное выражение", expression: "count(lessons) >", wantErr: "неожиданный конец выражения"},
+		{name: "пустое выражение", expression: "", wantErr: "неожиданный конец выражения"},
+		{name: "неизвестный символ", expression: "count(lessons) = 1", wantErr: "неожиданный символ"},
+		{name: "оператор вместо значения", expression: "&& true", wantErr: "неожиданный оператор"},
+		// Ошибки типов обнаруживаются при компиляции, а не при проверке каждого дня
+		{name: "число со строкой", expression: `count(lessons) == "1"`, wantErr: "нельзя сравнить значения разных типов: число и строка"},
+		{name: "строка с числом", expression: `count(lessons where discipline > 1) > 0`, wantErr: "нельзя сравнить значения разных типов: строка и число"},
+		{name: "упорядочивание логических", expression: "true > false", wantErr: "только через == и !="},
+		{name: "отрицание числа", expression: "!count(lessons)", wantErr: "оператор ! применим только к логическим значениям"},
+		{name: "число в &&", expression: "count(lessons) && true", wantErr: "оператор && применим только к логическим значениям"},
+		{name: "нелогический результат", expression: "hours(lessons)", wantErr: "выражение должно быть логическим"},
+		{name: "поле вне where", expression: `discipline == "Вокал"`, wantErr: "только внутри where"},
+		{name: "поле вне where в правой части", expression: "count(lessons) > number", wantErr: "только внутри where"},
+		{name: "нелогическое условие where", expression: "count(lessons where number) > 0", wantErr: "условие where должно быть логическим"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := domain.CompileRule("правило", tt.expression)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CompileRule(%q): ошибка %v, ожидалась содержащая %q", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Schedule — набор занятий с методами выборки и группировки.
+// Собирает в одном месте логику, которая раньше дублировалась в валидаторе и отчете.
+type Schedule []Lesson
+
+// ForStudent возвращает расписание студента: его индивидуальные занятия и групповые пары его группы
+func (s Schedule) ForStudent(student Student) Schedule {
+	var result Schedule
+	for _, lesson := range s {
+		if lesson.Student != "" && lesson.Student == student.Name {
+			result = append(result, lesson)
+			continue
+		}
+		if lesson.Student == "" && lesson.Group == student.Group {
+			result = append(result, lesson)
+		}
+	}
+	return result
+}
+
+// ForTeacher возвращает занятия, которые ведет указанный преподаватель
+func (s Schedule) ForTeacher(name string) Schedule {
+	var result Schedule
+	for _, lesson := range s {
+		if lesson.HasTeacher(name) {
+			result = append(result, lesson)
+		}
+	}
+	return result
+}
+
+// ForCabinet возвращает занятия в указанном кабинете
+func (s Schedule) ForCabinet(cabinet string) Schedule {
+	var result Schedule
+	for _, lesson := range s {
+		if strings.TrimSpace(lesson.Cabinet) == cabinet {
+			result = append(result, lesson)
+		}
+	}
+	return result
+}
+
+// ForWeek возвращает занятия, даты которых попадают в неделю week
+func (s Schedule) ForWeek(week Week) Schedule {
+	var result Schedule
+	for _, lesson := range s {
+		if week.Contains(lesson.Time.Date) {
+			result = append(result, lesson)
+		}
+	}
+	return result
+}
+
+// Cabinets возвращает кабинеты, в которых есть занятия, по алфавиту
+func (s Schedule) Cabinets() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, lesson := range s {
+		cabinet := strings.TrimSpace(lesson.Cabinet)
+		if cabinet != "" && !seen[cabinet] {
+			seen[cabinet] = true
+			result = append(result, cabinet)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ByDay группирует занятия по дате в формате "2006-01-02"
+func (s Schedule) ByDay() map[string]Schedule {
+	result := make(map[string]Schedule)
+	for _, lesson := range s {
+		date := lesson.Time.DateString()
+		result[date] = append(result[date], lesson)
+	}
+	return result
+}
+
+// Hours возвращает суммарное количество академических часов
+func (s Schedule) Hours() int {
+	total := 0
+	for _, lesson := range s {
+		total += lesson.Time.Hours
+	}
+	return total
+}
+
+// DailyLoad возвращает среднюю дневную нагрузку студентов в академических часах:
+// сумму часов, делённую на количество учебных дней всех студентов. Дни без занятий не учитываются.
+func (s Schedule) DailyLoad(students []Student) float64 {
+	hours, days := 0, 0
+	for _, student := range students {
+		for _, day := range s.ForStudent(student).MergeSubgroups().ByDay() {
+			hours += day.Hours()
+			days++
+		}
+	}
+	if days == 0 {
+		return 0
+	}
+	return float64(hours) / float64(days)
+}
+
+// DisciplineHours — часы по дисциплине за неделю: индивидуальные и групповые занятия
+type DisciplineHours struct {
+	Discipline string
+	Individual int
+	Group      int
+}
+
+// Total возвращает все часы по дисциплине
+func (h DisciplineHours) Total() int {
+	return h.Individual + h.Group
+}
+
+// DisciplineHours суммирует часы расписания студента по дисциплинам, начиная с наибольших.
+// Подгруппы одной пары учитываются один раз, как при проверке нагрузки: по дисциплине первой из них.
+func (s Schedule) DisciplineHours() []DisciplineHours {
+	type slotKey struct {
+		group    string
+		date     string
+		number   int
+		pairHalf int
+	}
+	subgroupSlots := make(map[slotKey]bool)
+	index := make(map[string]int)
+	var result []DisciplineHours
+	for _, lesson := range s {
+		if lesson.Subgroup != "" {
+			key := slotKey{lesson.Group, lesson.Time.DateString(), lesson.Time.Number, lesson.Time.PairHalf}
+			if subgroupSlots[key] {
+				continue
+			}
+			subgroupSlots[key] = true
+		}
+		i, ok := index[lesson.Discipline]
+		if !ok {
+			i = len(result)
+			index[lesson.Discipline] = i
+			result = append(result, DisciplineHours{Discipline: lesson.Discipline})
+		}
+		if lesson.Student != "" {
+			result[i].Individual += lesson.Time.Hours
+		} else {
+			result[i].Group += lesson.Time.Hours
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Total() != result[j].Total() {
+			return result[i].Total() > result[j].Total()
+		}
+		return result[i].Discipline < result[j].Discipline
+	})
+	return result
+}
+
+// SlotsOccupied возвращает занятые половинки пар. Половинка нумеруется как
+// (Number-1)*2 + (PairHalf-1); пара с PairHalf == 0 занимает обе половинки.
+func (s Schedule) SlotsOccupied() map[int]bool {
+	occupied := make(map[int]bool)
+	for _, lesson := range s {
+		first := (lesson.Time.Number - 1) * 2
+		if lesson.Time.PairHalf == 0 {
+			occupied[first] = true
+			occupied[first+1] = true
+			continue
+		}
+		occupied[first+lesson.Time.PairHalf-1] = true
+	}
+	return occupied
+}
+
+// Gaps считает пустые половинки пар между первой и последней занятой половинкой дня
+func (s Schedule) Gaps() int {
+	occupiedSlots := s.SlotsOccupied()
+	if len(occupiedSlots) == 0 {
+		return 0
+	}
+
+	minSlot, maxSlot := -1, -1
+	for slot := range occupiedSlots {
+		if minSlot == -1 || slot < minSlot {
+			minSlot = slot
+		}
+		if slot > maxSlot {
+			maxSlot = slot
+		}
+	}
+
+	totalSlots := maxSlot - minSlot + 1
+	return totalSlots - len(occupiedSlots)
+}
+
+// LongestRun возвращает самую длинную серию занятых половинок пар подряд, без свободной половинки между ними
+func (s Schedule) LongestRun() int {
+	occupiedSlots := s.SlotsOccupied()
+	longest := 0
+	for slot := range occupiedSlots {
+		if occupiedSlots[slot-1] {
+			continue // серия считается от своей первой половинки
+		}
+		run := 1
+		for occupiedSlots[slot+run] {
+			run++
+		}
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+// MergeSubgroups объединяет занятия подгрупп одной группы в одной паре в одно занятие:
+// студент ходит только в одну подгруппу, поэтому пара должна учитываться один раз.
+// Дисциплины и кабинеты подгрупп перечисляются через " / ", преподаватели объединяются.
+func (s Schedule) MergeSubgroups() Schedule {
+	type slotKey struct {
+		group  string
+		date   string
+		number int
+	}
+
+	index := make(map[slotKey]int)
+	var result Schedule
+	for _, lesson := range s {
+		if lesson.Subgroup == "" {
+			result = append(result, lesson)
+			continue
+		}
+		key := slotKey{group: lesson.Group, date: lesson.Time.DateString(), number: lesson.Time.Number}
+		i, exists := index[key]
+		if !exists {
+			index[key] = len(result)
+			result = append(result, lesson)
+			continue
+		}
+		merged := &result[i]
+		merged.Discipline += " / " + lesson.Discipline
+		merged.Cabinet += " / " + lesson.Cabinet
+		merged.Teachers = MergeTeachers(merged.Teachers, lesson.Teachers)
+		merged.Subgroup += "," + lesson.Subgroup
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SearchKey приводит строку к виду для поиска: нижний регистр, без диакритических знаков
+// и лишних пробелов. Поэтому «Ёлкин», «елкин» и «ЕЛКИН» совпадают, как и «й» с «и».
+func SearchKey(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.Join(strings.Fields(s), " ")) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return norm.NFC.String(b.String())
+}
+
+// SearchRank оценивает, насколько text подходит под запрос, уже приведённый SearchKey:
+// 0 — совпадает целиком, 1 — с запроса начинается одно из слов, 2 — запрос входит в середину
+// слова. Если text не содержит запроса, возвращается false.
+func SearchRank(text, query string) (int, bool) {
+	key := SearchKey(text)
+	switch {
+	case query == "" || !strings.Contains(key, query):
+		return 0, false
+	case key == query:
+		return 0, true
+	case strings.HasPrefix(key, query) || strings.Contains(key, " "+query):
+		return 1, true
+	default:
+		return 2, true
+	}
+}
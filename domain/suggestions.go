@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	// MaxDailyHours — допустимая дневная нагрузка студента в академических часах
+	MaxDailyHours = 10
+	// maxAlternatives ограничивает количество предлагаемых вариантов для одного занятия
+	maxAlternatives = 5
+)
+
+// SlotSuggestion содержит свободные слоты, на которые можно перенести занятие
+type SlotSuggestion struct {
+	Lesson       Lesson
+	Alternatives []LessonTime
+}
+
+// SlotString возвращает описание слота для отчета, например "вторник 2024-09-03, пара 3 (1-я половина)"
+func (t LessonTime) SlotString() string {
+	return t.SlotStringIn(LangRU)
+}
+
+// SlotStringIn возвращает описание слота для отчета на языке lang
+func (t LessonTime) SlotStringIn(lang Lang) string {
+	s := lang.Tf("%s %s, пара %d", lang.T(t.DayName()), t.DateString(), t.Number)
+	if t.PairHalf > 0 {
+		s += lang.Tf(" (%d-я половина)", t.PairHalf)
+	}
+	return s
+}
+
+// SuggestSlots подбирает для индивидуальных занятий дня с нарушением свободные слоты на той же неделе,
+// в которые свободны студент, преподаватель и кабинет и дневная нагрузка студента не превысит
+// MaxDailyHours. Групповые пары не переносятся. Для превышения нагрузки день нарушения пропускается:
+// перенос внутри дня нагрузку не уменьшает. pairs — количество пар в учебном дне по расписанию звонков.
+func SuggestSlots(all Schedule, v Violation, pairs int) []SlotSuggestion {
+	// Перенос внутри недели не уменьшает недельную нагрузку
+	if v.Kind == ViolationWeeklyOverload {
+		return nil
+	}
+	student := Student{Name: v.StudentName, Group: v.Group, Year: v.Year}
+	studentSchedule := all.ForStudent(student).MergeSubgroups()
+	byDay := studentSchedule.ByDay()
+	violationDate := v.Date.In(location).Format("2006-01-02")
+	week := WeekOf(v.Date)
+
+	var suggestions []SlotSuggestion
+	for _, lesson := range byDay[violationDate] {
+		if lesson.Source == SourceGroup {
+			continue
+		}
+
+		suggestion := SlotSuggestion{Lesson: lesson}
+		for d := 0; d < 6 && len(suggestion.Alternatives) < maxAlternatives; d++ {
+			date := week.Start().AddDate(0, 0, d)
+			dateString := date.Format("2006-01-02")
+			if v.Kind == ViolationOverload && dateString == violationDate {
+				continue
+			}
+
+			studentDay := byDay[dateString].without(lesson.ID)
+			if studentDay.Hours()+lesson.Time.Hours > MaxDailyHours {
+				continue
+			}
+			busy := studentDay.SlotsOccupied()
+			for slot := range all.busyFor(lesson, dateString).SlotsOccupied() {
+				busy[slot] = true
+			}
+
+			for _, candidate := range candidateTimes(lesson.Time, date, pairs) {
+				if candidate.Number == lesson.Time.Number && candidate.PairHalf == lesson.Time.PairHalf && dateString == lesson.Time.DateString() {
+					continue
+				}
+				if overlaps(busy, candidate) {
+					continue
+				}
+				suggestion.Alternatives = append(suggestion.Alternatives, candidate)
+				if len(suggestion.Alternatives) >= maxAlternatives {
+					break
+				}
+			}
+		}
+
+		if len(suggestion.Alternatives) > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
+// busyFor возвращает занятия указанного дня, в которые заняты преподаватели или кабинет урока
+func (s Schedule) busyFor(lesson Lesson, date string) Schedule {
+	var result Schedule
+	for _, other := range s {
+		if other.ID == lesson.ID || other.Time.DateString() != date {
+			continue
+		}
+		if lesson.Cabinet != "" && other.Cabinet == lesson.Cabinet {
+			result = append(result, other)
+			continue
+		}
+		for _, teacher := range lesson.Teachers {
+			if other.HasTeacher(teacher.Name) {
+				result = append(result, other)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// without возвращает расписание без занятия с указанным идентификатором
+func (s Schedule) without(id string) Schedule {
+	var result Schedule
+	for _, lesson := range s {
+		if lesson.ID != id {
+			result = append(result, lesson)
+		}
+	}
+	return result
+}
+
+// candidateTimes перечисляет слоты дня из pairs пар той же длительности, что и исходное занятие
+func candidateTimes(original LessonTime, date time.Time, pairs int) []LessonTime {
+	var result []LessonTime
+	for number := 1; number <= pairs; number++ {
+		if original.PairHalf == 0 {
+			result = append(result, LessonTime{Date: date, Number: number, Hours: original.Hours})
+			continue
+		}
+		for half := 1; half <= 2; half++ {
+			result = append(result, LessonTime{Date: date, Number: number, PairHalf: half, Hours: original.Hours})
+		}
+	}
+	return result
+}
+
+// overlaps проверяет, занята ли хотя бы одна половинка пары слота
+func overlaps(busy map[int]bool, t LessonTime) bool {
+	for slot := range (Schedule{{Time: t}}).SlotsOccupied() {
+		if busy[slot] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// TallyRow — часы индивидуальных занятий преподавателя по одной дисциплине с одним студентом
+type TallyRow struct {
+	Discipline string
+	Student    string
+	Group      string
+	Hours      int
+}
+
+// TeacherTally — табель преподавателя за месяц
+type TeacherTally struct {
+	Teacher string
+	Rows    []TallyRow // строки, отсортированные по дисциплине и студенту
+	Total   int        // всего часов за месяц
+}
+
+// MonthlyTally — табель индивидуальных занятий всех преподавателей за месяц
+type MonthlyTally struct {
+	Month    time.Time // первое число месяца в часовом поясе расписания
+	Weeks    []Week    // недели, пересекающиеся с месяцем
+	Teachers []TeacherTally
+	Total    int
+}
+
+// monthNames — названия месяцев в именительном падеже
+var monthNames = []string{
+	"январь", "февраль", "март", "апрель", "май", "июнь",
+	"июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь",
+}
+
+// MonthName возвращает название месяца табеля и год, например "сентябрь 2024"
+func (t MonthlyTally) MonthName() string {
+	return t.MonthNameIn(LangRU)
+}
+
+// MonthNameIn возвращает название месяца табеля и год на языке lang
+func (t MonthlyTally) MonthNameIn(lang Lang) string {
+	return lang.T(monthNames[t.Month.Month()-1]) + " " + t.Month.Format("2006")
+}
+
+// MonthStart возвращает первое число месяца, которому принадлежит дата
+func MonthStart(date time.Time) time.Time {
+	date = date.In(location)
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, location)
+}
+
+// WeeksOfMonth возвращает все недели, хотя бы один день которых попадает в месяц
+func WeeksOfMonth(month time.Time) []Week {
+	start := MonthStart(month)
+	end := start.AddDate(0, 1, 0)
+	var weeks []Week
+	for week := WeekOf(start); week.Start().Before(end); week = week.Next() {
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// BuildMonthlyTally считает часы индивидуальных занятий за месяц по преподавателям,
+// дисциплинам и студентам. Занятие с несколькими преподавателями учитывается у каждого.
+func BuildMonthlyTally(lessons Schedule, month time.Time) MonthlyTally {
+	start := MonthStart(month)
+	tally := MonthlyTally{Month: start, Weeks: WeeksOfMonth(start)}
+
+	type rowKey struct {
+		discipline string
+		student    string
+		group      string
+	}
+	hours := make(map[string]map[rowKey]int)
+
+	for _, lesson := range lessons {
+		if lesson.Source == SourceGroup || MonthStart(lesson.Time.Date) != start {
+			continue
+		}
+		for _, teacher := range lesson.Teachers {
+			if hours[teacher.Name] == nil {
+				hours[teacher.Name] = make(map[rowKey]int)
+			}
+			hours[teacher.Name][rowKey{lesson.Discipline, lesson.Student, lesson.Group}] += lesson.Time.Hours
+		}
+	}
+
+	for teacher, rows := range hours {
+		teacherTally := TeacherTally{Teacher: teacher}
+		for key, h := range rows {
+			teacherTally.Rows = append(teacherTally.Rows, TallyRow{
+				Discipline: key.discipline,
+				Student:    key.student,
+				Group:      key.group,
+				Hours:      h,
+			})
+			teacherTally.Total += h
+		}
+		sort.Slice(teacherTally.Rows, func(i, j int) bool {
+			a, b := teacherTally.Rows[i], teacherTally.Rows[j]
+			if a.Discipline != b.Discipline {
+				return a.Discipline < b.Discipline
+			}
+			return a.Student < b.Student
+		})
+		tally.Teachers = append(tally.Teachers, teacherTally)
+		tally.Total += teacherTally.Total
+	}
+	sort.Slice(tally.Teachers, func(i, j int) bool {
+		return tally.Teachers[i].Teacher < tally.Teachers[j].Teacher
+	})
+	return tally
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Teacher описывает преподавателя
+type Teacher struct {
+	Name    string   // каноническое имя в формате "Фамилия И.О."
+	Aliases []string // альтернативные написания имени (например, с сайта вуза)
+	MaxLoad int      // максимальная недельная нагрузка в академических часах, 0 — без ограничения
+
+	// Individual — преподаватель ведёт индивидуальные занятия: его файл расписания ожидается
+	// каждую неделю, и проверка сообщает, если занятий преподавателя на неделе нет
+	Individual bool
+	Email      string // e-mail для напоминаний о файле расписания
+	Telegram   string // chat_id в Telegram для напоминаний о файле расписания
+	// AccessKey — ключ страницы преподавателя, на которой он видит только свои занятия
+	// и нарушения с их участием; пусто — доступа нет
+	AccessKey string
+}
+
+// HasContacts сообщает, что у преподавателя есть хотя бы один контакт для напоминаний
+func (t Teacher) HasContacts() bool {
+	return t.Email != "" || t.Telegram != ""
+}
+
+// NewTeacher создает преподавателя с указанным каноническим именем
+func NewTeacher(name string) Teacher {
+	return Teacher{Name: strings.TrimSpace(name)}
+}
+
+// Matches проверяет, соответствует ли имя преподавателю (по каноническому имени или псевдониму)
+func (t Teacher) Matches(name string) bool {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(t.Name, name) {
+		return true
+	}
+	for _, alias := range t.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeTeachers объединяет списки преподавателей без повторов, упорядочивая их по имени
+func MergeTeachers(lists ...[]Teacher) []Teacher {
+	seen := make(map[string]bool)
+	var result []Teacher
+	for _, list := range lists {
+		for _, teacher := range list {
+			if teacher.Name == "" || seen[teacher.Name] {
+				continue
+			}
+			seen[teacher.Name] = true
+			result = append(result, teacher)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// TeacherNames возвращает имена преподавателей урока через " & "
+func (l Lesson) TeacherNames() string {
+	names := make([]string, len(l.Teachers))
+	for i, teacher := range l.Teachers {
+		names[i] = teacher.Name
+	}
+	return strings.Join(names, " & ")
+}
+
+// HasTeacher проверяет, ведет ли урок указанный преподаватель
+func (l Lesson) HasTeacher(name string) bool {
+	for _, teacher := range l.Teachers {
+		if teacher.Matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShortTeacherName приводит имя преподавателя к виду "Фамилия И.О.", в котором оно записано
+// в XLS-файлах: "Иванов Иван Иванович" и "Иванов И. И." дают "Иванов И.И.".
+// Имена другого вида возвращаются без изменений.
+func ShortTeacherName(name string) string {
+	fields := strings.Fields(strings.ReplaceAll(name, ".", ". "))
+	if len(fields) < 2 || len(fields) > 3 {
+		return strings.TrimSpace(name)
+	}
+	short := fields[0] + " "
+	for _, part := range fields[1:] {
+		initial := []rune(strings.TrimSuffix(part, "."))
+		if len(initial) == 0 {
+			return strings.TrimSpace(name)
+		}
+		short += string(initial[0]) + "."
+	}
+	return short
+}
+
+// TeacherDirectory — справочник преподавателей вуза для проверки имён из XLS-файлов
+type TeacherDirectory struct {
+	names      []string          // имена в виде "Фамилия И.О."
+	normalized map[string]string // нормализованное имя → имя из справочника
+}
+
+// NewTeacherDirectory создаёт справочник по именам преподавателей в любом из видов,
+// которые понимает ShortTeacherName
+func NewTeacherDirectory(names []string) TeacherDirectory {
+	d := TeacherDirectory{normalized: make(map[string]string)}
+	for _, name := range names {
+		short := ShortTeacherName(name)
+		if short == "" {
+			continue
+		}
+		key := normalizeName(short)
+		if _, exists := d.normalized[key]; exists {
+			continue
+		}
+		d.normalized[key] = short
+		d.names = append(d.names, short)
+	}
+	sort.Strings(d.names)
+	return d
+}
+
+// IsEmpty сообщает, что справочник пуст и проверять имена не по чему
+func (d TeacherDirectory) IsEmpty() bool {
+	return len(d.names) == 0
+}
+
+// Resolve ищет преподавателя в справочнике. Если имя совпадает с точностью до регистра,
+// пробелов и букв другой раскладки, возвращается написание из справочника и true.
+// Иначе возвращается похожее имя из справочника (вероятная опечатка) или пустая строка и false.
+func (d TeacherDirectory) Resolve(name string) (string, bool) {
+	if canonical, ok := d.normalized[normalizeName(ShortTeacherName(name))]; ok {
+		return canonical, true
+	}
+	similar, _ := ClosestName(name, d.names)
+	return similar, false
+}
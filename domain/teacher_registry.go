@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"crypto/subtle"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TeacherAliasCandidate — два написания имени одного преподавателя, встреченные у одного
+// занятия. Ждёт подтверждения: после него Alias считается псевдонимом Name.
+type TeacherAliasCandidate struct {
+	Name      string // предлагаемое основное написание
+	Alias     string // написание, которое станет псевдонимом
+	Dismissed bool   // отклонено: это разные преподаватели, больше не предлагать
+}
+
+// TeacherRegistry — справочник преподавателей с подтверждёнными псевдонимами и кандидатами
+// в псевдонимы, найденными при разборе расписания
+type TeacherRegistry struct {
+	Teachers   []Teacher
+	Candidates []TeacherAliasCandidate
+}
+
+// SameTeacherName сообщает, что имена различаются только пробелами и знаками препинания,
+// например "Иванова И.И." и "Иванова И. И."
+func SameTeacherName(a, b string) bool {
+	return a != b && nameLetters(a) == nameLetters(b)
+}
+
+// nameLetters оставляет в имени только буквы и цифры
+func nameLetters(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// Canonical возвращает основное написание имени преподавателя, если имя — его подтверждённый
+// псевдоним, иначе само имя
+func (r TeacherRegistry) Canonical(name string) string {
+	for _, teacher := range r.Teachers {
+		if teacher.Matches(name) {
+			return teacher.Name
+		}
+	}
+	return name
+}
+
+// Expected возвращает преподавателей, ведущих индивидуальные занятия: их файлы расписания
+// ожидаются каждую неделю
+func (r TeacherRegistry) Expected() []Teacher {
+	var expected []Teacher
+	for _, teacher := range r.Teachers {
+		if teacher.Individual {
+			expected = append(expected, teacher)
+		}
+	}
+	return expected
+}
+
+// Pending возвращает кандидатов, ожидающих подтверждения
+func (r TeacherRegistry) Pending() []TeacherAliasCandidate {
+	var pending []TeacherAliasCandidate
+	for _, candidate := range r.Candidates {
+		if !candidate.Dismissed {
+			pending = append(pending, candidate)
+		}
+	}
+	return pending
+}
+
+// Suggest добавляет кандидата в псевдонимы по двум написаниям имени. Основным предлагается
+// написание вида "Фамилия И.О.". Пары, уже подтверждённые, ожидающие или отклонённые,
+// повторно не добавляются. Возвращает true, если кандидат добавлен.
+func (r *TeacherRegistry) Suggest(a, b string) bool {
+	if r.Canonical(a) == r.Canonical(b) || r.find(a, b) >= 0 {
+		return false
+	}
+	name, alias := a, b
+	aShort, bShort := ShortTeacherName(a) == a, ShortTeacherName(b) == b
+	if bShort && !aShort || aShort == bShort && b < a {
+		name, alias = b, a
+	}
+	r.Candidates = append(r.Candidates, TeacherAliasCandidate{Name: r.Canonical(name), Alias: alias})
+	return true
+}
+
+// Confirm делает alias псевдонимом преподавателя name и убирает кандидата, в каком бы
+// порядке были предложены написания
+func (r *TeacherRegistry) Confirm(name, alias string) {
+	if i := r.find(name, alias); i >= 0 {
+		r.Candidates = append(r.Candidates[:i], r.Candidates[i+1:]...)
+	}
+	name = r.Canonical(name)
+	if name == alias {
+		return
+	}
+	for i := range r.Teachers {
+		if r.Teachers[i].Name == name {
+			if !r.Teachers[i].Matches(alias) {
+				r.Teachers[i].Aliases = append(r.Teachers[i].Aliases, alias)
+			}
+			return
+		}
+	}
+	teacher := NewTeacher(name)
+	teacher.Aliases = []string{alias}
+	r.Teachers = append(r.Teachers, teacher)
+	sort.Slice(r.Teachers, func(i, j int) bool {
+		return r.Teachers[i].Name < r.Teachers[j].Name
+	})
+}
+
+// Dismiss отклоняет кандидата: написания принадлежат разным преподавателям
+func (r *TeacherRegistry) Dismiss(name, alias string) {
+	if i := r.find(name, alias); i >= 0 {
+		r.Candidates[i].Dismissed = true
+	}
+}
+
+// Expect отмечает преподавателя ведущим индивидуальные занятия и задаёт его контакты
+// для напоминаний; преподавателя, которого нет в справочнике, добавляет
+func (r *TeacherRegistry) Expect(name, email, telegram string) {
+	name = r.Canonical(name)
+	for i := range r.Teachers {
+		if r.Teachers[i].Name == name {
+			r.Teachers[i].Individual = true
+			r.Teachers[i].Email = email
+			r.Teachers[i].Telegram = telegram
+			return
+		}
+	}
+	teacher := NewTeacher(name)
+	teacher.Individual, teacher.Email, teacher.Telegram = true, email, telegram
+	r.Teachers = append(r.Teachers, teacher)
+	sort.Slice(r.Teachers, func(i, j int) bool {
+		return r.Teachers[i].Name < r.Teachers[j].Name
+	})
+}
+
+// Unexpect убирает преподавателя из ведущих индивидуальные занятия вместе с контактами
+// и доступом к странице преподавателя; преподаватель без псевдонимов и нормы нагрузки
+// удаляется из справочника
+func (r *TeacherRegistry) Unexpect(name string) {
+	for i := range r.Teachers {
+		if r.Teachers[i].Name != name {
+			continue
+		}
+		r.Teachers[i].Individual = false
+		r.Teachers[i].Email, r.Teachers[i].Telegram = "", ""
+		r.Teachers[i].AccessKey = ""
+		r.dropIfUnused(i)
+		return
+	}
+}
+
+// GrantAccess задаёт преподавателю ключ страницы преподавателя, заменяя прежний;
+// преподавателя, которого нет в справочнике, добавляет
+func (r *TeacherRegistry) GrantAccess(name, key string) {
+	name = r.Canonical(name)
+	for i := range r.Teachers {
+		if r.Teachers[i].Name == name {
+			r.Teachers[i].AccessKey = key
+			return
+		}
+	}
+	teacher := NewTeacher(name)
+	teacher.AccessKey = key
+	r.Teachers = append(r.Teachers, teacher)
+	sort.Slice(r.Teachers, func(i, j int) bool {
+		return r.Teachers[i].Name < r.Teachers[j].Name
+	})
+}
+
+// RevokeAccess отзывает ключ страницы преподавателя
+func (r *TeacherRegistry) RevokeAccess(name string) {
+	for i := range r.Teachers {
+		if r.Teachers[i].Name == name {
+			r.Teachers[i].AccessKey = ""
+			r.dropIfUnused(i)
+			return
+		}
+	}
+}
+
+// ByAccessKey находит преподавателя по ключу страницы преподавателя. Ключи сравниваются
+// за постоянное время, чтобы ключ нельзя было подобрать по времени ответа.
+func (r TeacherRegistry) ByAccessKey(key string) (Teacher, bool) {
+	if key == "" {
+		return Teacher{}, false
+	}
+	for _, teacher := range r.Teachers {
+		if teacher.AccessKey != "" && subtle.ConstantTimeCompare([]byte(teacher.AccessKey), []byte(key)) == 1 {
+			return teacher, true
+		}
+	}
+	return Teacher{}, false
+}
+
+// dropIfUnused удаляет из справочника преподавателя i, если о нём ничего не хранится,
+// кроме имени
+func (r *TeacherRegistry) dropIfUnused(i int) {
+	t := r.Teachers[i]
+	if len(t.Aliases) == 0 && t.MaxLoad == 0 && !t.Individual && t.AccessKey == "" {
+		r.Teachers = append(r.Teachers[:i], r.Teachers[i+1:]...)
+	}
+}
+
+// RemoveAlias убирает подтверждённый псевдоним преподавателя; преподаватель, о котором
+// больше ничего не хранится, удаляется из справочника
+func (r *TeacherRegistry) RemoveAlias(name, alias string) {
+	for i := range r.Teachers {
+		if r.Teachers[i].Name != name {
+			continue
+		}
+		aliases := r.Teachers[i].Aliases[:0]
+		for _, a := range r.Teachers[i].Aliases {
+			if a != alias {
+				aliases = append(aliases, a)
+			}
+		}
+		r.Teachers[i].Aliases = aliases
+		r.dropIfUnused(i)
+		return
+	}
+}
+
+// find возвращает индекс кандидата с этой парой написаний в любом порядке или -1
+func (r TeacherRegistry) find(a, b string) int {
+	for i, candidate := range r.Candidates {
+		if candidate.Name == a && candidate.Alias == b || candidate.Name == b && candidate.Alias == a {
+			return i
+		}
+	}
+	return -1
+}
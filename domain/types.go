@@ -1,13 +1,64 @@
 package domain
 
-import "time"
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Student содержит информацию о студенте
 type Student struct {
-	Name       string
-	Group      string
-	Department string
-	Year       int
+	Name         string
+	Group        string
+	Department   string
+	Year         int
+	Email        string `yaml:"email,omitempty"`         // e-mail студента для уведомлений
+	Telegram     string `yaml:"telegram,omitempty"`      // chat_id студента в Telegram
+	CuratorEmail string `yaml:"curator_email,omitempty"` // e-mail куратора, если у студента нет своих контактов
+}
+
+// HasContacts сообщает, что у студента указан хотя бы один собственный контакт
+func (s Student) HasContacts() bool {
+	return s.Email != "" || s.Telegram != ""
+}
+
+// ViolationKind определяет вид нарушения в расписании
+type ViolationKind string
+
+const (
+	ViolationOverload    ViolationKind = "overload"    // превышение дневной нагрузки
+	ViolationGaps        ViolationKind = "gaps"        // превышение окон
+	ViolationCustom      ViolationKind = "custom"      // нарушение пользовательского правила
+	ViolationClash       ViolationKind = "clash"       // индивидуальное занятие во время групповой пары
+	ViolationConsecutive ViolationKind = "consecutive" // слишком много половинок пар подряд без перерыва
+	ViolationOverlap     ViolationKind = "overlap"     // два занятия студента в одной половинке пары
+	// ViolationWeeklyOverload — превышение недельной нагрузки; дата нарушения — понедельник недели
+	ViolationWeeklyOverload ViolationKind = "weekly_overload"
+)
+
+// DisplayName возвращает название вида нарушения для отображения пользователю
+func (k ViolationKind) DisplayName() string {
+	switch k {
+	case ViolationOverload:
+		return "Превышение нагрузки"
+	case ViolationGaps:
+		return "Превышение окон"
+	case ViolationCustom:
+		return "Нарушение правила"
+	case ViolationClash:
+		return "Наложение на групповую пару"
+	case ViolationWeeklyOverload:
+		return "Превышение недельной нагрузки"
+	case ViolationConsecutive:
+		return "Занятия без перерыва"
+	case ViolationOverlap:
+		return "Наложение занятий"
+	default:
+		return string(k)
+	}
 }
 
 // Violation описывает нарушение в расписании студента
@@ -15,30 +66,105 @@ type Violation struct {
 	StudentName string
 	Group       string
 	Year        int
-	Date        time.Time // дата
-	Type        string    // "Превышение нагрузки" или "Превышение окон"
-	Hours       int       // количество академических часов
+	Date        time.Time     // дата
+	Kind        ViolationKind // вид нарушения
+	Hours       int           // количество академических часов
+	Rule        string        // название пользовательского правила для ViolationCustom
+}
+
+// Title возвращает название нарушения для отчета: для пользовательских правил — название правила
+func (v Violation) Title() string {
+	return v.TitleIn(LangRU)
+}
+
+// TitleIn возвращает название нарушения на языке lang; название пользовательского правила не переводится
+func (v Violation) TitleIn(lang Lang) string {
+	if v.Kind == ViolationCustom && v.Rule != "" {
+		return lang.Tf("%s «%s»", lang.T(v.Kind.DisplayName()), v.Rule)
+	}
+	return lang.T(v.Kind.DisplayName())
+}
+
+// ID возвращает идентификатор нарушения: студент, дата, вид и правило. Идентификатор
+// не меняется при повторной проверке той же недели, поэтому к нему привязываются
+// комментарии и отметки о нарушении.
+func (v Violation) ID() string {
+	key := fmt.Sprintf("%s|%s|%s|%s", v.StudentName, v.Date.In(location).Format("2006-01-02"), v.Kind, v.Rule)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
 }
 
 // NewViolation создает новое нарушение
-func NewViolation(studentName, group string, year int, date time.Time, violationType string, hours int) Violation {
+func NewViolation(studentName, group string, year int, date time.Time, kind ViolationKind, hours int) Violation {
 	return Violation{
 		StudentName: studentName,
 		Group:       group,
 		Year:        year,
 		Date:        date,
-		Type:        violationType,
+		Kind:        kind,
 		Hours:       hours,
 	}
 }
 
+// LessonSource указывает, откуда получено занятие
+type LessonSource string
+
+const (
+	SourceIndividual LessonSource = "individual" // XLS-файлы индивидуальных занятий
+	SourceGroup      LessonSource = "group"      // групповое расписание с сайта вуза
+	SourceImported   LessonSource = "imported"   // импортированные данные
+)
+
+// DisplayName возвращает название источника для отображения в отчете
+func (s LessonSource) DisplayName() string {
+	switch s {
+	case SourceIndividual:
+		return "Индивидуальное расписание"
+	case SourceGroup:
+		return "Групповое расписание"
+	case SourceImported:
+		return "Импорт"
+	default:
+		return "Неизвестный источник"
+	}
+}
+
+// Lesson описывает одно занятие в расписании
 type Lesson struct {
+	ID         string // детерминированный идентификатор, см. ComputeID
 	Time       LessonTime
 	Discipline string
-	Teacher    string
+	Teachers   []Teacher // преподаватели, ведущие занятие
 	Cabinet    string
 	Group      string
 	Student    string
+	Subgroup   string       // подгруппа группового занятия, пусто — вся группа
+	Source     LessonSource // источник, из которого получено занятие
+}
+
+// ComputeID вычисляет идентификатор занятия по источнику, студенту (или группе с подгруппой), слоту,
+// дисциплине и преподавателям — так два разных занятия в одном слоте (наложение) получают разные
+// идентификаторы. Идентификатор не меняется между запусками, пока не меняются эти поля, поэтому
+// на него можно ссылаться из API, сравнений и отметок о просмотре.
+func (l Lesson) ComputeID() string {
+	teachers := make([]string, len(l.Teachers))
+	for i, teacher := range l.Teachers {
+		teachers[i] = teacher.Name
+	}
+	sort.Strings(teachers)
+	key := fmt.Sprintf("%s|%s|%s|%s|%s|%d|%d|%s|%s",
+		l.Source,
+		l.Student,
+		l.Group,
+		l.Subgroup,
+		l.Time.DateString(),
+		l.Time.Number,
+		l.Time.PairHalf,
+		l.Discipline,
+		strings.Join(teachers, "&"),
+	)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
 }
 
 // LessonTime содержит информацию о дате и времени пары
@@ -51,39 +177,55 @@ type LessonTime struct {
 	EndTime   time.Time // конец пары
 }
 
+// location — часовой пояс, в котором интерпретируются даты и время занятий.
+// По умолчанию UTC+5 (время вуза), чтобы даты не сдвигались на сервере в другом поясе.
+var location = time.FixedZone("UTC+5", 5*60*60)
+
+// SetLocation задаёт часовой пояс расписания. nil игнорируется
+func SetLocation(loc *time.Location) {
+	if loc != nil {
+		location = loc
+	}
+}
+
+// Location возвращает часовой пояс расписания
+func Location() *time.Location {
+	return location
+}
+
 // DateString возвращает дату в формате "2006-01-02"
 func (t LessonTime) DateString() string {
-	return t.Date.Format("2006-01-02")
+	return t.Date.In(location).Format("2006-01-02")
 }
 
 // DayName возвращает название дня недели
 func (t LessonTime) DayName() string {
-	names := []string{
-		"воскресенье", "понедельник", "вторник",
-		"среда", "четверг", "пятница", "суббота",
-	}
-	return names[t.Date.Weekday()]
+	return WeekdayName(t.Date.In(location).Weekday())
 }
 
 // StartTimeString возвращает время начала в формате "15:04"
 func (t LessonTime) StartTimeString() string {
-	return t.StartTime.Format("15:04")
+	return t.StartTime.In(location).Format("15:04")
 }
 
 // EndTimeString возвращает время конца в формате "15:04"
 func (t LessonTime) EndTimeString() string {
-	return t.EndTime.Format("15:04")
+	return t.EndTime.In(location).Format("15:04")
 }
 
+// WeekStart возвращает дату начала недели (понедельник) в часовом поясе расписания
 func (t LessonTime) WeekStart() time.Time {
-	offset := (int(t.Date.Weekday()) + 6) % 7
-	start := time.Date(t.Date.Year(), t.Date.Month(), t.Date.Day(), 0, 0, 0, 0, t.Date.Location())
-	return start.AddDate(0, 0, -offset)
+	return t.Week().Start()
 }
 
 // WeekEnd возвращает дату конца недели (воскресенье)
 func (t LessonTime) WeekEnd() time.Time {
-	return t.WeekStart().AddDate(0, 0, 6)
+	return t.Week().End()
+}
+
+// Week возвращает неделю, к которой относится пара
+func (t LessonTime) Week() Week {
+	return WeekOf(t.Date)
 }
 
 // WeekStartString возвращает дату начала недели в формате "2006-01-02"
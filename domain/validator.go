@@ -6,8 +6,11 @@ import (
 
 // Validator содержит данные и методы для проверки расписания
 type Validator struct {
-	students []Student
-	lessons  []Lesson
+	students    []Student
+	lessons     []Lesson
+	customRules []CustomRule
+	limits      Limits
+	rules       []Rule // дополнительные правила, см. RegisterRule
 }
 
 // NewValidator создаёт новый Validator
@@ -15,147 +18,73 @@ func NewValidator(students []Student, lessons []Lesson) *Validator {
 	return &Validator{
 		students: students,
 		lessons:  lessons,
+		limits:   DefaultLimits(),
 	}
 }
-func (v *Validator) ValidateSchedule() []Violation {
-	violations := []Violation{}
-
-	for _, student := range v.students {
-		studentSchedule := v.collectStudentSchedule(student)
-		scheduleByDate := v.groupByDate(studentSchedule)
-
-		for _, dayLessons := range scheduleByDate {
-			studentViolations := v.validateDay(student, dayLessons)
-			violations = append(violations, studentViolations...)
-		}
-	}
 
-	fmt.Printf("Найдено нарушений: %d\n", len(violations))
-	return violations
+// SetCustomRules задает пользовательские правила, проверяемые для каждого дня студента
+func (v *Validator) SetCustomRules(rules []CustomRule) {
+	v.customRules = rules
 }
 
-// collectStudentSchedule собирает расписание студента (индивидуальные + групповые пары)
-func (v *Validator) collectStudentSchedule(student Student) []Lesson {
-	schedule := []Lesson{}
-
-	for _, lesson := range v.lessons {
-		if lesson.Student != "" && lesson.Student == student.Name {
-			schedule = append(schedule, lesson)
-			continue
-		}
-		if lesson.Student == "" && lesson.Group == student.Group {
-			schedule = append(schedule, lesson)
-		}
-	}
-
-	return schedule
+// SetLimits задает пороги нагрузки и окон вместо значений по умолчанию
+func (v *Validator) SetLimits(limits Limits) {
+	v.limits = limits
 }
 
-// groupByDate группирует уроки по дате
-func (v *Validator) groupByDate(lessons []Lesson) map[string][]Lesson {
-	result := make(map[string][]Lesson)
-
-	for _, lesson := range lessons {
-		date := lesson.Time.DateString()
-		result[date] = append(result[date], lesson)
-	}
-
-	return result
+// RegisterRule добавляет в реестр правило проверки; оно выполняется после стандартных
+// и пользовательских правил
+func (v *Validator) RegisterRule(rule Rule) {
+	v.rules = append(v.rules, rule)
 }
 
-// validateDay проверяет один день расписания студента
-func (v *Validator) validateDay(student Student, dayLessons []Lesson) []Violation {
-	violations := []Violation{}
-
-	if len(dayLessons) == 0 {
-		return violations
-	}
-
-	date := dayLessons[0].Time.Date
-
-	// Нагрузка (сумма часов)
-	totalHours := v.calculateLoad(dayLessons)
-	if totalHours > 10 {
-		violation := NewViolation(
-			student.Name,
-			student.Group,
-			student.Year,
-			date,
-			"Превышение нагрузки",
-			totalHours,
-		)
-		violations = append(violations, violation)
-	}
-
-	// Окна — считаем пустые PairHalf
-	gapPairs := v.calculateGaps(dayLessons)
-	maxGaps := 4
-	if student.Year == 1 {
-		maxGaps = 2
+// Rules возвращает реестр правил, которыми проверяется каждый день студента: включённые
+// стандартные правила с текущими порогами, пользовательские правила и правила из RegisterRule
+func (v *Validator) Rules() []Rule {
+	var rules []Rule
+	for _, rule := range []Rule{OverloadRule{v.limits}, GapsRule{v.limits}, ConsecutiveRule{v.limits}, ClashRule{}, OverlapRule{}} {
+		if v.limits.IsEnabled(ViolationKind(rule.Name())) {
+			rules = append(rules, rule)
+		}
 	}
-	if gapPairs > maxGaps {
-		violation := NewViolation(
-			student.Name,
-			student.Group,
-			student.Year,
-			date,
-			"Превышение окон",
-			gapPairs,
-		)
-		violations = append(violations, violation)
+	for _, rule := range v.customRules {
+		rules = append(rules, customRuleCheck{rule})
 	}
-
-	return violations
+	return append(rules, v.rules...)
 }
 
-// calculateLoad считает суммарную нагрузку по сумме Hours у уроков
-func (v *Validator) calculateLoad(dayLessons []Lesson) int {
-	total := 0
-	for _, lesson := range dayLessons {
-		total += lesson.Time.Hours
+// WeekRules возвращает включённые правила, которыми проверяется вся неделя студента
+func (v *Validator) WeekRules() []WeekRule {
+	var rules []WeekRule
+	if v.limits.IsEnabled(ViolationWeeklyOverload) {
+		rules = append(rules, WeeklyOverloadRule{v.limits})
 	}
-	return total
+	return rules
 }
 
-// calculateGaps считает количество пустых PairHalf между минимальной и максимальной занятой ячейкой
-func (v *Validator) calculateGaps(dayLessons []Lesson) int {
-	if len(dayLessons) == 0 {
-		return 0
-	}
-
-	minSlot := int(^uint(0) >> 1) // max int - очень большая циферь, можно было ставить что-то в районе 15
-	maxSlot := -1
-	occupiedSlots := make(map[int]bool)
-
-	for _, lesson := range dayLessons {
-		if lesson.Time.PairHalf == 0 {
-			// Пара занимает обе половинки
-			slot1 := (lesson.Time.Number - 1) * 2
-			slot2 := slot1 + 1
-			occupiedSlots[slot1] = true
-			occupiedSlots[slot2] = true
-
-			if slot1 < minSlot {
-				minSlot = slot1
-			}
-			if slot2 > maxSlot {
-				maxSlot = slot2
-			}
-		} else {
-			// Пара занимает только одну половинку (1 или 2)
-			slot := (lesson.Time.Number-1)*2 + (lesson.Time.PairHalf - 1)
-			occupiedSlots[slot] = true
+// ValidateSchedule проверяет расписание всех студентов за неделю week всеми правилами реестра:
+// каждый день — правилами Rules, всю неделю — правилами WeekRules — и возвращает найденные
+// нарушения. Занятия других недель (XLS-файл может охватывать несколько недель) не проверяются.
+func (v *Validator) ValidateSchedule(week Week) []Violation {
+	violations := []Violation{}
+	schedule := Schedule(v.lessons).ForWeek(week)
+	rules, weekRules := v.Rules(), v.WeekRules()
 
-			if slot < minSlot {
-				minSlot = slot
+	for _, student := range v.students {
+		weekLessons := schedule.ForStudent(student).MergeSubgroups()
+		for _, dayLessons := range weekLessons.ByDay() {
+			if len(dayLessons) == 0 {
+				continue
 			}
-			if slot > maxSlot {
-				maxSlot = slot
+			for _, rule := range rules {
+				violations = append(violations, rule.Check(student, dayLessons)...)
 			}
 		}
+		for _, rule := range weekRules {
+			violations = append(violations, rule.CheckWeek(student, week, weekLessons)...)
+		}
 	}
 
-	totalSlots := maxSlot - minSlot + 1
-	occupied := len(occupiedSlots)
-	return totalSlots - occupied
+	fmt.Printf("Найдено нарушений: %d\n", len(violations))
+	return violations
 }
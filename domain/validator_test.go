@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/testsupport"
+)
+
+// week — неделя занятий testsupport.NewLesson по умолчанию
+var week = testsupport.MustWeek("2024-09-02")
+
+func TestValidateSchedule(t *testing.T) {
+	const group = "МД-23-о"
+	firstYear := domain.Student{Name: "Смирнова Анна", Group: group, Year: 1}
+	secondYear := domain.Student{Name: "Иванов Пётр", Group: group, Year: 2}
+
+	groupPair := func(number int) domain.Lesson {
+		return testsupport.NewLesson().Pair(number).Group(group).Teacher("Соколова Е.Н.").Build()
+	}
+	individual := func(student string, number int) domain.Lesson {
+		return testsupport.NewLesson().Pair(number).Group(group).Individual(student).Teacher("Петров А.В.").Build()
+	}
+
+	tests := []struct {
+		name    string
+		student domain.Student
+		lessons []domain.Lesson
+		want    []domain.ViolationKind
+	}{
+		{
+			name:    "без нарушений",
+			student: secondYear,
+			lessons: []domain.Lesson{groupPair(1), groupPair(2), individual(secondYear.Name, 3)},
+		},
+		{
+			name:    "нагрузка больше 10 часов и шесть пар подряд",
+			student: secondYear,
+			lessons: []domain.Lesson{groupPair(1), groupPair(2), groupPair(3), individual(secondYear.Name, 4), individual(secondYear.Name, 5), individual(secondYear.Name, 6)},
+			want:    []domain.ViolationKind{domain.ViolationOverload, domain.ViolationConsecutive},
+		},
+		{
+			name:    "окно в две пары допустимо со второго курса",
+			student: secondYear,
+			lessons: []domain.Lesson{groupPair(1), individual(secondYear.Name, 4)},
+		},
+		{
+			name:    "окно в две пары недопустимо на первом курсе",
+			student: firstYear,
+			lessons: []domain.Lesson{groupPair(1), individual(firstYear.Name, 4)},
+			want:    []domain.ViolationKind{domain.ViolationGaps},
+		},
+		{
+			name:    "индивидуальное занятие на групповой паре",
+			student: secondYear,
+			lessons: []domain.Lesson{
+				groupPair(1),
+				testsupport.NewLesson().Pair(1).Half(2).Group(group).Individual(secondYear.Name).Build(),
+			},
+			want: []domain.ViolationKind{domain.ViolationClash},
+		},
+		{
+			name:    "два индивидуальных занятия в одной паре",
+			student: secondYear,
+			lessons: []domain.Lesson{
+				groupPair(1),
+				individual(secondYear.Name, 2),
+				testsupport.NewLesson().Pair(2).Half(1).Group(group).Individual(secondYear.Name).Teacher("Кузнецова О.И.").Build(),
+			},
+			want: []domain.ViolationKind{domain.ViolationOverlap},
+		},
+		{
+			name:    "одно занятие, полученное дважды, не наложение",
+			student: secondYear,
+			lessons: []domain.Lesson{groupPair(1), individual(secondYear.Name, 2), individual(secondYear.Name, 2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := domain.NewValidator([]domain.Student{tt.student}, tt.lessons).ValidateSchedule(week)
+
+			var got []domain.ViolationKind
+			for _, v := range violations {
+				got = append(got, v.Kind)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("нарушения = %v, ожидалось %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("нарушения = %v, ожидалось %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	const group = "МД-23-о"
+	student := domain.Student{Name: "Иванов Пётр", Group: group, Year: 2}
+	day := domain.Schedule{
+		testsupport.NewLesson().Pair(1).Group(group).Teacher("Соколова Е.Н.").Build(),
+		testsupport.NewLesson().Pair(4).Group(group).Individual(student.Name).Teacher("Петров А.В.").Build(),
+	}
+
+	tests := []struct {
+		name string
+		rule domain.Rule
+		want int
+	}{
+		{name: "окна в пределах порога", rule: domain.GapsRule{Limits: domain.DefaultLimits()}},
+		{name: "окна сверх порога", rule: domain.GapsRule{Limits: domain.Limits{MaxGaps: 2}}, want: 1},
+		{name: "нагрузка в пределах порога", rule: domain.OverloadRule{Limits: domain.DefaultLimits()}},
+		{name: "нет наложений", rule: domain.ClashRule{}},
+		{name: "нет двух занятий в одной паре", rule: domain.OverlapRule{}},
+		{name: "пары подряд в пределах порога", rule: domain.ConsecutiveRule{Limits: domain.Limits{MaxConsecutive: 2}}},
+		{name: "пары подряд сверх порога", rule: domain.ConsecutiveRule{Limits: domain.Limits{MaxConsecutive: 1}}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Check(student, day); len(got) != tt.want {
+				t.Errorf("%s: нарушений %d, ожидалось %d", tt.rule.Name(), len(got), tt.want)
+			}
+		})
+	}
+
+	t.Run("недельная нагрузка сверх порога", func(t *testing.T) {
+		rule := domain.WeeklyOverloadRule{Limits: domain.Limits{MaxWeeklyHours: day.Hours() - 1}}
+		got := rule.CheckWeek(student, week, day)
+		if len(got) != 1 || got[0].Kind != domain.ViolationWeeklyOverload || got[0].Hours != day.Hours() {
+			t.Errorf("нарушения = %v, ожидалось одно превышение недельной нагрузки на %d ак. ч", got, day.Hours())
+		}
+	})
+
+	t.Run("выключенное правило не проверяется", func(t *testing.T) {
+		validator := domain.NewValidator([]domain.Student{student}, day)
+		validator.SetLimits(domain.Limits{MaxDailyHours: 10, MaxGaps: 2, MaxGapsFirstYear: 2,
+			Enabled: map[domain.ViolationKind]bool{domain.ViolationGaps: false}})
+		if got := validator.ValidateSchedule(week); len(got) != 0 {
+			t.Errorf("нарушения = %v, ожидалось без нарушений", got)
+		}
+	})
+}
+
+func TestValidateScheduleWeek(t *testing.T) {
+	student := domain.Student{Name: "Иванов Пётр", Group: "МД-23-о", Year: 2}
+	next := week.Next()
+	// Файл преподавателя на две недели: по три пары в понедельник каждой недели
+	var lessons []domain.Lesson
+	for _, monday := range []string{"2024-09-02", "2024-09-09"} {
+		for pair := 1; pair <= 3; pair++ {
+			lessons = append(lessons, testsupport.NewLesson().On(monday).Pair(pair).Individual(student.Name).Teacher("Петров А.В.").Build())
+		}
+	}
+	weekHours := domain.Schedule(lessons).ForWeek(week).Hours()
+
+	tests := []struct {
+		name      string
+		week      domain.Week
+		maxWeekly int
+		want      int // ожидаемых превышений недельной нагрузки
+	}{
+		{name: "часы другой недели не суммируются", week: week, maxWeekly: weekHours},
+		{name: "превышение в первой неделе", week: week, maxWeekly: weekHours - 1, want: 1},
+		{name: "превышение во второй неделе", week: next, maxWeekly: weekHours - 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := domain.DefaultLimits()
+			limits.MaxWeeklyHours = tt.maxWeekly
+			validator := domain.NewValidator([]domain.Student{student}, lessons)
+			validator.SetLimits(limits)
+
+			var got []domain.Violation
+			for _, v := range validator.ValidateSchedule(tt.week) {
+				if v.Kind == domain.ViolationWeeklyOverload {
+					got = append(got, v)
+				}
+			}
+			if len(got) != tt.want {
+				t.Fatalf("превышений недельной нагрузки %d, ожидалось %d: %v", len(got), tt.want, got)
+			}
+			for _, v := range got {
+				if v.Hours != weekHours || !v.Date.Equal(tt.week.Start()) {
+					t.Errorf("нарушение на %d ак. ч от %s, ожидалось %d ак. ч от %s",
+						v.Hours, v.Date.Format("02.01.2006"), weekHours, tt.week.Start().Format("02.01.2006"))
+				}
+			}
+		})
+	}
+}
+
+func TestLessonComputeID(t *testing.T) {
+	lesson := func() *testsupport.LessonBuilder {
+		return testsupport.NewLesson().Pair(2).Individual("Иванов Пётр").Discipline("Фортепиано")
+	}
+	base := lesson().Teacher("Петров А.В.").Build()
+
+	tests := []struct {
+		name  string
+		other domain.Lesson
+		same  bool
+	}{
+		{name: "то же занятие", other: lesson().Teacher("Петров А.В.").Build(), same: true},
+		{name: "другая дисциплина в том же слоте", other: lesson().Discipline("Сольфеджио").Teacher("Петров А.В.").Build()},
+		{name: "другой преподаватель в том же слоте", other: lesson().Teacher("Кузнецова О.И.").Build()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := base.ID == tt.other.ID; same != tt.same {
+				t.Errorf("идентификаторы %s и %s: совпадение %v, ожидалось %v", base.ID, tt.other.ID, same, tt.same)
+			}
+		})
+	}
+
+	t.Run("порядок преподавателей не важен", func(t *testing.T) {
+		a := lesson().Teacher("Петров А.В.").Teacher("Кузнецова О.И.").Build()
+		b := lesson().Teacher("Кузнецова О.И.").Teacher("Петров А.В.").Build()
+		if a.ID != b.ID {
+			t.Errorf("идентификаторы %s и %s различаются", a.ID, b.ID)
+		}
+	})
+}
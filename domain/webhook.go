@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// WebhookStatus — состояние доставки уведомления на внешний адрес (webhook)
+type WebhookStatus string
+
+const (
+	WebhookPending   WebhookStatus = "pending"   // доставляется, возможны повторные попытки
+	WebhookDelivered WebhookStatus = "delivered" // получатель подтвердил приём (код 2xx)
+	WebhookFailed    WebhookStatus = "failed"    // все попытки исчерпаны или получатель отказал
+)
+
+// DisplayName возвращает название состояния для отображения пользователю
+func (s WebhookStatus) DisplayName() string {
+	switch s {
+	case WebhookPending:
+		return "Доставляется"
+	case WebhookDelivered:
+		return "Доставлено"
+	case WebhookFailed:
+		return "Не доставлено"
+	default:
+		return string(s)
+	}
+}
+
+// WebhookDelivery — одно уведомление, отправленное на внешний адрес, с итогом доставки.
+// Тело хранится целиком, чтобы получатель мог сверить его с принятым, а диспетчер — повторить отправку.
+type WebhookDelivery struct {
+	ID           string // идентификатор, передаётся получателю в заголовке
+	Event        string // событие, например check.completed
+	URL          string
+	Payload      string // тело запроса JSON
+	Status       WebhookStatus
+	Attempts     int    // выполненных попыток
+	ResponseCode int    // код ответа последней попытки, 0 — ответа не было
+	Error        string // ошибка последней попытки
+	CreatedAt    time.Time
+	FinishedAt   time.Time // время доставки или последней неудачной попытки
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekLayouts — поддерживаемые форматы даты при разборе недели
+var weekLayouts = []string{"2006-01-02", "02.01.2006", "2.1.2006", "02/01/2006", "2/1/2006", "2006/01/02"}
+
+// weekFormatsHint перечисляет допустимые форматы недели для сообщений об ошибках
+const weekFormatsHint = "допустимые форматы: 2025-02-10, 10.02.2025, 10/02/2025 или «неделя N» по учебному календарю"
+
+// Week — учебная неделя с понедельника по воскресенье в часовом поясе расписания
+type Week struct {
+	start time.Time
+}
+
+// WeekOf возвращает неделю, которой принадлежит дата
+func WeekOf(date time.Time) Week {
+	date = date.In(location)
+	offset := (int(date.Weekday()) + 6) % 7
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+	return Week{start: start.AddDate(0, 0, -offset)}
+}
+
+// ParseWeek разбирает дату в формате "2006-01-02", "02.01.2006" или "02/01/2006" и возвращает
+// неделю, которой она принадлежит. Дата не обязана быть понедельником. Номер недели
+// («неделя 7») разбирает AcademicCalendar.ParseWeek.
+func ParseWeek(s string) (Week, error) {
+	return AcademicCalendar{}.ParseWeek(s)
+}
+
+// parseWeekDate разбирает дату недели в одном из форматов weekLayouts
+func parseWeekDate(s string) (Week, bool) {
+	for _, layout := range weekLayouts {
+		if date, err := time.ParseInLocation(layout, s, location); err == nil {
+			return WeekOf(date), true
+		}
+	}
+	return Week{}, false
+}
+
+// parseWeekNumber разбирает номер недели: "7", "неделя 7", "Неделя №7", "7 неделя", "7-я неделя"
+func parseWeekNumber(s string) (int, bool) {
+	s = strings.ToLower(s)
+	for _, word := range []string{"неделя", "№", "-я"} {
+		s = strings.ReplaceAll(s, word, "")
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// IsZero сообщает, что неделя не задана
+func (w Week) IsZero() bool {
+	return w.start.IsZero()
+}
+
+// Start возвращает начало недели (понедельник, 00:00)
+func (w Week) Start() time.Time {
+	return w.start
+}
+
+// End возвращает дату конца недели (воскресенье)
+func (w Week) End() time.Time {
+	return w.start.AddDate(0, 0, 6)
+}
+
+// Contains проверяет, попадает ли дата в неделю
+func (w Week) Contains(date time.Time) bool {
+	return WeekOf(date).start.Equal(w.start)
+}
+
+// Next возвращает следующую неделю
+func (w Week) Next() Week {
+	return Week{start: w.start.AddDate(0, 0, 7)}
+}
+
+// Prev возвращает предыдущую неделю
+func (w Week) Prev() Week {
+	return Week{start: w.start.AddDate(0, 0, -7)}
+}
+
+// String возвращает дату начала недели в формате "2006-01-02"
+func (w Week) String() string {
+	if w.IsZero() {
+		return ""
+	}
+	return w.start.Format("2006-01-02")
+}
@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// alertsLimit — сколько последних оповещений хранится
+const alertsLimit = 200
+
+// AlertsConfig структура файла оповещений
+type AlertsConfig struct {
+	Alerts []AlertYAML `yaml:"alerts"`
+}
+
+// AlertYAML представление domain.Alert в YAML
+type AlertYAML struct {
+	ID        string            `yaml:"id"`
+	CreatedAt time.Time         `yaml:"created_at"`
+	Level     domain.AlertLevel `yaml:"level"`
+	Title     string            `yaml:"title"`
+	Text      string            `yaml:"text,omitempty"`
+	Link      string            `yaml:"link,omitempty"`
+	Read      bool              `yaml:"read,omitempty"`
+}
+
+// YAMLAlertRepository хранит оповещения и отметки о прочтении в YAML-файле. Старые оповещения
+// сверх alertsLimit удаляются.
+type YAMLAlertRepository struct {
+	filename string
+	store    documentStore // файл или база, см. Storage
+	mutex    sync.Mutex
+}
+
+// NewYAMLAlertRepository создает новый экземпляр репозитория оповещений
+func NewYAMLAlertRepository(filename string) *YAMLAlertRepository {
+	return &YAMLAlertRepository{
+		filename: filename,
+		store:    fileStore{},
+	}
+}
+
+// SaveAlerts добавляет оповещения в конец списка
+func (r *YAMLAlertRepository) SaveAlerts(alerts []domain.Alert) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		config.Alerts = append(config.Alerts, AlertYAML{
+			ID:        alert.ID,
+			CreatedAt: alert.CreatedAt,
+			Level:     alert.Level,
+			Title:     alert.Title,
+			Text:      alert.Text,
+			Link:      alert.Link,
+			Read:      alert.Read,
+		})
+	}
+	if extra := len(config.Alerts) - alertsLimit; extra > 0 {
+		config.Alerts = config.Alerts[extra:]
+	}
+	return r.saveUnsafe(config)
+}
+
+// LoadAlerts возвращает оповещения, новые первыми. Отсутствие файла не считается ошибкой.
+func (r *YAMLAlertRepository) LoadAlerts() ([]domain.Alert, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]domain.Alert, 0, len(config.Alerts))
+	for i := len(config.Alerts) - 1; i >= 0; i-- {
+		alerts = append(alerts, config.Alerts[i].toDomain())
+	}
+	return alerts, nil
+}
+
+// MarkRead отмечает прочитанными оповещения с идентификаторами ids; без идентификаторов —
+// все оповещения. Возвращает ошибку, если какого-то из оповещений нет.
+func (r *YAMLAlertRepository) MarkRead(ids ...string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for i := range config.Alerts {
+		if len(ids) == 0 || wanted[config.Alerts[i].ID] {
+			config.Alerts[i].Read = true
+			delete(wanted, config.Alerts[i].ID)
+		}
+	}
+	for id := range wanted {
+		return fmt.Errorf("оповещение %s не найдено", id)
+	}
+	return r.saveUnsafe(config)
+}
+
+func (e AlertYAML) toDomain() domain.Alert {
+	return domain.Alert{
+		ID:        e.ID,
+		CreatedAt: e.CreatedAt,
+		Level:     e.Level,
+		Title:     e.Title,
+		Text:      e.Text,
+		Link:      e.Link,
+		Read:      e.Read,
+	}
+}
+
+// loadUnsafe читает оповещения без блокировки (внутренний метод)
+func (r *YAMLAlertRepository) loadUnsafe() (AlertsConfig, error) {
+	var config AlertsConfig
+	data, err := r.store.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
+
+// saveUnsafe записывает оповещения без блокировки (внутренний метод)
+func (r *YAMLAlertRepository) saveUnsafe(config AlertsConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := r.store.WriteFile(r.filename, data); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
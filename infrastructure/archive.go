@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// ScheduleArchive переносит обработанные XLS-файлы в папки архива по неделям (archive/2025-07),
+// чтобы в рабочей папке оставались только актуальные файлы
+type ScheduleArchive struct {
+	dir string
+}
+
+// NewScheduleArchive создаёт архив в каталоге dir
+func NewScheduleArchive(dir string) *ScheduleArchive {
+	return &ScheduleArchive{dir: dir}
+}
+
+// Dir возвращает каталог архива
+func (a *ScheduleArchive) Dir() string {
+	return a.dir
+}
+
+// WeekDir возвращает папку архива для недели в формате ГГГГ-НН (год и номер недели по ISO 8601)
+func (a *ScheduleArchive) WeekDir(week domain.Week) string {
+	year, number := week.Start().ISOWeek()
+	return filepath.Join(a.dir, fmt.Sprintf("%04d-%02d", year, number))
+}
+
+// ArchiveWeek переносит в папку недели файлы с занятиями этой недели, все даты которых
+// не позже конца недели. Файлы, охватывающие и следующие недели, остаются на месте: они
+// понадобятся для следующих проверок. Файлы только прошлых или только будущих недель тоже
+// не трогаются — они попадут в архив при проверке своей недели. Возвращает новые пути
+// перенесённых файлов.
+func (a *ScheduleArchive) ArchiveWeek(week domain.Week, files []domain.FileCoverage) ([]string, error) {
+	weekDir := a.WeekDir(week)
+	var moved []string
+	for _, file := range files {
+		if file.Path == "" || file.Last.Before(week.Start()) || file.Last.After(week.End()) {
+			continue
+		}
+		if err := os.MkdirAll(weekDir, 0755); err != nil {
+			return moved, fmt.Errorf("не удалось создать папку архива: %w", err)
+		}
+		target := freeArchivePath(filepath.Join(weekDir, file.File))
+		if err := os.Rename(file.Path, target); err != nil {
+			return moved, fmt.Errorf("не удалось перенести файл %s в архив: %w", file.File, err)
+		}
+		moved = append(moved, target)
+	}
+	return moved, nil
+}
+
+// freeArchivePath возвращает путь, не занятый другим файлом: при совпадении имени
+// добавляется номер ("файл (2).xls")
+func freeArchivePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
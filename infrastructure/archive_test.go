@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+func TestArchiveWeek(t *testing.T) {
+	date := func(value string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02", value, domain.Location())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	week := domain.WeekOf(date("2024-09-09"))
+
+	tests := []struct {
+		name        string
+		first, last string
+		archived    bool
+	}{
+		{name: "файл недели", first: "2024-09-09", last: "2024-09-14", archived: true},
+		{name: "файл с начала прошлой недели", first: "2024-09-02", last: "2024-09-11", archived: true},
+		{name: "файл и на следующую неделю", first: "2024-09-09", last: "2024-09-18"},
+		{name: "устаревший файл прошлой недели", first: "2024-09-02", last: "2024-09-06"},
+		{name: "файл будущей недели", first: "2024-09-16", last: "2024-09-20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := t.TempDir()
+			path := filepath.Join(work, "Петров.xls")
+			if err := os.WriteFile(path, []byte("xls"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			archive := NewScheduleArchive(filepath.Join(work, "archive"))
+			file := domain.FileCoverage{File: "Петров.xls", Path: path, First: date(tt.first), Last: date(tt.last)}
+
+			moved, err := archive.ArchiveWeek(week, []domain.FileCoverage{file})
+			if err != nil {
+				t.Fatalf("ArchiveWeek: %v", err)
+			}
+			if archived := len(moved) == 1; archived != tt.archived {
+				t.Fatalf("перенесено %v, ожидалось перенести: %v", moved, tt.archived)
+			}
+			_, err = os.Stat(path)
+			if stayed := err == nil; stayed == tt.archived {
+				t.Errorf("файл в рабочей папке: %v, ожидалось: %v", stayed, !tt.archived)
+			}
+			if tt.archived && filepath.Dir(moved[0]) != archive.WeekDir(week) {
+				t.Errorf("файл перенесён в %s, ожидалась папка %s", moved[0], archive.WeekDir(week))
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// BellsConfig структура файла расписания звонков. Дни недели задаются английскими
+// названиями: monday, tuesday, ...
+type BellsConfig struct {
+	Default map[int]PairTimeYAML            `yaml:"default"`
+	Days    map[string]map[int]PairTimeYAML `yaml:"days,omitempty"`
+}
+
+// PairTimeYAML описывает время пары в YAML
+type PairTimeYAML struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// weekdayKeys сопоставляет дни недели ключам файла
+var weekdayKeys = map[time.Weekday]string{
+	time.Monday:    "monday",
+	time.Tuesday:   "tuesday",
+	time.Wednesday: "wednesday",
+	time.Thursday:  "thursday",
+	time.Friday:    "friday",
+	time.Saturday:  "saturday",
+	time.Sunday:    "sunday",
+}
+
+// YAMLBellRepository хранит расписание звонков в YAML-файле
+type YAMLBellRepository struct {
+	filename string
+	mutex    sync.RWMutex
+}
+
+// NewYAMLBellRepository создает новый экземпляр репозитория расписания звонков
+func NewYAMLBellRepository(filename string) *YAMLBellRepository {
+	return &YAMLBellRepository{
+		filename: filename,
+	}
+}
+
+// LoadBellSchedule загружает расписание звонков. Если файла нет, возвращается
+// расписание по умолчанию.
+func (r *YAMLBellRepository) LoadBellSchedule() (domain.BellSchedule, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return domain.DefaultBellSchedule(), nil
+	}
+	if err != nil {
+		return domain.BellSchedule{}, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	var config BellsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return domain.BellSchedule{}, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+
+	bells := domain.BellSchedule{
+		Default: pairTimesToDomain(config.Default),
+		Days:    make(map[time.Weekday]map[int]domain.PairTime),
+	}
+	for key, pairs := range config.Days {
+		weekday, ok := weekdayByKey(key)
+		if !ok {
+			return domain.BellSchedule{}, fmt.Errorf("неизвестный день недели %q", key)
+		}
+		bells.Days[weekday] = pairTimesToDomain(pairs)
+	}
+
+	if err := bells.Validate(); err != nil {
+		return domain.BellSchedule{}, err
+	}
+	return bells, nil
+}
+
+// SaveBellSchedule сохраняет расписание звонков
+func (r *YAMLBellRepository) SaveBellSchedule(bells domain.BellSchedule) error {
+	if err := bells.Validate(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config := BellsConfig{
+		Default: pairTimesToYAML(bells.Default),
+		Days:    make(map[string]map[int]PairTimeYAML),
+	}
+	for weekday, pairs := range bells.Days {
+		if len(pairs) > 0 {
+			config.Days[weekdayKeys[weekday]] = pairTimesToYAML(pairs)
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
+
+func weekdayByKey(key string) (time.Weekday, bool) {
+	for weekday, k := range weekdayKeys {
+		if k == key {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+func pairTimesToDomain(pairs map[int]PairTimeYAML) map[int]domain.PairTime {
+	result := make(map[int]domain.PairTime, len(pairs))
+	for number, pair := range pairs {
+		result[number] = domain.PairTime{Start: pair.Start, End: pair.End}
+	}
+	return result
+}
+
+func pairTimesToYAML(pairs map[int]domain.PairTime) map[int]PairTimeYAML {
+	result := make(map[int]PairTimeYAML, len(pairs))
+	for number, pair := range pairs {
+		result[number] = PairTimeYAML{Start: pair.Start, End: pair.End}
+	}
+	return result
+}
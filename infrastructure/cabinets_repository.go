@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// CabinetsConfig структура файла справочника кабинетов
+type CabinetsConfig struct {
+	Cabinets []CabinetYAML           `yaml:"cabinets"` // кабинеты справочника
+	Patterns []domain.CabinetPattern `yaml:"patterns"` // правила приведения записей к идентификаторам
+}
+
+// CabinetYAML — кабинет справочника: строка с идентификатором ("К3-24") или запись
+// с типом и корпусом ({id: К3-24, type: класс фортепиано, building: К3})
+type CabinetYAML struct {
+	ID       string `yaml:"id"`
+	Type     string `yaml:"type,omitempty"`
+	Building string `yaml:"building,omitempty"`
+}
+
+// UnmarshalYAML принимает кабинет строкой или записью
+func (c *CabinetYAML) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&c.ID)
+	}
+	type plain CabinetYAML
+	return node.Decode((*plain)(c))
+}
+
+// YAMLCabinetRepository загружает справочник кабинетов из YAML-файла
+type YAMLCabinetRepository struct {
+	filename string
+}
+
+// NewYAMLCabinetRepository создает новый экземпляр репозитория кабинетов
+func NewYAMLCabinetRepository(filename string) *YAMLCabinetRepository {
+	return &YAMLCabinetRepository{
+		filename: filename,
+	}
+}
+
+// LoadNormalizer загружает справочник кабинетов и правила приведения записей.
+// Если файла нет, возвращается пустой нормализатор, не меняющий записи.
+func (r *YAMLCabinetRepository) LoadNormalizer() (domain.CabinetNormalizer, error) {
+	config, err := r.load()
+	if err != nil {
+		return domain.CabinetNormalizer{}, err
+	}
+	ids := make([]string, 0, len(config.Cabinets))
+	for _, cabinet := range config.Cabinets {
+		ids = append(ids, cabinet.ID)
+	}
+	return domain.NewCabinetNormalizer(ids, config.Patterns)
+}
+
+// LoadCabinets загружает кабинеты справочника с типами и корпусами.
+// Если файла нет, возвращается пустой справочник.
+func (r *YAMLCabinetRepository) LoadCabinets() ([]domain.Cabinet, error) {
+	config, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	var cabinets []domain.Cabinet
+	for _, cabinet := range config.Cabinets {
+		id := strings.Join(strings.Fields(cabinet.ID), " ")
+		if id == "" {
+			continue
+		}
+		cabinets = append(cabinets, domain.Cabinet{
+			ID:       id,
+			Type:     strings.TrimSpace(cabinet.Type),
+			Building: strings.TrimSpace(cabinet.Building),
+		})
+	}
+	return cabinets, nil
+}
+
+// load читает файл справочника; отсутствие файла не считается ошибкой
+func (r *YAMLCabinetRepository) load() (CabinetsConfig, error) {
+	var config CabinetsConfig
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
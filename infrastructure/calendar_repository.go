@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// CalendarConfig структура файла учебного календаря. Даты в формате "2006-01-02"
+type CalendarConfig struct {
+	YearStart string         `yaml:"year_start"`
+	Semesters []SemesterYAML `yaml:"semesters"`
+	Holidays  []HolidayYAML  `yaml:"holidays"`
+}
+
+// HolidayYAML описывает каникулы в YAML
+type HolidayYAML struct {
+	Name  string `yaml:"name"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// YAMLCalendarRepository загружает учебный календарь из YAML-файла
+type YAMLCalendarRepository struct {
+	filename string
+}
+
+// NewYAMLCalendarRepository создает новый экземпляр репозитория календаря
+func NewYAMLCalendarRepository(filename string) *YAMLCalendarRepository {
+	return &YAMLCalendarRepository{
+		filename: filename,
+	}
+}
+
+// LoadCalendar загружает учебный календарь. Если файла нет, возвращается пустой календарь.
+func (r *YAMLCalendarRepository) LoadCalendar() (domain.AcademicCalendar, error) {
+	var calendar domain.AcademicCalendar
+
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return calendar, nil
+	}
+	if err != nil {
+		return calendar, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	var config CalendarConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return calendar, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+
+	yearStart, err := parseConfigDate(config.YearStart)
+	if err != nil {
+		return calendar, fmt.Errorf("неверная дата начала учебного года: %w", err)
+	}
+	calendar.YearStart = domain.WeekOf(yearStart)
+
+	for _, s := range config.Semesters {
+		semester, err := s.toDomain()
+		if err != nil {
+			return domain.AcademicCalendar{}, err
+		}
+		calendar.Semesters = append(calendar.Semesters, semester)
+	}
+
+	for _, h := range config.Holidays {
+		start, err := parseConfigDate(h.Start)
+		if err != nil {
+			return domain.AcademicCalendar{}, fmt.Errorf("каникулы %q: неверная дата начала: %w", h.Name, err)
+		}
+		end, err := parseConfigDate(h.End)
+		if err != nil {
+			return domain.AcademicCalendar{}, fmt.Errorf("каникулы %q: неверная дата окончания: %w", h.Name, err)
+		}
+		calendar.Holidays = append(calendar.Holidays, domain.Holiday{
+			Name:  h.Name,
+			Start: domain.WeekOf(start),
+			End:   domain.WeekOf(end),
+		})
+	}
+	return calendar, nil
+}
+
+// parseConfigDate разбирает дату в формате "2006-01-02" в часовом поясе расписания
+func parseConfigDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, domain.Location())
+}
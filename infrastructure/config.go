@@ -0,0 +1,500 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Config содержит настройки программы из config.yaml. Значения из файла можно
+// переопределить переменными окружения LESSON_COUNTER_*, а их — флагами командной строки.
+type Config struct {
+	// Port — порт веб-интерфейса. Страницы и API на нём не защищены паролем: ключи
+	// преподавателей закрывают только /teacher, остальное доступно всем, кто видит порт.
+	Port      int             `yaml:"port"`
+	GRPCPort  int             `yaml:"grpc_port"` // порт gRPC API, 0 — не запускать
+	Templates string          `yaml:"templates"` // каталог шаблонов, заменяющих встроенные
+	Timezone  string          `yaml:"timezone"`  // часовой пояс расписания, пусто — UTC+5
+	Tray      bool            `yaml:"tray"`      // работать из значка в области уведомлений без окна консоли
+	Paths     PathsConfig     `yaml:"paths"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Site      SiteConfig      `yaml:"site"`
+	Check     CheckConfig     `yaml:"check"`
+	SMTP      SMTPConfig      `yaml:"smtp"`
+	Telegram  TelegramConfig  `yaml:"telegram"`
+	Webhook   WebhookConfig   `yaml:"webhook"`
+	Archive   ArchiveConfig   `yaml:"archive"`
+	Updates   UpdatesConfig   `yaml:"updates"`
+	Warmup    WarmupConfig    `yaml:"warmup"`
+	Retention RetentionConfig `yaml:"retention"`
+	Debug     DebugConfig     `yaml:"debug"`
+}
+
+// DebugConfig содержит настройки записи промежуточных данных разбора XLS-файлов
+type DebugConfig struct {
+	Enabled bool   `yaml:"enabled"` // записывать ячейки и списки занятий при каждой проверке
+	Dir     string `yaml:"dir"`     // каталог, в котором для каждой проверки создаётся подкаталог
+}
+
+// UpdatesConfig содержит настройки проверки обновлений программы
+type UpdatesConfig struct {
+	Check bool   `yaml:"check"` // проверять при запуске и раз в сутки, вышла ли новая версия
+	Repo  string `yaml:"repo"`  // репозиторий GitHub с выпусками, "владелец/имя"
+}
+
+// WarmupConfig содержит настройки прогрева кэша расписания групп в нерабочее время
+type WarmupConfig struct {
+	Enabled bool          `yaml:"enabled"` // каждый день загружать расписание групп заранее
+	At      string        `yaml:"at"`      // время прогрева "15:04" в часовом поясе расписания
+	TTL     time.Duration `yaml:"ttl"`     // сколько хранить прогретое расписание
+}
+
+// Time возвращает время суток прогрева как смещение от полуночи
+func (w WarmupConfig) Time() (time.Duration, error) {
+	at, err := time.Parse("15:04", w.At)
+	if err != nil {
+		return 0, fmt.Errorf("ожидается время вида 06:00, указано %q", w.At)
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, nil
+}
+
+// RetentionConfig содержит сроки хранения данных, по истечении которых они удаляются
+type RetentionConfig struct {
+	Enabled       bool `yaml:"enabled"`        // удалять устаревшие данные при запуске и раз в сутки
+	HistoryMonths int  `yaml:"history_months"` // сколько месяцев хранить историю проверок, 0 — всю
+	DebugMonths   int  `yaml:"debug_months"`   // сколько месяцев хранить отладочные данные, 0 — все
+	ArchiveMonths int  `yaml:"archive_months"` // сколько месяцев хранить архив XLS-файлов, 0 — весь
+}
+
+// ArchiveConfig содержит настройки архива обработанных XLS-файлов
+type ArchiveConfig struct {
+	Enabled bool   `yaml:"enabled"` // переносить файлы в архив после успешной проверки
+	Dir     string `yaml:"dir"`     // каталог архива
+}
+
+// PathsConfig содержит пути к файлам данных программы
+type PathsConfig struct {
+	Students string `yaml:"students"` // список студентов
+	History  string `yaml:"history"`  // история проверок
+	Plan     string `yaml:"plan"`     // учебный план часов
+	Jobs     string `yaml:"jobs"`     // база очереди заданий
+	Uploads  string `yaml:"uploads"`  // папка загруженных через веб-интерфейс XLS-файлов
+	Webhooks string `yaml:"webhooks"` // журнал доставки уведомлений на внешний адрес
+	Alerts   string `yaml:"alerts"`   // оповещения и отметки о прочтении
+}
+
+// StorageConfig содержит выбор хранилища данных: YAML-файлы по путям из paths, SQLite или PostgreSQL
+type StorageConfig struct {
+	Backend  string            `yaml:"backend"`            // хранилище по умолчанию: yaml, sqlite или postgres
+	Data     map[string]string `yaml:"data,omitempty"`     // хранилище отдельных данных: students, history, plan, webhooks, alerts
+	SQLite   string            `yaml:"sqlite"`             // файл базы SQLite
+	Postgres string            `yaml:"postgres,omitempty"` // строка подключения к PostgreSQL
+}
+
+// BackendFor возвращает хранилище данных name: указанное в Data или хранилище по умолчанию
+func (c StorageConfig) BackendFor(name string) string {
+	if backend, ok := c.Data[name]; ok && backend != "" {
+		return backend
+	}
+	return c.Backend
+}
+
+// dsn возвращает строку подключения к базе хранилища backend
+func (c StorageConfig) dsn(backend string) string {
+	if backend == StoragePostgres {
+		return c.Postgres
+	}
+	return c.SQLite
+}
+
+// SiteConfig содержит настройки загрузки группового расписания с сайта вуза
+type SiteConfig struct {
+	ScheduleURL string        `yaml:"schedule_url"` // адрес сайта с модулем AutoRasp
+	AliasURL    string        `yaml:"alias_url"`    // страница расписания, выдающая cookie сессии
+	CacheTTL    time.Duration `yaml:"cache_ttl"`    // время хранения загруженного расписания групп
+	MaxParallel int           `yaml:"max_parallel"` // одновременных запросов за расписанием групп
+}
+
+// CheckConfig содержит настройки проверки расписания
+type CheckConfig struct {
+	MergePolicy string `yaml:"merge_policy"` // объединение половинок пары: join, prefer-individual, flag-as-warning
+}
+
+// SMTPConfig содержит настройки почтового сервера для рассылки студентам
+type SMTPConfig struct {
+	Addr     string `yaml:"addr"` // адрес "host:port", пусто — почта не отправляется
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"` // пусто — совпадает с User
+}
+
+// TelegramConfig содержит настройки бота Telegram для рассылки студентам
+type TelegramConfig struct {
+	Token string `yaml:"token"` // пусто — Telegram не используется
+}
+
+// WebhookConfig содержит настройки уведомлений о проверках на внешний адрес (например, портал вуза)
+type WebhookConfig struct {
+	URL      string        `yaml:"url"`      // адрес получателя, пусто — уведомления не отправляются
+	Secret   string        `yaml:"secret"`   // общий с получателем секрет подписи HMAC-SHA256
+	Attempts int           `yaml:"attempts"` // попыток доставки одного уведомления
+	Backoff  time.Duration `yaml:"backoff"`  // пауза перед повторной попыткой, дальше удваивается
+}
+
+// DefaultConfig возвращает настройки, действующие без config.yaml
+func DefaultConfig() Config {
+	return Config{
+		Port:      8060,
+		Templates: "templates",
+		Paths: PathsConfig{
+			Students: "students.yaml",
+			History:  "history.yaml",
+			Plan:     "plan.yaml",
+			Jobs:     "jobs.db",
+			Uploads:  "uploads",
+			Webhooks: "webhooks.yaml",
+			Alerts:   "alerts.yaml",
+		},
+		Storage: StorageConfig{Backend: StorageYAML, SQLite: "data.db"},
+		Site: SiteConfig{
+			ScheduleURL: scheduleURL,
+			AliasURL:    aliasURL,
+			CacheTTL:    groupCacheTTL,
+			MaxParallel: siteMaxParallel,
+		},
+		Check:   CheckConfig{MergePolicy: string(domain.MergePolicyJoin)},
+		Archive: ArchiveConfig{Dir: "archive"},
+		Updates: UpdatesConfig{Repo: "Vaflel/lesson-counter"},
+		Warmup:  WarmupConfig{At: "06:00", TTL: 4 * time.Hour},
+		Webhook: WebhookConfig{Attempts: 5, Backoff: 30 * time.Second},
+		Debug:   DebugConfig{Dir: "debug"},
+	}
+}
+
+// LoadConfig читает настройки из файла и применяет переопределения из переменных окружения.
+// Отсутствующий файл не является ошибкой: используются настройки по умолчанию.
+// Неизвестные ключи в файле считаются ошибкой, чтобы опечатка в настройке не терялась молча.
+func LoadConfig(filename string) (Config, error) {
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err == nil {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+		}
+	}
+
+	return config, config.applyEnv(os.LookupEnv)
+}
+
+// SaveConfig записывает настройки в файл
+func SaveConfig(filename string, config Config) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
+
+// applyEnv переопределяет настройки переменными окружения. Возвращает все ошибки разбора сразу.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+
+	texts := map[string]*string{
+		"LESSON_COUNTER_TEMPLATES":      &c.Templates,
+		"LESSON_COUNTER_TZ":             &c.Timezone,
+		"LESSON_COUNTER_STUDENTS":       &c.Paths.Students,
+		"LESSON_COUNTER_HISTORY":        &c.Paths.History,
+		"LESSON_COUNTER_PLAN":           &c.Paths.Plan,
+		"LESSON_COUNTER_JOBS":           &c.Paths.Jobs,
+		"LESSON_COUNTER_UPLOADS":        &c.Paths.Uploads,
+		"LESSON_COUNTER_SCHEDULE_URL":   &c.Site.ScheduleURL,
+		"LESSON_COUNTER_ALIAS_URL":      &c.Site.AliasURL,
+		"LESSON_COUNTER_MERGE_POLICY":   &c.Check.MergePolicy,
+		"LESSON_COUNTER_SMTP_ADDR":      &c.SMTP.Addr,
+		"LESSON_COUNTER_SMTP_USER":      &c.SMTP.User,
+		"LESSON_COUNTER_SMTP_PASSWORD":  &c.SMTP.Password,
+		"LESSON_COUNTER_SMTP_FROM":      &c.SMTP.From,
+		"LESSON_COUNTER_ARCHIVE_DIR":    &c.Archive.Dir,
+		"LESSON_COUNTER_DEBUG_DIR":      &c.Debug.Dir,
+		"LESSON_COUNTER_TELEGRAM_TOKEN": &c.Telegram.Token,
+		"LESSON_COUNTER_WEBHOOK_URL":    &c.Webhook.URL,
+		"LESSON_COUNTER_WEBHOOK_SECRET": &c.Webhook.Secret,
+		"LESSON_COUNTER_WEBHOOKS":       &c.Paths.Webhooks,
+		"LESSON_COUNTER_ALERTS":         &c.Paths.Alerts,
+		"LESSON_COUNTER_STORAGE":        &c.Storage.Backend,
+		"LESSON_COUNTER_SQLITE":         &c.Storage.SQLite,
+		"LESSON_COUNTER_POSTGRES":       &c.Storage.Postgres,
+	}
+	for name, field := range texts {
+		if value, ok := lookup(name); ok && value != "" {
+			*field = value
+		}
+	}
+
+	// Числа и флаги перечислены срезами, а не картами: ошибки нескольких переменных
+	// выводятся всегда в одном порядке
+	ints := []struct {
+		name  string
+		field *int
+	}{
+		{"LESSON_COUNTER_PORT", &c.Port},
+		{"LESSON_COUNTER_GRPC_PORT", &c.GRPCPort},
+		{"LESSON_COUNTER_MAX_PARALLEL", &c.Site.MaxParallel},
+	}
+	for _, i := range ints {
+		if value, ok := lookup(i.name); ok && value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: ожидается число, получено %q", i.name, value))
+				continue
+			}
+			*i.field = n
+		}
+	}
+
+	if value, ok := lookup("LESSON_COUNTER_CACHE_TTL"); ok && value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("LESSON_COUNTER_CACHE_TTL: ожидается длительность (например, 30m), получено %q", value))
+		} else {
+			c.Site.CacheTTL = ttl
+		}
+	}
+
+	bools := []struct {
+		name  string
+		field *bool
+	}{
+		{"LESSON_COUNTER_ARCHIVE", &c.Archive.Enabled},
+		{"LESSON_COUNTER_UPDATE_CHECK", &c.Updates.Check},
+		{"LESSON_COUNTER_WARMUP", &c.Warmup.Enabled},
+		{"LESSON_COUNTER_DEBUG", &c.Debug.Enabled},
+		{"LESSON_COUNTER_RETENTION", &c.Retention.Enabled},
+		{"LESSON_COUNTER_TRAY", &c.Tray},
+	}
+	for _, b := range bools {
+		if value, ok := lookup(b.name); ok && value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: ожидается true или false, получено %q", b.name, value))
+				continue
+			}
+			*b.field = enabled
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate проверяет настройки и возвращает все найденные ошибки сразу
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		add("port: порт должен быть от 1 до 65535, указан %d", c.Port)
+	}
+	if c.GRPCPort < 0 || c.GRPCPort > 65535 {
+		add("grpc_port: порт должен быть от 0 до 65535, указан %d", c.GRPCPort)
+	} else if c.GRPCPort == c.Port {
+		add("grpc_port: совпадает с портом веб-интерфейса %d", c.Port)
+	}
+	if _, err := c.Location(); err != nil {
+		add("timezone: %v", err)
+	}
+
+	paths := []struct{ name, value string }{
+		{"paths.students", c.Paths.Students},
+		{"paths.history", c.Paths.History},
+		{"paths.plan", c.Paths.Plan},
+		{"paths.jobs", c.Paths.Jobs},
+		{"paths.uploads", c.Paths.Uploads},
+		{"paths.webhooks", c.Paths.Webhooks},
+		{"paths.alerts", c.Paths.Alerts},
+	}
+	for _, p := range paths {
+		if p.value == "" {
+			add("%s: путь не указан", p.name)
+		}
+	}
+
+	backends := []string{StorageYAML, StorageSQLite, StoragePostgres}
+	if !slices.Contains(backends, c.Storage.Backend) {
+		add("storage.backend: ожидается yaml, sqlite или postgres, указано %q", c.Storage.Backend)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Storage.Data)) {
+		backend := c.Storage.Data[name]
+		if !slices.Contains(storageData, name) {
+			add("storage.data: неизвестные данные %q, доступны %s", name, strings.Join(storageData, ", "))
+		} else if !slices.Contains(backends, backend) {
+			add("storage.data.%s: ожидается yaml, sqlite или postgres, указано %q", name, backend)
+		}
+	}
+	used := make(map[string]bool)
+	for _, name := range storageData {
+		used[c.Storage.BackendFor(name)] = true
+	}
+	if used[StorageSQLite] && c.Storage.SQLite == "" {
+		add("storage.sqlite: не указан файл базы SQLite")
+	}
+	if used[StoragePostgres] && c.Storage.Postgres == "" {
+		add("storage.postgres: не указана строка подключения к PostgreSQL")
+	}
+
+	for _, u := range []struct{ name, value string }{
+		{"site.schedule_url", c.Site.ScheduleURL},
+		{"site.alias_url", c.Site.AliasURL},
+	} {
+		parsed, err := url.Parse(u.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("%s: ожидается адрес http(s), указан %q", u.name, u.value)
+		}
+	}
+	if c.Site.CacheTTL <= 0 {
+		add("site.cache_ttl: время хранения должно быть больше нуля, указано %v", c.Site.CacheTTL)
+	}
+	if c.Site.MaxParallel < 1 {
+		add("site.max_parallel: одновременных запросов должно быть не меньше 1, указано %d", c.Site.MaxParallel)
+	}
+
+	if _, err := c.MergePolicy(); err != nil {
+		add("check.merge_policy: %v", err)
+	}
+
+	if c.Archive.Enabled && c.Archive.Dir == "" {
+		add("archive.dir: не указан каталог архива")
+	}
+
+	if c.Debug.Enabled && c.Debug.Dir == "" {
+		add("debug.dir: не указан каталог отладочных данных")
+	}
+
+	if c.Warmup.Enabled {
+		if _, err := c.Warmup.Time(); err != nil {
+			add("warmup.at: %v", err)
+		}
+		if c.Warmup.TTL <= 0 {
+			add("warmup.ttl: время хранения должно быть больше нуля, указано %v", c.Warmup.TTL)
+		}
+	}
+
+	for _, months := range []struct {
+		name  string
+		value int
+	}{
+		{"retention.history_months", c.Retention.HistoryMonths},
+		{"retention.debug_months", c.Retention.DebugMonths},
+		{"retention.archive_months", c.Retention.ArchiveMonths},
+	} {
+		if months.value < 0 {
+			add("%s: срок хранения не может быть отрицательным, указано %d", months.name, months.value)
+		}
+	}
+
+	if c.Updates.Check {
+		if owner, name, ok := strings.Cut(c.Updates.Repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			add("updates.repo: ожидается репозиторий вида владелец/имя, указан %q", c.Updates.Repo)
+		}
+	}
+
+	if c.SMTP.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.SMTP.Addr); err != nil {
+			add("smtp.addr: ожидается адрес вида host:port, указан %q", c.SMTP.Addr)
+		}
+	} else if c.SMTP.User != "" || c.SMTP.Password != "" || c.SMTP.From != "" {
+		add("smtp.addr: не указан адрес сервера, хотя заданы другие настройки почты")
+	}
+
+	if c.Webhook.URL != "" {
+		parsed, err := url.Parse(c.Webhook.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add("webhook.url: ожидается адрес http(s), указан %q", c.Webhook.URL)
+		}
+		if c.Webhook.Secret == "" {
+			add("webhook.secret: не указан секрет подписи уведомлений")
+		}
+		if c.Webhook.Attempts < 1 {
+			add("webhook.attempts: попыток должно быть не меньше 1, указано %d", c.Webhook.Attempts)
+		}
+		if c.Webhook.Backoff < 0 {
+			add("webhook.backoff: пауза не может быть отрицательной, указано %v", c.Webhook.Backoff)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Location возвращает часовой пояс расписания; пустая настройка — nil (пояс по умолчанию)
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("неизвестный часовой пояс %q", c.Timezone)
+	}
+	return loc, nil
+}
+
+// MergePolicy возвращает политику объединения половинок пары
+func (c Config) MergePolicy() (domain.MergePolicy, error) {
+	return domain.ParseMergePolicy(c.Check.MergePolicy)
+}
+
+// SMTPSender возвращает отправителя почты по настройкам или nil, если почта не настроена
+func (c Config) SMTPSender() *SMTPSender {
+	if c.SMTP.Addr == "" {
+		return nil
+	}
+	from := c.SMTP.From
+	if from == "" {
+		from = c.SMTP.User
+	}
+	return NewSMTPSender(c.SMTP.Addr, c.SMTP.User, c.SMTP.Password, from)
+}
+
+// WebhookSender возвращает отправителя уведомлений на внешний адрес или nil, если адрес не задан
+func (c Config) WebhookSender() *WebhookSender {
+	if c.Webhook.URL == "" {
+		return nil
+	}
+	return NewWebhookSender(c.Webhook.URL, c.Webhook.Secret)
+}
+
+// TelegramSender возвращает отправителя Telegram по настройкам или nil, если бот не настроен
+func (c Config) TelegramSender() *TelegramSender {
+	if c.Telegram.Token == "" {
+		return nil
+	}
+	return NewTelegramSender(c.Telegram.Token)
+}
+
+// Apply применяет настройки загрузки расписания с сайта вуза
+func (c Config) Apply() {
+	SetScheduleSite(c.Site.ScheduleURL, c.Site.AliasURL)
+	SetGroupCacheTTL(c.Site.CacheTTL)
+	SetSiteMaxParallel(c.Site.MaxParallel)
+}
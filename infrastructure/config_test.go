@@ -0,0 +1,32 @@
+package infrastructure
+
+import "testing"
+
+func TestConfigApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"LESSON_COUNTER_PORT":      "8070",
+		"LESSON_COUNTER_GRPC_PORT": "девять",
+		"LESSON_COUNTER_ARCHIVE":   "да",
+		"LESSON_COUNTER_DEBUG":     "true",
+		"LESSON_COUNTER_TRAY":      "нет",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	// Ошибки нескольких переменных выводятся всегда в порядке их описания
+	const want = `LESSON_COUNTER_GRPC_PORT: ожидается число, получено "девять"
+LESSON_COUNTER_ARCHIVE: ожидается true или false, получено "да"
+LESSON_COUNTER_TRAY: ожидается true или false, получено "нет"`
+	for i := 0; i < 20; i++ {
+		config := DefaultConfig()
+		err := config.applyEnv(lookup)
+		if err == nil || err.Error() != want {
+			t.Fatalf("ошибка:\n%v\nожидалось:\n%s", err, want)
+		}
+		if config.Port != 8070 || !config.Debug.Enabled {
+			t.Errorf("port = %d, debug = %v: корректные переменные не применены", config.Port, config.Debug.Enabled)
+		}
+	}
+}
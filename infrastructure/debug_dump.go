@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/extrame/xls"
+)
+
+// DebugDump записывает промежуточные данные разбора XLS-файлов в каталог одного запуска
+// проверки: исходные ячейки каждого блока преподавателя и списки занятий до и после
+// объединения половинок пар. По ним можно выяснить, на каком этапе потерялось занятие.
+// Методы nil-безопасны: без отладки парсер получает nil и ничего не записывает.
+type DebugDump struct {
+	dir string
+}
+
+// NewDebugDump создаёт каталог dir для отладочных данных запуска
+func NewDebugDump(dir string) (*DebugDump, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог отладки: %w", err)
+	}
+	return &DebugDump{dir: dir}, nil
+}
+
+// Dir возвращает каталог отладочных данных запуска
+func (d *DebugDump) Dir() string {
+	return d.dir
+}
+
+// Cells записывает в <файл>.cells.txt непустые ячейки каждого блока преподавателя листа:
+// от строки «Преподаватель» до начала следующего блока. Ячейки и строки подписаны так же,
+// как в Excel (B12 — столбец B, строка 12).
+func (d *DebugDump) Cells(file string, sheet *xls.WorkSheet, teacherRows []int) {
+	if d == nil {
+		return
+	}
+	var b strings.Builder
+	for i, start := range teacherRows {
+		end := int(sheet.MaxRow)
+		if i+1 < len(teacherRows) {
+			end = teacherRows[i+1] - 1
+		}
+		fmt.Fprintf(&b, "=== Блок преподавателя: строки %d–%d ===\n", start+1, end+1)
+		for row := start; row <= end; row++ {
+			for col, value := range rowCells(sheet, row) {
+				if value = strings.TrimSpace(value); value != "" {
+					fmt.Fprintf(&b, "%s: %s\n", cellName(row, col), strings.ReplaceAll(value, "\n", `\n`))
+				}
+			}
+		}
+		b.WriteString("\n")
+	}
+	d.write(filepath.Base(file)+".cells.txt", b.String(), false)
+}
+
+// Lessons дописывает занятия в таблицу name (через табуляцию, открывается в Excel).
+// file — XLS-файл, из которого получены занятия, пусто — занятия из разных файлов.
+func (d *DebugDump) Lessons(name, file string, lessons []domain.Lesson) {
+	if d == nil {
+		return
+	}
+	var b strings.Builder
+	if _, err := os.Stat(filepath.Join(d.dir, name)); err != nil {
+		b.WriteString("Файл\tДата\tПара\tПоловина\tПреподаватели\tДисциплина\tКабинет\tГруппа\tСтудент\n")
+	}
+	for _, lesson := range lessons {
+		fields := []string{
+			filepath.Base(file),
+			lesson.Time.DateString(),
+			strconv.Itoa(lesson.Time.Number),
+			strconv.Itoa(lesson.Time.PairHalf),
+			lesson.TeacherNames(),
+			lesson.Discipline,
+			lesson.Cabinet,
+			lesson.Group,
+			lesson.Student,
+		}
+		if file == "" {
+			fields[0] = ""
+		}
+		b.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	d.write(name, b.String(), true)
+}
+
+// write записывает или дописывает файл; ошибки только журналируются, чтобы отладка
+// не мешала проверке
+func (d *DebugDump) write(name, content string, appendTo bool) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendTo {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(filepath.Join(d.dir, name), flags, 0644)
+	if err == nil {
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("Не удалось записать отладочный файл %s: %v", name, err)
+	}
+}
+
+// cellName возвращает адрес ячейки в обозначениях Excel по номерам строки и столбца с нуля
+func cellName(row, col int) string {
+	var letters []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		letters = append([]byte{byte('A' + (col-1)%26)}, letters...)
+	}
+	return string(letters) + strconv.Itoa(row+1)
+}
+
+// rowCells возвращает ячейки строки листа; отсутствующая в файле строка даёт nil
+func rowCells(sheet *xls.WorkSheet, row int) (cells []string) {
+	defer func() {
+		if recover() != nil {
+			cells = nil
+		}
+	}()
+	r := sheet.Row(row)
+	for col := 0; col <= r.LastCol(); col++ {
+		cells = append(cells, r.Col(col))
+	}
+	return cells
+}
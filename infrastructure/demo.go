@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// demoFiles содержит демонстрационные данные: студентов, отделения и расписание
+//
+//go:embed demo/students.yaml demo/departments.yaml demo/lessons.yaml
+var demoFiles embed.FS
+
+// demoLessonsConfig структура файла демонстрационного расписания
+type demoLessonsConfig struct {
+	Lessons []demoLesson `yaml:"lessons"`
+}
+
+// demoLesson описывает занятие демонстрационного расписания относительно начала недели
+type demoLesson struct {
+	Day        int    `yaml:"day"`
+	Number     int    `yaml:"number"`
+	Half       int    `yaml:"half"`
+	Discipline string `yaml:"discipline"`
+	Teacher    string `yaml:"teacher"`
+	Cabinet    string `yaml:"cabinet"`
+	Group      string `yaml:"group"`
+	Student    string `yaml:"student"`
+}
+
+// PrepareDemoDir создаёт временный каталог с демонстрационными students.yaml и departments.yaml.
+// Программа в демо-режиме работает в этом каталоге, чтобы не затрагивать настоящие файлы.
+func PrepareDemoDir() (string, error) {
+	dir, err := os.MkdirTemp("", "lesson-counter-demo-")
+	if err != nil {
+		return "", fmt.Errorf("не удалось создать каталог демо-режима: %w", err)
+	}
+
+	for _, name := range []string{"students.yaml", "departments.yaml"} {
+		data, err := fs.ReadFile(demoFiles, "demo/"+name)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return "", fmt.Errorf("не удалось записать %s: %w", name, err)
+		}
+	}
+	return dir, nil
+}
+
+// DemoLessonsRepository отдаёт встроенное демонстрационное расписание на любую неделю
+// без обращения к сайту и XLS-файлам
+type DemoLessonsRepository struct {
+	week domain.Week
+}
+
+// NewDemoLessonsRepository создаёт репозиторий демонстрационного расписания на неделю
+func NewDemoLessonsRepository(week domain.Week) *DemoLessonsRepository {
+	return &DemoLessonsRepository{week: week}
+}
+
+// GetLessons возвращает демонстрационные занятия, перенесённые на неделю репозитория
+func (r *DemoLessonsRepository) GetLessons() ([]domain.Lesson, error) {
+	data, err := fs.ReadFile(demoFiles, "demo/lessons.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var config demoLessonsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("не удалось распарсить демонстрационное расписание: %w", err)
+	}
+
+	times := NewIndividualScheduleParser(nil)
+	lessons := make([]domain.Lesson, 0, len(config.Lessons))
+	for _, l := range config.Lessons {
+		date := r.week.Start().AddDate(0, 0, l.Day-1)
+		start, end := times.parsePairTime(date, l.Number)
+		hours := 2
+		if l.Half > 0 {
+			hours = 1
+		}
+
+		lesson := domain.Lesson{
+			Time:       domain.NewLessonTime(date, l.Number, l.Half, hours, start, end),
+			Discipline: l.Discipline,
+			Teachers:   []domain.Teacher{domain.NewTeacher(l.Teacher)},
+			Cabinet:    l.Cabinet,
+			Group:      l.Group,
+			Student:    l.Student,
+			Source:     domain.SourceGroup,
+		}
+		if l.Student != "" {
+			lesson.Source = domain.SourceIndividual
+		}
+		lesson.ID = lesson.ComputeID()
+		lessons = append(lessons, lesson)
+	}
+	return lessons, nil
+}
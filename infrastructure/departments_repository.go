@@ -0,0 +1,159 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// DepartmentsConfig структура для загрузки из YAML
+type DepartmentsConfig struct {
+	Departments []domain.Department `yaml:"departments"`
+}
+
+// YAMLDepartmentRepository реализует DepartmentRepository для работы с YAML-файлом отделений
+type YAMLDepartmentRepository struct {
+	filename string
+	mutex    sync.RWMutex
+}
+
+// NewYAMLDepartmentRepository создает новый экземпляр репозитория
+func NewYAMLDepartmentRepository(filename string) *YAMLDepartmentRepository {
+	return &YAMLDepartmentRepository{
+		filename: filename,
+	}
+}
+
+// LoadDepartments загружает список отделений из YAML файла.
+// Если файла нет, возвращается пустой список.
+func (r *YAMLDepartmentRepository) LoadDepartments() ([]domain.Department, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.loadDepartmentsUnsafe()
+}
+
+// AddDepartment добавляет новое отделение в YAML файл
+func (r *YAMLDepartmentRepository) AddDepartment(department domain.Department) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Загружаем текущий список отделений
+	departments, err := r.loadDepartmentsUnsafe()
+	if err != nil {
+		return err
+	}
+
+	// Проверяем, нет ли уже отделения с таким названием
+	for _, s := range departments {
+		if s.Name == department.Name {
+			return fmt.Errorf("отделение %s уже существует", department.Name)
+		}
+	}
+
+	// Добавляем новое отделение
+	departments = append(departments, department)
+
+	// Сохраняем обновленный список
+	return r.saveDepartmentsUnsafe(departments)
+}
+
+// GetDepartment возвращает отделение по названию
+func (r *YAMLDepartmentRepository) GetDepartment(name string) (domain.Department, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	departments, err := r.loadDepartmentsUnsafe()
+	if err != nil {
+		return domain.Department{}, err
+	}
+
+	for _, s := range departments {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return domain.Department{}, fmt.Errorf("отделение %s не найдено", name)
+}
+
+// UpdateDepartment обновляет данные отделения
+func (r *YAMLDepartmentRepository) UpdateDepartment(name string, updated domain.Department) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Загружаем текущий список отделений
+	departments, err := r.loadDepartmentsUnsafe()
+	if err != nil {
+		return err
+	}
+
+	// Ищем отделение для обновления
+	for i, s := range departments {
+		if s.Name == name {
+			departments[i] = updated
+			return r.saveDepartmentsUnsafe(departments)
+		}
+	}
+
+	return fmt.Errorf("отделение %s не найдено", name)
+}
+
+// DeleteDepartment удаляет отделение по названию
+func (r *YAMLDepartmentRepository) DeleteDepartment(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Загружаем текущий список отделений
+	departments, err := r.loadDepartmentsUnsafe()
+	if err != nil {
+		return err
+	}
+
+	// Ищем и удаляем отделение
+	for i, s := range departments {
+		if s.Name == name {
+			departments = append(departments[:i], departments[i+1:]...)
+			return r.saveDepartmentsUnsafe(departments)
+		}
+	}
+
+	return fmt.Errorf("отделение %s не найдено", name)
+}
+
+// loadDepartmentsUnsafe загружает отделения без блокировки (внутренний метод)
+func (r *YAMLDepartmentRepository) loadDepartmentsUnsafe() ([]domain.Department, error) {
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	var config DepartmentsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+
+	return config.Departments, nil
+}
+
+// saveDepartmentsUnsafe сохраняет отделения в YAML файл без блокировки (внутренний метод)
+func (r *YAMLDepartmentRepository) saveDepartmentsUnsafe(departments []domain.Department) error {
+	config := DepartmentsConfig{Departments: departments}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+
+	return nil
+}
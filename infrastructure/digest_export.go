@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"io"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// ExportDigestXLSX записывает сводку нарушений недель с first по last по группам в формате XLSX
+// на языке lang: отдельный лист для каждой группы, чтобы куратор мог распечатать свою
+func ExportDigestXLSX(w io.Writer, first, last domain.Week, digest []domain.GroupDigest, lang domain.Lang) error {
+	period := first.Start().Format("02.01.2006") + " – " + last.End().Format("02.01.2006")
+	if len(digest) == 0 {
+		return WriteXLSX(w, XLSXSheet{Name: lang.T("Сводка"), Rows: [][]any{
+			{lang.Tf("Сводка нарушений по группам за %s", period)},
+			{},
+			{lang.T("Нарушений не найдено")},
+		}})
+	}
+
+	sheets := make([]XLSXSheet, 0, len(digest))
+	for _, group := range digest {
+		rows := [][]any{
+			{lang.Tf("Группа %s: нарушения за %s", group.Group, period)},
+			{},
+			{lang.T("Студент"), lang.T("Курс"), lang.T("День"), lang.T("Дата"), lang.T("Нарушение"), lang.T("Ак.ч")},
+		}
+		for _, student := range group.Students {
+			for _, v := range student.Violations {
+				t := domain.LessonTime{Date: v.Date}
+				rows = append(rows, []any{student.Student, student.Year, lang.T(t.DayName()), t.DateString(), v.TitleIn(lang), v.Hours})
+			}
+		}
+		rows = append(rows, []any{}, []any{lang.T("Всего нарушений"), nil, nil, nil, nil, group.Total})
+		sheets = append(sheets, XLSXSheet{Name: group.Group, Rows: rows})
+	}
+	return WriteXLSX(w, sheets...)
+}
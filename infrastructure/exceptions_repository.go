@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// ExceptionsConfig структура файла допущенных исключений
+type ExceptionsConfig struct {
+	Exceptions []ExceptionYAML `yaml:"exceptions"`
+}
+
+// ExceptionYAML представление domain.Exception в YAML. День недели задаётся
+// английским названием (monday, tuesday, ...), дата окончания — в формате 2006-01-02.
+type ExceptionYAML struct {
+	Student    string               `yaml:"student"`
+	Kind       domain.ViolationKind `yaml:"kind"`
+	Rule       string               `yaml:"rule,omitempty"`
+	Weekday    string               `yaml:"weekday"`
+	Until      string               `yaml:"until"`
+	Reason     string               `yaml:"reason,omitempty"`
+	ApprovedBy string               `yaml:"approved_by,omitempty"`
+}
+
+// YAMLExceptionRepository хранит допущенные исключения в YAML-файле
+type YAMLExceptionRepository struct {
+	filename string
+	mutex    sync.RWMutex
+}
+
+// NewYAMLExceptionRepository создает новый экземпляр репозитория исключений
+func NewYAMLExceptionRepository(filename string) *YAMLExceptionRepository {
+	return &YAMLExceptionRepository{
+		filename: filename,
+	}
+}
+
+// LoadExceptions загружает все исключения, включая истёкшие. Отсутствие файла не считается ошибкой.
+func (r *YAMLExceptionRepository) LoadExceptions() ([]domain.Exception, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions := make([]domain.Exception, 0, len(config.Exceptions))
+	for _, entry := range config.Exceptions {
+		exception, err := entry.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, nil
+}
+
+// AddException добавляет исключение. Повторное добавление того же исключения не дублирует запись.
+func (r *YAMLExceptionRepository) AddException(exception domain.Exception) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range config.Exceptions {
+		existing, err := entry.toDomain()
+		if err == nil && existing.ID() == exception.ID() {
+			return nil
+		}
+	}
+
+	config.Exceptions = append(config.Exceptions, exceptionToYAML(exception))
+	return r.saveUnsafe(config)
+}
+
+// DeleteException удаляет исключение по идентификатору
+func (r *YAMLExceptionRepository) DeleteException(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range config.Exceptions {
+		exception, err := entry.toDomain()
+		if err == nil && exception.ID() == id {
+			config.Exceptions = append(config.Exceptions[:i], config.Exceptions[i+1:]...)
+			return r.saveUnsafe(config)
+		}
+	}
+	return fmt.Errorf("исключение %s не найдено", id)
+}
+
+// RenameStudent переносит исключения студента from на студента to. Исключения, которые
+// у to уже есть, не дублируются. Возвращает количество перенесённых исключений.
+func (r *YAMLExceptionRepository) RenameStudent(from, to string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	seen := make(map[string]bool)
+	exceptions := config.Exceptions[:0]
+	for _, entry := range config.Exceptions {
+		if entry.Student == from {
+			entry.Student = to
+			changed++
+		}
+		if exception, err := entry.toDomain(); err == nil {
+			if seen[exception.ID()] {
+				continue
+			}
+			seen[exception.ID()] = true
+		}
+		exceptions = append(exceptions, entry)
+	}
+	config.Exceptions = exceptions
+
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, r.saveUnsafe(config)
+}
+
+func (e ExceptionYAML) toDomain() (domain.Exception, error) {
+	weekday, ok := weekdayByKey(e.Weekday)
+	if !ok {
+		return domain.Exception{}, fmt.Errorf("исключение для %s: неизвестный день недели %q", e.Student, e.Weekday)
+	}
+	until, err := parseConfigDate(e.Until)
+	if err != nil {
+		return domain.Exception{}, fmt.Errorf("исключение для %s: неверная дата окончания %q", e.Student, e.Until)
+	}
+	return domain.Exception{
+		StudentName: e.Student,
+		Kind:        e.Kind,
+		Rule:        e.Rule,
+		Weekday:     weekday,
+		Until:       until,
+		Reason:      e.Reason,
+		ApprovedBy:  e.ApprovedBy,
+	}, nil
+}
+
+// loadUnsafe читает файл исключений без блокировки (внутренний метод)
+func (r *YAMLExceptionRepository) loadUnsafe() (ExceptionsConfig, error) {
+	var config ExceptionsConfig
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
+
+// saveUnsafe записывает файл исключений без блокировки (внутренний метод)
+func (r *YAMLExceptionRepository) saveUnsafe(config ExceptionsConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
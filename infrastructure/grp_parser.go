@@ -8,7 +8,10 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -37,30 +40,167 @@ type LessonListResponse struct {
 	LessonList map[string]LessonData `json:"LessonList"`
 }
 
-const (
+// Адреса сайта вуза; меняются через SetScheduleSite
+var (
 	scheduleURL = "https://sspi.ru/"
 	aliasURL    = "https://sspi.ru/?alias=429"
 )
 
+// SetScheduleSite задаёт адрес сайта с модулем AutoRasp (с завершающим "/") и страницу
+// расписания, выдающую cookie сессии. Пустые значения игнорируются
+func SetScheduleSite(siteURL, aliasPageURL string) {
+	if siteURL != "" {
+		if !strings.HasSuffix(siteURL, "/") {
+			siteURL += "/"
+		}
+		scheduleURL = siteURL
+	}
+	if aliasPageURL != "" {
+		aliasURL = aliasPageURL
+	}
+
+	siteDepartments.mu.Lock()
+	siteDepartments.expiry = time.Time{}
+	siteDepartments.mu.Unlock()
+
+	siteGroupIDs.mu.Lock()
+	siteGroupIDs.departments = nil
+	siteGroupIDs.mu.Unlock()
+}
+
+// CheckScheduleSite проверяет, что страница расписания на сайте вуза открывается
+func CheckScheduleSite() error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(aliasURL)
+	if err != nil {
+		return fmt.Errorf("сайт %s недоступен: %w", aliasURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("сайт %s ответил %s", aliasURL, resp.Status)
+	}
+	return nil
+}
+
+// SiteDepartment — отделение на сайте расписания со списком его групп
+type SiteDepartment struct {
+	Name   string
+	ID     string
+	Groups []string
+}
+
+// siteDepartmentsRetry — через сколько повторять загрузку отделений после ошибки, чтобы
+// недоступный сайт не задерживал каждую страницу
+const siteDepartmentsRetry = time.Minute
+
+// siteDepartments — кэш отделений и групп сайта для CachedSiteDepartments
+var siteDepartments struct {
+	mu          sync.Mutex
+	departments []SiteDepartment
+	err         error
+	expiry      time.Time
+}
+
+// siteGroupIDs — кэш идентификаторов групп по отделениям сайта. Список групп один для всех
+// недель, поэтому проверки нескольких недель подряд и группы одного отделения загружают его
+// с сайта один раз за groupCacheTTL.
+var siteGroupIDs struct {
+	mu          sync.Mutex
+	departments map[string]cachedGroupIDs // по идентификатору отделения
+}
+
+// cachedGroupIDs — идентификаторы групп отделения по названиям и время их загрузки
+type cachedGroupIDs struct {
+	groups map[string]string
+	loaded time.Time
+}
+
+// CachedSiteDepartments возвращает отделения и группы сайта, загружая их не чаще раза
+// в groupCacheTTL. Ошибка загрузки запоминается на siteDepartmentsRetry.
+func CachedSiteDepartments() ([]SiteDepartment, error) {
+	siteDepartments.mu.Lock()
+	defer siteDepartments.mu.Unlock()
+	if time.Now().Before(siteDepartments.expiry) {
+		return siteDepartments.departments, siteDepartments.err
+	}
+
+	departments, err := LoadSiteDepartments()
+	ttl := groupCacheTTL
+	if err != nil {
+		ttl = siteDepartmentsRetry
+	}
+	siteDepartments.departments, siteDepartments.err = departments, err
+	siteDepartments.expiry = time.Now().Add(ttl)
+	return departments, err
+}
+
+// LoadSiteDepartments загружает с сайта расписания все отделения и их группы,
+// упорядоченные по названию
+func LoadSiteDepartments() ([]SiteDepartment, error) {
+	gsp := NewGroupScheduleParser(domain.Department{}, "", domain.Week{})
+	gsp.client.Timeout = 30 * time.Second
+	departments, err := gsp.fetchDepartments()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить отделения с сайта: %w", err)
+	}
+	if len(departments) == 0 {
+		return nil, fmt.Errorf("на странице %s не найден список отделений", aliasURL)
+	}
+
+	var result []SiteDepartment
+	for name, id := range departments {
+		groups, err := gsp.fetchGroups(id)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить группы отделения %s: %w", name, err)
+		}
+		department := SiteDepartment{Name: name, ID: id}
+		for group := range groups {
+			department.Groups = append(department.Groups, group)
+		}
+		sort.Strings(department.Groups)
+		result = append(result, department)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GroupNotFoundError сообщает, что список групп отделения получен с сайта, но искомой группы в нём нет
+type GroupNotFoundError struct {
+	Group      string
+	Department string
+	Known      []string // группы отделения на сайте
+}
+
+func (e *GroupNotFoundError) Error() string {
+	return fmt.Sprintf("group '%s' not found", e.Group)
+}
+
 // GroupScheduleParser обрабатывает парсинг группового расписания
 type GroupScheduleParser struct {
-	departmentName string
-	groupName      string
-	weekStart      string
-	client         *http.Client
+	department domain.Department
+	groupName  string
+	week       domain.Week
+	weekNumber int // номер недели по учебному календарю, 0 — не задан
+	client     *http.Client
 }
 
 // NewGroupScheduleParser создаёт новый экземпляр парсера
-func NewGroupScheduleParser(departmentName, groupName, weekStart string) *GroupScheduleParser {
+func NewGroupScheduleParser(department domain.Department, groupName string, week domain.Week) *GroupScheduleParser {
 	jar, _ := cookiejar.New(nil)
 	return &GroupScheduleParser{
-		departmentName: departmentName,
-		groupName:      groupName,
-		weekStart:      weekStart,
-		client:         &http.Client{Jar: jar},
+		department: department,
+		groupName:  groupName,
+		week:       week,
+		client:     &http.Client{Jar: jar},
 	}
 }
 
+// SetWeekNumber задаёт номер недели по учебному календарю, который передаётся сайту
+// вместо даты начала недели
+func (gsp *GroupScheduleParser) SetWeekNumber(n int) {
+	gsp.weekNumber = n
+}
+
 func (gsp *GroupScheduleParser) createRequest(method, url string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
@@ -165,8 +305,13 @@ func (gsp *GroupScheduleParser) fetchLessons(groupID string) ([]LessonData, erro
 	if err := writer.WriteField("GroupId", groupID); err != nil {
 		return nil, err
 	}
-	if gsp.weekStart != "" {
-		if err := writer.WriteField("WeekNum", gsp.weekStart); err != nil {
+	switch {
+	case gsp.weekNumber > 0:
+		if err := writer.WriteField("WeekNum", strconv.Itoa(gsp.weekNumber)); err != nil {
+			return nil, err
+		}
+	case !gsp.week.IsZero():
+		if err := writer.WriteField("WeekNum", gsp.week.String()); err != nil {
 			return nil, err
 		}
 	}
@@ -216,122 +361,128 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen] + "..."
 }
 
+// deduplicateLessons преобразует данные API в уроки, пропуская индивидуальные занятия
+// и повторы одной и той же пары. Уроки подгрупп остаются отдельными записями с
+// заполненным полем Subgroup — объединять их или нет, решают валидатор и отчёт.
+// Повтором считается запись того же слота группы и подгруппы, даже с другой дисциплиной
+// или преподавателем: из расписания группы берётся первая запись пары.
 func deduplicateLessons(lessonsData []LessonData) []domain.Lesson {
-	// Группируем уроки по дате, номеру пары и подгруппе
-	type lessonKey struct {
-		date      string
-		lessonNum int
-	}
-	groups := make(map[lessonKey][]LessonData)
+	seen := make(map[string]bool)
+	var result []domain.Lesson
 
 	for _, data := range lessonsData {
 		if data.DiscName == "Индивидуальные занятия" {
 			continue
 		}
-		key := lessonKey{date: data.WeekDayDate, lessonNum: data.LessonNum}
-		groups[key] = append(groups[key], data)
-	}
-
-	var result []domain.Lesson
-	for _, group := range groups {
-		if len(group) == 1 && group[0].DiscSubgroup == "0" {
-			// Одиночный урок без подгруппы
-			data := group[0]
-			lesson := createLesson(data)
-			result = append(result, lesson)
-		} else {
-			// Объединяем уроки с подгруппами
-			mergedLessons := mergeLessons(group)
-			lesson := createLesson(mergedLessons)
-			result = append(result, lesson)
+		lesson := createLesson(data)
+		slot := fmt.Sprintf("%s|%s|%s|%d", lesson.Group, lesson.Subgroup, lesson.Time.DateString(), lesson.Time.Number)
+		if seen[slot] {
+			continue
 		}
+		seen[slot] = true
+		result = append(result, lesson)
 	}
 
 	return result
 }
 
 func createLesson(data LessonData) domain.Lesson {
-	date, _ := time.Parse("2006-01-02", data.WeekDayDate)
-	startTime, _ := time.Parse("15:04", data.LessonTimeStart)
-	endTime, _ := time.Parse("15:04", data.LessonTimeEnd)
+	date, _ := time.ParseInLocation("2006-01-02", data.WeekDayDate, domain.Location())
+	startTime, _ := time.ParseInLocation("15:04", data.LessonTimeStart, domain.Location())
+	endTime, _ := time.ParseInLocation("15:04", data.LessonTimeEnd, domain.Location())
 
 	discipline := data.DiscName
 	if data.DiscType != "" {
 		discipline = fmt.Sprintf("%s [%s]", data.DiscName, data.DiscType)
 	}
 
-	for pairHalf := 1; pairHalf <= 2; pairHalf++ {
-
-	}
-	return domain.Lesson{
+	lesson := domain.Lesson{
 		Time:       domain.NewLessonTime(date, data.LessonNum, 0, 2, startTime, endTime),
 		Discipline: discipline,
-		Teacher:    data.TeacherName,
+		Teachers:   []domain.Teacher{domain.NewTeacher(data.TeacherName)},
 		Cabinet:    data.AuditName,
 		Group:      data.GroupName,
 		Student:    "",
+		Source:     domain.SourceGroup,
 	}
-
+	if data.DiscSubgroup != "" && data.DiscSubgroup != "0" {
+		lesson.Subgroup = data.DiscSubgroup
+	}
+	lesson.ID = lesson.ComputeID()
+	return lesson
 }
 
-func mergeLessons(data []LessonData) LessonData {
-	if len(data) == 0 {
-		return LessonData{}
+// resolveDepartmentID возвращает идентификатор отделения на сайте: заданный в настройках
+// отделения или найденный по названию
+func (gsp *GroupScheduleParser) resolveDepartmentID() (string, error) {
+	if gsp.department.SiteID != "" {
+		return gsp.department.SiteID, nil
 	}
 
-	first := data[0]
-
-	// парсинг даты/времени — оставляю, чтобы сохранить побочный эффект проверки формата
-	_, _ = time.Parse("2006-01-02", first.WeekDayDate)
-	_, _ = time.Parse("15:04", first.LessonTimeStart)
-	_, _ = time.Parse("15:04", first.LessonTimeEnd)
-
-	disciplines := make([]string, len(data))
-	teachers := make([]string, len(data))
-	cabinets := make([]string, len(data))
-
-	for i, d := range data {
-		discipline := d.DiscName
-		if d.DiscType != "" {
-			discipline = fmt.Sprintf("%s [%s]", d.DiscName, d.DiscType)
-		}
-		disciplines[i] = discipline
-		teachers[i] = d.TeacherName
-		cabinets[i] = d.AuditName
+	departments, err := gsp.fetchDepartments()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch departments: %w", err)
 	}
 
-	merged := first // копируем первый элемент как базу
-
-	merged.DiscName = strings.Join(disciplines, " / ")
-	merged.TeacherName = strings.Join(teachers, " & ")
-	merged.AuditName = strings.Join(cabinets, " / ")
+	departmentID, ok := departments[gsp.department.Name]
+	if !ok {
+		return "", fmt.Errorf("department '%s' not found", gsp.department.Name)
+	}
+	return departmentID, nil
+}
 
-	// если нужно пометить, что это объединённый урок, можно изменить другие поля,
-	// например DiscSubgroup, но оставляю их без изменений по умолчанию.
+// groupIDs возвращает идентификаторы групп отделения из кэша siteGroupIDs или загружает их
+// с сайта, если кэш старше groupCacheTTL. С refresh список перезагружается, если он старше
+// siteDepartmentsRetry: группу ищут во всех отделениях, и без этого каждое отделение, где её нет,
+// загружалось бы заново. Ошибки загрузки не кэшируются.
+func (gsp *GroupScheduleParser) groupIDs(departmentID string, refresh bool) (map[string]string, error) {
+	siteGroupIDs.mu.Lock()
+	defer siteGroupIDs.mu.Unlock()
 
-	return merged
+	maxAge := groupCacheTTL
+	if refresh {
+		maxAge = siteDepartmentsRetry
+	}
+	if cached, ok := siteGroupIDs.departments[departmentID]; ok && time.Since(cached.loaded) < maxAge {
+		return cached.groups, nil
+	}
+	groups, err := gsp.fetchGroups(departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if siteGroupIDs.departments == nil {
+		siteGroupIDs.departments = make(map[string]cachedGroupIDs)
+	}
+	siteGroupIDs.departments[departmentID] = cachedGroupIDs{groups: groups, loaded: time.Now()}
+	return groups, nil
 }
 
 // Parse извлекает расписание группы
 func (gsp *GroupScheduleParser) Parse() ([]domain.Lesson, error) {
-	departments, err := gsp.fetchDepartments()
+	departmentID, err := gsp.resolveDepartmentID()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch departments: %w", err)
-	}
-
-	departmentID, ok := departments[gsp.departmentName]
-	if !ok {
-		return nil, fmt.Errorf("department '%s' not found", gsp.departmentName)
+		return nil, err
 	}
 
-	groups, err := gsp.fetchGroups(departmentID)
+	groups, err := gsp.groupIDs(departmentID, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch groups: %w", err)
 	}
 
 	groupID, ok := groups[gsp.groupName]
 	if !ok {
-		return nil, fmt.Errorf("group '%s' not found", gsp.groupName)
+		// группа могла появиться на сайте после загрузки списка в кэш
+		if groups, err = gsp.groupIDs(departmentID, true); err != nil {
+			return nil, fmt.Errorf("failed to fetch groups: %w", err)
+		}
+		groupID, ok = groups[gsp.groupName]
+	}
+	if !ok {
+		known := make([]string, 0, len(groups))
+		for name := range groups {
+			known = append(known, name)
+		}
+		return nil, &GroupNotFoundError{Group: gsp.groupName, Department: gsp.department.Name, Known: known}
 	}
 
 	lessonsData, err := gsp.fetchLessons(groupID)
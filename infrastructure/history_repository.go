@@ -0,0 +1,308 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// HistoryConfig структура файла истории проверок
+type HistoryConfig struct {
+	Checks   []CheckRecordYAML `yaml:"checks"`
+	Comments []CommentYAML     `yaml:"comments,omitempty"`
+}
+
+// CommentYAML представление domain.Comment в YAML
+type CommentYAML struct {
+	ViolationID string    `yaml:"violation_id"`
+	Author      string    `yaml:"author"`
+	Text        string    `yaml:"text"`
+	CreatedAt   time.Time `yaml:"created_at"`
+}
+
+// CheckRecordYAML представление domain.CheckRecord в YAML
+type CheckRecordYAML struct {
+	Week         string               `yaml:"week"`
+	CheckedAt    time.Time            `yaml:"checked_at"`
+	LessonsCount int                  `yaml:"lessons_count"`
+	DailyLoad    float64              `yaml:"daily_load,omitempty"`
+	Completeness int                  `yaml:"completeness,omitempty"`
+	Violations   []domain.Violation   `yaml:"violations"`
+	Hours        []DeliveredHoursYAML `yaml:"hours,omitempty"`
+	Groups       []GroupLoadYAML      `yaml:"groups,omitempty"`
+	Resolved     []domain.Violation   `yaml:"resolved,omitempty"`
+}
+
+// GroupLoadYAML представление domain.GroupLoad в YAML
+type GroupLoadYAML struct {
+	Group       string `yaml:"group"`
+	Year        int    `yaml:"year"`
+	Students    int    `yaml:"students"`
+	StudentDays int    `yaml:"student_days"`
+	Hours       int    `yaml:"hours"`
+	GapHalves   int    `yaml:"gap_halves"`
+}
+
+// DeliveredHoursYAML представление domain.DeliveredHours в YAML
+type DeliveredHoursYAML struct {
+	Student    string `yaml:"student"`
+	Discipline string `yaml:"discipline"`
+	Hours      int    `yaml:"hours"`
+}
+
+// YAMLHistoryRepository хранит историю проверок в YAML-файле.
+// Для каждой недели хранится только последняя проверка.
+type YAMLHistoryRepository struct {
+	filename string
+	store    documentStore // файл или база, см. Storage
+	mutex    sync.RWMutex
+}
+
+// NewYAMLHistoryRepository создает новый экземпляр репозитория истории
+func NewYAMLHistoryRepository(filename string) *YAMLHistoryRepository {
+	return &YAMLHistoryRepository{
+		filename: filename,
+		store:    fileStore{},
+	}
+}
+
+// SaveCheck сохраняет результат проверки, заменяя предыдущую проверку той же недели.
+// Нарушения предыдущей проверки, исчезнувшие в новой, сохраняются как устранённые.
+func (r *YAMLHistoryRepository) SaveCheck(record domain.CheckRecord) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+
+	for _, check := range config.Checks {
+		if check.Week == record.Week.String() {
+			record = record.Supersede(domain.CheckRecord{Violations: check.Violations, Resolved: check.Resolved})
+			break
+		}
+	}
+
+	entry := CheckRecordYAML{
+		Week:         record.Week.String(),
+		CheckedAt:    record.CheckedAt,
+		LessonsCount: record.LessonsCount,
+		DailyLoad:    record.DailyLoad,
+		Completeness: record.Completeness,
+		Violations:   record.Violations,
+		Resolved:     record.Resolved,
+	}
+	for _, h := range record.Hours {
+		entry.Hours = append(entry.Hours, DeliveredHoursYAML{Student: h.Student, Discipline: h.Discipline, Hours: h.Hours})
+	}
+	for _, g := range record.Groups {
+		entry.Groups = append(entry.Groups, GroupLoadYAML(g))
+	}
+
+	replaced := false
+	for i, check := range config.Checks {
+		if check.Week == entry.Week {
+			config.Checks[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		config.Checks = append(config.Checks, entry)
+	}
+
+	sort.Slice(config.Checks, func(i, j int) bool {
+		return config.Checks[i].Week < config.Checks[j].Week
+	})
+
+	return r.saveUnsafe(config)
+}
+
+// LoadChecks возвращает все сохранённые проверки в порядке недель.
+// Если файла истории нет, возвращается пустой список.
+func (r *YAMLHistoryRepository) LoadChecks() ([]domain.CheckRecord, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]domain.CheckRecord, 0, len(config.Checks))
+	for _, check := range config.Checks {
+		week, err := domain.ParseWeek(check.Week)
+		if err != nil {
+			return nil, fmt.Errorf("повреждена запись истории: %w", err)
+		}
+		record := domain.CheckRecord{
+			Week:         week,
+			CheckedAt:    check.CheckedAt,
+			LessonsCount: check.LessonsCount,
+			DailyLoad:    check.DailyLoad,
+			Completeness: check.Completeness,
+			Violations:   check.Violations,
+			Resolved:     check.Resolved,
+		}
+		for _, h := range check.Hours {
+			record.Hours = append(record.Hours, domain.DeliveredHours{Student: h.Student, Discipline: h.Discipline, Hours: h.Hours})
+		}
+		for _, g := range check.Groups {
+			record.Groups = append(record.Groups, domain.GroupLoad(g))
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// AddComment добавляет комментарий к нарушению
+func (r *YAMLHistoryRepository) AddComment(comment domain.Comment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+
+	config.Comments = append(config.Comments, CommentYAML{
+		ViolationID: comment.ViolationID,
+		Author:      comment.Author,
+		Text:        comment.Text,
+		CreatedAt:   comment.CreatedAt,
+	})
+	return r.saveUnsafe(config)
+}
+
+// LoadComments возвращает комментарии к нарушению в порядке добавления.
+// Пустой violationID возвращает комментарии ко всем нарушениям.
+func (r *YAMLHistoryRepository) LoadComments(violationID string) ([]domain.Comment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []domain.Comment
+	for _, c := range config.Comments {
+		if violationID != "" && c.ViolationID != violationID {
+			continue
+		}
+		comments = append(comments, domain.Comment{
+			ViolationID: c.ViolationID,
+			Author:      c.Author,
+			Text:        c.Text,
+			CreatedAt:   c.CreatedAt,
+		})
+	}
+	return comments, nil
+}
+
+// RenameStudent переносит на студента to нарушения, устранённые нарушения и часы студента from
+// во всех сохранённых проверках. Комментарии переносятся на новые идентификаторы нарушений.
+// Возвращает количество изменённых записей.
+func (r *YAMLHistoryRepository) RenameStudent(from, to string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	newIDs := make(map[string]string)
+	rename := func(violations []domain.Violation) []domain.Violation {
+		seen := make(map[string]bool, len(violations))
+		result := violations[:0]
+		for _, v := range violations {
+			if v.StudentName == from {
+				oldID := v.ID()
+				v.StudentName = to
+				newIDs[oldID] = v.ID()
+				changed++
+			}
+			// у обоих студентов могло быть одно и то же нарушение
+			if seen[v.ID()] {
+				continue
+			}
+			seen[v.ID()] = true
+			result = append(result, v)
+		}
+		return result
+	}
+
+	for i, check := range config.Checks {
+		check.Violations = rename(check.Violations)
+		check.Resolved = rename(check.Resolved)
+
+		index := make(map[string]int)
+		hours := check.Hours[:0]
+		for _, h := range check.Hours {
+			if h.Student == from {
+				h.Student = to
+				changed++
+			}
+			key := h.Student + "|" + h.Discipline
+			if j, ok := index[key]; ok {
+				hours[j].Hours += h.Hours
+				continue
+			}
+			index[key] = len(hours)
+			hours = append(hours, h)
+		}
+		check.Hours = hours
+		config.Checks[i] = check
+	}
+
+	for i, comment := range config.Comments {
+		if id, ok := newIDs[comment.ViolationID]; ok {
+			config.Comments[i].ViolationID = id
+		}
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, r.saveUnsafe(config)
+}
+
+// loadUnsafe читает файл истории без блокировки (внутренний метод)
+func (r *YAMLHistoryRepository) loadUnsafe() (HistoryConfig, error) {
+	var config HistoryConfig
+
+	data, err := r.store.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
+
+// saveUnsafe записывает файл истории без блокировки (внутренний метод)
+func (r *YAMLHistoryRepository) saveUnsafe(config HistoryConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+
+	if err := r.store.WriteFile(r.filename, data); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
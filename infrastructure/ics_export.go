@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// icsTimeFormat — время в iCalendar (RFC 5545) в UTC
+const icsTimeFormat = "20060102T150405Z"
+
+// ExportCabinetICS записывает занятия кабинета календарём iCalendar (.ics) для экранов
+// у дверей аудиторий и календарных программ. Занятия без времени по звонкам пропускаются;
+// половина пары занимает соответствующую половину времени пары.
+func ExportCabinetICS(w io.Writer, cabinet string, lessons []domain.Lesson, now time.Time) error {
+	sorted := append([]domain.Lesson(nil), lessons...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.StartTime.Before(sorted[j].Time.StartTime)
+	})
+
+	bw := bufio.NewWriter(w)
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//Lesson Counter//Кабинеты//RU")
+	writeICSLine(bw, "CALSCALE:GREGORIAN")
+	writeICSLine(bw, "X-WR-CALNAME:"+icsEscape("Кабинет "+cabinet))
+	for _, lesson := range sorted {
+		start, end, ok := lessonInterval(lesson.Time)
+		if !ok {
+			continue
+		}
+		writeICSLine(bw, "BEGIN:VEVENT")
+		writeICSLine(bw, "UID:"+lesson.ID+"@lesson-counter")
+		writeICSLine(bw, "DTSTAMP:"+now.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "DTSTART:"+start.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "DTEND:"+end.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "SUMMARY:"+icsEscape(lesson.Discipline))
+		writeICSLine(bw, "LOCATION:"+icsEscape(cabinet))
+		if description := lessonDescription(lesson); description != "" {
+			writeICSLine(bw, "DESCRIPTION:"+icsEscape(description))
+		}
+		writeICSLine(bw, "END:VEVENT")
+	}
+	writeICSLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// lessonInterval возвращает время занятия: всей пары или её половины
+func lessonInterval(t domain.LessonTime) (time.Time, time.Time, bool) {
+	if t.StartTime.IsZero() || !t.EndTime.After(t.StartTime) {
+		return time.Time{}, time.Time{}, false
+	}
+	start, end := t.StartTime, t.EndTime
+	middle := start.Add(end.Sub(start) / 2)
+	switch t.PairHalf {
+	case 1:
+		end = middle
+	case 2:
+		start = middle
+	}
+	return start, end, true
+}
+
+// lessonDescription описывает, кто занимается: преподаватели, группа или студент
+func lessonDescription(lesson domain.Lesson) string {
+	var parts []string
+	if teachers := lesson.TeacherNames(); teachers != "" {
+		parts = append(parts, teachers)
+	}
+	switch {
+	case lesson.Student != "":
+		parts = append(parts, lesson.Student)
+	case lesson.Group != "":
+		group := "группа " + lesson.Group
+		if lesson.Subgroup != "" {
+			group += ", подгруппа " + lesson.Subgroup
+		}
+		parts = append(parts, group)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// icsEscape экранирует текстовое значение iCalendar
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine записывает строку iCalendar, перенося её после 75 байт (RFC 5545, 3.1)
+// без разрыва символов UTF-8
+func writeICSLine(w *bufio.Writer, line string) {
+	width := 75
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n ", line[:cut])
+		line = line[cut:]
+		width = 74 // продолжение начинается с пробела
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
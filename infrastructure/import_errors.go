@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ImportError — ошибка в одной записи загружаемых данных: в файле архива или в занятии
+// выгрузки JSON
+type ImportError struct {
+	File    string `json:"file,omitempty"`  // файл; для архива — путь внутри архива
+	Row     int    `json:"row,omitempty"`   // номер записи в файле, с 1; 0 — ошибка всего файла
+	Field   string `json:"field,omitempty"` // поле записи
+	Message string `json:"message"`
+}
+
+// Error возвращает описание ошибки с местом, где она найдена
+func (e ImportError) Error() string {
+	var place []string
+	if e.File != "" {
+		place = append(place, e.File)
+	}
+	if e.Row > 0 {
+		place = append(place, fmt.Sprintf("запись %d", e.Row))
+	}
+	if e.Field != "" {
+		place = append(place, "поле "+e.Field)
+	}
+	if len(place) == 0 {
+		return e.Message
+	}
+	return strings.Join(place, ", ") + ": " + e.Message
+}
+
+// ImportErrors — все ошибки загружаемых данных. Данные, в которых есть хотя бы одна ошибка,
+// не загружаются целиком, чтобы проверка не шла по части файла.
+type ImportErrors []ImportError
+
+// Error перечисляет ошибки по одной на строку
+func (e ImportErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, item := range e {
+		lines[i] = item.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InFile возвращает ошибки с указанным файлом; файл, уже указанный в ошибке, считается
+// вложенным в file (файл внутри архива)
+func (e ImportErrors) InFile(file string) ImportErrors {
+	result := make(ImportErrors, len(e))
+	for i, item := range e {
+		if item.File != "" {
+			item.File = file + "/" + item.File
+		} else {
+			item.File = file
+		}
+		result[i] = item
+	}
+	return result
+}
+
+// AsImportErrors извлекает из err ошибки по записям загружаемых данных
+func AsImportErrors(err error) (ImportErrors, bool) {
+	var errs ImportErrors
+	if errors.As(err, &errs) {
+		return errs, true
+	}
+	return nil, false
+}
@@ -15,7 +15,7 @@ package infrastructure
 // дисциплин.
 //
 // Использование:
-// 1. Создайте экземпляр парсера с помощью NewIndividualScheduleParser().
+// 1. Создайте экземпляр парсера с помощью NewIndividualScheduleParser(diagnostics).
 // 2. Вызовите метод Parse() для обработки всех XLS-файлов в текущей директории.
 // 3. Получите список уроков (domain.Lesson) или ошибку в случае неудачи.
 //
@@ -24,7 +24,8 @@ package infrastructure
 // - Предполагается, что структура таблицы соответствует определённому формату
 //   (например, наличие заголовка "Расписание индивидуальных занятий", строк с
 //   датами, номерами пар и т.д.).
-// - Время пар фиксировано и задаётся в pairTime внутри структуры парсера.
+// - Время пар берётся из расписания звонков (domain.BellSchedule), по умолчанию —
+//   domain.DefaultBellSchedule; другое расписание задаётся через SetBellSchedule.
 //
 // Зависимости:
 // - Пакет "github.com/extrame/xls" для работы с XLS-файлами.
@@ -32,10 +33,11 @@ package infrastructure
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -45,28 +47,40 @@ import (
 )
 
 // IndividualScheduleParser обрабатывает парсинг индивидуальных расписаний из XLS-файлов.
-// Содержит пути к файлам и карту времени пар для преобразования номеров уроков во временные интервалы.
+// Содержит пути к файлам и расписание звонков для преобразования номеров уроков во временные интервалы.
 type IndividualScheduleParser struct {
-	filePaths []string
-	pairTime  map[int][2]string
+	filePaths   []string
+	bells       domain.BellSchedule
+	diagnostics *domain.Diagnostics // сюда записываются пропущенные файлы, может быть nil
+	mergePolicy domain.MergePolicy  // политика объединения расходящихся половинок пары
+	coverage    []domain.FileCoverage
+	skipDir     string                 // каталог, файлы которого не разбираются (архив), пусто — нет
+	files       []string               // разбираемые файлы вместо поиска в текущей директории, пусто — искать
+	debug       *DebugDump             // промежуточные данные разбора, nil — не записываются
+	teachers    domain.TeacherRegistry // подтверждённые псевдонимы преподавателей
+	aliasPairs  [][2]string            // написания одного имени у одного занятия, найденные mergeTeachers
 }
 
-// NewIndividualScheduleParser создаёт новый экземпляр парсера с предустановленной
-// картой времени пар (pairTime), где каждому номеру пары соответствует время начала и конца.
-func NewIndividualScheduleParser() *IndividualScheduleParser {
+// NewIndividualScheduleParser создаёт новый экземпляр парсера с расписанием звонков по умолчанию.
+// Пропущенные файлы записываются в diagnostics, если он передан.
+func NewIndividualScheduleParser(diagnostics *domain.Diagnostics) *IndividualScheduleParser {
 	return &IndividualScheduleParser{
-		pairTime: map[int][2]string{
-			1: {"08:30", "10:00"},
-			2: {"10:10", "11:40"},
-			3: {"11:50", "13:20"},
-			4: {"13:40", "15:10"},
-			5: {"15:20", "16:50"},
-			6: {"16:55", "18:25"},
-			7: {"18:30", "20:00"},
-		},
+		diagnostics: diagnostics,
+		mergePolicy: domain.MergePolicyJoin,
+		bells:       domain.DefaultBellSchedule(),
 	}
 }
 
+// SetBellSchedule задаёт расписание звонков, по которому вычисляется время пар
+func (p *IndividualScheduleParser) SetBellSchedule(bells domain.BellSchedule) {
+	p.bells = bells
+}
+
+// SetMergePolicy задаёт политику объединения половинок пары с разными данными
+func (p *IndividualScheduleParser) SetMergePolicy(policy domain.MergePolicy) {
+	p.mergePolicy = policy
+}
+
 // Parse обрабатывает все XLS-файлы в текущей директории и возвращает список уроков (domain.Lesson).
 // Метод загружает пути к файлам, парсит их содержимое и объединяет уроки с одинаковыми параметрами.
 // Возвращает ошибку, если файлы не найдены или данные некорректны.
@@ -78,95 +92,161 @@ func (p *IndividualScheduleParser) Parse() ([]domain.Lesson, error) {
 	var allLessons []domain.Lesson
 
 	for _, filePath := range p.filePaths {
-		file, err := xls.Open(filePath, "windows-1251")
+		lessons, err := p.parseFile(filePath)
 		if err != nil {
+			log.Printf("Ошибка разбора файла %s: %v", filepath.Base(filePath), err)
+			p.diagnostics.Add(domain.IssueFileSkipped, filepath.Base(filePath), "%v", err)
 			continue
 		}
+		p.debug.Lessons("lessons_before_merge.tsv", filePath, lessons)
+		allLessons = append(allLessons, lessons...)
+		p.coverage = append(p.coverage, domain.CoverageOf(filePath, lessons))
+	}
+
+	if len(allLessons) == 0 {
+		return nil, fmt.Errorf("не найдено уроков в файлах")
+	}
+
+	mergedLessons := p.mergeTeachers(allLessons)
+	joinedLessons := p.joinIndLessons(mergedLessons)
+	p.debug.Lessons("lessons_after_merge.tsv", "", joinedLessons)
+	return joinedLessons, nil
+
+}
+
+// SetSkipDir исключает из разбора каталог со всеми вложенными (например, архив обработанных файлов)
+func (p *IndividualScheduleParser) SetSkipDir(dir string) {
+	p.skipDir = dir
+}
+
+// SetFiles задаёт файлы для разбора вместо поиска XLS-файлов в текущей директории
+func (p *IndividualScheduleParser) SetFiles(paths []string) {
+	p.files = paths
+}
+
+// SetDebugDump включает запись промежуточных данных разбора
+func (p *IndividualScheduleParser) SetDebugDump(debug *DebugDump) {
+	p.debug = debug
+}
+
+// SetTeacherRegistry задаёт справочник преподавателей: имена, подтверждённые как псевдонимы,
+// заменяются основным написанием
+func (p *IndividualScheduleParser) SetTeacherRegistry(registry domain.TeacherRegistry) {
+	p.teachers = registry
+}
+
+// AliasPairs возвращает пары написаний имени, различающихся только пробелами и знаками
+// препинания, которые встретились у одного занятия при последнем Parse
+func (p *IndividualScheduleParser) AliasPairs() [][2]string {
+	return p.aliasPairs
+}
 
-		sheet := file.GetSheet(0)
-		if sheet == nil {
+// Coverage возвращает охват дат и преподавателей каждого разобранного файла после Parse
+func (p *IndividualScheduleParser) Coverage() []domain.FileCoverage {
+	return p.coverage
+}
+
+// parseFile разбирает один XLS-файл и возвращает найденные в нём уроки.
+// Паника при разборе некорректного листа перехватывается и возвращается как ошибка,
+// чтобы один повреждённый файл не останавливал всю проверку.
+func (p *IndividualScheduleParser) parseFile(filePath string) (allLessons []domain.Lesson, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			allLessons = nil
+			err = fmt.Errorf("паника при разборе файла: %v", r)
+		}
+	}()
+
+	file, err := xls.Open(filePath, "windows-1251")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл: %w", err)
+	}
+
+	sheet := file.GetSheet(0)
+	if sheet == nil {
+		return nil, fmt.Errorf("в файле нет листов")
+	}
+
+	teacherRows := p.findTeacherRows(sheet)
+	p.debug.Cells(filePath, sheet, teacherRows)
+
+	for _, teacherRow := range teacherRows {
+		teacherName, err := p.extractTeacher(sheet, teacherRow)
+		if err != nil {
 			continue
 		}
+		blockStart := len(allLessons)
 
-		teacherRows := p.findTeacherRows(sheet)
+		dayColumns, err := p.extractDayColumns(sheet, teacherRow)
+		if err != nil {
+			continue
+		}
 
-		for _, teacherRow := range teacherRows {
-			teacherName, err := p.extractTeacher(sheet, teacherRow)
-			if err != nil {
-				continue
-			}
+		lessonsRowStart := teacherRow + 6
 
-			dayColumns, err := p.extractDayColumns(sheet, teacherRow)
+		for _, dayColumn := range dayColumns {
+			date, err := p.extractDate(sheet, lessonsRowStart, dayColumn)
 			if err != nil {
 				continue
 			}
 
-			lessonsRowStart := teacherRow + 6
+			for lessonRow := lessonsRowStart; lessonRow < int(sheet.MaxRow); lessonRow++ {
+				lessonNumber, err := p.extractLessonNumber(sheet, lessonRow)
+				if err != nil || lessonNumber == 0 {
+					break
+				}
 
-			for _, dayColumn := range dayColumns {
-				date, err := p.extractDate(sheet, lessonsRowStart, dayColumn)
+				disciplineName, err := p.extractDiscipline(sheet, lessonRow, dayColumn)
 				if err != nil {
 					continue
 				}
 
-				for lessonRow := lessonsRowStart; lessonRow < int(sheet.MaxRow); lessonRow++ {
-					lessonNumber, err := p.extractLessonNumber(sheet, lessonRow)
-					if err != nil || lessonNumber == 0 {
-						break
-					}
+				cabinetNumber, err := p.extractCabinet(sheet, lessonRow, dayColumn)
+				if err != nil {
+					continue
+				}
 
-					disciplineName, err := p.extractDiscipline(sheet, lessonRow, dayColumn)
-					if err != nil {
-						continue
-					}
+				groupName, err := p.extractGroup(sheet, lessonRow, dayColumn)
+				if err != nil {
+					continue
+				}
 
-					cabinetNumber, err := p.extractCabinet(sheet, lessonRow, dayColumn)
-					if err != nil {
-						continue
-					}
+				studentNames, err := p.extractStudentNames(sheet, lessonRow, dayColumn)
+				if err != nil {
+					continue
+				}
 
-					groupName, err := p.extractGroup(sheet, lessonRow, dayColumn)
-					if err != nil {
-						continue
-					}
+				startTime, endTime := p.parsePairTime(date, lessonNumber)
 
-					studentNames, err := p.extractStudentNames(sheet, lessonRow, dayColumn)
-					if err != nil {
-						continue
+				for index, studentName := range studentNames {
+					lessonGroup := groupName
+					if studentName == "" {
+						lessonGroup = ""
 					}
-
-					startTime, endTime := p.parsePairTime(date, lessonNumber)
-
-					for index, studentName := range studentNames {
-						lessonGroup := groupName
-						if studentName == "" {
-							lessonGroup = ""
-						}
-						hoursStub := 1
-						pairHalf := index + 1
-						lesson := domain.Lesson{
-							Time:       domain.NewLessonTime(date, lessonNumber, pairHalf, hoursStub, startTime, endTime),
-							Discipline: disciplineName,
-							Teacher:    teacherName,
-							Cabinet:    cabinetNumber,
-							Group:      lessonGroup,
-							Student:    studentName,
-						}
-						allLessons = append(allLessons, lesson)
+					hoursStub := 1
+					pairHalf := index + 1
+					lesson := domain.Lesson{
+						Time:       domain.NewLessonTime(date, lessonNumber, pairHalf, hoursStub, startTime, endTime),
+						Discipline: disciplineName,
+						Teachers:   []domain.Teacher{domain.NewTeacher(teacherName)},
+						Cabinet:    cabinetNumber,
+						Group:      lessonGroup,
+						Student:    studentName,
+						Source:     domain.SourceIndividual,
 					}
+					allLessons = append(allLessons, lesson)
 				}
 			}
 		}
-	}
 
-	if len(allLessons) == 0 {
-		return nil, fmt.Errorf("не найдено уроков в файлах")
+		// Занятия блока всё равно учитываются у студентов, но без преподавателя их не найти
+		// в календарях и сверке; заголовок показывается как есть, чтобы файл можно было исправить
+		if count := len(allLessons) - blockStart; teacherName == "Unknown" && count > 0 {
+			p.reportUnknownTeacher(filePath, teacherRow, strings.TrimSpace(sheet.Row(teacherRow).Col(0)), count)
+		}
 	}
 
-	mergedLessons := p.mergeTeachers(allLessons)
-	joinedLessons := p.joinIndLessons(mergedLessons)
-	return joinedLessons, nil
-
+	return allLessons, nil
 }
 
 // findTeacherRows находит строки, содержащие "Преподаватель" в колонке 0.
@@ -210,7 +290,7 @@ func (p *IndividualScheduleParser) extractDate(sheet *xls.WorkSheet, lessonsRowS
 	dateStr = strings.TrimSuffix(dateStr, ".")
 	fullDate := fmt.Sprintf("%s.%s", dateStr, year)
 
-	date, err := time.Parse("02.01.2006", fullDate)
+	date, err := time.ParseInLocation("02.01.2006", fullDate, domain.Location())
 	if err != nil {
 		return time.Time{}, fmt.Errorf("ошибка парсинга даты '%s': %w", fullDate, err)
 	}
@@ -232,22 +312,10 @@ func (p *IndividualScheduleParser) extractLessonNumber(sheet *xls.WorkSheet, row
 	return num, nil
 }
 
-// parsePairTime преобразует номер урока во время начала и конца на основе предустановленной карты pairTime.
-// Объединяет дату с временем для получения полного time.Time.
+// parsePairTime преобразует номер урока во время начала и конца по расписанию звонков
+// с учётом варианта для дня недели.
 func (p *IndividualScheduleParser) parsePairTime(date time.Time, lessonNumber int) (time.Time, time.Time) {
-	times := p.pairTime[lessonNumber]
-
-	// Парсим время и объединяем с датой
-	startHM, _ := time.Parse("15:04", times[0])
-	endHM, _ := time.Parse("15:04", times[1])
-
-	// Создаём полное время с датой
-	startTime := time.Date(date.Year(), date.Month(), date.Day(),
-		startHM.Hour(), startHM.Minute(), 0, 0, date.Location())
-	endTime := time.Date(date.Year(), date.Month(), date.Day(),
-		endHM.Hour(), endHM.Minute(), 0, 0, date.Location())
-
-	return startTime, endTime
+	return p.bells.Times(date, lessonNumber)
 }
 
 // extractDiscipline извлекает название дисциплины из ячейки, очищая данные от информации о кабинете и группе.
@@ -292,6 +360,15 @@ func (p *IndividualScheduleParser) extractDiscipline(sheet *xls.WorkSheet, row,
 	}
 }
 
+// reportUnknownTeacher записывает в диагностику блок занятий, заголовок преподавателя которого
+// не удалось разобрать: строку файла, исходный текст заголовка и число занятий блока
+func (p *IndividualScheduleParser) reportUnknownTeacher(filePath string, row int, header string, count int) {
+	log.Printf("Файл %s, строка %d: не распознан преподаватель в заголовке %q, занятий: %d", filepath.Base(filePath), row+1, header, count)
+	p.diagnostics.Add(domain.IssueTeacherUnread, filepath.Base(filePath),
+		"строка %d: заголовок «%s» не похож на «Преподаватель Фамилия И.О.» — %d занятий учтены с преподавателем Unknown; исправьте заголовок в файле",
+		row+1, header, count)
+}
+
 // extractTeacher извлекает имя преподавателя из таблицы, используя регулярное выражение.
 // Возвращает форматированное имя в формате "Фамилия И.О." или "Unknown", если данные некорректны.
 func (p *IndividualScheduleParser) extractTeacher(sheet *xls.WorkSheet, teacherRow int) (string, error) {
@@ -393,11 +470,20 @@ func (p *IndividualScheduleParser) extractDayColumns(sheet *xls.WorkSheet, teach
 }
 
 // mergeTeachers объединяет уроки с одинаковыми параметрами, но разными преподавателями.
-// Формирует уникальный ключ для каждого урока и объединяет имена преподавателей через "& " в алфавитном порядке.
+// Формирует уникальный ключ для каждого урока и объединяет списки преподавателей в алфавитном порядке.
+// Псевдонимы из справочника заменяются основным написанием, а имена, различающиеся только
+// пробелами и знаками препинания, запоминаются как кандидаты в псевдонимы (см. AliasPairs).
 func (p *IndividualScheduleParser) mergeTeachers(lessons []domain.Lesson) []domain.Lesson {
 	groups := make(map[string][]domain.Lesson)
 	for _, lesson := range lessons {
-		key := fmt.Sprintf("%s|%d|%d|%s|%s|%s|%s",
+		for i, teacher := range lesson.Teachers {
+			if canonical := p.teachers.Canonical(teacher.Name); canonical != teacher.Name {
+				lesson.Teachers = append([]domain.Teacher(nil), lesson.Teachers...)
+				lesson.Teachers[i] = domain.NewTeacher(canonical)
+			}
+		}
+		key := fmt.Sprintf("%s|%s|%d|%d|%s|%s|%s|%s",
+			lesson.Source,
 			lesson.Time.DateString(),
 			lesson.Time.Number,
 			lesson.Time.PairHalf,
@@ -415,37 +501,59 @@ func (p *IndividualScheduleParser) mergeTeachers(lessons []domain.Lesson) []doma
 		}
 		merged := group[0]
 		if len(group) > 1 {
-			teacherSet := make(map[string]bool)
-			for _, lesson := range group {
-				if lesson.Teacher != "" {
-					teacherSet[lesson.Teacher] = true
-				}
+			teacherLists := make([][]domain.Teacher, len(group))
+			for i, lesson := range group {
+				teacherLists[i] = lesson.Teachers
 			}
-			teachers := make([]string, 0, len(teacherSet))
-			for teacher := range teacherSet {
-				teachers = append(teachers, teacher)
-			}
-			// Сортируем преподавателей в алфавитном порядке
-			sort.Strings(teachers)
-			merged.Teacher = strings.Join(teachers, "& ")
+			merged.Teachers = domain.MergeTeachers(teacherLists...)
+			p.collectAliasPairs(merged.Teachers)
 		}
 		result = append(result, merged)
 	}
 	return result
 }
 
+// collectAliasPairs запоминает пары имён преподавателей занятия, различающиеся только
+// пробелами и знаками препинания
+func (p *IndividualScheduleParser) collectAliasPairs(teachers []domain.Teacher) {
+	for i := range teachers {
+		for j := i + 1; j < len(teachers); j++ {
+			if !domain.SameTeacherName(teachers[i].Name, teachers[j].Name) {
+				continue
+			}
+			pair := [2]string{teachers[i].Name, teachers[j].Name}
+			if !slices.Contains(p.aliasPairs, pair) {
+				p.aliasPairs = append(p.aliasPairs, pair)
+			}
+		}
+	}
+}
+
 // loadFilePaths загружает пути ко всем XLS-файлам в текущей директории.
 // Сохраняет пути в поле filePaths структуры парсера. Возвращает ошибку, если файлы не найдены.
 func (p *IndividualScheduleParser) loadFilePaths() error {
+	if len(p.files) > 0 {
+		p.filePaths = append(p.filePaths, p.files...)
+		return nil
+	}
+
 	dataDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("не удалось получить текущую директорию: %w", err)
 	}
 
+	skipDir := ""
+	if p.skipDir != "" {
+		skipDir, _ = filepath.Abs(p.skipDir)
+	}
+
 	err = filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if info.IsDir() && skipDir != "" && path == skipDir {
+			return filepath.SkipDir
+		}
 		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".xls") {
 			p.filePaths = append(p.filePaths, path)
 		}
@@ -463,10 +571,13 @@ func (p *IndividualScheduleParser) loadFilePaths() error {
 	return nil
 }
 
-// joinIndLessons объединяет индивидуальные занятия студентов в полные пары
+// joinIndLessons объединяет индивидуальные занятия студентов в полные пары.
+// Если данные половинок расходятся, поведение определяется политикой mergePolicy,
+// а каждое такое объединение записывается в diagnostics.
 func (p *IndividualScheduleParser) joinIndLessons(lessons []domain.Lesson) []domain.Lesson {
 	// Группируем уроки по студенту, дате и номеру пары
 	type lessonKey struct {
+		source  domain.LessonSource
 		student string
 		date    string
 		number  int
@@ -477,6 +588,7 @@ func (p *IndividualScheduleParser) joinIndLessons(lessons []domain.Lesson) []dom
 	// Собираем все индивидуальные уроки в map
 	for _, lesson := range lessons {
 		key := lessonKey{
+			source:  lesson.Source,
 			student: lesson.Student,
 			date:    lesson.Time.DateString(),
 			number:  lesson.Time.Number,
@@ -497,6 +609,26 @@ func (p *IndividualScheduleParser) joinIndLessons(lessons []domain.Lesson) []dom
 
 		var resultLesson domain.Lesson
 
+		conflict := hasFirst && hasSecond && halvesConflict(lesson1, lesson2)
+		if conflict && p.mergePolicy == domain.MergePolicyPreferIndividual {
+			// Расходящиеся половинки оставляем отдельными занятиями
+			for _, half := range []domain.Lesson{lesson1, lesson2} {
+				half.ID = half.ComputeID()
+				result = append(result, half)
+			}
+			continue
+		}
+		if conflict {
+			category := domain.IssueHalvesMerged
+			if p.mergePolicy == domain.MergePolicyWarn {
+				category = domain.IssueMergeConflict
+			}
+			p.diagnostics.Add(category, lesson1.Student, "%s, пара %d: «%s» (%s, %s) и «%s» (%s, %s)",
+				lesson1.Time.DateString(), lesson1.Time.Number,
+				lesson1.Discipline, lesson1.TeacherNames(), lesson1.Cabinet,
+				lesson2.Discipline, lesson2.TeacherNames(), lesson2.Cabinet)
+		}
+
 		if hasFirst && hasSecond {
 			// Обе половинки есть - объединяем
 			resultLesson = lesson1
@@ -510,12 +642,8 @@ func (p *IndividualScheduleParser) joinIndLessons(lessons []domain.Lesson) []dom
 				resultLesson.Discipline = lesson1.Discipline + "/" + lesson2.Discipline
 			}
 
-			// Проверяем, одинаковые ли преподаватели
-			if lesson1.Teacher == lesson2.Teacher {
-				resultLesson.Teacher = lesson1.Teacher
-			} else {
-				resultLesson.Teacher = lesson1.Teacher + "/" + lesson2.Teacher
-			}
+			// Объединяем преподавателей обеих половинок
+			resultLesson.Teachers = domain.MergeTeachers(lesson1.Teachers, lesson2.Teachers)
 
 			// Проверяем, одинаковые ли кабинеты
 			if lesson1.Cabinet == lesson2.Cabinet {
@@ -530,18 +658,24 @@ func (p *IndividualScheduleParser) joinIndLessons(lessons []domain.Lesson) []dom
 			// Только первая половинка
 			resultLesson = lesson1
 			resultLesson.Discipline = lesson1.Discipline + "/"
-			resultLesson.Teacher = lesson1.Teacher + "/"
 			resultLesson.Cabinet = lesson1.Cabinet + "/"
 		} else if hasSecond {
 			// Только вторая половинка
 			resultLesson = lesson2
 			resultLesson.Discipline = "/" + lesson2.Discipline
-			resultLesson.Teacher = "/" + lesson2.Teacher
 			resultLesson.Cabinet = "/" + lesson2.Cabinet
 		}
 
+		resultLesson.ID = resultLesson.ComputeID()
 		result = append(result, resultLesson)
 	}
 
 	return result
 }
+
+// halvesConflict проверяет, различаются ли дисциплина, преподаватели или кабинет половинок пары
+func halvesConflict(first, second domain.Lesson) bool {
+	return first.Discipline != second.Discipline ||
+		first.Cabinet != second.Cabinet ||
+		first.TeacherNames() != second.TeacherNames()
+}
@@ -0,0 +1,313 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	_ "modernc.org/sqlite" // драйвер SQLite без cgo
+)
+
+// jobsSchema создает таблицу очереди заданий
+const jobsSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	week         TEXT    NOT NULL,
+	full_report  INTEGER NOT NULL DEFAULT 0,
+	status       TEXT    NOT NULL,
+	attempts     INTEGER NOT NULL DEFAULT 0,
+	error        TEXT    NOT NULL DEFAULT '',
+	created_at   INTEGER NOT NULL,
+	scheduled_at INTEGER NOT NULL,
+	started_at   INTEGER NOT NULL DEFAULT 0,
+	finished_at  INTEGER NOT NULL DEFAULT 0,
+	batch        INTEGER NOT NULL DEFAULT 0,
+	failed_groups TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS jobs_status ON jobs (status, scheduled_at);
+`
+
+// jobsMigrations добавляют столбцы, которых нет в базах прежних версий
+var jobsMigrations = []struct{ column, statement string }{
+	{"batch", `ALTER TABLE jobs ADD COLUMN batch INTEGER NOT NULL DEFAULT 0`},
+	{"failed_groups", `ALTER TABLE jobs ADD COLUMN failed_groups TEXT NOT NULL DEFAULT ''`},
+}
+
+// jobColumns — столбцы задания в порядке сканирования scanJob
+const jobColumns = `id, week, full_report, status, attempts, error, created_at, scheduled_at, started_at, finished_at, batch, failed_groups`
+
+// SQLiteJobRepository хранит очередь заданий на проверку в базе SQLite, чтобы задания
+// переживали перезапуск программы
+type SQLiteJobRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteJobRepository открывает (или создает) базу очереди заданий
+func NewSQLiteJobRepository(filename string) (*SQLiteJobRepository, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть базу заданий: %w", err)
+	}
+	// SQLite не поддерживает параллельную запись; одно соединение исключает ошибки блокировки
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(jobsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось создать таблицу заданий: %w", err)
+	}
+	if err := migrateJobs(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteJobRepository{db: db}, nil
+}
+
+// migrateJobs добавляет в таблицу заданий недостающие столбцы
+func migrateJobs(db *sql.DB) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('jobs')`)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать таблицу заданий: %w", err)
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("не удалось прочитать таблицу заданий: %w", err)
+		}
+		columns[name] = true
+	}
+	rows.Close()
+
+	for _, migration := range jobsMigrations {
+		if columns[migration.column] {
+			continue
+		}
+		if _, err := db.Exec(migration.statement); err != nil {
+			return fmt.Errorf("не удалось обновить таблицу заданий: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close закрывает базу заданий
+func (r *SQLiteJobRepository) Close() error {
+	return r.db.Close()
+}
+
+// EnqueueJob добавляет задание в очередь и возвращает его с присвоенным идентификатором
+func (r *SQLiteJobRepository) EnqueueJob(job domain.Job) (domain.Job, error) {
+	return insertJob(r.db, job)
+}
+
+// EnqueueJobs добавляет задания в очередь одним пакетом: всем заданиям присваивается Batch —
+// идентификатор первого из них. Если хотя бы одно задание добавить не удалось, не добавляется ни одно.
+func (r *SQLiteJobRepository) EnqueueJobs(jobs []domain.Job) ([]domain.Job, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось добавить задания: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := make([]domain.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if len(result) > 0 {
+			job.Batch = result[0].ID
+		}
+		job, err := insertJob(tx, job)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, job)
+	}
+	if len(result) > 1 {
+		first := result[0].ID
+		if _, err := tx.Exec(`UPDATE jobs SET batch = ? WHERE id = ?`, first, first); err != nil {
+			return nil, fmt.Errorf("не удалось добавить задания: %w", err)
+		}
+		result[0].Batch = first
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("не удалось добавить задания: %w", err)
+	}
+	return result, nil
+}
+
+// LoadBatch возвращает задания пакета в порядке недель
+func (r *SQLiteJobRepository) LoadBatch(batch int64) ([]domain.Job, error) {
+	rows, err := r.db.Query(`SELECT `+jobColumns+` FROM jobs WHERE batch = ? ORDER BY week, id`, batch)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить задания: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось загрузить задания: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("пакет заданий %d не найден", batch)
+	}
+	return jobs, nil
+}
+
+// jobExecer — общий интерфейс *sql.DB и *sql.Tx для добавления заданий
+type jobExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertJob добавляет задание в очередь и возвращает его с присвоенным идентификатором
+func insertJob(db jobExecer, job domain.Job) (domain.Job, error) {
+	job.Status = domain.JobQueued
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	if job.ScheduledAt.IsZero() {
+		job.ScheduledAt = job.CreatedAt
+	}
+
+	result, err := db.Exec(
+		`INSERT INTO jobs (week, full_report, status, created_at, scheduled_at, batch) VALUES (?, ?, ?, ?, ?, ?)`,
+		job.Week.String(), job.FullReport, string(job.Status), unixTime(job.CreatedAt), unixTime(job.ScheduledAt), job.Batch,
+	)
+	if err != nil {
+		return domain.Job{}, fmt.Errorf("не удалось добавить задание: %w", err)
+	}
+	job.ID, err = result.LastInsertId()
+	if err != nil {
+		return domain.Job{}, fmt.Errorf("не удалось добавить задание: %w", err)
+	}
+	return job, nil
+}
+
+// LoadJobs возвращает все задания, новые первыми
+func (r *SQLiteJobRepository) LoadJobs() ([]domain.Job, error) {
+	rows, err := r.db.Query(`SELECT ` + jobColumns + ` FROM jobs ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить задания: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось загрузить задания: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetJob возвращает задание по идентификатору
+func (r *SQLiteJobRepository) GetJob(id int64) (domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, fmt.Errorf("задание %d не найдено", id)
+	}
+	return job, err
+}
+
+// NextDueJob возвращает самое раннее задание, время которого наступило.
+// Если таких заданий нет, возвращается false.
+func (r *SQLiteJobRepository) NextDueJob(now time.Time) (domain.Job, bool, error) {
+	job, err := scanJob(r.db.QueryRow(
+		`SELECT `+jobColumns+` FROM jobs WHERE status = ? AND scheduled_at <= ? ORDER BY scheduled_at, id LIMIT 1`,
+		string(domain.JobQueued), unixTime(now),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, false, nil
+	}
+	if err != nil {
+		return domain.Job{}, false, err
+	}
+	return job, true, nil
+}
+
+// UpdateJob сохраняет состояние задания
+func (r *SQLiteJobRepository) UpdateJob(job domain.Job) error {
+	result, err := r.db.Exec(
+		`UPDATE jobs SET status = ?, attempts = ?, error = ?, scheduled_at = ?, started_at = ?, finished_at = ?, failed_groups = ? WHERE id = ?`,
+		string(job.Status), job.Attempts, job.Error, unixTime(job.ScheduledAt), unixTime(job.StartedAt), unixTime(job.FinishedAt),
+		strings.Join(job.FailedGroups, "\n"), job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить задание: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("задание %d не найдено", job.ID)
+	}
+	return nil
+}
+
+// RequeueInterrupted возвращает в очередь задания, выполнение которых прервал
+// перезапуск программы. Возвращает количество таких заданий.
+func (r *SQLiteJobRepository) RequeueInterrupted() (int, error) {
+	result, err := r.db.Exec(`UPDATE jobs SET status = ? WHERE status = ?`, string(domain.JobQueued), string(domain.JobRunning))
+	if err != nil {
+		return 0, fmt.Errorf("не удалось вернуть прерванные задания в очередь: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (domain.Job, error) {
+	var (
+		job                                   domain.Job
+		week, status, failedGroups            string
+		created, scheduled, started, finished int64
+	)
+	if err := row.Scan(&job.ID, &week, &job.FullReport, &status, &job.Attempts, &job.Error, &created, &scheduled, &started, &finished, &job.Batch, &failedGroups); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Job{}, err
+		}
+		return domain.Job{}, fmt.Errorf("повреждена запись задания: %w", err)
+	}
+
+	parsed, err := domain.ParseWeek(week)
+	if err != nil {
+		return domain.Job{}, fmt.Errorf("повреждена запись задания %d: %w", job.ID, err)
+	}
+	job.Week = parsed
+	job.Status = domain.JobStatus(status)
+	job.CreatedAt = fromUnixTime(created)
+	job.ScheduledAt = fromUnixTime(scheduled)
+	job.StartedAt = fromUnixTime(started)
+	job.FinishedAt = fromUnixTime(finished)
+	if failedGroups != "" {
+		job.FailedGroups = strings.Split(failedGroups, "\n")
+	}
+	return job, nil
+}
+
+// unixTime хранит момент времени в секундах Unix; нулевое время — 0
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromUnixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).In(domain.Location())
+}
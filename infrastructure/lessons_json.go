@@ -0,0 +1,306 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// exportedLesson — занятие в выгрузке JSON. Поля совпадают с занятием JSON API
+type exportedLesson struct {
+	ID         string   `json:"id"`
+	Date       string   `json:"date"` // 2006-01-02
+	Weekday    string   `json:"weekday"`
+	Number     int      `json:"number"`
+	PairHalf   int      `json:"pairHalf"` // 0 — вся пара, 1 или 2 — половина
+	Hours      int      `json:"hours"`
+	StartTime  string   `json:"startTime,omitempty"` // 15:04
+	EndTime    string   `json:"endTime,omitempty"`
+	Discipline string   `json:"discipline"`
+	Teachers   []string `json:"teachers"`
+	Cabinet    string   `json:"cabinet,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Student    string   `json:"student,omitempty"`
+	Subgroup   string   `json:"subgroup,omitempty"`
+	Source     string   `json:"source"` // individual, group, imported
+}
+
+// ExportLessonsJSON записывает занятия массивом JSON — в том виде, в каком их получила
+// проверка, для самостоятельного анализа (pandas.read_json, Power Query в Excel)
+func ExportLessonsJSON(w io.Writer, lessons []domain.Lesson) error {
+	result := make([]exportedLesson, 0, len(lessons))
+	for _, lesson := range lessons {
+		item := exportedLesson{
+			ID:         lesson.ID,
+			Date:       lesson.Time.DateString(),
+			Weekday:    lesson.Time.DayName(),
+			Number:     lesson.Time.Number,
+			PairHalf:   lesson.Time.PairHalf,
+			Hours:      lesson.Time.Hours,
+			Discipline: lesson.Discipline,
+			Teachers:   []string{},
+			Cabinet:    lesson.Cabinet,
+			Group:      lesson.Group,
+			Student:    lesson.Student,
+			Subgroup:   lesson.Subgroup,
+			Source:     string(lesson.Source),
+		}
+		if !lesson.Time.StartTime.IsZero() && lesson.Time.EndTime.After(lesson.Time.StartTime) {
+			item.StartTime = lesson.Time.StartTimeString()
+			item.EndTime = lesson.Time.EndTimeString()
+		}
+		for _, teacher := range lesson.Teachers {
+			item.Teachers = append(item.Teachers, teacher.Name)
+		}
+		result = append(result, item)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// ImportLessonsJSON читает занятия, выгруженные ExportLessonsJSON. Идентификаторы
+// вычисляются заново, поэтому файл можно править вручную (например, обезличить студентов);
+// время пары без startTime/endTime берётся по расписанию звонков по умолчанию.
+// Каждое занятие сверяется со схемой выгрузки: неизвестные поля, значения не того типа,
+// пропущенные обязательные поля. Если хоть одно занятие не прошло проверку, файл
+// не загружается, а все ошибки возвращаются вместе как ImportErrors с номерами занятий.
+func ImportLessonsJSON(r io.Reader) ([]domain.Lesson, error) {
+	var rows []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать JSON (ожидается массив занятий): %w", err)
+	}
+
+	times := NewIndividualScheduleParser(nil)
+	lessons := make([]domain.Lesson, 0, len(rows))
+	var errs ImportErrors
+	for i, row := range rows {
+		lesson, rowErrs := importLesson(row, times)
+		for _, e := range rowErrs {
+			e.Row = i + 1
+			errs = append(errs, e)
+		}
+		if len(rowErrs) == 0 {
+			lessons = append(lessons, lesson)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return lessons, nil
+}
+
+// importLesson разбирает одно занятие выгрузки и возвращает все найденные в нём ошибки
+func importLesson(row json.RawMessage, times *IndividualScheduleParser) (domain.Lesson, []ImportError) {
+	item, errs := decodeLesson(row)
+	if len(errs) > 0 {
+		return domain.Lesson{}, errs
+	}
+
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, ImportError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	date, err := time.ParseInLocation("2006-01-02", item.Date, domain.Location())
+	if err != nil {
+		invalid("date", "некорректная дата %q, ожидается ГГГГ-ММ-ДД", item.Date)
+	}
+	if item.Number < 1 {
+		invalid("number", "некорректный номер пары %d", item.Number)
+	}
+	if item.PairHalf < 0 || item.PairHalf > 2 {
+		invalid("pairHalf", "некорректная половина пары %d, ожидается 0, 1 или 2", item.PairHalf)
+	}
+	if item.Hours < 1 {
+		invalid("hours", "количество часов должно быть положительным, указано %d", item.Hours)
+	}
+	if strings.TrimSpace(item.Discipline) == "" {
+		invalid("discipline", "не указана дисциплина")
+	}
+	if item.Group == "" && item.Student == "" {
+		invalid("group", "не указаны ни группа, ни студент")
+	}
+	for _, name := range item.Teachers {
+		if strings.TrimSpace(name) == "" {
+			invalid("teachers", "пустое имя преподавателя")
+			break
+		}
+	}
+	source := domain.LessonSource(item.Source)
+	switch source {
+	case domain.SourceIndividual, domain.SourceGroup, domain.SourceImported:
+	case "":
+		source = domain.SourceImported
+	default:
+		invalid("source", "неизвестный источник %q", item.Source)
+	}
+
+	var start, end time.Time
+	if item.StartTime != "" || item.EndTime != "" {
+		var startErr, endErr error
+		if start, startErr = lessonClock(date, item.StartTime); startErr != nil {
+			invalid("startTime", "%v", startErr)
+		}
+		if end, endErr = lessonClock(date, item.EndTime); endErr != nil {
+			invalid("endTime", "%v", endErr)
+		}
+		if startErr == nil && endErr == nil && !end.After(start) {
+			invalid("endTime", "окончание %s не позже начала %s", item.EndTime, item.StartTime)
+		}
+	}
+	if len(errs) > 0 {
+		return domain.Lesson{}, errs
+	}
+	if start.IsZero() {
+		start, end = times.parsePairTime(date, item.Number)
+	}
+
+	lesson := domain.Lesson{
+		Time:       domain.NewLessonTime(date, item.Number, item.PairHalf, item.Hours, start, end),
+		Discipline: item.Discipline,
+		Cabinet:    item.Cabinet,
+		Group:      item.Group,
+		Student:    item.Student,
+		Subgroup:   item.Subgroup,
+		Source:     source,
+	}
+	for _, name := range item.Teachers {
+		lesson.Teachers = append(lesson.Teachers, domain.NewTeacher(name))
+	}
+	lesson.ID = lesson.ComputeID()
+	return lesson, nil
+}
+
+// lessonFields — поля занятия в выгрузке JSON
+var lessonFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(exportedLesson{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+	return fields
+}()
+
+// decodeLesson разбирает занятие по полям, чтобы сообщить обо всех неизвестных полях
+// и значениях не того типа, а не только о первом
+func decodeLesson(row json.RawMessage) (exportedLesson, []ImportError) {
+	var item exportedLesson
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return item, []ImportError{jsonFieldError(err)}
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []ImportError
+	for _, name := range names {
+		if !lessonFields[name] {
+			errs = append(errs, ImportError{Field: name, Message: "неизвестное поле"})
+			continue
+		}
+		field, err := json.Marshal(map[string]json.RawMessage{name: fields[name]})
+		if err == nil {
+			err = json.Unmarshal(field, &item)
+		}
+		if err != nil {
+			errs = append(errs, jsonFieldError(err))
+		}
+	}
+	return item, errs
+}
+
+// jsonFieldError описывает ошибку разбора занятия: значение не того типа или занятие,
+// записанное не объектом
+func jsonFieldError(err error) ImportError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return ImportError{Message: fmt.Sprintf("занятие должно быть объектом, указано %s", jsonTypeName(typeErr.Value))}
+		}
+		expected := "строка"
+		switch typeErr.Type.Kind() {
+		case reflect.Int:
+			expected = "целое число"
+		case reflect.Slice:
+			expected = "массив строк"
+		}
+		return ImportError{Field: typeErr.Field, Message: fmt.Sprintf("ожидается %s, указано %s", expected, jsonTypeName(typeErr.Value))}
+	}
+	return ImportError{Message: fmt.Sprintf("не удалось разобрать занятие: %v", err)}
+}
+
+// jsonTypeName возвращает название типа значения JSON из ошибки разбора
+func jsonTypeName(value string) string {
+	switch {
+	case value == "string":
+		return "строка"
+	case value == "bool":
+		return "логическое значение"
+	case value == "array":
+		return "массив"
+	case value == "object":
+		return "объект"
+	case strings.HasPrefix(value, "number"):
+		return "число"
+	default:
+		return value
+	}
+}
+
+// lessonClock возвращает время value ("15:04") в день date
+func lessonClock(date time.Time, value string) (time.Time, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректное время %q", value)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, domain.Location()), nil
+}
+
+// JSONLessonsRepository отдаёт занятия недели из выгрузки lessons.json вместо разбора
+// XLS-файлов и сайта: для повторной проверки тех же данных с изменёнными правилами
+// и для примеров к сообщениям об ошибках без настоящих XLS-файлов
+type JSONLessonsRepository struct {
+	filename string
+	week     domain.Week
+}
+
+// NewJSONLessonsRepository создаёт репозиторий занятий недели week из файла filename
+func NewJSONLessonsRepository(filename string, week domain.Week) *JSONLessonsRepository {
+	return &JSONLessonsRepository{filename: filename, week: week}
+}
+
+// GetLessons возвращает занятия файла, попадающие в неделю репозитория
+func (r *JSONLessonsRepository) GetLessons() ([]domain.Lesson, error) {
+	file, err := os.Open(r.filename)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл занятий: %w", err)
+	}
+	defer file.Close()
+
+	lessons, err := ImportLessonsJSON(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.filename, err)
+	}
+	var result []domain.Lesson
+	for _, lesson := range lessons {
+		if r.week.Contains(lesson.Time.Date) {
+			result = append(result, lesson)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("в файле %s нет занятий за неделю %s", r.filename, r.week)
+	}
+	return result, nil
+}
@@ -1,6 +1,8 @@
 package infrastructure
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -8,126 +10,350 @@ import (
 	"github.com/Vaflel/lesson-counter/domain"
 )
 
+// groupCacheTTL — время хранения групповых уроков в кэше; меняется через SetGroupCacheTTL
+var groupCacheTTL = 30 * time.Minute
+
+// SetGroupCacheTTL задаёт время хранения групповых уроков в кэше. Неположительные значения игнорируются
+func SetGroupCacheTTL(ttl time.Duration) {
+	if ttl > 0 {
+		groupCacheTTL = ttl
+	}
+}
+
+// siteMaxParallel — сколько расписаний групп загружается с сайта одновременно;
+// меняется через SetSiteMaxParallel
+var siteMaxParallel = 4
+
+// SetSiteMaxParallel задаёт общий предел одновременных запросов к сайту за расписанием групп.
+// Неположительные значения игнорируются
+func SetSiteMaxParallel(n int) {
+	if n > 0 {
+		siteMaxParallel = n
+	}
+}
+
 // GroupLessonsCache представляет объект кэша для хранения групповых уроков в оперативной памяти.
-// Кэш хранит уроки по ключу weekStart с временем истечения (TTL 30 минут).
+// Кэш хранит уроки группы отделения за неделю с временем истечения (по умолчанию 30 минут).
+// Истёкшая запись не выдаётся, но остаётся в кэше, чтобы новую версию расписания группы
+// можно было сравнить с прежней.
 // Доступ к кэшу синхронизирован с помощью мьютекса для безопасной работы в многопоточной среде.
 type GroupLessonsCache struct {
 	mu   sync.Mutex
-	data map[string]struct {
-		lessons []domain.Lesson
-		expiry  time.Time // Время истечения кэша для записи
-	}
+	data map[string]groupCacheEntry
+}
+
+// groupCacheEntry — запись кэша: уроки группы и их отличия от прежней версии
+type groupCacheEntry struct {
+	lessons []domain.Lesson
+	expiry  time.Time            // Время истечения кэша для записи
+	changes domain.LessonChanges // Отличия от версии, которую запись заменила
 }
 
 // NewGroupLessonsCache создаёт новый экземпляр кэша групповых уроков.
 func NewGroupLessonsCache() *GroupLessonsCache {
 	return &GroupLessonsCache{
-		data: make(map[string]struct {
-			lessons []domain.Lesson
-			expiry  time.Time
-		}),
+		data: make(map[string]groupCacheEntry),
 	}
 }
 
-// Get возвращает кэшированные уроки для указанного weekStart, если они существуют и не истекли.
-// Если кэш истёк, запись удаляется. Возвращает уроки и флаг успеха (true, если кэш валиден).
-func (c *GroupLessonsCache) Get(weekStart string) ([]domain.Lesson, bool) {
+// sharedGroupLessons — кэш групповых уроков, общий для всех проверок и прогрева
+var sharedGroupLessons = NewGroupLessonsCache()
+
+// groupCacheKey возвращает ключ записи кэша: неделя, отделение и группа
+func groupCacheKey(week domain.Week, department domain.Department, group string) string {
+	return week.String() + "|" + department.Name + "|" + group
+}
+
+// Get возвращает кэшированные уроки группы отделения за неделю, если они существуют и не истекли,
+// и их отличия от версии, загруженной перед ними. Возвращает уроки, изменения и флаг успеха
+// (true, если кэш валиден).
+func (c *GroupLessonsCache) Get(week domain.Week, department domain.Department, group string) ([]domain.Lesson, domain.LessonChanges, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	entry, exists := c.data[weekStart]
-	if !exists {
-		return nil, false
+	entry, exists := c.data[groupCacheKey(week, department, group)]
+	if !exists || time.Now().After(entry.expiry) {
+		return nil, domain.LessonChanges{}, false
 	}
+	return entry.lessons, entry.changes, true
+}
 
-	if time.Now().After(entry.expiry) {
-		delete(c.data, weekStart)
-		return nil, false
-	}
+// Diff сравнивает загруженные уроки группы с версией в кэше, в том числе истёкшей.
+// Если группы в кэше нет, изменений нет.
+func (c *GroupLessonsCache) Diff(week domain.Week, department domain.Department, group string, lessons []domain.Lesson) domain.LessonChanges {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return entry.lessons, true
+	entry, exists := c.data[groupCacheKey(week, department, group)]
+	if !exists {
+		return domain.LessonChanges{}
+	}
+	return domain.DiffLessons(entry.lessons, lessons)
 }
 
-// Set сохраняет уроки в кэш для указанного weekStart с TTL 30 минут.
-func (c *GroupLessonsCache) Set(weekStart string, lessons []domain.Lesson) {
+// Set сохраняет уроки группы отделения за неделю в кэш на время ttl и возвращает их отличия
+// от прежней версии в кэше. Запись с более поздним временем истечения (например, от прогрева)
+// не сокращается.
+func (c *GroupLessonsCache) Set(week domain.Week, department domain.Department, group string, lessons []domain.Lesson, ttl time.Duration) domain.LessonChanges {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data[weekStart] = struct {
-		lessons []domain.Lesson
-		expiry  time.Time
-	}{
+	key := groupCacheKey(week, department, group)
+	expiry := time.Now().Add(ttl)
+	var changes domain.LessonChanges
+	if entry, exists := c.data[key]; exists {
+		if entry.expiry.After(expiry) {
+			expiry = entry.expiry
+		}
+		changes = domain.DiffLessons(entry.lessons, lessons)
+	}
+	c.data[key] = groupCacheEntry{
 		lessons: lessons,
-		expiry:  time.Now().Add(30 * time.Minute),
+		expiry:  expiry,
+		changes: changes,
 	}
+	return changes
 }
 
 // LessonsRepositoryImpl реализует интерфейс LessonsRepository для получения уроков.
 // Хранит списки отделений, групп, начало недели и список уроков.
 // Использует встроенный кэш для групповых уроков в оперативной памяти.
 type LessonsRepositoryImpl struct {
-	departments []string
+	departments []domain.Department
 	groups      []string
-	weekStart   string
+	week        domain.Week
 	lessons     []domain.Lesson
 	mu          sync.Mutex
-	cache       *GroupLessonsCache // Встроенный объект кэша для групповых уроков
+	cache       *GroupLessonsCache  // Кэш групповых уроков, общий для всех проверок
+	diagnostics *domain.Diagnostics // Некритичные проблемы загрузки, может быть nil
+	mergePolicy domain.MergePolicy  // Политика объединения половинок индивидуальных пар
+	weekNumber  int                 // Номер недели по учебному календарю, 0 — не задан
+	bells       domain.BellSchedule // Расписание звонков для индивидуальных занятий
+	skipDir     string              // Каталог, XLS-файлы которого не разбираются (архив)
+	files       []string            // XLS-файлы вместо поиска в рабочей папке, пусто — искать
+	debug       *DebugDump          // Промежуточные данные разбора XLS-файлов, nil — не записываются
+	coverage    []domain.FileCoverage
+	teachers    domain.TeacherRegistry // Подтверждённые псевдонимы преподавателей
+	aliasPairs  [][2]string            // Кандидаты в псевдонимы, найденные при разборе XLS-файлов
+	failed      []string               // Группы, расписание которых не загрузилось с сайта даже после повторов
+	dryRun      bool                   // Пробная проверка: загруженное с сайта не сохраняется в кэш
 }
 
-// NewLessonsRepository создаёт новый репозиторий уроков с инициализацией кэша.
-func NewLessonsRepository(departments []string, groups []string, weekStart string) *LessonsRepositoryImpl {
+// NewLessonsRepository создаёт новый репозиторий уроков, использующий общий кэш групповых уроков.
+// Проблемы с отдельными источниками записываются в diagnostics, если он передан.
+func NewLessonsRepository(departments []domain.Department, groups []string, week domain.Week, diagnostics *domain.Diagnostics) *LessonsRepositoryImpl {
 	return &LessonsRepositoryImpl{
 		departments: departments,
 		groups:      groups,
-		weekStart:   weekStart,
+		week:        week,
 		lessons:     make([]domain.Lesson, 0),
-		cache:       NewGroupLessonsCache(),
+		cache:       sharedGroupLessons,
+		diagnostics: diagnostics,
+		mergePolicy: domain.MergePolicyJoin,
+		bells:       domain.DefaultBellSchedule(),
 	}
 }
 
+// SetBellSchedule задаёт расписание звонков для вычисления времени индивидуальных занятий.
+func (r *LessonsRepositoryImpl) SetBellSchedule(bells domain.BellSchedule) {
+	r.bells = bells
+}
+
+// SetSkipDir исключает каталог (например, архив обработанных файлов) из поиска XLS-файлов.
+func (r *LessonsRepositoryImpl) SetSkipDir(dir string) {
+	r.skipDir = dir
+}
+
+// SetFiles ограничивает индивидуальное расписание указанными XLS-файлами (например, только что
+// загруженными) вместо всех файлов рабочей папки.
+func (r *LessonsRepositoryImpl) SetFiles(paths []string) {
+	r.files = paths
+}
+
+// SetDebugDump включает запись промежуточных данных разбора XLS-файлов.
+func (r *LessonsRepositoryImpl) SetDebugDump(debug *DebugDump) {
+	r.debug = debug
+}
+
+// SetTeacherRegistry задаёт справочник преподавателей для замены псевдонимов в XLS-файлах.
+func (r *LessonsRepositoryImpl) SetTeacherRegistry(registry domain.TeacherRegistry) {
+	r.teachers = registry
+}
+
+// AliasPairs возвращает пары написаний имени преподавателя, различающихся только пробелами
+// и знаками препинания, найденные GetLessons у одних и тех же занятий.
+func (r *LessonsRepositoryImpl) AliasPairs() [][2]string {
+	return r.aliasPairs
+}
+
+// Coverage возвращает охват дат и преподавателей XLS-файлов, разобранных GetLessons.
+func (r *LessonsRepositoryImpl) Coverage() []domain.FileCoverage {
+	return r.coverage
+}
+
+// SetMergePolicy задаёт политику объединения половинок индивидуальных пар с разными данными.
+func (r *LessonsRepositoryImpl) SetMergePolicy(policy domain.MergePolicy) {
+	r.mergePolicy = policy
+}
+
+// SetDryRun включает пробный режим: расписание групп по-прежнему берётся из кэша, но загруженное
+// с сайта в кэш не сохраняется
+func (r *LessonsRepositoryImpl) SetDryRun(dryRun bool) {
+	r.dryRun = dryRun
+}
+
+// SetWeekNumber задаёт номер недели по учебному календарю для запросов к сайту расписания.
+func (r *LessonsRepositoryImpl) SetWeekNumber(n int) {
+	r.weekNumber = n
+}
+
 // GetLessons возвращает список индивидуальных и групповых уроков.
-// Сначала парсит индивидуальные уроки, затем проверяет кэш на наличие групповых.
-// Если кэш валиден, использует его; иначе парсит групповые уроки, сохраняет в кэш и возвращает.
-// Парсинг групповых уроков выполняется параллельно в горутинах.
+// Сначала парсит индивидуальные уроки, затем групповые: расписание группы берётся из кэша,
+// если оно там есть, иначе загружается с сайта и сохраняется в кэш.
+// Загрузка групповых уроков выполняется параллельно в горутинах.
+// Ошибки отдельных источников не прерывают загрузку: возвращаются все полученные уроки
+// и объединённая ошибка по источникам, которые загрузить не удалось.
 func (r *LessonsRepositoryImpl) GetLessons() ([]domain.Lesson, error) {
+	var errs []error
+
 	// Парсинг индивидуальных уроков (без кэширования)
-	individualParser := NewIndividualScheduleParser()
-	if individualLessons, err := individualParser.Parse(); err == nil {
+	individualParser := NewIndividualScheduleParser(r.diagnostics)
+	individualParser.SetMergePolicy(r.mergePolicy)
+	individualParser.SetBellSchedule(r.bells)
+	individualParser.SetSkipDir(r.skipDir)
+	individualParser.SetFiles(r.files)
+	individualParser.SetDebugDump(r.debug)
+	individualParser.SetTeacherRegistry(r.teachers)
+	if individualLessons, err := safeParse(individualParser.Parse); err == nil {
 		r.mu.Lock()
 		r.lessons = append(r.lessons, individualLessons...)
 		r.mu.Unlock()
+		r.coverage = individualParser.Coverage()
+		r.aliasPairs = individualParser.AliasPairs()
+		// Преподаватель, не приславший файл на новую неделю, — частая причина «пропавших» занятий
+		for _, stale := range domain.StaleTeachers(r.coverage, r.week) {
+			if stale.Teacher == "Unknown" {
+				continue
+			}
+			r.diagnostics.Add(domain.IssueStaleFile, stale.File,
+				"последний файл преподавателя %s заканчивается %s — раньше проверяемой недели; возможно, новый файл не прислан",
+				stale.Teacher, stale.Last.Format("02.01.2006"))
+		}
 	} else {
 		log.Printf("Error parsing individual schedule: %v", err)
+		errs = append(errs, fmt.Errorf("индивидуальное расписание: %w", err))
+		r.diagnostics.Add(domain.IssueSourceFailed, "индивидуальное расписание", "%v", err)
 	}
 
-	// Проверка кэша для групповых уроков
-	if cachedLessons, ok := r.cache.Get(r.weekStart); ok {
-		r.mu.Lock()
-		r.lessons = append(r.lessons, cachedLessons...)
-		r.mu.Unlock()
-		return r.lessons, nil
-	}
+	groupLessons, groupErrs := r.loadGroupLessons(false, groupCacheTTL)
+	r.mu.Lock()
+	r.lessons = append(r.lessons, groupLessons...)
+	r.mu.Unlock()
 
-	// Парсинг групповых уроков в горутинах, если кэш не валиден
+	return r.lessons, errors.Join(append(errs, groupErrs...)...)
+}
+
+// RefreshGroups заново загружает с сайта расписание групп репозитория, не заглядывая в кэш, —
+// для повторной загрузки групп, не загрузившихся при проверке. Загруженное сохраняется в кэш;
+// группы, которые снова не загрузились, возвращает FailedGroups.
+func (r *LessonsRepositoryImpl) RefreshGroups() ([]domain.Lesson, error) {
+	lessons, errs := r.loadGroupLessons(true, groupCacheTTL)
+	return lessons, errors.Join(errs...)
+}
+
+// FailedGroups возвращает группы, расписание которых не удалось загрузить с сайта даже
+// после повторов. Группы, которых нет на сайте, сюда не входят: повтор им не поможет.
+func (r *LessonsRepositoryImpl) FailedGroups() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.failed...)
+}
+
+// WarmGroupCache загружает с сайта расписание всех групп за неделю, не заглядывая в кэш,
+// и сохраняет его в кэш на время ttl, чтобы следующая проверка не ждала сайт.
+// Возвращает число загруженных занятий и ошибки по группам, которые загрузить не удалось.
+func (r *LessonsRepositoryImpl) WarmGroupCache(ttl time.Duration) (int, error) {
+	lessons, errs := r.loadGroupLessons(true, ttl)
+	return len(lessons), errors.Join(errs...)
+}
+
+// loadGroupLessons загружает групповые уроки всех групп во всех отделениях, параллельно
+// в горутинах. Без refresh расписание группы сначала ищется в кэше; загруженное с сайта
+// сохраняется в кэш на время ttl.
+func (r *LessonsRepositoryImpl) loadGroupLessons(refresh bool, ttl time.Duration) ([]domain.Lesson, []error) {
+	var errs []error
 	var wg sync.WaitGroup
 	var groupLessons []domain.Lesson
 	var groupMu sync.Mutex
+	// Группа ищется во всех отделениях, поэтому ошибка засчитывается,
+	// только если группу не удалось загрузить ни из одного отделения
+	groupLoaded := make(map[string]bool)
+	groupErrs := make(map[string]error)
+	// Сколько отделений искали группу и во скольких из них её точно нет на сайте
+	groupAttempts := make(map[string]int)
+	groupMissing := make(map[string]int)
+	var siteGroups []string
+	// Медленные отделения сайта не выдерживают много запросов сразу: общий предел
+	// задаётся в настройках, а для отдельного отделения его можно уменьшить
+	parallel := make(chan struct{}, siteMaxParallel)
 
 	for _, department := range r.departments {
+		if !department.LoadsFromSite() {
+			continue
+		}
+		var departmentParallel chan struct{}
+		if department.MaxParallel > 0 {
+			departmentParallel = make(chan struct{}, department.MaxParallel)
+		}
 		for _, group := range r.groups {
 			wg.Add(1)
-			go func(dep, grp string) {
+			go func(dep domain.Department, grp string) {
 				defer wg.Done()
-				gsp := NewGroupScheduleParser(dep, grp, r.weekStart)
-				if lessons, err := gsp.Parse(); err == nil {
+				if !refresh {
+					if cached, changes, ok := r.cache.Get(r.week, dep, grp); ok {
+						groupMu.Lock()
+						groupLessons = append(groupLessons, cached...)
+						groupLoaded[grp] = true
+						groupMu.Unlock()
+						r.reportChanges(grp, changes)
+						return
+					}
+				}
+				if departmentParallel != nil {
+					departmentParallel <- struct{}{}
+					defer func() { <-departmentParallel }()
+				}
+				parallel <- struct{}{}
+				defer func() { <-parallel }()
+
+				gsp := NewGroupScheduleParser(dep, grp, r.week)
+				gsp.SetWeekNumber(r.weekNumber)
+				lessons, err := parseGroupWithRetries(gsp)
+
+				groupMu.Lock()
+				groupAttempts[grp]++
+				var notFound *GroupNotFoundError
+				if errors.As(err, &notFound) {
+					groupMissing[grp]++
+					siteGroups = append(siteGroups, notFound.Known...)
+				}
+				groupMu.Unlock()
+
+				if err == nil {
+					if r.dryRun {
+						r.reportChanges(grp, r.cache.Diff(r.week, dep, grp, lessons))
+					} else {
+						r.reportChanges(grp, r.cache.Set(r.week, dep, grp, lessons, ttl))
+					}
 					groupMu.Lock()
 					groupLessons = append(groupLessons, lessons...)
+					groupLoaded[grp] = true
 					groupMu.Unlock()
-					r.mu.Lock()
-					r.lessons = append(r.lessons, lessons...)
-					r.mu.Unlock()
 				} else {
 					log.Printf("Error parsing group schedule for group %s: %v", grp, err)
+					groupMu.Lock()
+					groupErrs[grp] = errors.Join(groupErrs[grp], fmt.Errorf("%s: %w", dep.Name, err))
+					groupMu.Unlock()
 				}
 			}(department, group)
 		}
@@ -135,8 +361,68 @@ func (r *LessonsRepositoryImpl) GetLessons() ([]domain.Lesson, error) {
 
 	wg.Wait()
 
-	// Сохранение групповых уроков в кэш
-	r.cache.Set(r.weekStart, groupLessons)
+	for _, group := range r.groups {
+		if groupLoaded[group] || groupErrs[group] == nil {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("расписание группы %s: %w", group, groupErrs[group]))
+		if groupMissing[group] == groupAttempts[group] {
+			// сайт ответил, но группы нет ни в одном отделении — скорее всего, опечатка в students.yaml
+			hint := ""
+			if similar, ok := domain.ClosestName(group, siteGroups); ok {
+				hint = fmt.Sprintf(" Возможно, имелась в виду %s.", similar)
+			}
+			r.diagnostics.Add(domain.IssueGroupUnknown, group, "группа %s не найдена на сайте — опечатка?%s", group, hint)
+			continue
+		}
+		r.diagnostics.Add(domain.IssueSourceFailed, group, "%v", groupErrs[group])
+		r.mu.Lock()
+		r.failed = append(r.failed, group)
+		r.mu.Unlock()
+	}
+
+	return groupLessons, errs
+}
+
+// reportChanges отмечает в диагностике, что расписание группы на сайте изменилось с прошлой
+// загрузки: изменения посреди недели чаще всего и ломают индивидуальные расписания
+func (r *LessonsRepositoryImpl) reportChanges(group string, changes domain.LessonChanges) {
+	if changes.IsEmpty() {
+		return
+	}
+	log.Printf("Расписание группы %s изменилось: %s", group, changes)
+	r.diagnostics.Add(domain.IssueGroupChanged, group, "расписание группы %s изменилось с прошлой загрузки — %s", group, changes)
+}
+
+// groupFetchAttempts — сколько раз загружать расписание группы, прежде чем считать загрузку
+// неудачной: под нагрузкой сайт расписания иногда обрывает отдельные запросы
+const groupFetchAttempts = 3
+
+// groupRetryDelay — пауза перед повторной загрузкой группы; растёт с каждой попыткой
+var groupRetryDelay = 2 * time.Second
 
-	return r.lessons, nil
+// parseGroupWithRetries загружает расписание группы, повторяя загрузку при ошибке. Если сайт
+// ответил, что группы нет, повторов не делается.
+func parseGroupWithRetries(gsp *GroupScheduleParser) ([]domain.Lesson, error) {
+	for attempt := 1; ; attempt++ {
+		lessons, err := safeParse(gsp.Parse)
+		var notFound *GroupNotFoundError
+		if err == nil || errors.As(err, &notFound) || attempt == groupFetchAttempts {
+			return lessons, err
+		}
+		log.Printf("Повтор загрузки группы %s (%s), попытка %d из %d: %v", gsp.groupName, gsp.department.Name, attempt+1, groupFetchAttempts, err)
+		time.Sleep(groupRetryDelay * time.Duration(attempt))
+	}
+}
+
+// safeParse вызывает функцию парсинга и превращает панику в обычную ошибку,
+// чтобы сбой одного источника расписания не завершал весь процесс.
+func safeParse(parse func() ([]domain.Lesson, error)) (lessons []domain.Lesson, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			lessons = nil
+			err = fmt.Errorf("паника при парсинге: %v", r)
+		}
+	}()
+	return parse()
 }
@@ -0,0 +1,35 @@
+package infrastructure
+
+import (
+	"log"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// LogListener записывает события проверки в стандартный лог
+type LogListener struct{}
+
+// NewLogListener создаёт слушателя, логирующего события проверки
+func NewLogListener() *LogListener {
+	return &LogListener{}
+}
+
+// HandleEvent реализует domain.EventListener
+func (l *LogListener) HandleEvent(event domain.Event) {
+	switch event.Type {
+	case domain.EventCheckStarted:
+		log.Printf("Проверка расписания за неделю %s запущена", event.Week)
+	case domain.EventSourceParsed:
+		log.Printf("Загружено занятий из источника «%s»: %d", event.Source.DisplayName(), event.Count)
+	case domain.EventViolationFound:
+		v := event.Violation
+		log.Printf("Нарушение: %s (%s), %s, %s — %d ак.ч",
+			v.StudentName, v.Group, v.Date.Format("2006-01-02"), v.Kind.DisplayName(), v.Hours)
+	case domain.EventCheckCompleted:
+		if event.Err != nil {
+			log.Printf("Проверка за неделю %s завершилась ошибкой: %v", event.Week, event.Err)
+			return
+		}
+		log.Printf("Проверка за неделю %s завершена, нарушений: %d", event.Week, event.Count)
+	}
+}
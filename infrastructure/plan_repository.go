@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// PlanConfig структура файла учебного плана
+type PlanConfig struct {
+	Semesters []SemesterYAML  `yaml:"semesters"`
+	Plan      []PlanEntryYAML `yaml:"plan"`
+}
+
+// SemesterYAML описывает семестр в YAML: даты в формате "2006-01-02"
+type SemesterYAML struct {
+	Name  string `yaml:"name"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// toDomain преобразует семестр из YAML в domain.Semester
+func (s SemesterYAML) toDomain() (domain.Semester, error) {
+	start, err := parseConfigDate(s.Start)
+	if err != nil {
+		return domain.Semester{}, fmt.Errorf("семестр %q: неверная дата начала: %w", s.Name, err)
+	}
+	end, err := parseConfigDate(s.End)
+	if err != nil {
+		return domain.Semester{}, fmt.Errorf("семестр %q: неверная дата окончания: %w", s.Name, err)
+	}
+	return domain.Semester{Name: s.Name, Start: start, End: end}, nil
+}
+
+// PlanEntryYAML описывает строку плана в YAML
+type PlanEntryYAML struct {
+	Student    string `yaml:"student"`
+	Discipline string `yaml:"discipline"`
+	Semester   string `yaml:"semester"`
+	Hours      int    `yaml:"hours"`
+}
+
+// YAMLPlanRepository загружает учебный план часов из YAML-файла
+type YAMLPlanRepository struct {
+	filename string
+	store    documentStore // файл или база, см. Storage
+}
+
+// NewYAMLPlanRepository создает новый экземпляр репозитория плана
+func NewYAMLPlanRepository(filename string) *YAMLPlanRepository {
+	return &YAMLPlanRepository{
+		filename: filename,
+		store:    fileStore{},
+	}
+}
+
+// LoadPlan загружает семестры и строки плана. Отсутствие файла не считается ошибкой.
+func (r *YAMLPlanRepository) LoadPlan() ([]domain.Semester, []domain.PlanEntry, error) {
+	data, err := r.store.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	var config PlanConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+
+	semesters := make([]domain.Semester, 0, len(config.Semesters))
+	for _, s := range config.Semesters {
+		semester, err := s.toDomain()
+		if err != nil {
+			return nil, nil, err
+		}
+		semesters = append(semesters, semester)
+	}
+
+	plan := make([]domain.PlanEntry, 0, len(config.Plan))
+	for _, p := range config.Plan {
+		plan = append(plan, domain.PlanEntry{
+			Student:    p.Student,
+			Discipline: p.Discipline,
+			Semester:   p.Semester,
+			Hours:      p.Hours,
+		})
+	}
+	return semesters, plan, nil
+}
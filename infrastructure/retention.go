@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// Prune удаляет из истории проверки недель, закончившихся до before, и комментарии к их
+// нарушениям, которых нет в оставшихся проверках. Возвращает число удалённых проверок.
+func (r *YAMLHistoryRepository) Prune(before time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := config.Checks[:0]
+	keptViolations := make(map[string]bool)
+	removed := 0
+	for _, check := range config.Checks {
+		week, err := domain.ParseWeek(check.Week)
+		if err != nil {
+			return 0, fmt.Errorf("повреждена запись истории: %w", err)
+		}
+		if week.End().Before(before) {
+			removed++
+			continue
+		}
+		kept = append(kept, check)
+		for _, v := range append(check.Violations, check.Resolved...) {
+			keptViolations[v.ID()] = true
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	config.Checks = kept
+
+	comments := config.Comments[:0]
+	for _, comment := range config.Comments {
+		if keptViolations[comment.ViolationID] {
+			comments = append(comments, comment)
+		}
+	}
+	config.Comments = comments
+
+	return removed, r.saveUnsafe(config)
+}
+
+// Prune удаляет папки архива недель, закончившихся до before. Возвращает число удалённых недель.
+func (a *ScheduleArchive) Prune(before time.Time) (int, error) {
+	return pruneDirs(a.dir, func(name string) (time.Time, bool) {
+		var year, number int
+		if _, err := fmt.Sscanf(name, "%04d-%02d", &year, &number); err != nil || number < 1 || number > 53 {
+			return time.Time{}, false
+		}
+		// 4 января всегда приходится на первую неделю года по ISO 8601
+		january4 := time.Date(year, time.January, 4, 0, 0, 0, 0, domain.Location())
+		return domain.WeekOf(january4.AddDate(0, 0, (number-1)*7)).End(), true
+	}, before)
+}
+
+// DebugDirs — каталог отладочных данных разбора XLS-файлов с подкаталогами запусков проверки
+// вида 2025-02-12_153000_2025-02-10 (время запуска и неделя)
+type DebugDirs struct {
+	dir string
+}
+
+// NewDebugDirs создаёт очистку отладочных данных в каталоге dir
+func NewDebugDirs(dir string) *DebugDirs {
+	return &DebugDirs{dir: dir}
+}
+
+// Prune удаляет подкаталоги запусков проверки раньше before. Возвращает число удалённых каталогов.
+func (d *DebugDirs) Prune(before time.Time) (int, error) {
+	const layout = "2006-01-02_150405"
+	return pruneDirs(d.dir, func(name string) (time.Time, bool) {
+		if len(name) < len(layout) {
+			return time.Time{}, false
+		}
+		at, err := time.ParseInLocation(layout, name[:len(layout)], time.Local)
+		return at, err == nil
+	}, before)
+}
+
+// pruneDirs удаляет подкаталоги dir, время которых (по имени, см. parse) раньше before.
+// Подкаталоги с другими именами не трогаются; отсутствующий dir не является ошибкой.
+func pruneDirs(dir string, parse func(name string) (time.Time, bool), before time.Time) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("не удалось прочитать каталог %s: %w", dir, err)
+	}
+
+	removed := 0
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		at, ok := parse(entry.Name())
+		if !ok || !at.Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			errs = append(errs, fmt.Errorf("не удалось удалить %s: %w", entry.Name(), err))
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// ruleConfigVersion — версия формата файла настроек проверки
+const ruleConfigVersion = 1
+
+// RuleConfig — полная настройка проверки в одном файле: пороги стандартных правил,
+// пользовательские правила и допущенные исключения. Файлом обмениваются отделения
+// (площадки), чтобы проверять расписание по одним нормам.
+type RuleConfig struct {
+	Version    int                `yaml:"version"`
+	Exported   string             `yaml:"exported,omitempty"` // время выгрузки, RFC 3339
+	Limits     domain.Limits      `yaml:"limits"`
+	Custom     []CustomRuleConfig `yaml:"custom"`
+	Exceptions []ExceptionYAML    `yaml:"exceptions"`
+}
+
+// LoadRulesConfig возвращает пороги и пользовательские правила в том виде, в каком они
+// записаны в файле; пороги, не заданные в файле, берутся по умолчанию
+func (r *YAMLRulesRepository) LoadRulesConfig() (RulesConfig, error) {
+	config, err := r.load()
+	if err != nil {
+		return config, err
+	}
+	limits := domain.DefaultLimits()
+	if config.Limits != nil {
+		limits = config.Limits.WithDefaults()
+	}
+	config.Limits = &limits
+	return config, nil
+}
+
+// ReplaceRules заменяет пороги и пользовательские правила файла. Правила не проверяются:
+// их проверяет ParseRuleConfig до замены.
+func (r *YAMLRulesRepository) ReplaceRules(limits domain.Limits, custom []CustomRuleConfig) error {
+	data, err := yaml.Marshal(RulesConfig{Limits: &limits, Custom: custom})
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
+
+// NewRuleConfig собирает настройку проверки для выгрузки
+func NewRuleConfig(rules RulesConfig, exceptions []domain.Exception) RuleConfig {
+	config := RuleConfig{
+		Version:    ruleConfigVersion,
+		Exported:   time.Now().In(domain.Location()).Format(time.RFC3339),
+		Limits:     domain.DefaultLimits(),
+		Custom:     rules.Custom,
+		Exceptions: make([]ExceptionYAML, 0, len(exceptions)),
+	}
+	if rules.Limits != nil {
+		config.Limits = *rules.Limits
+	}
+	if config.Custom == nil {
+		config.Custom = []CustomRuleConfig{}
+	}
+	for _, exception := range exceptions {
+		config.Exceptions = append(config.Exceptions, exceptionToYAML(exception))
+	}
+	return config
+}
+
+// ExportRuleConfig записывает настройку проверки в YAML
+func ExportRuleConfig(w io.Writer, config RuleConfig) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+// ParseRuleConfig читает настройку проверки, выгруженную ExportRuleConfig, и проверяет её
+// целиком: пороги, выражения пользовательских правил, виды нарушений, дни недели и даты
+// исключений. Если ошибка есть хоть в одной записи, настройка не возвращается, а все
+// ошибки возвращаются вместе как ImportErrors с номерами записей.
+func ParseRuleConfig(r io.Reader) (RuleConfig, []domain.Exception, error) {
+	var config RuleConfig
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		if errors.Is(err, io.EOF) {
+			return config, nil, errors.New("файл настроек пуст")
+		}
+		return config, nil, fmt.Errorf("не удалось разобрать файл настроек: %w", err)
+	}
+	if config.Version != ruleConfigVersion {
+		return config, nil, fmt.Errorf("неподдерживаемая версия файла настроек %d, ожидается %d", config.Version, ruleConfigVersion)
+	}
+
+	var errs ImportErrors
+	config.Limits = config.Limits.WithDefaults()
+	if err := config.Limits.Validate(); err != nil {
+		errs = append(errs, ImportError{Field: "limits", Message: err.Error()})
+	}
+
+	names := make(map[string]bool)
+	for i, rule := range config.Custom {
+		switch {
+		case rule.Name == "":
+			errs = append(errs, ImportError{File: "custom", Row: i + 1, Field: "name", Message: "не указано название правила"})
+		case names[rule.Name]:
+			errs = append(errs, ImportError{File: "custom", Row: i + 1, Field: "name", Message: fmt.Sprintf("правило %q указано дважды", rule.Name)})
+		}
+		names[rule.Name] = true
+		if _, err := domain.CompileRule(rule.Name, rule.Expression); err != nil {
+			errs = append(errs, ImportError{File: "custom", Row: i + 1, Field: "expression", Message: err.Error()})
+		}
+	}
+
+	exceptions := make([]domain.Exception, 0, len(config.Exceptions))
+	for i, entry := range config.Exceptions {
+		switch entry.Kind {
+		case domain.ViolationOverload, domain.ViolationGaps, domain.ViolationCustom, domain.ViolationClash, domain.ViolationWeeklyOverload, domain.ViolationConsecutive, domain.ViolationOverlap:
+		default:
+			errs = append(errs, ImportError{File: "exceptions", Row: i + 1, Field: "kind", Message: fmt.Sprintf("неизвестный вид нарушения %q", entry.Kind)})
+			continue
+		}
+		if entry.Student == "" {
+			errs = append(errs, ImportError{File: "exceptions", Row: i + 1, Field: "student", Message: "не указан студент"})
+			continue
+		}
+		exception, err := entry.toDomain()
+		if err != nil {
+			errs = append(errs, ImportError{File: "exceptions", Row: i + 1, Message: err.Error()})
+			continue
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	if len(errs) > 0 {
+		return RuleConfig{}, nil, errs
+	}
+	return config, exceptions, nil
+}
+
+// exceptionToYAML представляет исключение в виде записи файла
+func exceptionToYAML(exception domain.Exception) ExceptionYAML {
+	return ExceptionYAML{
+		Student:    exception.StudentName,
+		Kind:       exception.Kind,
+		Rule:       exception.Rule,
+		Weekday:    weekdayKeys[exception.Weekday],
+		Until:      exception.Until.In(domain.Location()).Format("2006-01-02"),
+		Reason:     exception.Reason,
+		ApprovedBy: exception.ApprovedBy,
+	}
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// RulesConfig структура файла с пользовательскими правилами
+type RulesConfig struct {
+	Limits *domain.Limits     `yaml:"limits,omitempty"` // пороги стандартных правил, nil — по умолчанию
+	Custom []CustomRuleConfig `yaml:"custom"`
+}
+
+// CustomRuleConfig описывает пользовательское правило в YAML
+type CustomRuleConfig struct {
+	Name       string `yaml:"name"`
+	Expression string `yaml:"expression"`
+}
+
+// YAMLRulesRepository загружает пользовательские правила проверки из YAML-файла
+type YAMLRulesRepository struct {
+	filename string
+}
+
+// NewYAMLRulesRepository создает новый экземпляр репозитория правил
+func NewYAMLRulesRepository(filename string) *YAMLRulesRepository {
+	return &YAMLRulesRepository{
+		filename: filename,
+	}
+}
+
+// LoadLimits загружает пороги стандартных правил. Если файла или раздела limits нет,
+// возвращаются пороги по умолчанию; незаданные в разделе значения тоже берутся по умолчанию.
+func (r *YAMLRulesRepository) LoadLimits() (domain.Limits, error) {
+	config, err := r.load()
+	if err != nil {
+		return domain.DefaultLimits(), err
+	}
+	if config.Limits == nil {
+		return domain.DefaultLimits(), nil
+	}
+	limits := config.Limits.WithDefaults()
+	if err := limits.Validate(); err != nil {
+		return domain.DefaultLimits(), fmt.Errorf("limits: %w", err)
+	}
+	return limits, nil
+}
+
+// SaveLimits сохраняет пороги стандартных правил, не затрагивая пользовательские правила
+func (r *YAMLRulesRepository) SaveLimits(limits domain.Limits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	config, err := r.load()
+	if err != nil {
+		return err
+	}
+	config.Limits = &limits
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
+
+// load читает файл правил; отсутствие файла не считается ошибкой
+func (r *YAMLRulesRepository) load() (RulesConfig, error) {
+	var config RulesConfig
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
+
+// LoadCustomRules загружает и компилирует пользовательские правила.
+// Отсутствие файла не считается ошибкой. Правила с ошибками пропускаются и
+// возвращаются в объединённой ошибке вместе с корректными правилами.
+func (r *YAMLRulesRepository) LoadCustomRules() ([]domain.CustomRule, error) {
+	config, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []domain.CustomRule
+	var errs []error
+	for _, rc := range config.Custom {
+		rule, err := domain.CompileRule(rc.Name, rc.Expression)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, errors.Join(errs...)
+}
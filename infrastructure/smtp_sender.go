@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPSender отправляет уведомления по электронной почте через SMTP-сервер
+type SMTPSender struct {
+	addr     string // адрес сервера "host:port"
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender создаёт отправителя почты. Если username пуст, авторизация не выполняется
+func NewSMTPSender(addr, username, password, from string) *SMTPSender {
+	return &SMTPSender{addr: addr, username: username, password: password, from: from}
+}
+
+// Send отправляет письмо в кодировке UTF-8
+func (s *SMTPSender) Send(to, subject, text string) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		host, _, err := net.SplitHostPort(s.addr)
+		if err != nil {
+			return fmt.Errorf("неверный адрес SMTP-сервера %q: %w", s.addr, err)
+		}
+		auth = smtp.PlainAuth("", s.username, s.password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+
+	if err := smtp.SendMail(s.addr, auth, s.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("не удалось отправить письмо на %s: %w", to, err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // драйвер pgx для хранилища PostgreSQL
+)
+
+// Хранилища данных программы
+const (
+	StorageYAML     = "yaml"     // файлы YAML по путям из раздела paths
+	StorageSQLite   = "sqlite"   // база SQLite
+	StoragePostgres = "postgres" // база PostgreSQL
+)
+
+// storageData — данные, хранилище которых выбирается в разделе storage настроек
+var storageData = []string{"students", "history", "plan", "webhooks", "alerts"}
+
+// documentStore читает и записывает документы с данными целиком — так же, как YAML-репозитории
+// читают и записывают свои файлы. Отсутствующий документ — ошибка fs.ErrNotExist.
+type documentStore interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+}
+
+// fileStore хранит документы в файлах
+type fileStore struct{}
+
+func (fileStore) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (fileStore) WriteFile(name string, data []byte) error {
+	return os.WriteFile(name, data, 0644)
+}
+
+// documentsSchema создает таблицу документов; запрос подходит и для SQLite, и для PostgreSQL
+const documentsSchema = `
+CREATE TABLE IF NOT EXISTS documents (
+	name       TEXT   PRIMARY KEY,
+	data       TEXT   NOT NULL,
+	updated_at BIGINT NOT NULL
+)`
+
+// sqlDocumentStore хранит документы в таблице documents базы SQLite или PostgreSQL.
+// Содержимое документа то же, что в YAML-файле, поэтому данные переносятся между хранилищами без изменений.
+type sqlDocumentStore struct {
+	db *sql.DB
+}
+
+// openSQLDocumentStore открывает базу хранилища backend и создает в ней таблицу документов
+func openSQLDocumentStore(backend, dsn string) (*sqlDocumentStore, error) {
+	driver := "sqlite"
+	if backend == StoragePostgres {
+		driver = "pgx"
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть базу %s: %w", backend, err)
+	}
+	if backend == StorageSQLite {
+		// SQLite не поддерживает параллельную запись; одно соединение исключает ошибки блокировки
+		db.SetMaxOpenConns(1)
+	}
+	if _, err := db.Exec(documentsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось создать таблицу документов в базе %s: %w", backend, err)
+	}
+	return &sqlDocumentStore{db: db}, nil
+}
+
+func (s *sqlDocumentStore) ReadFile(name string) ([]byte, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM documents WHERE name = $1`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+func (s *sqlDocumentStore) WriteFile(name string, data []byte) error {
+	_, err := s.db.Exec(`
+		INSERT INTO documents (name, data, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		name, string(data), time.Now().Unix())
+	return err
+}
+
+// Storage создаёт репозитории данных в хранилищах, выбранных в разделе storage настроек:
+// все данные в YAML-файлах, все в базе или вперемешку (например, студенты в PostgreSQL,
+// история проверок в файле). Кэш расписания групп, очередь заданий и файлы настроек
+// (rules.yaml, calendar.yaml и другие) от выбора хранилища не зависят.
+type Storage struct {
+	config StorageConfig
+	paths  PathsConfig
+	stores map[string]documentStore // по хранилищу
+}
+
+// OpenStorage открывает базы выбранных хранилищ. Документ, которого ещё нет в базе, переносится
+// в неё из YAML-файла по пути из paths, если файл есть, — так хранилище меняется без потери данных.
+func OpenStorage(config StorageConfig, paths PathsConfig) (*Storage, error) {
+	s := &Storage{
+		config: config,
+		paths:  paths,
+		stores: map[string]documentStore{StorageYAML: fileStore{}},
+	}
+	for _, name := range storageData {
+		backend := config.BackendFor(name)
+		store, ok := s.stores[backend]
+		if !ok {
+			opened, err := openSQLDocumentStore(backend, config.dsn(backend))
+			if err != nil {
+				s.Close()
+				return nil, err
+			}
+			s.stores[backend], store = opened, opened
+		}
+		if err := s.importFile(name, store); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// importFile переносит YAML-файл данных name в базу, если в ней ещё нет такого документа
+func (s *Storage) importFile(name string, store documentStore) error {
+	if _, isFile := store.(fileStore); isFile {
+		return nil
+	}
+	if _, err := store.ReadFile(name); !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать %s для переноса в базу: %w", s.path(name), err)
+	}
+	if err := store.WriteFile(name, data); err != nil {
+		return fmt.Errorf("не удалось перенести %s в базу: %w", s.path(name), err)
+	}
+	log.Printf("Данные %s перенесены в базу %s", s.path(name), s.config.BackendFor(name))
+	return nil
+}
+
+// Close закрывает базы хранилищ
+func (s *Storage) Close() error {
+	var errs []error
+	for _, store := range s.stores {
+		if db, ok := store.(*sqlDocumentStore); ok {
+			errs = append(errs, db.db.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// document возвращает хранилище данных name и имя их документа: путь к файлу или название данных в базе
+func (s *Storage) document(name string) (documentStore, string) {
+	store := s.stores[s.config.BackendFor(name)]
+	if _, isFile := store.(fileStore); isFile {
+		return store, s.path(name)
+	}
+	return store, name
+}
+
+// path возвращает путь к YAML-файлу данных name
+func (s *Storage) path(name string) string {
+	switch name {
+	case "students":
+		return s.paths.Students
+	case "history":
+		return s.paths.History
+	case "plan":
+		return s.paths.Plan
+	case "webhooks":
+		return s.paths.Webhooks
+	case "alerts":
+		return s.paths.Alerts
+	}
+	return name
+}
+
+// Exists сообщает, что данные name уже сохранены в своём хранилище
+func (s *Storage) Exists(name string) (bool, error) {
+	store, document := s.document(name)
+	_, err := store.ReadFile(document)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Students возвращает репозиторий списка студентов
+func (s *Storage) Students() *YAMLStudentRepository {
+	store, document := s.document("students")
+	return &YAMLStudentRepository{filename: document, store: store}
+}
+
+// History возвращает репозиторий истории проверок
+func (s *Storage) History() *YAMLHistoryRepository {
+	store, document := s.document("history")
+	return &YAMLHistoryRepository{filename: document, store: store}
+}
+
+// Plan возвращает репозиторий учебного плана
+func (s *Storage) Plan() *YAMLPlanRepository {
+	store, document := s.document("plan")
+	return &YAMLPlanRepository{filename: document, store: store}
+}
+
+// Webhooks возвращает журнал доставки уведомлений на внешний адрес
+func (s *Storage) Webhooks() *YAMLWebhookLog {
+	store, document := s.document("webhooks")
+	return &YAMLWebhookLog{filename: document, store: store}
+}
+
+// Alerts возвращает репозиторий оповещений страницы «Оповещения»
+func (s *Storage) Alerts() *YAMLAlertRepository {
+	store, document := s.document("alerts")
+	return &YAMLAlertRepository{filename: document, store: store}
+}
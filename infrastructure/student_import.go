@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// ParseStudentList разбирает список студентов, вставленный из таблицы: одна строка — один
+// студент, столбцы «ФИО; группа; курс» разделены точкой с запятой или табуляцией.
+// Пустые строки пропускаются. Ошибки возвращаются сразу по всем строкам с их номерами.
+func ParseStudentList(text string) ([]domain.Student, error) {
+	var students []domain.Student
+	var errs []error
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == ';' || r == '\t' })
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		if len(fields) != 3 {
+			errs = append(errs, fmt.Errorf("строка %d: ожидается «ФИО; группа; курс», получено %q", i+1, line))
+			continue
+		}
+		year, err := strconv.Atoi(fields[2])
+		if err != nil || year < 1 || year > 6 {
+			errs = append(errs, fmt.Errorf("строка %d: курс должен быть числом от 1 до 6, указано %q", i+1, fields[2]))
+			continue
+		}
+		if fields[0] == "" || fields[1] == "" {
+			errs = append(errs, fmt.Errorf("строка %d: не указаны ФИО или группа", i+1))
+			continue
+		}
+		students = append(students, domain.Student{Name: fields[0], Group: fields[1], Year: year})
+	}
+	return students, errors.Join(errs...)
+}
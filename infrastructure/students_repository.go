@@ -1,8 +1,9 @@
 package infrastructure
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"sync"
 
 	"github.com/Vaflel/lesson-counter/domain"
@@ -17,6 +18,7 @@ type StudentsConfig struct {
 // YAMLStudentRepository реализует StudentRepository для работы с YAML-файлом
 type YAMLStudentRepository struct {
 	filename string
+	store    documentStore // файл или база, см. Storage
 	mutex    sync.RWMutex
 }
 
@@ -24,6 +26,7 @@ type YAMLStudentRepository struct {
 func NewYAMLStudentRepository(filename string) *YAMLStudentRepository {
 	return &YAMLStudentRepository{
 		filename: filename,
+		store:    fileStore{},
 	}
 }
 
@@ -32,7 +35,7 @@ func (r *YAMLStudentRepository) LoadStudents() ([]domain.Student, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	data, err := os.ReadFile(r.filename)
+	data, err := r.store.ReadFile(r.filename)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
 	}
@@ -70,6 +73,14 @@ func (r *YAMLStudentRepository) AddStudent(student domain.Student) error {
 	return r.saveStudentsUnsafe(students)
 }
 
+// SaveStudents заменяет весь список студентов, создавая файл, если его нет
+func (r *YAMLStudentRepository) SaveStudents(students []domain.Student) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.saveStudentsUnsafe(students)
+}
+
 // GetStudent возвращает студента по имени
 func (r *YAMLStudentRepository) GetStudent(name string) (domain.Student, error) {
 	r.mutex.RLock()
@@ -133,9 +144,92 @@ func (r *YAMLStudentRepository) DeleteStudent(name string) error {
 	return fmt.Errorf("студент %s не найден", name)
 }
 
+// Filename возвращает путь к файлу студентов
+func (r *YAMLStudentRepository) Filename() string {
+	return r.filename
+}
+
+// Validate проверяет файл студентов целиком: синтаксис YAML, пустые имена и группы,
+// повторяющиеся имена и курс вне 1–6. Каждая ошибка содержит номер строки файла,
+// чтобы её можно было сразу найти и исправить.
+func (r *YAMLStudentRepository) Validate() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	data, err := r.store.ReadFile(r.filename)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	return validateStudentsYAML(data)
+}
+
+// validateStudentsYAML проверяет содержимое файла студентов, см. Validate
+func validateStudentsYAML(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		message := strings.Replace(strings.TrimPrefix(err.Error(), "yaml: "), "line ", "строка ", 1)
+		return fmt.Errorf("некорректный YAML: %s", message)
+	}
+	if len(root.Content) == 0 {
+		return errors.New("файл пуст: нет раздела students")
+	}
+	document := root.Content[0]
+	if document.Kind != yaml.MappingNode {
+		return fmt.Errorf("строка %d: ожидается раздел students", document.Line)
+	}
+
+	var list *yaml.Node
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value == "students" {
+			list = document.Content[i+1]
+		}
+	}
+	if list == nil {
+		return errors.New("нет раздела students")
+	}
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("строка %d: students должен быть списком", list.Line)
+	}
+
+	var errs []error
+	firstLine := make(map[string]int)
+	for _, item := range list.Content {
+		var student domain.Student
+		if err := item.Decode(&student); err != nil {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				errs = append(errs, fmt.Errorf("строка %d: %s", item.Line, strings.TrimPrefix(err.Error(), "yaml: ")))
+				continue
+			}
+			// сообщения вида "line 7: cannot unmarshal !!str `abc` into int"
+			for _, message := range typeErr.Errors {
+				line, detail, _ := strings.Cut(strings.TrimPrefix(message, "line "), ": ")
+				errs = append(errs, fmt.Errorf("строка %s: неверное значение (%s)", line, detail))
+			}
+			continue
+		}
+		if student.Name == "" {
+			errs = append(errs, fmt.Errorf("строка %d: не указано имя студента (name)", item.Line))
+			continue
+		}
+		if line, ok := firstLine[student.Name]; ok {
+			errs = append(errs, fmt.Errorf("строка %d: студент %s уже указан в строке %d", item.Line, student.Name, line))
+		} else {
+			firstLine[student.Name] = item.Line
+		}
+		if student.Group == "" {
+			errs = append(errs, fmt.Errorf("строка %d: у студента %s не указана группа (group)", item.Line, student.Name))
+		}
+		if student.Year < 1 || student.Year > 6 {
+			errs = append(errs, fmt.Errorf("строка %d: у студента %s курс (year) должен быть от 1 до 6, указано %d", item.Line, student.Name, student.Year))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // loadStudentsUnsafe загружает студентов без блокировки (внутренний метод)
 func (r *YAMLStudentRepository) loadStudentsUnsafe() ([]domain.Student, error) {
-	data, err := os.ReadFile(r.filename)
+	data, err := r.store.ReadFile(r.filename)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
 	}
@@ -156,7 +250,7 @@ func (r *YAMLStudentRepository) saveStudentsUnsafe(students []domain.Student) er
 		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
 	}
 
-	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+	if err := r.store.WriteFile(r.filename, data); err != nil {
 		return fmt.Errorf("не удалось записать файл: %w", err)
 	}
 
@@ -0,0 +1,26 @@
+package infrastructure
+
+import (
+	"io"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// ExportTallyXLSX записывает табель часов индивидуальных занятий за месяц в формате XLSX
+// на языке lang: по преподавателям, с разбивкой по дисциплинам и студентам и итогами
+func ExportTallyXLSX(w io.Writer, tally domain.MonthlyTally, lang domain.Lang) error {
+	rows := [][]any{
+		{lang.Tf("Табель индивидуальных занятий за %s", tally.MonthNameIn(lang))},
+		{},
+		{lang.T("Преподаватель"), lang.T("Дисциплина"), lang.T("Студент"), lang.T("Группа"), lang.T("Ак.ч")},
+	}
+	for _, teacher := range tally.Teachers {
+		for _, row := range teacher.Rows {
+			rows = append(rows, []any{teacher.Teacher, row.Discipline, row.Student, row.Group, row.Hours})
+		}
+		rows = append(rows, []any{teacher.Teacher, lang.T("Итого"), nil, nil, teacher.Total})
+	}
+	rows = append(rows, []any{}, []any{lang.T("Всего"), nil, nil, nil, tally.Total})
+
+	return WriteXLSX(w, XLSXSheet{Name: lang.T("Табель"), Rows: rows})
+}
@@ -0,0 +1,205 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// TeachersConfig структура файла справочника преподавателей
+type TeachersConfig struct {
+	Teachers   []TeacherYAML        `yaml:"teachers"`
+	Candidates []AliasCandidateYAML `yaml:"candidates,omitempty"`
+}
+
+// TeacherYAML представление domain.Teacher в YAML
+type TeacherYAML struct {
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases,omitempty"`
+	MaxLoad int      `yaml:"max_load,omitempty"`
+	// Individual — преподаватель ведёт индивидуальные занятия, его файл ожидается каждую неделю
+	Individual bool   `yaml:"individual,omitempty"`
+	Email      string `yaml:"email,omitempty"`
+	Telegram   string `yaml:"telegram,omitempty"`
+	AccessKey  string `yaml:"access_key,omitempty"` // ключ страницы преподавателя
+}
+
+// AliasCandidateYAML представление domain.TeacherAliasCandidate в YAML
+type AliasCandidateYAML struct {
+	Name      string `yaml:"name"`
+	Alias     string `yaml:"alias"`
+	Dismissed bool   `yaml:"dismissed,omitempty"`
+}
+
+// YAMLTeacherRepository хранит справочник преподавателей с псевдонимами в YAML-файле
+type YAMLTeacherRepository struct {
+	filename string
+	mutex    sync.RWMutex
+}
+
+// NewYAMLTeacherRepository создает новый экземпляр репозитория преподавателей
+func NewYAMLTeacherRepository(filename string) *YAMLTeacherRepository {
+	return &YAMLTeacherRepository{
+		filename: filename,
+	}
+}
+
+// LoadRegistry загружает справочник преподавателей. Отсутствие файла не считается ошибкой.
+func (r *YAMLTeacherRepository) LoadRegistry() (domain.TeacherRegistry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.loadUnsafe()
+}
+
+// SuggestAliases добавляет в справочник кандидатов в псевдонимы по парам написаний имён.
+// Возвращает количество новых кандидатов.
+func (r *YAMLTeacherRepository) SuggestAliases(pairs [][2]string) (int, error) {
+	return r.update(func(registry *domain.TeacherRegistry) int {
+		added := 0
+		for _, pair := range pairs {
+			if registry.Suggest(pair[0], pair[1]) {
+				added++
+			}
+		}
+		return added
+	})
+}
+
+// ConfirmAlias делает alias псевдонимом преподавателя name
+func (r *YAMLTeacherRepository) ConfirmAlias(name, alias string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.Confirm(name, alias)
+		return 1
+	})
+	return err
+}
+
+// DismissAlias отклоняет кандидата в псевдонимы
+func (r *YAMLTeacherRepository) DismissAlias(name, alias string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.Dismiss(name, alias)
+		return 1
+	})
+	return err
+}
+
+// RemoveAlias убирает подтверждённый псевдоним преподавателя
+func (r *YAMLTeacherRepository) RemoveAlias(name, alias string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.RemoveAlias(name, alias)
+		return 1
+	})
+	return err
+}
+
+// ExpectTeacher отмечает преподавателя ведущим индивидуальные занятия с контактами для напоминаний
+func (r *YAMLTeacherRepository) ExpectTeacher(name, email, telegram string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.Expect(name, email, telegram)
+		return 1
+	})
+	return err
+}
+
+// UnexpectTeacher убирает преподавателя из ведущих индивидуальные занятия
+func (r *YAMLTeacherRepository) UnexpectTeacher(name string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.Unexpect(name)
+		return 1
+	})
+	return err
+}
+
+// GrantAccess выдаёт преподавателю новый ключ страницы преподавателя, заменяя прежний,
+// и возвращает его
+func (r *YAMLTeacherRepository) GrantAccess(name string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("не удалось создать ключ доступа: %w", err)
+	}
+	key := hex.EncodeToString(buf)
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.GrantAccess(name, key)
+		return 1
+	})
+	return key, err
+}
+
+// RevokeAccess отзывает ключ страницы преподавателя
+func (r *YAMLTeacherRepository) RevokeAccess(name string) error {
+	_, err := r.update(func(registry *domain.TeacherRegistry) int {
+		registry.RevokeAccess(name)
+		return 1
+	})
+	return err
+}
+
+// update загружает справочник, изменяет его и сохраняет, если change сообщил об изменениях
+func (r *YAMLTeacherRepository) update(change func(registry *domain.TeacherRegistry) int) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	registry, err := r.loadUnsafe()
+	if err != nil {
+		return 0, err
+	}
+	n := change(&registry)
+	if n == 0 {
+		return 0, nil
+	}
+	return n, r.saveUnsafe(registry)
+}
+
+// loadUnsafe читает файл преподавателей без блокировки (внутренний метод)
+func (r *YAMLTeacherRepository) loadUnsafe() (domain.TeacherRegistry, error) {
+	var registry domain.TeacherRegistry
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return registry, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	var config TeachersConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return registry, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	for _, t := range config.Teachers {
+		registry.Teachers = append(registry.Teachers, domain.Teacher{
+			Name: t.Name, Aliases: t.Aliases, MaxLoad: t.MaxLoad,
+			Individual: t.Individual, Email: t.Email, Telegram: t.Telegram, AccessKey: t.AccessKey,
+		})
+	}
+	for _, c := range config.Candidates {
+		registry.Candidates = append(registry.Candidates, domain.TeacherAliasCandidate{Name: c.Name, Alias: c.Alias, Dismissed: c.Dismissed})
+	}
+	return registry, nil
+}
+
+// saveUnsafe записывает файл преподавателей без блокировки (внутренний метод)
+func (r *YAMLTeacherRepository) saveUnsafe(registry domain.TeacherRegistry) error {
+	var config TeachersConfig
+	for _, t := range registry.Teachers {
+		config.Teachers = append(config.Teachers, TeacherYAML{
+			Name: t.Name, Aliases: t.Aliases, MaxLoad: t.MaxLoad,
+			Individual: t.Individual, Email: t.Email, Telegram: t.Telegram, AccessKey: t.AccessKey,
+		})
+	}
+	for _, c := range registry.Candidates {
+		config.Candidates = append(config.Candidates, AliasCandidateYAML{Name: c.Name, Alias: c.Alias, Dismissed: c.Dismissed})
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := os.WriteFile(r.filename, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TelegramSender отправляет уведомления через бота Telegram
+type TelegramSender struct {
+	token  string
+	client *http.Client
+}
+
+// NewTelegramSender создаёт отправителя с токеном бота
+func NewTelegramSender(token string) *TelegramSender {
+	return &TelegramSender{token: token, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Send отправляет сообщение в чат chatID. Тема добавляется первой строкой сообщения
+func (s *TelegramSender) Send(chatID, subject, text string) error {
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.token)
+	resp, err := s.client.PostForm(endpoint, url.Values{
+		"chat_id": {chatID},
+		"text":    {subject + "\n\n" + text},
+	})
+	if err != nil {
+		// ошибка клиента содержит URL с токеном бота, поэтому в сообщение она не попадает
+		return fmt.Errorf("не удалось отправить сообщение в Telegram чат %s", chatID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Telegram вернул статус %d для чата %s", resp.StatusCode, chatID)
+	}
+	return nil
+}
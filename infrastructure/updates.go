@@ -0,0 +1,243 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Файлы, которые прикладываются к выпуску на GitHub для установки обновления из программы
+const (
+	updateAsset    = "schedule.exe"  // исполняемый файл для Windows
+	checksumsAsset = "checksums.txt" // контрольные суммы в формате sha256sum: "<SHA-256>  schedule.exe"
+)
+
+// Release — выпуск программы на GitHub
+type Release struct {
+	Version      string // тег выпуска, например v1.4.0
+	URL          string // страница выпуска
+	DownloadURL  string // исполняемый файл для Windows (updateAsset), пусто — не приложен
+	ChecksumsURL string // контрольные суммы файлов выпуска (checksumsAsset), пусто — не приложены
+	PublishedAt  time.Time
+}
+
+// UpdateChecker проверяет, вышла ли новая версия программы, по выпускам репозитория на GitHub
+type UpdateChecker struct {
+	repo    string // владелец/репозиторий
+	current string // версия запущенной программы
+	client  *http.Client
+}
+
+// NewUpdateChecker создаёт проверку обновлений для репозитория repo ("владелец/имя")
+// и версии current запущенной программы
+func NewUpdateChecker(repo, current string) *UpdateChecker {
+	return &UpdateChecker{repo: repo, current: current, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Current возвращает версию запущенной программы
+func (u *UpdateChecker) Current() string {
+	return u.current
+}
+
+// Latest запрашивает последний выпуск и сообщает, новее ли он запущенной версии.
+// Сборки без номера версии (dev) обновлений не предлагают.
+func (u *UpdateChecker) Latest(ctx context.Context) (Release, bool, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", u.repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Release{}, false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return Release{}, false, fmt.Errorf("не удалось запросить выпуски на GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, false, fmt.Errorf("GitHub вернул статус %d", resp.StatusCode)
+	}
+
+	var data struct {
+		TagName     string    `json:"tag_name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+		Assets      []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Release{}, false, fmt.Errorf("не удалось разобрать ответ GitHub: %w", err)
+	}
+
+	release := Release{Version: data.TagName, URL: data.HTMLURL, PublishedAt: data.PublishedAt}
+	for _, asset := range data.Assets {
+		switch asset.Name {
+		case updateAsset:
+			release.DownloadURL = asset.URL
+		case checksumsAsset:
+			release.ChecksumsURL = asset.URL
+		}
+	}
+	return release, newerVersion(release.Version, u.current), nil
+}
+
+// CanInstall сообщает, может ли программа сама заменить свой исполняемый файл: только в Windows
+// и только для выпуска, к которому приложены исполняемый файл и его контрольная сумма
+func (u *UpdateChecker) CanInstall(release Release) bool {
+	return runtime.GOOS == "windows" && release.DownloadURL != "" && release.ChecksumsURL != ""
+}
+
+// Install скачивает исполняемый файл выпуска, сверяет его SHA-256 с контрольной суммой выпуска
+// и подменяет им запущенный. Если сумма не совпала, программа не меняется. Windows не даёт
+// перезаписать работающий файл, но позволяет переименовать его, поэтому текущий файл
+// переименовывается в .old, а новый занимает его место и запустится при следующем запуске.
+// Файл .old удаляется при следующем запуске через RemoveOldBinary.
+func (u *UpdateChecker) Install(ctx context.Context, release Release) error {
+	if !u.CanInstall(release) {
+		return fmt.Errorf("автоматическая установка доступна только в Windows для выпусков с файлами %s и %s", updateAsset, checksumsAsset)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("не удалось определить путь к программе: %w", err)
+	}
+
+	var checksums bytes.Buffer
+	if err := u.download(ctx, release.ChecksumsURL, &checksums); err != nil {
+		return fmt.Errorf("не удалось скачать контрольные суммы: %w", err)
+	}
+	want, err := assetChecksum(checksums.Bytes(), updateAsset)
+	if err != nil {
+		return err
+	}
+
+	newPath := exe + ".new"
+	file, err := os.OpenFile(newPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить обновление: %w", err)
+	}
+	hash := sha256.New()
+	downloadErr := u.download(ctx, release.DownloadURL, io.MultiWriter(file, hash))
+	closeErr := file.Close()
+	if downloadErr != nil || closeErr != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("не удалось скачать обновление: %w", errors.Join(downloadErr, closeErr))
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		os.Remove(newPath)
+		return fmt.Errorf("контрольная сумма %s не совпадает с %s выпуска (%s вместо %s): файл повреждён или подменён, обновление не установлено", updateAsset, checksumsAsset, got, want)
+	}
+
+	oldPath := exe + ".old"
+	os.Remove(oldPath)
+	if err := os.Rename(exe, oldPath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("не удалось заменить программу: %w", err)
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// возвращаем прежний файл, чтобы программа запускалась
+		os.Rename(oldPath, exe)
+		return fmt.Errorf("не удалось заменить программу: %w", err)
+	}
+	return nil
+}
+
+// download скачивает файл url в w
+func (u *UpdateChecker) download(ctx context.Context, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Minute}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("статус %d", resp.StatusCode)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// assetChecksum находит SHA-256 файла name в контрольных суммах формата sha256sum
+// ("<SHA-256>  <имя файла>", перед именем двоичного файла может стоять «*»)
+func assetChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := strings.ToLower(fields[0])
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != sha256.Size {
+			return "", fmt.Errorf("неверная контрольная сумма %s в %s: %q", name, checksumsAsset, fields[0])
+		}
+		return sum, nil
+	}
+	return "", fmt.Errorf("в %s нет контрольной суммы %s", checksumsAsset, name)
+}
+
+// RemoveOldBinary удаляет исполняемый файл, оставшийся от предыдущей версии после Install
+func RemoveOldBinary() {
+	if exe, err := os.Executable(); err == nil {
+		os.Remove(exe + ".old")
+	}
+}
+
+// newerVersion сообщает, новее ли версия latest версии current. Версии сравниваются
+// по числам через точку (v1.10.0 новее v1.9.2); нечисловые версии не сравниваются.
+func newerVersion(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var a, b int
+		if i < len(l) {
+			a = l[i]
+		}
+		if i < len(c) {
+			b = c[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+// parseVersion разбирает версию вида v1.2.3; суффикс после дефиса (1.2.3-rc1) отбрасывается
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "-")
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
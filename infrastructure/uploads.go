@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/extrame/xls"
+)
+
+// Ограничения на загружаемые архивы, защищающие от «ZIP-бомб»
+const (
+	maxZipEntries   = 500
+	maxUploadedSize = 200 << 20 // суммарный размер распакованных файлов
+)
+
+// UploadedFile — загруженный файл расписания
+type UploadedFile struct {
+	Name string
+	Data []byte
+}
+
+// UploadBatch — файлы, загруженные для одной недели
+type UploadBatch struct {
+	Week  domain.Week
+	Dir   string
+	Files []string // имена файлов
+}
+
+// ScheduleUploads хранит загруженные через веб-интерфейс XLS-файлы в папках по неделям
+// (uploads/2025-02-10). Папка находится внутри рабочей, поэтому файлы попадают в проверку
+// вместе с остальными.
+type ScheduleUploads struct {
+	dir string
+}
+
+// NewScheduleUploads создаёт хранилище загрузок в каталоге dir
+func NewScheduleUploads(dir string) *ScheduleUploads {
+	return &ScheduleUploads{dir: dir}
+}
+
+// WeekDir возвращает папку загрузок недели
+func (u *ScheduleUploads) WeekDir(week domain.Week) string {
+	return filepath.Join(u.dir, week.String())
+}
+
+// Save проверяет файлы и сохраняет их в папку недели. ZIP-архивы распаковываются.
+// Если хотя бы один файл не прошёл проверку, ничего не сохраняется и возвращаются
+// ошибки по всем таким файлам (ImportErrors, для файлов архива — с путём внутри архива).
+func (u *ScheduleUploads) Save(week domain.Week, uploaded []UploadedFile) ([]string, error) {
+	var files []UploadedFile
+	var errs ImportErrors
+	for _, file := range uploaded {
+		switch strings.ToLower(filepath.Ext(file.Name)) {
+		case ".zip":
+			extracted, err := ExtractScheduleZip(file.Data)
+			if entryErrs, ok := AsImportErrors(err); ok {
+				errs = append(errs, entryErrs.InFile(file.Name)...)
+				continue
+			}
+			if err != nil {
+				errs = append(errs, ImportError{File: file.Name, Message: err.Error()})
+				continue
+			}
+			files = append(files, extracted...)
+		case ".xls":
+			if err := ValidateScheduleXLS(file.Data); err != nil {
+				errs = append(errs, ImportError{File: file.Name, Message: err.Error()})
+				continue
+			}
+			files = append(files, UploadedFile{Name: filepath.Base(file.Name), Data: file.Data})
+		default:
+			errs = append(errs, ImportError{File: file.Name, Message: "поддерживаются только файлы .xls и архивы .zip"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("не выбрано ни одного файла")
+	}
+
+	dir := u.WeekDir(week)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("не удалось создать папку загрузок: %w", err)
+	}
+	var saved []string
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name)
+		if err := os.WriteFile(path, file.Data, 0644); err != nil {
+			return saved, fmt.Errorf("не удалось записать файл: %w", err)
+		}
+		saved = append(saved, path)
+	}
+	return saved, nil
+}
+
+// Batches возвращает загрузки по неделям, новые недели первыми
+func (u *ScheduleUploads) Batches() ([]UploadBatch, error) {
+	entries, err := os.ReadDir(u.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать папку загрузок: %w", err)
+	}
+
+	var batches []UploadBatch
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		week, err := domain.ParseWeek(entry.Name())
+		if err != nil {
+			continue
+		}
+		batch := UploadBatch{Week: week, Dir: filepath.Join(u.dir, entry.Name())}
+		files, err := os.ReadDir(batch.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать папку загрузок: %w", err)
+		}
+		for _, file := range files {
+			if !file.IsDir() {
+				batch.Files = append(batch.Files, file.Name())
+			}
+		}
+		batches = append(batches, batch)
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].Week.Start().After(batches[j].Week.Start())
+	})
+	return batches, nil
+}
+
+// ExtractScheduleZip распаковывает архив с XLS-файлами в память и проверяет содержимое:
+// в архиве должны быть только XLS-файлы расписания (вложенные папки допускаются, служебные
+// файлы вроде __MACOSX пропускаются), без повторяющихся имён и в пределах ограничений размера.
+// Ошибки в отдельных файлах архива возвращаются все вместе как ImportErrors.
+func ExtractScheduleZip(data []byte) ([]UploadedFile, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть ZIP-архив: %w", err)
+	}
+	if len(reader.File) > maxZipEntries {
+		return nil, fmt.Errorf("в архиве больше %d файлов", maxZipEntries)
+	}
+
+	var files []UploadedFile
+	var errs ImportErrors
+	seen := make(map[string]bool)
+	var total int64
+	for _, entry := range reader.File {
+		name := filepath.Base(filepath.FromSlash(entry.Name))
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !strings.EqualFold(filepath.Ext(name), ".xls") {
+			errs = append(errs, ImportError{File: entry.Name, Message: "в архиве допускаются только файлы .xls"})
+			continue
+		}
+		if seen[strings.ToLower(name)] {
+			errs = append(errs, ImportError{File: entry.Name, Message: "файл с таким именем уже есть в архиве"})
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		content, err := readZipEntry(entry, maxUploadedSize-total)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		total += int64(len(content))
+		if err := ValidateScheduleXLS(content); err != nil {
+			errs = append(errs, ImportError{File: entry.Name, Message: err.Error()})
+			continue
+		}
+		files = append(files, UploadedFile{Name: name, Data: content})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("в архиве нет файлов .xls")
+	}
+	return files, nil
+}
+
+// readZipEntry читает файл архива, не больше limit байт
+func readZipEntry(entry *zip.File, limit int64) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось распаковать файл: %w", err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось распаковать файл: %w", err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("архив слишком большой: распакованные файлы больше %d МБ", maxUploadedSize>>20)
+	}
+	return content, nil
+}
+
+// ValidateScheduleXLS проверяет, что данные — читаемая книга XLS хотя бы с одним листом
+func ValidateScheduleXLS(data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("файл повреждён или не является книгой XLS")
+		}
+	}()
+
+	book, err := xls.OpenReader(bytes.NewReader(data), "windows-1251")
+	if err != nil {
+		return fmt.Errorf("файл повреждён или не является книгой XLS: %w", err)
+	}
+	if book.GetSheet(0) == nil {
+		return fmt.Errorf("в файле нет листов")
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// webhookLogLimit — сколько последних доставок хранится в журнале
+const webhookLogLimit = 200
+
+// WebhookLogConfig структура файла журнала доставки уведомлений
+type WebhookLogConfig struct {
+	Deliveries []WebhookDeliveryYAML `yaml:"deliveries"`
+}
+
+// WebhookDeliveryYAML представление domain.WebhookDelivery в YAML
+type WebhookDeliveryYAML struct {
+	ID           string               `yaml:"id"`
+	Event        string               `yaml:"event"`
+	URL          string               `yaml:"url"`
+	Status       domain.WebhookStatus `yaml:"status"`
+	Attempts     int                  `yaml:"attempts"`
+	ResponseCode int                  `yaml:"response_code,omitempty"`
+	Error        string               `yaml:"error,omitempty"`
+	CreatedAt    time.Time            `yaml:"created_at"`
+	FinishedAt   time.Time            `yaml:"finished_at,omitempty"`
+	Payload      string               `yaml:"payload"`
+}
+
+// YAMLWebhookLog хранит журнал доставки уведомлений в YAML-файле. Старые доставки
+// сверх webhookLogLimit удаляются.
+type YAMLWebhookLog struct {
+	filename string
+	store    documentStore // файл или база, см. Storage
+	mutex    sync.Mutex
+}
+
+// NewYAMLWebhookLog создает новый экземпляр журнала доставки уведомлений
+func NewYAMLWebhookLog(filename string) *YAMLWebhookLog {
+	return &YAMLWebhookLog{
+		filename: filename,
+		store:    fileStore{},
+	}
+}
+
+// SaveDelivery добавляет доставку в журнал или обновляет запись с тем же идентификатором
+func (r *YAMLWebhookLog) SaveDelivery(delivery domain.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return err
+	}
+
+	entry := WebhookDeliveryYAML{
+		ID:           delivery.ID,
+		Event:        delivery.Event,
+		URL:          delivery.URL,
+		Status:       delivery.Status,
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		Error:        delivery.Error,
+		CreatedAt:    delivery.CreatedAt,
+		FinishedAt:   delivery.FinishedAt,
+		Payload:      delivery.Payload,
+	}
+	replaced := false
+	for i := range config.Deliveries {
+		if config.Deliveries[i].ID == delivery.ID {
+			config.Deliveries[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		config.Deliveries = append(config.Deliveries, entry)
+	}
+
+	sort.SliceStable(config.Deliveries, func(i, j int) bool {
+		return config.Deliveries[i].CreatedAt.Before(config.Deliveries[j].CreatedAt)
+	})
+	if extra := len(config.Deliveries) - webhookLogLimit; extra > 0 {
+		config.Deliveries = config.Deliveries[extra:]
+	}
+	return r.saveUnsafe(config)
+}
+
+// LoadDeliveries возвращает журнал доставки, новые первыми. Отсутствие файла не считается ошибкой.
+func (r *YAMLWebhookLog) LoadDeliveries() ([]domain.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make([]domain.WebhookDelivery, 0, len(config.Deliveries))
+	for i := len(config.Deliveries) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, config.Deliveries[i].toDomain())
+	}
+	return deliveries, nil
+}
+
+// GetDelivery возвращает доставку по идентификатору
+func (r *YAMLWebhookLog) GetDelivery(id string) (domain.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config, err := r.loadUnsafe()
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	for _, entry := range config.Deliveries {
+		if entry.ID == id {
+			return entry.toDomain(), nil
+		}
+	}
+	return domain.WebhookDelivery{}, fmt.Errorf("уведомление %s не найдено в журнале", id)
+}
+
+func (e WebhookDeliveryYAML) toDomain() domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:           e.ID,
+		Event:        e.Event,
+		URL:          e.URL,
+		Payload:      e.Payload,
+		Status:       e.Status,
+		Attempts:     e.Attempts,
+		ResponseCode: e.ResponseCode,
+		Error:        e.Error,
+		CreatedAt:    e.CreatedAt,
+		FinishedAt:   e.FinishedAt,
+	}
+}
+
+// loadUnsafe читает журнал без блокировки (внутренний метод)
+func (r *YAMLWebhookLog) loadUnsafe() (WebhookLogConfig, error) {
+	var config WebhookLogConfig
+	data, err := r.store.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("не удалось распарсить YAML: %w", err)
+	}
+	return config, nil
+}
+
+// saveUnsafe записывает журнал без блокировки (внутренний метод)
+func (r *YAMLWebhookLog) saveUnsafe(config WebhookLogConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать YAML: %w", err)
+	}
+	if err := r.store.WriteFile(r.filename, data); err != nil {
+		return fmt.Errorf("не удалось записать файл: %w", err)
+	}
+	return nil
+}
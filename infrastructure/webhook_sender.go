@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// Заголовки запроса уведомления. Получатель проверяет подпись: HMAC-SHA256 с общим секретом
+// от строки "<timestamp>.<тело запроса>", в шестнадцатеричном виде с префиксом "sha256=".
+// Время подписи входит в подпись, чтобы перехваченный запрос нельзя было повторить позже.
+const (
+	WebhookEventHeader     = "X-Lesson-Counter-Event"
+	WebhookDeliveryHeader  = "X-Lesson-Counter-Delivery"
+	WebhookTimestampHeader = "X-Lesson-Counter-Timestamp"
+	WebhookSignatureHeader = "X-Lesson-Counter-Signature"
+)
+
+// WebhookSender отправляет уведомления POST-запросом JSON на внешний адрес и подписывает их
+type WebhookSender struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSender создаёт отправителя уведомлений на адрес url с секретом подписи secret
+func NewWebhookSender(url, secret string) *WebhookSender {
+	return &WebhookSender{url: url, secret: secret, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// URL возвращает адрес получателя уведомлений
+func (s *WebhookSender) URL() string {
+	return s.url
+}
+
+// Post выполняет одну попытку доставки уведомления и возвращает код ответа получателя.
+// Подпись вычисляется заново при каждой попытке, с текущим временем.
+func (s *WebhookSender) Post(delivery domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("неверный адрес уведомлений: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(s.secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось отправить уведомление: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// SignWebhook вычисляет подпись уведомления для заголовка X-Lesson-Counter-Signature
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
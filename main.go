@@ -1,40 +1,355 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"time"
+	_ "time/tzdata" // база часовых поясов для Windows
 
+	"fyne.io/systray"
+
+	"github.com/Vaflel/lesson-counter/domain"
 	"github.com/Vaflel/lesson-counter/infrastructure"
+	"github.com/Vaflel/lesson-counter/usecases"
 	"github.com/Vaflel/lesson-counter/web"
 )
 
-// openBrowser opens the specified URL in the default browser on Windows
+// version — версия программы, задаётся при сборке выпуска:
+// go build -ldflags "-X main.version=v1.4.0"
+var version = "dev"
+
+// openBrowser opens the specified URL in the default browser
 func openBrowser(url string) {
-	err := exec.Command("cmd", "/c", "start", url).Start()
-	if err != nil {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
 		log.Printf("Не удалось открыть браузер: %v", err)
 		log.Printf("Откройте вручную: %s", url)
 	}
 }
 
+// exportWeekLessons загружает занятия недели так же, как проверка, и записывает их
+// в lessons-<неделя>.json в текущем каталоге
+func exportWeekLessons(value string, studentRepo usecases.StudentRepository, serviceOpts []usecases.Option) error {
+	calendar, err := infrastructure.NewYAMLCalendarRepository("calendar.yaml").LoadCalendar()
+	if err != nil {
+		return err
+	}
+	week, err := calendar.ParseWeek(value)
+	if err != nil {
+		return err
+	}
+
+	// Выгрузка не должна переносить XLS-файлы в архив, как успешная проверка
+	opts := append(serviceOpts, usecases.WithScheduleArchive(nil))
+	result, err := usecases.NewScheduleService(week, studentRepo, opts...).ProcessSchedule()
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("lessons-%s.json", week)
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := infrastructure.ExportLessonsJSON(file, result.Lessons); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	log.Printf("Занятий за неделю %s: %d, записаны в %s", week, len(result.Lessons), filename)
+	return nil
+}
+
 func main() {
-	studentRepo := infrastructure.NewYAMLStudentRepository("students.yaml")
+	configPath := flag.String("config", "config.yaml", "файл настроек")
+	demo := flag.Bool("demo", false, "запустить с демонстрационными данными: без сайта, XLS-файлов и изменения настоящих файлов")
+	port := flag.Int("port", 0, "порт веб-интерфейса (по умолчанию из настроек, 8060)")
+	grpcPort := flag.Int("grpc-port", 0, "порт gRPC API для других сервисов; 0 — не запускать")
+	studentsPath := flag.String("students", "", "файл со списком студентов (по умолчанию students.yaml)")
+	templatesDir := flag.String("templates", "", "каталог с шаблонами страниц и отчета, заменяющими встроенные (по умолчанию templates)")
+	debug := flag.Bool("debug", false, "записывать промежуточные данные разбора XLS-файлов в каталог debug")
+	lessonsJSON := flag.String("lessons-json", "", "брать занятия из выгрузки lessons.json вместо XLS-файлов и сайта")
+	exportLessons := flag.String("export-lessons", "", "выгрузить занятия недели (любая дата недели или «неделя N») в lessons-<неделя>.json и выйти")
+	tray := flag.Bool("tray", false, "работать из значка в области уведомлений без окна консоли")
+	readOnly := flag.Bool("read-only", false, "режим только для просмотра: без изменения студентов, загрузки файлов, проверок и завершения работы через веб-интерфейс")
+	noBrowser := flag.Bool("no-browser", false, "не открывать браузер при запуске (для запуска на сервере)")
+	flag.Parse()
 
-	server := web.NewServer(studentRepo)
+	log.Printf("Lesson Counter %s", version)
+	infrastructure.RemoveOldBinary()
 
-	port := 8060
+	// Настройки: config.yaml, затем переменные окружения LESSON_COUNTER_*, затем флаги
+	config, loadErr := infrastructure.LoadConfig(*configPath)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			config.Port = *port
+		case "grpc-port":
+			config.GRPCPort = *grpcPort
+		case "templates":
+			config.Templates = *templatesDir
+		case "students":
+			config.Paths.Students = *studentsPath
+		case "tray":
+			config.Tray = *tray
+		case "debug":
+			config.Debug.Enabled = *debug
+		}
+	})
+	if err := errors.Join(loadErr, config.Validate()); err != nil {
+		log.Fatalf("Ошибки в настройках (%s):\n%v", *configPath, err)
+	}
+	config.Apply()
+
+	// Путь к шаблонам запоминается до смены каталога в демо-режиме
+	if dir, err := filepath.Abs(config.Templates); err == nil {
+		config.Templates = dir
+	}
+	if *lessonsJSON != "" {
+		if path, err := filepath.Abs(*lessonsJSON); err == nil {
+			*lessonsJSON = path
+		}
+	}
+
+	loc, _ := config.Location()
+	domain.SetLocation(loc)
+	// Число пар в дне для сетки отчета — по расписанию звонков
+	if bells, err := infrastructure.NewYAMLBellRepository("bells.yaml").LoadBellSchedule(); err == nil {
+		domain.SetPairsPerDay(bells.PairCount())
+	}
+	mergePolicy, _ := config.MergePolicy()
+	// Пороги перечитываются при каждой проверке; при запуске — только сообщение об ошибках в rules.yaml,
+	// иначе неверный порог заметят лишь по сноскам отчета
+	if _, err := infrastructure.NewYAMLRulesRepository("rules.yaml").LoadLimits(); err != nil {
+		log.Printf("Ошибка в rules.yaml, действуют пороги по умолчанию: %v", err)
+	}
+
+	serviceOpts := []usecases.Option{usecases.WithMergePolicy(mergePolicy)}
+	if *demo {
+		dir, err := infrastructure.PrepareDemoDir()
+		if err != nil {
+			log.Fatalf("Ошибка запуска демо-режима: %v", err)
+		}
+		if err := os.Chdir(dir); err != nil {
+			log.Fatalf("Ошибка запуска демо-режима: %v", err)
+		}
+		log.Printf("Демо-режим: данные во временном каталоге %s", dir)
+		// Файлы данных берутся из демо-каталога, даже если в настройках указаны другие пути
+		config.Paths = infrastructure.DefaultConfig().Paths
+		config.Storage = infrastructure.DefaultConfig().Storage
+		serviceOpts = append(serviceOpts, usecases.WithLessonsRepository(func(week domain.Week) usecases.LessonsRepository {
+			return infrastructure.NewDemoLessonsRepository(week)
+		}))
+	}
 
-	go func() {
+	// Занятия из выгрузки: повторная проверка тех же данных без XLS-файлов и сайта
+	if *lessonsJSON != "" {
+		log.Printf("Занятия загружаются из %s", *lessonsJSON)
+		serviceOpts = append(serviceOpts, usecases.WithLessonsRepository(func(week domain.Week) usecases.LessonsRepository {
+			return infrastructure.NewJSONLessonsRepository(*lessonsJSON, week)
+		}))
+	}
+
+	if config.Debug.Enabled {
+		serviceOpts = append(serviceOpts, usecases.WithDebugDir(config.Debug.Dir))
+	}
+
+	if config.Archive.Enabled && !*demo {
+		serviceOpts = append(serviceOpts, usecases.WithScheduleArchive(infrastructure.NewScheduleArchive(config.Archive.Dir)))
+	}
+
+	// Правила, исключения, справочники и звонки одни на проверку и страницы настроек:
+	// изменения на страницах действуют с ближайшей проверки
+	exceptionRepo := infrastructure.NewYAMLExceptionRepository("exceptions.yaml")
+	teacherRepo := infrastructure.NewYAMLTeacherRepository("teachers.yaml")
+	bellRepo := infrastructure.NewYAMLBellRepository("bells.yaml")
+	serviceOpts = append(serviceOpts,
+		usecases.WithRulesRepository(infrastructure.NewYAMLRulesRepository("rules.yaml")),
+		usecases.WithExceptionRepository(exceptionRepo),
+		usecases.WithCabinetRepository(infrastructure.NewYAMLCabinetRepository("cabinets.yaml")),
+		usecases.WithTeacherRepository(teacherRepo),
+		usecases.WithBellRepository(bellRepo),
+	)
+
+	// Студенты, история проверок, план, журнал уведомлений и оповещения хранятся в выбранных в настройках
+	// хранилищах: YAML-файлах, SQLite или PostgreSQL
+	storage, err := infrastructure.OpenStorage(config.Storage, config.Paths)
+	if err != nil {
+		log.Fatalf("Ошибка открытия хранилища данных: %v", err)
+	}
+	defer storage.Close()
 
-		time.Sleep(500 * time.Millisecond)
-		url := fmt.Sprintf("http://localhost:%d", port)
-		log.Printf("Открываем браузер: %s\n", url)
-		openBrowser(url)
-	}()
+	// Один и тот же список студентов используется страницами и проверкой
+	studentRepo := storage.Students()
+	deptRepo := infrastructure.NewYAMLDepartmentRepository("departments.yaml")
+	// История проверок тоже одна на страницы и очистку: репозиторий блокирует запись в свой
+	// документ только внутри одного экземпляра
+	historyRepo := storage.History()
+
+	if *exportLessons != "" {
+		if err := exportWeekLessons(*exportLessons, studentRepo, serviceOpts); err != nil {
+			log.Fatalf("Ошибка выгрузки занятий: %v", err)
+		}
+		return
+	}
+
+	// Рассылка студентам: почта и Telegram настраиваются в config.yaml или переменными окружения
+	var emailSender, telegramSender usecases.Sender
+	if sender := config.SMTPSender(); sender != nil {
+		emailSender = sender
+	}
+	if sender := config.TelegramSender(); sender != nil {
+		telegramSender = sender
+	}
+	var notifier *usecases.NotificationService
+	if emailSender != nil || telegramSender != nil {
+		notifier = usecases.NewNotificationService(emailSender, telegramSender)
+	}
+
+	// Уведомления о проверках на внешний адрес подписываются и повторяются при сбоях
+	var webhooks *usecases.WebhookService
+	if sender := config.WebhookSender(); sender != nil && !*demo {
+		webhooks = usecases.NewWebhookService(sender, storage.Webhooks(),
+			config.Webhook.Attempts, config.Webhook.Backoff)
+	}
+
+	// Очередь заданий на проверку хранится в SQLite и переживает перезапуск
+	jobRepo, err := infrastructure.NewSQLiteJobRepository(config.Paths.Jobs)
+	if err != nil {
+		log.Fatalf("Ошибка открытия очереди заданий: %v", err)
+	}
+	defer jobRepo.Close()
+	if n, err := jobRepo.RequeueInterrupted(); err != nil {
+		log.Printf("Ошибка восстановления очереди заданий: %v", err)
+	} else if n > 0 {
+		log.Printf("Возвращено в очередь прерванных заданий: %d", n)
+	}
+
+	// Очистка устаревших данных: кнопкой на странице «Хранение данных», а при retention.enabled —
+	// ещё и раз в сутки. В демо-режиме данные временные, и очищать нечего.
+	var retention *usecases.RetentionService
+	policy := usecases.RetentionPolicy{
+		HistoryMonths: config.Retention.HistoryMonths,
+		DebugMonths:   config.Retention.DebugMonths,
+		ArchiveMonths: config.Retention.ArchiveMonths,
+	}
+	if !policy.IsZero() && !*demo {
+		retention = usecases.NewRetentionService(policy, historyRepo,
+			infrastructure.NewDebugDirs(config.Debug.Dir), infrastructure.NewScheduleArchive(config.Archive.Dir))
+	}
+
+	serverOpts := []web.Option{
+		web.WithServiceOptions(serviceOpts...),
+		web.WithHistoryRepository(historyRepo),
+		web.WithPlanRepository(storage.Plan()),
+		web.WithCalendarRepository(infrastructure.NewYAMLCalendarRepository("calendar.yaml")),
+		web.WithBellRepository(bellRepo),
+		web.WithExceptionRepository(exceptionRepo),
+		web.WithTeacherRepository(teacherRepo),
+		web.WithJobRepository(jobRepo),
+		web.WithUploads(infrastructure.NewScheduleUploads(config.Paths.Uploads)),
+		web.WithNotifier(notifier),
+		web.WithWebhooks(webhooks),
+		web.WithAlertCenter(usecases.NewAlertCenter(storage.Alerts())),
+		web.WithTemplatesDir(config.Templates),
+	}
+	// Без списка студентов проверять нечего: при первом запуске открывается мастер настройки
+	if exists, err := storage.Exists("students"); err == nil && !exists && !*demo && !*readOnly {
+		log.Printf("Список студентов не найден, открывается мастер первоначальной настройки")
+		serverOpts = append(serverOpts, web.WithSetup(*configPath))
+	} else if err := studentRepo.Validate(); err != nil {
+		// Страницы покажут эти ошибки, пока файл не будет исправлен
+		log.Printf("Ошибки в %s:\n%v", config.Paths.Students, err)
+	}
+	// Выпадающие списки отделений и групп в формах студентов загружаются с сайта
+	if !*demo {
+		serverOpts = append(serverOpts, web.WithSiteDepartments(infrastructure.CachedSiteDepartments))
+	}
+	// Проверка обновлений обращается к GitHub, поэтому включается только в настройках
+	if config.Updates.Check && !*demo {
+		serverOpts = append(serverOpts, web.WithUpdateChecker(infrastructure.NewUpdateChecker(config.Updates.Repo, version)))
+	}
+	// Прогрев кэша нужен только для загрузки расписания групп с сайта
+	if config.Warmup.Enabled && !*demo && *lessonsJSON == "" {
+		at, _ := config.Warmup.Time()
+		serverOpts = append(serverOpts, web.WithWarmup(at, config.Warmup.TTL))
+	}
+	if retention != nil {
+		serverOpts = append(serverOpts, web.WithRetention(retention, config.Retention.Enabled))
+	}
+	if *readOnly {
+		log.Printf("Режим только для просмотра")
+		serverOpts = append(serverOpts, web.WithReadOnly())
+	}
+	// Из значка в области уведомлений программа закрывается выходом из его цикла
+	if config.Tray {
+		serverOpts = append(serverOpts, web.WithOnShutdown(systray.Quit))
+	}
+	server := web.NewServer(studentRepo, deptRepo, serverOpts...)
+
+	// Строки журнала во время проверки показываются на главной странице
+	log.SetOutput(io.MultiWriter(os.Stderr, server.LogWriter()))
+
+	if config.GRPCPort != 0 {
+		go func() {
+			if err := server.StartGRPC(config.GRPCPort); err != nil {
+				log.Printf("Ошибка запуска gRPC API: %v", err)
+			}
+		}()
+	}
+
+	go server.RunJobQueue(context.Background())
+	go server.RunUpdateCheck(context.Background())
+	go server.RunWarmup(context.Background())
+	go server.RunRetention(context.Background())
+
+	// Если порт занят, веб-интерфейс запускается на следующем свободном
+	webPort, err := server.Listen(config.Port)
+	if err != nil {
+		log.Fatalf("Ошибка запуска веб-сервера: %v", err)
+	}
+	url := fmt.Sprintf("http://localhost:%d", webPort)
+	if *noBrowser {
+		log.Printf("Веб-интерфейс: %s", url)
+	} else {
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			log.Printf("Открываем браузер: %s\n", url)
+			openBrowser(url)
+		}()
+	}
+
+	if config.Tray {
+		hideConsole()
+		go func() {
+			if err := server.Serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Ошибка запуска веб-сервера: %v", err)
+			}
+		}()
+		runTray(server, url)
+		return
+	}
 
-	if err := server.Start(port); err != nil {
+	if err := server.Serve(); err != nil {
 		log.Fatalf("Ошибка запуска веб-сервера: %v", err)
 	}
 }
@@ -0,0 +1,10 @@
+// Package testsupport содержит хранилища в памяти и построители занятий для тестов.
+// С их помощью правила проверки можно покрывать табличными тестами без XLS-файлов,
+// students.yaml и обращения к сайту вуза:
+//
+//	lessons := []domain.Lesson{
+//		testsupport.NewLesson().On("2024-09-02").Pair(1).Group("МД-23-о").Build(),
+//		testsupport.NewLesson().On("2024-09-02").Pair(5).Individual("Иванов Пётр").Build(),
+//	}
+//	violations := domain.NewValidator(students, lessons).ValidateSchedule(testsupport.MustWeek("2024-09-02"))
+package testsupport
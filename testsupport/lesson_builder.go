@@ -0,0 +1,108 @@
+package testsupport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// LessonBuilder собирает domain.Lesson для тестов. По умолчанию это групповая пара
+// № 1 в понедельник 2024-09-02 на 2 академических часа.
+type LessonBuilder struct {
+	lesson domain.Lesson
+}
+
+// NewLesson создаёт построитель занятия со значениями по умолчанию
+func NewLesson() *LessonBuilder {
+	date := time.Date(2024, 9, 2, 0, 0, 0, 0, domain.Location())
+	return &LessonBuilder{lesson: domain.Lesson{
+		Time:       domain.NewLessonTime(date, 1, 0, 2, date, date),
+		Discipline: "Дисциплина",
+		Source:     domain.SourceGroup,
+	}}
+}
+
+// On задаёт дату занятия в формате "2006-01-02"
+func (b *LessonBuilder) On(date string) *LessonBuilder {
+	b.lesson.Time.Date = MustDate(date)
+	return b
+}
+
+// Pair задаёт номер пары
+func (b *LessonBuilder) Pair(number int) *LessonBuilder {
+	b.lesson.Time.Number = number
+	return b
+}
+
+// Half делает занятие половиной пары (1 или 2) на один академический час
+func (b *LessonBuilder) Half(half int) *LessonBuilder {
+	b.lesson.Time.PairHalf = half
+	b.lesson.Time.Hours = 1
+	return b
+}
+
+// Hours задаёт количество академических часов
+func (b *LessonBuilder) Hours(hours int) *LessonBuilder {
+	b.lesson.Time.Hours = hours
+	return b
+}
+
+// Discipline задаёт дисциплину
+func (b *LessonBuilder) Discipline(discipline string) *LessonBuilder {
+	b.lesson.Discipline = discipline
+	return b
+}
+
+// Teacher добавляет преподавателя
+func (b *LessonBuilder) Teacher(name string) *LessonBuilder {
+	b.lesson.Teachers = append(b.lesson.Teachers, domain.NewTeacher(name))
+	return b
+}
+
+// Cabinet задаёт кабинет
+func (b *LessonBuilder) Cabinet(cabinet string) *LessonBuilder {
+	b.lesson.Cabinet = cabinet
+	return b
+}
+
+// Group задаёт группу
+func (b *LessonBuilder) Group(group string) *LessonBuilder {
+	b.lesson.Group = group
+	return b
+}
+
+// Subgroup задаёт подгруппу группового занятия
+func (b *LessonBuilder) Subgroup(subgroup string) *LessonBuilder {
+	b.lesson.Subgroup = subgroup
+	return b
+}
+
+// Individual делает занятие индивидуальным занятием студента
+func (b *LessonBuilder) Individual(student string) *LessonBuilder {
+	b.lesson.Student = student
+	b.lesson.Source = domain.SourceIndividual
+	return b
+}
+
+// Build возвращает занятие с вычисленным идентификатором
+func (b *LessonBuilder) Build() domain.Lesson {
+	lesson := b.lesson
+	lesson.Teachers = append([]domain.Teacher(nil), b.lesson.Teachers...)
+	lesson.ID = lesson.ComputeID()
+	return lesson
+}
+
+// MustDate разбирает дату в формате "2006-01-02" в часовом поясе расписания и паникует при ошибке
+func MustDate(date string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", date, domain.Location())
+	if err != nil {
+		panic(fmt.Sprintf("testsupport: неверная дата %q: %v", date, err))
+	}
+	return t
+}
+
+// MustWeek возвращает неделю, которой принадлежит дата в формате "2006-01-02"
+func MustWeek(date string) domain.Week {
+	return domain.WeekOf(MustDate(date))
+}
@@ -0,0 +1,108 @@
+package testsupport
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// MemoryStudentRepository хранит студентов в памяти и реализует usecases.StudentRepository
+type MemoryStudentRepository struct {
+	mutex    sync.RWMutex
+	students []domain.Student
+}
+
+// NewMemoryStudentRepository создаёт хранилище с копией переданного списка студентов
+func NewMemoryStudentRepository(students ...domain.Student) *MemoryStudentRepository {
+	return &MemoryStudentRepository{students: append([]domain.Student(nil), students...)}
+}
+
+// LoadStudents возвращает копию списка студентов
+func (r *MemoryStudentRepository) LoadStudents() ([]domain.Student, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]domain.Student(nil), r.students...), nil
+}
+
+// AddStudent добавляет студента, если студента с таким именем ещё нет
+func (r *MemoryStudentRepository) AddStudent(student domain.Student) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, s := range r.students {
+		if s.Name == student.Name {
+			return fmt.Errorf("студент %s уже существует", student.Name)
+		}
+	}
+	r.students = append(r.students, student)
+	return nil
+}
+
+// SaveStudents заменяет весь список студентов копией переданного
+func (r *MemoryStudentRepository) SaveStudents(students []domain.Student) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.students = append([]domain.Student(nil), students...)
+	return nil
+}
+
+// GetStudent возвращает студента по имени
+func (r *MemoryStudentRepository) GetStudent(name string) (domain.Student, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, s := range r.students {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return domain.Student{}, fmt.Errorf("студент %s не найден", name)
+}
+
+// UpdateStudent обновляет данные студента
+func (r *MemoryStudentRepository) UpdateStudent(name string, updated domain.Student) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, s := range r.students {
+		if s.Name == name {
+			r.students[i] = updated
+			return nil
+		}
+	}
+	return fmt.Errorf("студент %s не найден", name)
+}
+
+// DeleteStudent удаляет студента по имени
+func (r *MemoryStudentRepository) DeleteStudent(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, s := range r.students {
+		if s.Name == name {
+			r.students = append(r.students[:i], r.students[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("студент %s не найден", name)
+}
+
+// MemoryLessonsRepository отдаёт заранее заданные занятия и реализует usecases.LessonsRepository.
+// Если задано Err, оно возвращается вместе с занятиями — так имитируется частичный сбой источников.
+type MemoryLessonsRepository struct {
+	Lessons []domain.Lesson
+	Err     error
+}
+
+// NewMemoryLessonsRepository создаёт хранилище с указанными занятиями
+func NewMemoryLessonsRepository(lessons ...domain.Lesson) *MemoryLessonsRepository {
+	return &MemoryLessonsRepository{Lessons: lessons}
+}
+
+// GetLessons возвращает копию занятий и заданную ошибку
+func (r *MemoryLessonsRepository) GetLessons() ([]domain.Lesson, error) {
+	return append([]domain.Lesson(nil), r.Lessons...), r.Err
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"runtime"
+
+	"fyne.io/systray"
+
+	"github.com/Vaflel/lesson-counter/web"
+)
+
+// runTray показывает значок в области уведомлений с быстрыми действиями: открыть
+// веб-интерфейс, проверить текущую неделю и закрыть программу. Возвращает управление
+// после выхода из программы через меню значка или кнопку «Закрыть программу».
+func runTray(server *web.Server, url string) {
+	systray.Run(func() {
+		systray.SetIcon(trayIcon())
+		systray.SetTitle("Lesson Counter")
+		systray.SetTooltip("Lesson Counter — проверка расписания")
+
+		open := systray.AddMenuItem("Открыть", "Открыть веб-интерфейс в браузере")
+		check := systray.AddMenuItem("Проверить текущую неделю", "Запустить проверку текущей недели")
+		systray.AddSeparator()
+		quit := systray.AddMenuItem("Закрыть программу", "Остановить сервер и выйти")
+
+		go func() {
+			for {
+				select {
+				case <-open.ClickedCh:
+					openBrowser(url)
+				case <-check.ClickedCh:
+					if err := server.CheckCurrentWeek(); err != nil {
+						log.Printf("Не удалось запустить проверку: %v", err)
+						continue
+					}
+					// результат проверки показывается на главной странице
+					openBrowser(url)
+				case <-quit.ClickedCh:
+					server.Shutdown()
+					return
+				}
+			}
+		}()
+	}, nil)
+}
+
+// trayIcon рисует значок: белый прямоугольник-«расписание» на синем фоне. Windows
+// принимает значок в формате ICO, остальные системы — PNG.
+func trayIcon() []byte {
+	const size = 32
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	blue := color.RGBA{R: 0x1e, G: 0x63, B: 0xb8, A: 0xff}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := blue
+			if x >= 7 && x < 25 && y >= 7 && y < 25 && (y-7)%6 != 5 {
+				c = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var data bytes.Buffer
+	png.Encode(&data, img)
+	if runtime.GOOS != "windows" {
+		return data.Bytes()
+	}
+
+	// ICO с одним изображением PNG: заголовок, запись каталога, данные
+	var ico bytes.Buffer
+	binary.Write(&ico, binary.LittleEndian, []uint16{0, 1, 1})
+	ico.Write([]byte{size, size, 0, 0})
+	binary.Write(&ico, binary.LittleEndian, []uint16{1, 32})
+	binary.Write(&ico, binary.LittleEndian, []uint32{uint32(data.Len()), 6 + 16})
+	ico.Write(data.Bytes())
+	return ico.Bytes()
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// AlertCenter собирает оповещения из событий проверки и заданий и хранит их вместе
+// с отметками о прочтении, чтобы важные предупреждения не терялись в журнале
+type AlertCenter struct {
+	repo AlertRepository
+}
+
+// NewAlertCenter создаёт центр оповещений, хранящий оповещения в repo
+func NewAlertCenter(repo AlertRepository) *AlertCenter {
+	return &AlertCenter{repo: repo}
+}
+
+// HandleEvent реализует domain.EventListener: сохраняет оповещения о событии (см. domain.AlertsFor).
+// Оповещение, которое повторяет ещё не прочитанное (например, о той же новой версии после
+// перезапуска программы), не добавляется.
+func (c *AlertCenter) HandleEvent(event domain.Event) {
+	candidates := domain.AlertsFor(event)
+	if len(candidates) == 0 {
+		return
+	}
+	existing, err := c.repo.LoadAlerts()
+	if err != nil {
+		log.Printf("Ошибка загрузки оповещений: %v", err)
+		return
+	}
+	unread := make(map[string]bool)
+	for _, alert := range existing {
+		if !alert.Read {
+			unread[alert.Title+"\n"+alert.Text] = true
+		}
+	}
+
+	var alerts []domain.Alert
+	for _, alert := range candidates {
+		if unread[alert.Title+"\n"+alert.Text] {
+			continue
+		}
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			log.Printf("Ошибка создания идентификатора оповещения: %v", err)
+			return
+		}
+		alert.ID = hex.EncodeToString(id)
+		alerts = append(alerts, alert)
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	if err := c.repo.SaveAlerts(alerts); err != nil {
+		log.Printf("Ошибка сохранения оповещений: %v", err)
+	}
+}
+
+// Alerts возвращает оповещения, новые первыми
+func (c *AlertCenter) Alerts() ([]domain.Alert, error) {
+	return c.repo.LoadAlerts()
+}
+
+// Unread возвращает количество непрочитанных оповещений
+func (c *AlertCenter) Unread() (int, error) {
+	alerts, err := c.repo.LoadAlerts()
+	if err != nil {
+		return 0, err
+	}
+	unread := 0
+	for _, alert := range alerts {
+		if !alert.Read {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// MarkRead отмечает прочитанным оповещение id
+func (c *AlertCenter) MarkRead(id string) error {
+	return c.repo.MarkRead(id)
+}
+
+// MarkAllRead отмечает прочитанными все оповещения
+func (c *AlertCenter) MarkAllRead() error {
+	return c.repo.MarkRead()
+}
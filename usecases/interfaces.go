@@ -1,6 +1,10 @@
 package usecases
 
-import "github.com/Vaflel/lesson-counter/domain"
+import (
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
 
 type LessonsRepository interface {
 	GetLessons() ([]domain.Lesson, error)
@@ -13,4 +17,103 @@ type StudentRepository interface {
 	GetStudent(name string) (domain.Student, error)
 	UpdateStudent(name string, updated domain.Student) error
 	DeleteStudent(name string) error
+	SaveStudents(students []domain.Student) error
+}
+
+// RulesRepository определяет интерфейс для загрузки порогов и пользовательских правил проверки
+type RulesRepository interface {
+	LoadLimits() (domain.Limits, error)
+	LoadCustomRules() ([]domain.CustomRule, error)
+}
+
+// CabinetRepository определяет интерфейс для загрузки справочника кабинетов
+type CabinetRepository interface {
+	LoadNormalizer() (domain.CabinetNormalizer, error)
+	LoadCabinets() ([]domain.Cabinet, error)
+}
+
+// DepartmentRepository определяет интерфейс для работы с хранилищем отделений
+type DepartmentRepository interface {
+	LoadDepartments() ([]domain.Department, error)
+	AddDepartment(department domain.Department) error
+	GetDepartment(name string) (domain.Department, error)
+	UpdateDepartment(name string, updated domain.Department) error
+	DeleteDepartment(name string) error
+}
+
+// PlanRepository определяет интерфейс для загрузки учебного плана часов
+type PlanRepository interface {
+	LoadPlan() ([]domain.Semester, []domain.PlanEntry, error)
+}
+
+// CalendarRepository определяет интерфейс для загрузки учебного календаря
+type CalendarRepository interface {
+	LoadCalendar() (domain.AcademicCalendar, error)
+}
+
+// BellRepository определяет интерфейс для хранения расписания звонков
+type BellRepository interface {
+	LoadBellSchedule() (domain.BellSchedule, error)
+	SaveBellSchedule(bells domain.BellSchedule) error
+}
+
+// ExceptionRepository определяет интерфейс для хранения допущенных исключений
+type ExceptionRepository interface {
+	LoadExceptions() ([]domain.Exception, error)
+	AddException(exception domain.Exception) error
+	DeleteException(id string) error
+	RenameStudent(from, to string) (int, error)
+}
+
+// TeacherRepository определяет интерфейс для хранения справочника преподавателей с псевдонимами
+type TeacherRepository interface {
+	LoadRegistry() (domain.TeacherRegistry, error)
+	ConfirmAlias(name, alias string) error
+	DismissAlias(name, alias string) error
+	RemoveAlias(name, alias string) error
+	ExpectTeacher(name, email, telegram string) error
+	UnexpectTeacher(name string) error
+	GrantAccess(name string) (string, error)
+	RevokeAccess(name string) error
+	SuggestAliases(pairs [][2]string) (int, error)
+}
+
+// JobRepository определяет интерфейс для хранения очереди заданий на проверку
+type JobRepository interface {
+	EnqueueJob(job domain.Job) (domain.Job, error)
+	EnqueueJobs(jobs []domain.Job) ([]domain.Job, error)
+	LoadBatch(batch int64) ([]domain.Job, error)
+	LoadJobs() ([]domain.Job, error)
+	GetJob(id int64) (domain.Job, error)
+	NextDueJob(now time.Time) (domain.Job, bool, error)
+	UpdateJob(job domain.Job) error
+}
+
+// WebhookLog определяет интерфейс журнала доставки уведомлений на внешний адрес
+type WebhookLog interface {
+	SaveDelivery(delivery domain.WebhookDelivery) error
+	LoadDeliveries() ([]domain.WebhookDelivery, error)
+	GetDelivery(id string) (domain.WebhookDelivery, error)
+}
+
+// AlertRepository определяет интерфейс хранилища оповещений
+type AlertRepository interface {
+	SaveAlerts(alerts []domain.Alert) error
+	LoadAlerts() ([]domain.Alert, error)
+	MarkRead(ids ...string) error
+}
+
+// ScheduleArchiver определяет интерфейс архива обработанных файлов расписания
+type ScheduleArchiver interface {
+	Dir() string
+	ArchiveWeek(week domain.Week, files []domain.FileCoverage) ([]string, error)
+}
+
+// HistoryRepository определяет интерфейс для хранения истории проверок
+type HistoryRepository interface {
+	SaveCheck(record domain.CheckRecord) error
+	LoadChecks() ([]domain.CheckRecord, error)
+	AddComment(comment domain.Comment) error
+	LoadComments(violationID string) ([]domain.Comment, error)
+	RenameStudent(from, to string) (int, error)
 }
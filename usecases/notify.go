@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// Sender отправляет сообщение по одному каналу связи (e-mail, Telegram)
+type Sender interface {
+	Send(to, subject, text string) error
+}
+
+// NotificationService рассылает студентам их часть отчёта о нарушениях
+type NotificationService struct {
+	email    Sender // может быть nil, если почта не настроена
+	telegram Sender // может быть nil, если бот не настроен
+}
+
+// NewNotificationService создаёт сервис уведомлений. Ненастроенный канал передаётся как nil
+func NewNotificationService(email, telegram Sender) *NotificationService {
+	return &NotificationService{email: email, telegram: telegram}
+}
+
+// NotifyResult содержит итоги рассылки по студентам
+type NotifyResult struct {
+	Sent    []string // студенты, которым (или кураторам которых) отправлено уведомление
+	Skipped []string // студенты без контактов для настроенных каналов
+	Failed  []string // студенты, уведомление которым отправить не удалось
+}
+
+// NotifyStudents отправляет каждому студенту с нарушениями только его нарушения:
+// на e-mail и в Telegram, если они указаны, иначе — куратору на e-mail.
+func (n *NotificationService) NotifyStudents(students []domain.Student, violations []domain.Violation) (NotifyResult, error) {
+	var result NotifyResult
+	var errs []error
+
+	byStudent := make(map[string][]domain.Violation)
+	for _, v := range violations {
+		byStudent[v.StudentName] = append(byStudent[v.StudentName], v)
+	}
+
+	for _, student := range students {
+		studentViolations := byStudent[student.Name]
+		if len(studentViolations) == 0 {
+			continue
+		}
+
+		subject := "Нарушения в расписании: " + student.Name
+		text := StudentMessage(student, studentViolations)
+
+		sent := false
+		var failed error
+		send := func(sender Sender, to string) {
+			if sender == nil || to == "" {
+				return
+			}
+			if err := sender.Send(to, subject, text); err != nil {
+				failed = errors.Join(failed, err)
+				return
+			}
+			sent = true
+		}
+
+		send(n.email, student.Email)
+		send(n.telegram, student.Telegram)
+		if !sent && failed == nil {
+			send(n.email, student.CuratorEmail)
+		}
+
+		switch {
+		case failed != nil:
+			result.Failed = append(result.Failed, student.Name)
+			errs = append(errs, fmt.Errorf("%s: %w", student.Name, failed))
+		case sent:
+			result.Sent = append(result.Sent, student.Name)
+		default:
+			result.Skipped = append(result.Skipped, student.Name)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// NotifyAbsentTeachers напоминает преподавателям, у которых нет индивидуальных занятий на неделе
+// week, прислать файл расписания: на e-mail и в Telegram, если они указаны. В результате вместо
+// студентов перечислены преподаватели.
+func (n *NotificationService) NotifyAbsentTeachers(teachers []domain.Teacher, week domain.Week) (NotifyResult, error) {
+	var result NotifyResult
+	var errs []error
+
+	subject := "Расписание индивидуальных занятий на неделю " + week.Start().Format("02.01.2006")
+	for _, teacher := range teachers {
+		text := TeacherReminderMessage(teacher, week)
+		sent := false
+		var failed error
+		for _, channel := range []struct {
+			sender Sender
+			to     string
+		}{{n.email, teacher.Email}, {n.telegram, teacher.Telegram}} {
+			if channel.sender == nil || channel.to == "" {
+				continue
+			}
+			if err := channel.sender.Send(channel.to, subject, text); err != nil {
+				failed = errors.Join(failed, err)
+				continue
+			}
+			sent = true
+		}
+
+		switch {
+		case failed != nil:
+			result.Failed = append(result.Failed, teacher.Name)
+			errs = append(errs, fmt.Errorf("%s: %w", teacher.Name, failed))
+		case sent:
+			result.Sent = append(result.Sent, teacher.Name)
+		default:
+			result.Skipped = append(result.Skipped, teacher.Name)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// TeacherReminderMessage формирует текст напоминания преподавателю о файле расписания
+func TeacherReminderMessage(teacher domain.Teacher, week domain.Week) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Преподаватель: %s\n", teacher.Name)
+	fmt.Fprintf(&b, "При проверке расписания студентов за неделю с %s по %s не найдено ваших индивидуальных занятий.\n",
+		week.Start().Format("02.01.2006"), week.End().Format("02.01.2006"))
+	b.WriteString("Если занятия на этой неделе есть, пришлите, пожалуйста, файл расписания диспетчеру.")
+	return b.String()
+}
+
+// StudentMessage формирует текст уведомления с нарушениями одного студента
+func StudentMessage(student domain.Student, violations []domain.Violation) string {
+	sorted := append([]domain.Violation(nil), violations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Студент: %s (группа %s, курс %d)\n", student.Name, student.Group, student.Year)
+	b.WriteString("В вашем расписании найдены нарушения:\n")
+	for _, v := range sorted {
+		date := domain.LessonTime{Date: v.Date}
+		fmt.Fprintf(&b, "- %s, %s: %s (%d ак.ч)\n", date.DayName(), date.DateString(), v.Title(), v.Hours)
+	}
+	b.WriteString("\nПо вопросам расписания обратитесь к диспетчеру.")
+	return b.String()
+}
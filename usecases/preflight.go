@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// PreflightCheck — результат одной предварительной проверки перед запуском проверки расписания
+type PreflightCheck struct {
+	Name    string // что проверялось
+	OK      bool
+	Message string // подробности или причина ошибки
+}
+
+// Preflight проверяет, что для проверки недели сервиса всё готово: список студентов читается,
+// сайт расписания доступен, в XLS-файлах есть занятия этой недели, а файлы настроек
+// проверки корректны. В отличие от ProcessSchedule, групповое расписание не загружается.
+func (s ScheduleService) Preflight() []PreflightCheck {
+	var checks []PreflightCheck
+	add := func(name string, err error, message string) {
+		check := PreflightCheck{Name: name, OK: err == nil, Message: message}
+		if err != nil {
+			check.Message = err.Error()
+		}
+		checks = append(checks, check)
+	}
+
+	students, err := s.studentRepo.LoadStudents()
+	if err == nil && len(students) == 0 {
+		err = fmt.Errorf("список студентов пуст")
+	}
+	add("Список студентов", err, fmt.Sprintf("студентов: %d", len(students)))
+
+	if s.lessonsRepo != nil {
+		add("Сайт расписания", nil, "не используется: занятия загружаются из другого источника")
+		add("Файлы индивидуального расписания", nil, "не используются: занятия загружаются из другого источника")
+	} else {
+		add("Сайт расписания", infrastructure.CheckScheduleSite(), "доступен")
+		count, err := s.individualLessonsInWeek()
+		add("Файлы индивидуального расписания", err, fmt.Sprintf("занятий за неделю: %d", count))
+	}
+
+	if s.rulesRepo != nil {
+		_, err = s.rulesRepo.LoadCustomRules()
+		if err == nil {
+			_, err = s.rulesRepo.LoadLimits()
+		}
+		add("Правила проверки (rules.yaml)", err, "корректны")
+	} else {
+		add("Правила проверки (rules.yaml)", nil, "не используются: пороги по умолчанию")
+	}
+	if s.exceptionRepo != nil {
+		_, err = s.exceptionRepo.LoadExceptions()
+		add("Исключения (exceptions.yaml)", err, "корректны")
+	} else {
+		add("Исключения (exceptions.yaml)", nil, "не используются")
+	}
+	if s.bellRepo != nil {
+		_, err = s.bellRepo.LoadBellSchedule()
+		add("Расписание звонков (bells.yaml)", err, "корректно")
+	} else {
+		add("Расписание звонков (bells.yaml)", nil, "не используется: звонки по умолчанию")
+	}
+	_, err = infrastructure.NewYAMLCalendarRepository("calendar.yaml").LoadCalendar()
+	add("Учебный календарь (calendar.yaml)", err, "корректен")
+
+	return checks
+}
+
+// individualLessonsInWeek разбирает XLS-файлы и возвращает количество занятий за неделю сервиса
+func (s ScheduleService) individualLessonsInWeek() (int, error) {
+	bells, err := s.bellSchedule()
+	if err != nil {
+		bells = domain.DefaultBellSchedule()
+	}
+	parser := infrastructure.NewIndividualScheduleParser(nil)
+	parser.SetMergePolicy(s.mergePolicy)
+	parser.SetBellSchedule(bells)
+	lessons, err := parser.Parse()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, lesson := range lessons {
+		if s.week.Contains(lesson.Time.Date) {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("в XLS-файлах нет занятий за неделю %s–%s",
+			s.week.Start().Format("02.01.2006"), s.week.End().Format("02.01.2006"))
+	}
+	return count, nil
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// RetentionPolicy — сроки хранения данных в месяцах; 0 — хранить без ограничения
+type RetentionPolicy struct {
+	HistoryMonths int // история проверок
+	DebugMonths   int // отладочные данные разбора XLS-файлов
+	ArchiveMonths int // архив обработанных XLS-файлов
+}
+
+// IsZero сообщает, что ни для каких данных срок хранения не задан
+func (p RetentionPolicy) IsZero() bool {
+	return p.HistoryMonths <= 0 && p.DebugMonths <= 0 && p.ArchiveMonths <= 0
+}
+
+// Pruner удаляет данные, относящиеся ко времени до before, и возвращает число удалённых записей или папок
+type Pruner interface {
+	Prune(before time.Time) (int, error)
+}
+
+// CleanupResult — итог одной очистки устаревших данных
+type CleanupResult struct {
+	At      time.Time
+	Checks  int      // удалено проверок из истории
+	Debug   int      // удалено каталогов отладочных данных
+	Archive int      // удалено недель архива
+	Errors  []string // ошибки очистки отдельных данных; остальные данные очищены
+}
+
+// RetentionService удаляет данные старше сроков хранения, чтобы история проверок, отладочные
+// данные и архив XLS-файлов не росли бесконечно на долго работающей установке
+type RetentionService struct {
+	policy  RetentionPolicy
+	history Pruner // может быть nil
+	debug   Pruner // может быть nil
+	archive Pruner // может быть nil
+}
+
+// NewRetentionService создаёт сервис очистки; данные без хранилища (nil) не очищаются
+func NewRetentionService(policy RetentionPolicy, history, debug, archive Pruner) *RetentionService {
+	return &RetentionService{policy: policy, history: history, debug: debug, archive: archive}
+}
+
+// Policy возвращает сроки хранения
+func (s *RetentionService) Policy() RetentionPolicy {
+	return s.policy
+}
+
+// Cleanup удаляет данные старше сроков хранения на момент now
+func (s *RetentionService) Cleanup(now time.Time) CleanupResult {
+	result := CleanupResult{At: now}
+	targets := []struct {
+		name    string
+		pruner  Pruner
+		months  int
+		removed *int
+	}{
+		{"история проверок", s.history, s.policy.HistoryMonths, &result.Checks},
+		{"отладочные данные", s.debug, s.policy.DebugMonths, &result.Debug},
+		{"архив XLS-файлов", s.archive, s.policy.ArchiveMonths, &result.Archive},
+	}
+	for _, target := range targets {
+		if target.pruner == nil || target.months <= 0 {
+			continue
+		}
+		removed, err := target.pruner.Prune(now.AddDate(0, -target.months, 0))
+		*target.removed = removed
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", target.name, err))
+		}
+	}
+	log.Printf("Очистка устаревших данных: проверок %d, каталогов отладки %d, недель архива %d, ошибок %d",
+		result.Checks, result.Debug, result.Archive, len(result.Errors))
+	return result
+}
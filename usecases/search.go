@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// SearchKind — вид найденного объекта
+type SearchKind string
+
+const (
+	SearchStudent    SearchKind = "student"
+	SearchTeacher    SearchKind = "teacher"
+	SearchDiscipline SearchKind = "discipline"
+)
+
+// SearchResult — объект, найденный быстрым поиском
+type SearchResult struct {
+	Kind   SearchKind
+	Name   string
+	Detail string // группа и курс студента, число занятий преподавателя или дисциплины
+	rank   int
+}
+
+// Search ищет query среди студентов, преподавателей и дисциплин занятий и учебного плана
+// без учёта регистра и диакритических знаков. Сначала идут точные совпадения, затем
+// совпадения с началом слова; результатов не больше limit.
+func Search(query string, students []domain.Student, lessons []domain.Lesson, plan []domain.PlanEntry, limit int) []SearchResult {
+	query = domain.SearchKey(query)
+	if query == "" {
+		return nil
+	}
+
+	var results []SearchResult
+	for _, student := range students {
+		if rank, ok := domain.SearchRank(student.Name, query); ok {
+			results = append(results, SearchResult{
+				Kind:   SearchStudent,
+				Name:   student.Name,
+				Detail: fmt.Sprintf("%s, %d курс", student.Group, student.Year),
+				rank:   rank,
+			})
+		}
+	}
+
+	teacherLessons := make(map[string]int)
+	disciplineLessons := make(map[string]int)
+	for _, lesson := range lessons {
+		for _, teacher := range lesson.Teachers {
+			teacherLessons[teacher.Name]++
+		}
+		disciplineLessons[lesson.Discipline]++
+	}
+	for _, entry := range plan {
+		if _, ok := disciplineLessons[entry.Discipline]; !ok {
+			disciplineLessons[entry.Discipline] = 0
+		}
+	}
+
+	for _, source := range []struct {
+		kind   SearchKind
+		counts map[string]int
+	}{{SearchTeacher, teacherLessons}, {SearchDiscipline, disciplineLessons}} {
+		for name, count := range source.counts {
+			rank, ok := domain.SearchRank(name, query)
+			if !ok || name == "" {
+				continue
+			}
+			detail := "нет в последней проверке"
+			if count > 0 {
+				detail = fmt.Sprintf("занятий в последней проверке: %d", count)
+			}
+			results = append(results, SearchResult{Kind: source.kind, Name: name, Detail: detail, rank: rank})
+		}
+	}
+
+	kindOrder := map[SearchKind]int{SearchStudent: 0, SearchTeacher: 1, SearchDiscipline: 2}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return domain.SearchKey(a.Name) < domain.SearchKey(b.Name)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
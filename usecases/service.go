@@ -1,41 +1,396 @@
 package usecases
 
 import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/Vaflel/lesson-counter/domain"
 	"github.com/Vaflel/lesson-counter/infrastructure"
 )
 
 // ScheduleService управляет оркестрацией парсинга и валидации расписания
 type ScheduleService struct {
-	weekStart string
+	week        domain.Week
+	events      *domain.EventBus
+	mergePolicy domain.MergePolicy
+	lessonsRepo LessonsRepositoryFactory // источник занятий вместо XLS-файлов и сайта, может быть nil
+	studentRepo StudentRepository        // список студентов, чьё расписание проверяется
+	archive     ScheduleArchiver         // архив обработанных XLS-файлов, может быть nil
+	files       []string                 // XLS-файлы вместо всех файлов рабочей папки, пусто — все
+	debugDir    string                   // каталог отладочных данных разбора, пусто — не записываются
+	dryRun      bool                     // пробная проверка: без архива файлов, кэша и справочника преподавателей
+	// Настройки проверки; незаданные не используются: пороги по умолчанию, без исключений,
+	// без приведения кабинетов и справочника преподавателей, звонки по умолчанию
+	rulesRepo     RulesRepository
+	exceptionRepo ExceptionRepository
+	cabinetRepo   CabinetRepository
+	teacherRepo   TeacherRepository
+	bellRepo      BellRepository
+}
+
+// LessonsRepositoryFactory создаёт источник занятий за неделю
+type LessonsRepositoryFactory func(week domain.Week) LessonsRepository
+
+// Option настраивает ScheduleService при создании
+type Option func(*ScheduleService)
+
+// WithEventBus задаёт шину, в которую сервис публикует события проверки
+func WithEventBus(events *domain.EventBus) Option {
+	return func(s *ScheduleService) {
+		s.events = events
+	}
 }
 
 // ValidatingResult содержит результаты обработки расписания
 type ValidatingResult struct {
 	Violations []domain.Violation
 	Lessons    []domain.Lesson
+	Students   []domain.Student           // проверенные студенты
+	Issues     []domain.Issue             // некритичные проблемы: при их наличии результат может быть неполным
+	Excepted   []domain.ExceptedViolation // нарушения, подавленные допущенными исключениями
+	Rules      []domain.RuleDescription   // описания правил проверки с порогами для сносок отчета
+	// FailedGroups — группы, расписание которых не загрузилось с сайта даже после повторов;
+	// их можно загрузить снова через RetryFailedGroups, не повторяя всю проверку
+	FailedGroups []string
+	DryRun       bool                // пробная проверка: результат не сохраняется в историю и не рассылается
+	Completeness domain.Completeness // полнота данных: сколько ожидаемых преподавателей, групп и студентов попали в проверку
+	// CabinetConflicts — накладки кабинетов со свободными кабинетами того же типа для переноса
+	CabinetConflicts []domain.CabinetConflict
+	PairsPerDay      int // количество пар в учебном дне по расписанию звонков проверки
+}
+
+// WithMergePolicy задаёт политику объединения половинок пары с разными данными
+func WithMergePolicy(policy domain.MergePolicy) Option {
+	return func(s *ScheduleService) {
+		s.mergePolicy = policy
+	}
+}
+
+// WithLessonsRepository заменяет загрузку занятий из XLS-файлов и с сайта другим источником
+// (например, демонстрационным расписанием)
+func WithLessonsRepository(factory LessonsRepositoryFactory) Option {
+	return func(s *ScheduleService) {
+		s.lessonsRepo = factory
+	}
+}
+
+// WithScheduleArchive включает перенос обработанных XLS-файлов в архив после успешной проверки
+func WithScheduleArchive(archive ScheduleArchiver) Option {
+	return func(s *ScheduleService) {
+		s.archive = archive
+	}
 }
 
-// NewScheduleService создает новый экземпляр сервиса
-func NewScheduleService(weekStart string) *ScheduleService {
-	return &ScheduleService{
-		weekStart: weekStart,
+// WithScheduleFiles ограничивает проверку указанными XLS-файлами вместо всех файлов рабочей папки
+func WithScheduleFiles(paths []string) Option {
+	return func(s *ScheduleService) {
+		s.files = paths
 	}
 }
 
-func (s ScheduleService) ProcessSchedule(weekStart string) (ValidatingResult, error) {
-	students_repository := infrastructure.NewYAMLStudentRepository("students.yaml")
-	students, _ := students_repository.LoadStudents()
+// WithDebugDir включает запись промежуточных данных разбора XLS-файлов: для каждой
+// проверки в dir создаётся отдельный каталог
+func WithDebugDir(dir string) Option {
+	return func(s *ScheduleService) {
+		s.debugDir = dir
+	}
+}
+
+// WithDryRun включает пробную проверку — для опытов с порогами правил: занятия загружаются
+// и проверяются как обычно, но XLS-файлы не переносятся в архив, расписание групп с сайта
+// не сохраняется в кэш, а кандидаты в псевдонимы преподавателей не записываются. Результат
+// отмечается DryRun, чтобы вызывающий не сохранял его в историю и не рассылал.
+func WithDryRun() Option {
+	return func(s *ScheduleService) {
+		s.dryRun = true
+	}
+}
+
+// WithRulesRepository задаёт хранилище порогов и пользовательских правил проверки
+func WithRulesRepository(repo RulesRepository) Option {
+	return func(s *ScheduleService) {
+		s.rulesRepo = repo
+	}
+}
+
+// WithExceptionRepository задаёт хранилище допущенных исключений, подавляющих нарушения
+func WithExceptionRepository(repo ExceptionRepository) Option {
+	return func(s *ScheduleService) {
+		s.exceptionRepo = repo
+	}
+}
+
+// WithCabinetRepository задаёт справочник кабинетов для приведения записей кабинетов
+// и подбора свободных кабинетов при накладках
+func WithCabinetRepository(repo CabinetRepository) Option {
+	return func(s *ScheduleService) {
+		s.cabinetRepo = repo
+	}
+}
+
+// WithTeacherRepository задаёт справочник преподавателей: псевдонимы при разборе XLS-файлов
+// и список преподавателей, от которых ждут файл расписания
+func WithTeacherRepository(repo TeacherRepository) Option {
+	return func(s *ScheduleService) {
+		s.teacherRepo = repo
+	}
+}
+
+// WithBellRepository задаёт расписание звонков для разбора XLS-файлов и сетки пар отчета
+func WithBellRepository(repo BellRepository) Option {
+	return func(s *ScheduleService) {
+		s.bellRepo = repo
+	}
+}
+
+// NewScheduleService создает новый экземпляр сервиса. Студенты загружаются из studentRepo —
+// того же хранилища, с которым работают страницы программы.
+func NewScheduleService(week domain.Week, studentRepo StudentRepository, opts ...Option) *ScheduleService {
+	s := &ScheduleService{
+		week:        week,
+		mergePolicy: domain.MergePolicyJoin,
+		studentRepo: studentRepo,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
 
-	// соберём уникальные факультеты
-	deptSet := make(map[string]struct{})
+// ProcessSchedule загружает студентов и занятия за неделю сервиса и проверяет расписание.
+// О ходе проверки сообщается событиями в шину, заданную через WithEventBus.
+func (s ScheduleService) ProcessSchedule() (ValidatingResult, error) {
+	s.events.Publish(domain.Event{Type: domain.EventCheckStarted, Week: s.week})
+
+	result, err := s.process()
+
+	s.events.Publish(domain.Event{
+		Type:   domain.EventCheckCompleted,
+		Week:   s.week,
+		Count:  len(result.Violations),
+		Err:    err,
+		Issues: result.Issues,
+	})
+	return result, err
+}
+
+// process выполняет загрузку данных и проверку расписания
+func (s ScheduleService) process() (ValidatingResult, error) {
+	diagnostics := domain.NewDiagnostics()
+
+	students, departments, groups, err := s.loadScope()
+	if err != nil {
+		return ValidatingResult{}, err
+	}
+	lessons_repository, err := s.lessonsRepository(departments, groups, diagnostics)
+	if err != nil {
+		return ValidatingResult{}, err
+	}
+	lessons, err := lessons_repository.GetLessons()
+	if err != nil && len(lessons) == 0 {
+		return ValidatingResult{}, fmt.Errorf("не удалось загрузить расписание: %w", err)
+	}
+	// XLS-файл преподавателя может охватывать несколько недель: занятия других недель
+	// проверяются вместе со своей неделей, иначе отчет за месяц учтёт их в каждой неделе
+	lessons = domain.Schedule(lessons).ForWeek(s.week)
+
+	s.publishSourcesParsed(lessons)
+	if !s.dryRun {
+		s.suggestTeacherAliases(lessons_repository)
+	}
+
+	expected, absent := s.checkExpectedTeachers(lessons, diagnostics)
+	result := s.validate(students, lessons, diagnostics)
+	if failed, ok := lessons_repository.(interface{ FailedGroups() []string }); ok {
+		result.FailedGroups = failed.FailedGroups()
+	}
+	var coverage []domain.FileCoverage
+	if covered, ok := lessons_repository.(interface{ Coverage() []domain.FileCoverage }); ok {
+		coverage = covered.Coverage()
+	}
+	result.Completeness = domain.MeasureCompleteness(s.week, students, lessons, coverage, result.Issues)
+	if len(expected) > 0 {
+		result.Completeness.ExpectTeachers(len(expected), absent)
+	}
+
+	if !s.dryRun {
+		s.archiveFiles(lessons_repository)
+	}
+	return result, nil
+}
+
+// validate проверяет расписание студентов по загруженным занятиям: приводит кабинеты
+// к справочнику, сверяет преподавателей, применяет правила и допущенные исключения
+func (s ScheduleService) validate(students []domain.Student, lessons []domain.Lesson, diagnostics *domain.Diagnostics) ValidatingResult {
+	cabinets := s.normalizeCabinets(lessons, diagnostics)
+	checkTeacherNames(lessons, diagnostics)
+
+	schedule := domain.Schedule(lessons)
 	for _, student := range students {
-		deptSet[student.Department] = struct{}{}
+		if len(schedule.ForStudent(student)) == 0 {
+			diagnostics.Add(domain.IssueStudentUnmatched, student.Name, "не найдено занятий студента или его группы %s", student.Group)
+		}
+	}
+
+	valdator := domain.NewValidator(students, lessons)
+	if s.rulesRepo != nil {
+		customRules, err := s.rulesRepo.LoadCustomRules()
+		if err != nil {
+			diagnostics.Add(domain.IssueRuleInvalid, "rules.yaml", "%v", err)
+		}
+		valdator.SetCustomRules(customRules)
+		limits, err := s.rulesRepo.LoadLimits()
+		if err != nil {
+			diagnostics.Add(domain.IssueRuleInvalid, "rules.yaml", "%v", err)
+		}
+		valdator.SetLimits(limits)
+	}
+	violations := valdator.ValidateSchedule(s.week)
+	var exceptions []domain.Exception
+	if s.exceptionRepo != nil {
+		var err error
+		exceptions, err = s.exceptionRepo.LoadExceptions()
+		if err != nil {
+			diagnostics.Add(domain.IssueRuleInvalid, "exceptions.yaml", "%v", err)
+		}
+	}
+	// Сетка пар отчета и переносы занятий строятся по числу пар в расписании звонков проверки
+	pairs := domain.DefaultBellSchedule().PairCount()
+	if bells, err := s.bellSchedule(); err == nil && bells.PairCount() > 0 {
+		pairs = bells.PairCount()
+	}
+	violations, excepted := domain.ApplyExceptions(violations, exceptions)
+	for i := range violations {
+		s.events.Publish(domain.Event{Type: domain.EventViolationFound, Week: s.week, Violation: &violations[i]})
+	}
+
+	return ValidatingResult{
+		Violations: violations,
+		Lessons:    lessons,
+		Students:   students,
+		Issues:     diagnostics.Issues(),
+		Excepted:   excepted,
+		Rules:      valdator.DescribeRules(),
+		DryRun:     s.dryRun,
+		// накладки ищутся по приведённым кабинетам, иначе «24 (К3)» и «К3-24» не совпадут
+		CabinetConflicts: domain.FindCabinetConflicts(lessons, cabinets),
+		PairsPerDay:      pairs,
+	}
+}
+
+// bellSchedule загружает расписание звонков, заданное через WithBellRepository,
+// или возвращает расписание по умолчанию
+func (s ScheduleService) bellSchedule() (domain.BellSchedule, error) {
+	if s.bellRepo == nil {
+		return domain.DefaultBellSchedule(), nil
+	}
+	return s.bellRepo.LoadBellSchedule()
+}
+
+// RetryFailedGroups заново загружает с сайта только группы previous.FailedGroups, добавляет
+// их занятия к занятиям прежней проверки и проверяет неделю снова — без повторного разбора
+// XLS-файлов и загрузки остальных групп. Группы, которые снова не загрузились, остаются
+// в FailedGroups результата. О ходе проверки сообщается событиями, как в ProcessSchedule.
+func (s ScheduleService) RetryFailedGroups(previous ValidatingResult) (ValidatingResult, error) {
+	if len(previous.FailedGroups) == 0 || s.lessonsRepo != nil {
+		return previous, nil
+	}
+	s.events.Publish(domain.Event{Type: domain.EventCheckStarted, Week: s.week})
+
+	result, err := s.retryFailedGroups(previous)
+
+	s.events.Publish(domain.Event{
+		Type:   domain.EventCheckCompleted,
+		Week:   s.week,
+		Count:  len(result.Violations),
+		Err:    err,
+		Issues: result.Issues,
+	})
+	return result, err
+}
+
+// retryFailedGroups выполняет повторную загрузку групп и проверку для RetryFailedGroups
+func (s ScheduleService) retryFailedGroups(previous ValidatingResult) (ValidatingResult, error) {
+	diagnostics := domain.NewDiagnostics()
+
+	students, departments, _, err := s.loadScope()
+	if err != nil {
+		return ValidatingResult{}, err
+	}
+	repository, err := s.lessonsRepository(departments, previous.FailedGroups, diagnostics)
+	if err != nil {
+		return ValidatingResult{}, err
+	}
+	refresher, ok := repository.(interface {
+		RefreshGroups() ([]domain.Lesson, error)
+		FailedGroups() []string
+	})
+	if !ok {
+		return previous, nil
+	}
+	groupLessons, err := refresher.RefreshGroups()
+	if err != nil {
+		log.Printf("Повторная загрузка групп: %v", err)
+	}
+	log.Printf("Повторная загрузка групп %s: занятий %d", strings.Join(previous.FailedGroups, ", "), len(groupLessons))
+	s.publishSourcesParsed(groupLessons)
+
+	retried := make(map[string]bool)
+	for _, group := range previous.FailedGroups {
+		retried[group] = true
+	}
+	lessons := make([]domain.Lesson, 0, len(previous.Lessons)+len(groupLessons))
+	for _, lesson := range previous.Lessons {
+		if lesson.Source == domain.SourceGroup && retried[lesson.Group] {
+			continue
+		}
+		lessons = append(lessons, lesson)
 	}
+	lessons = append(lessons, groupLessons...)
 
-	departments := make([]string, 0, len(deptSet))
-	for dept := range deptSet {
-		departments = append(departments, dept)
+	result := s.validate(students, lessons, diagnostics)
+	result.FailedGroups = refresher.FailedGroups()
+	// Проблемы загрузки прежней проверки остаются, кроме тех, что проверены заново
+	var issues []domain.Issue
+	for _, issue := range previous.Issues {
+		switch issue.Category {
+		case domain.IssueSourceFailed, domain.IssueGroupUnknown:
+			if retried[issue.Source] {
+				continue
+			}
+		case domain.IssueStudentUnmatched, domain.IssueRuleInvalid, domain.IssueTeacherUnknown, domain.IssueCabinetUnknown:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	result.Issues = append(issues, result.Issues...)
+	// XLS-файлы не разбирались заново, поэтому полнота по преподавателям остаётся прежней
+	result.Completeness = domain.MeasureCompleteness(s.week, students, lessons, nil, result.Issues)
+	result.Completeness.TeachersFound = previous.Completeness.TeachersFound
+	result.Completeness.TeachersExpected = previous.Completeness.TeachersExpected
+	result.Completeness.MissingTeachers = previous.Completeness.MissingTeachers
+	return result, nil
+}
+
+// loadScope загружает студентов, отделения и группы студентов, расписание которых проверяется
+func (s ScheduleService) loadScope() ([]domain.Student, []domain.Department, []string, error) {
+	students, err := s.studentRepo.LoadStudents()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("не удалось загрузить список студентов: %w", err)
+	}
+	if len(students) == 0 {
+		return nil, nil, nil, fmt.Errorf("список студентов пуст")
+	}
+
+	departments, err := infrastructure.NewYAMLDepartmentRepository("departments.yaml").LoadDepartments()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("не удалось загрузить список отделений: %w", err)
+	}
+	if len(departments) == 0 {
+		departments = departmentsFromStudents(students)
 	}
 
 	// соберём уникальные группы
@@ -48,15 +403,266 @@ func (s ScheduleService) ProcessSchedule(weekStart string) (ValidatingResult, er
 	for group := range set {
 		groups = append(groups, group)
 	}
-	lessons_repository := infrastructure.NewLessonsRepository(departments, groups, weekStart)
-	lessons, _ := lessons_repository.GetLessons()
+	return students, departments, groups, nil
+}
 
-	valdator := domain.NewValidator(students, lessons)
-	violations := valdator.ValidateSchedule()
+// WarmUp заранее загружает с сайта расписание групп студентов за неделю сервиса и хранит его
+// в кэше ttl, чтобы проверка этой недели не ждала сайт. Если занятия берутся из другого источника
+// (WithLessonsRepository), прогревать нечего. Возвращает число загруженных занятий;
+// ошибки отдельных групп не мешают загрузке остальных.
+func (s ScheduleService) WarmUp(ttl time.Duration) (int, error) {
+	if s.lessonsRepo != nil {
+		return 0, nil
+	}
+	_, departments, groups, err := s.loadScope()
+	if err != nil {
+		return 0, err
+	}
+	repository := infrastructure.NewLessonsRepository(departments, groups, s.week, nil)
+	calendar, err := infrastructure.NewYAMLCalendarRepository("calendar.yaml").LoadCalendar()
+	if err != nil {
+		return 0, fmt.Errorf("не удалось загрузить учебный календарь: %w", err)
+	}
+	repository.SetWeekNumber(calendar.WeekNumber(s.week))
+	return repository.WarmGroupCache(ttl)
+}
 
-	return ValidatingResult{
-		Violations: violations,
-		Lessons:    lessons,
-	}, nil
+// lessonsRepository возвращает источник занятий за неделю сервиса: заданный через
+// WithLessonsRepository или XLS-файлы и сайт вуза
+func (s ScheduleService) lessonsRepository(departments []domain.Department, groups []string, diagnostics *domain.Diagnostics) (LessonsRepository, error) {
+	if s.lessonsRepo != nil {
+		return s.lessonsRepo(s.week), nil
+	}
+
+	repository := infrastructure.NewLessonsRepository(departments, groups, s.week, diagnostics)
+	repository.SetMergePolicy(s.mergePolicy)
+	calendar, err := infrastructure.NewYAMLCalendarRepository("calendar.yaml").LoadCalendar()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить учебный календарь: %w", err)
+	}
+	repository.SetWeekNumber(calendar.WeekNumber(s.week))
+	bells, err := s.bellSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить расписание звонков: %w", err)
+	}
+	repository.SetBellSchedule(bells)
+	if s.teacherRepo != nil {
+		teachers, err := s.teacherRepo.LoadRegistry()
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить справочник преподавателей: %w", err)
+		}
+		repository.SetTeacherRegistry(teachers)
+	}
+	repository.SetFiles(s.files)
+	repository.SetDryRun(s.dryRun)
+	if s.debugDir != "" {
+		dir := filepath.Join(s.debugDir, time.Now().Format("2006-01-02_150405")+"_"+s.week.String())
+		if debug, err := infrastructure.NewDebugDump(dir); err != nil {
+			log.Printf("Отладочные данные не будут записаны: %v", err)
+		} else {
+			log.Printf("Отладочные данные разбора: %s", debug.Dir())
+			repository.SetDebugDump(debug)
+		}
+	}
+	if s.archive != nil {
+		repository.SetSkipDir(s.archive.Dir())
+	}
+	return repository, nil
+}
+
+// archiveFiles переносит в архив XLS-файлы, полностью обработанные проверкой недели
+func (s ScheduleService) archiveFiles(repository LessonsRepository) {
+	if s.archive == nil {
+		return
+	}
+	covered, ok := repository.(interface{ Coverage() []domain.FileCoverage })
+	if !ok {
+		return
+	}
+	moved, err := s.archive.ArchiveWeek(s.week, covered.Coverage())
+	for _, path := range moved {
+		log.Printf("Файл перенесён в архив: %s", path)
+	}
+	if err != nil {
+		log.Printf("Ошибка архивирования файлов расписания: %v", err)
+	}
+}
+
+// suggestTeacherAliases записывает в справочник преподавателей кандидатов в псевдонимы:
+// написания имени, различающиеся только пробелами и знаками препинания, которые встретились
+// у одного занятия. После подтверждения они перестанут давать строки вида "А.А. & А. А.".
+func (s ScheduleService) suggestTeacherAliases(repository LessonsRepository) {
+	if s.teacherRepo == nil {
+		return
+	}
+	source, ok := repository.(interface{ AliasPairs() [][2]string })
+	if !ok || len(source.AliasPairs()) == 0 {
+		return
+	}
+	added, err := s.teacherRepo.SuggestAliases(source.AliasPairs())
+	if err != nil {
+		log.Printf("Ошибка сохранения кандидатов в псевдонимы преподавателей: %v", err)
+		return
+	}
+	if added > 0 {
+		log.Printf("Найдены разные написания имён преподавателей: %d, подтвердите их на странице «Преподаватели»", added)
+	}
+}
+
+// checkExpectedTeachers сверяет занятия недели со списком преподавателей, ведущих индивидуальные
+// занятия (teachers.yaml, individual: true): преподаватели без занятий на неделе отмечаются
+// в диагностике — скорее всего, они не прислали файл расписания. Возвращает ожидаемых
+// преподавателей и тех из них, у кого занятий нет.
+func (s ScheduleService) checkExpectedTeachers(lessons []domain.Lesson, diagnostics *domain.Diagnostics) ([]domain.Teacher, []domain.Teacher) {
+	if s.teacherRepo == nil {
+		return nil, nil
+	}
+	registry, err := s.teacherRepo.LoadRegistry()
+	if err != nil {
+		diagnostics.Add(domain.IssueRuleInvalid, "teachers.yaml", "%v", err)
+		return nil, nil
+	}
+	expected := registry.Expected()
+	absent := domain.AbsentTeachers(expected, lessons, s.week)
+	for _, teacher := range absent {
+		diagnostics.Add(domain.IssueTeacherAbsent, teacher.Name, "нет индивидуальных занятий преподавателя %s на неделе — возможно, не прислан файл расписания", teacher.Name)
+	}
+	return expected, absent
+}
+
+// normalizeCabinets приводит записи кабинетов всех занятий к идентификаторам из справочника
+// cabinets.yaml; записи, которых в справочнике нет, отмечаются в диагностике. Возвращает
+// кабинеты справочника для подбора свободных кабинетов при накладках.
+func (s ScheduleService) normalizeCabinets(lessons []domain.Lesson, diagnostics *domain.Diagnostics) []domain.Cabinet {
+	if s.cabinetRepo == nil {
+		return nil
+	}
+	normalizer, err := s.cabinetRepo.LoadNormalizer()
+	if err != nil {
+		diagnostics.Add(domain.IssueRuleInvalid, "cabinets.yaml", "%v", err)
+		return nil
+	}
+	for _, cabinet := range normalizer.Apply(lessons) {
+		hint := ""
+		if similar, ok := domain.ClosestName(cabinet, normalizer.Known()); ok {
+			hint = fmt.Sprintf(" Возможно, имелся в виду %s.", similar)
+		}
+		diagnostics.Add(domain.IssueCabinetUnknown, cabinet, "кабинета %s нет в справочнике cabinets.yaml — добавьте его или правило приведения.%s", cabinet, hint)
+	}
+	cabinets, err := s.cabinetRepo.LoadCabinets()
+	if err != nil {
+		diagnostics.Add(domain.IssueRuleInvalid, "cabinets.yaml", "%v", err)
+	}
+	return cabinets
+}
+
+// checkTeacherNames сверяет преподавателей индивидуальных занятий со справочником,
+// составленным по групповому расписанию с сайта (отдельного списка преподавателей
+// модуль AutoRasp не отдаёт). Имена, отличающиеся от сайта лишь написанием, заменяются
+// написанием с сайта; остальные отмечаются в диагностике как вероятные опечатки.
+func checkTeacherNames(lessons []domain.Lesson, diagnostics *domain.Diagnostics) {
+	var siteNames []string
+	for _, lesson := range lessons {
+		if lesson.Source != domain.SourceGroup {
+			continue
+		}
+		for _, teacher := range lesson.Teachers {
+			siteNames = append(siteNames, teacher.Name)
+		}
+	}
+	directory := domain.NewTeacherDirectory(siteNames)
+	if directory.IsEmpty() {
+		return
+	}
+
+	reported := make(map[string]bool)
+	for i := range lessons {
+		if lessons[i].Source != domain.SourceIndividual {
+			continue
+		}
+		teachers := append([]domain.Teacher(nil), lessons[i].Teachers...)
+		for j, teacher := range teachers {
+			if teacher.Name == "" || teacher.Name == "Unknown" {
+				continue
+			}
+			name, known := directory.Resolve(teacher.Name)
+			if known {
+				teachers[j].Name = name
+				continue
+			}
+			if reported[teacher.Name] {
+				continue
+			}
+			reported[teacher.Name] = true
+			if name != "" {
+				diagnostics.Add(domain.IssueTeacherUnknown, teacher.Name, "преподаватель %s не найден на сайте — опечатка? Возможно, имелся в виду %s", teacher.Name, name)
+			} else {
+				diagnostics.Add(domain.IssueTeacherUnknown, teacher.Name, "преподаватель %s не найден в расписании групп на сайте", teacher.Name)
+			}
+		}
+		lessons[i].Teachers = teachers
+	}
+}
+
+// publishSourcesParsed публикует по событию EventSourceParsed на каждый источник занятий
+func (s ScheduleService) publishSourcesParsed(lessons []domain.Lesson) {
+	counts := make(map[domain.LessonSource]int)
+	var sources []domain.LessonSource
+	for _, lesson := range lessons {
+		if _, seen := counts[lesson.Source]; !seen {
+			sources = append(sources, lesson.Source)
+		}
+		counts[lesson.Source]++
+	}
+	for _, source := range sources {
+		s.events.Publish(domain.Event{Type: domain.EventSourceParsed, Week: s.week, Source: source, Count: counts[source]})
+	}
+}
+
+// departmentsFromStudents собирает отделения из записей студентов. Используется,
+// пока отделения не настроены явно в departments.yaml.
+func departmentsFromStudents(students []domain.Student) []domain.Department {
+	seen := make(map[string]bool)
+	var departments []domain.Department
+	for _, student := range students {
+		if student.Department == "" || seen[student.Department] {
+			continue
+		}
+		seen[student.Department] = true
+		departments = append(departments, domain.Department{Name: student.Department, Source: domain.DepartmentSourceSite})
+	}
+	return departments
+}
+
+// ProcessMonth загружает индивидуальные занятия и строит табель часов преподавателей за месяц,
+// которому принадлежит month. Табель охватывает все недели, пересекающиеся с месяцем;
+// занятия за пределами месяца не учитываются. Групповое расписание для табеля не загружается.
+func (s ScheduleService) ProcessMonth(month time.Time) (domain.MonthlyTally, []domain.Issue, error) {
+	diagnostics := domain.NewDiagnostics()
+
+	if s.lessonsRepo != nil {
+		var lessons []domain.Lesson
+		for _, week := range domain.WeeksOfMonth(month) {
+			weekLessons, err := s.lessonsRepo(week).GetLessons()
+			if err != nil {
+				return domain.MonthlyTally{}, diagnostics.Issues(), fmt.Errorf("не удалось загрузить занятия недели %s: %w", week, err)
+			}
+			lessons = append(lessons, weekLessons...)
+		}
+		return domain.BuildMonthlyTally(lessons, month), diagnostics.Issues(), nil
+	}
+
+	bells, err := s.bellSchedule()
+	if err != nil {
+		return domain.MonthlyTally{}, nil, fmt.Errorf("не удалось загрузить расписание звонков: %w", err)
+	}
+	parser := infrastructure.NewIndividualScheduleParser(diagnostics)
+	parser.SetMergePolicy(s.mergePolicy)
+	parser.SetBellSchedule(bells)
+	lessons, err := parser.Parse()
+	if err != nil {
+		return domain.MonthlyTally{}, diagnostics.Issues(), fmt.Errorf("не удалось загрузить индивидуальное расписание: %w", err)
+	}
 
+	return domain.BuildMonthlyTally(lessons, month), diagnostics.Issues(), nil
 }
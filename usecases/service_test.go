@@ -0,0 +1,113 @@
+package usecases_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+	"github.com/Vaflel/lesson-counter/testsupport"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// spanningFile — источник занятий, который, как разбор XLS-файла, возвращает все занятия
+// файла независимо от проверяемой недели
+type spanningFile []domain.Lesson
+
+func (f spanningFile) GetLessons() ([]domain.Lesson, error) { return f, nil }
+
+func TestProcessScheduleSpanningFile(t *testing.T) {
+	const student = "Смирнова Анна"
+	students := infrastructure.NewYAMLStudentRepository(filepath.Join(t.TempDir(), "students.yaml"))
+	if err := students.SaveStudents([]domain.Student{{Name: student, Group: "МД-23-о", Year: 2}}); err != nil {
+		t.Fatalf("запись студентов: %v", err)
+	}
+
+	// Один файл преподавателя на две недели: по два занятия в каждой
+	file := spanningFile{
+		testsupport.NewLesson().On("2024-09-02").Pair(1).Individual(student).Teacher("Петров А.В.").Build(),
+		testsupport.NewLesson().On("2024-09-04").Pair(2).Individual(student).Teacher("Петров А.В.").Build(),
+		testsupport.NewLesson().On("2024-09-09").Pair(1).Individual(student).Teacher("Петров А.В.").Build(),
+		testsupport.NewLesson().On("2024-09-11").Pair(2).Individual(student).Teacher("Петров А.В.").Build(),
+	}
+	source := usecases.WithLessonsRepository(func(domain.Week) usecases.LessonsRepository { return file })
+
+	counted := make(map[string]int)
+	week := testsupport.MustWeek("2024-09-02")
+	for i := 0; i < 2; i++ {
+		result, err := usecases.NewScheduleService(week, students, source).ProcessSchedule()
+		if err != nil {
+			t.Fatalf("проверка недели %s: %v", week, err)
+		}
+		for _, lesson := range result.Lessons {
+			if !week.Contains(lesson.Time.Date) {
+				t.Errorf("неделя %s: занятие %s другой недели", week, lesson.Time.DateString())
+			}
+			counted[lesson.ID]++
+		}
+		week = week.Next()
+	}
+
+	if len(counted) != len(file) {
+		t.Errorf("учтено занятий %d, ожидалось %d", len(counted), len(file))
+	}
+	for _, lesson := range file {
+		if n := counted[lesson.ID]; n != 1 {
+			t.Errorf("занятие %s учтено %d раз, ожидался 1", lesson.Time.DateString(), n)
+		}
+	}
+}
+
+func TestProcessScheduleRepositories(t *testing.T) {
+	const student = "Смирнова Анна"
+	dir := t.TempDir()
+	students := infrastructure.NewYAMLStudentRepository(filepath.Join(dir, "students.yaml"))
+	if err := students.SaveStudents([]domain.Student{{Name: student, Group: "МД-23-о", Year: 2}}); err != nil {
+		t.Fatalf("запись студентов: %v", err)
+	}
+	rules := infrastructure.NewYAMLRulesRepository(filepath.Join(dir, "rules.yaml"))
+	limits := domain.DefaultLimits()
+	limits.MaxDailyHours = 2
+	if err := rules.SaveLimits(limits); err != nil {
+		t.Fatalf("запись порогов: %v", err)
+	}
+	bells := domain.DefaultBellSchedule()
+	bells.Default[8] = domain.PairTime{Start: "20:40", End: "22:10"}
+	bellRepo := infrastructure.NewYAMLBellRepository(filepath.Join(dir, "bells.yaml"))
+	if err := bellRepo.SaveBellSchedule(bells); err != nil {
+		t.Fatalf("запись звонков: %v", err)
+	}
+
+	day := spanningFile{
+		testsupport.NewLesson().On("2024-09-02").Pair(1).Individual(student).Teacher("Петров А.В.").Build(),
+		testsupport.NewLesson().On("2024-09-02").Pair(2).Individual(student).Teacher("Петров А.В.").Build(),
+	}
+	source := usecases.WithLessonsRepository(func(domain.Week) usecases.LessonsRepository { return day })
+	week := testsupport.MustWeek("2024-09-02")
+
+	result, err := usecases.NewScheduleService(week, students, source).ProcessSchedule()
+	if err != nil {
+		t.Fatalf("проверка без настроек: %v", err)
+	}
+	if len(result.Violations) != 0 {
+		t.Errorf("без хранилища правил нарушений %d, ожидалось 0 при порогах по умолчанию", len(result.Violations))
+	}
+	if want := domain.DefaultBellSchedule().PairCount(); result.PairsPerDay != want {
+		t.Errorf("без хранилища звонков пар в дне %d, ожидалось %d", result.PairsPerDay, want)
+	}
+
+	result, err = usecases.NewScheduleService(week, students, source,
+		usecases.WithRulesRepository(rules), usecases.WithBellRepository(bellRepo)).ProcessSchedule()
+	if err != nil {
+		t.Fatalf("проверка с настройками: %v", err)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Kind != domain.ViolationOverload {
+		t.Errorf("нарушения %v, ожидалось одно превышение нагрузки по порогу из хранилища правил", result.Violations)
+	}
+	if result.PairsPerDay != 8 {
+		t.Errorf("пар в дне %d, ожидалось 8 по хранилищу звонков", result.PairsPerDay)
+	}
+	if got := domain.PairsPerDay(); got != domain.DefaultBellSchedule().PairCount() {
+		t.Errorf("проверка изменила общее число пар в дне: %d", got)
+	}
+}
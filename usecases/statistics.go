@@ -0,0 +1,387 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// WeekStat содержит количество нарушений за неделю
+type WeekStat struct {
+	Week       domain.Week
+	Violations int
+}
+
+// CountStat — именованный счётчик (группа, день недели и т.п.)
+type CountStat struct {
+	Name  string
+	Count int
+}
+
+// Dashboard содержит агрегированную статистику по сохранённым проверкам
+type Dashboard struct {
+	ChecksCount     int
+	TotalViolations int
+	Weeks           []WeekStat         // нарушения по неделям в хронологическом порядке
+	Sparkline       string             // точки SVG-ломаной для графика нарушений по неделям
+	TopGroups       []CountStat        // группы с наибольшим числом нарушений
+	OverloadedDays  []CountStat        // дни недели с наибольшим числом превышений нагрузки
+	Remediation     []GroupRemediation // устранение нарушений по группам
+}
+
+// GroupRemediation показывает, как группа устраняет нарушения
+type GroupRemediation struct {
+	Group     string
+	Open      int // нарушения в последних проверках недель
+	Fixed     int // нарушения, исчезнувшие при повторной проверке той же недели
+	Recurring int // открытые нарушения, которые были у того же студента и на предыдущей неделе
+}
+
+// Размеры графика нарушений по неделям
+const (
+	SparklineWidth  = 300
+	SparklineHeight = 60
+	topGroupsLimit  = 5
+)
+
+// BuildDashboard вычисляет статистику по истории проверок
+func BuildDashboard(records []domain.CheckRecord) Dashboard {
+	dashboard := Dashboard{ChecksCount: len(records)}
+
+	groupCounts := make(map[string]int)
+	dayCounts := make(map[string]int)
+	for _, record := range records {
+		dashboard.Weeks = append(dashboard.Weeks, WeekStat{Week: record.Week, Violations: len(record.Violations)})
+		dashboard.TotalViolations += len(record.Violations)
+		for _, v := range record.Violations {
+			groupCounts[v.Group]++
+			if v.Kind == domain.ViolationOverload {
+				day := domain.LessonTime{Date: v.Date}.DayName()
+				dayCounts[day]++
+			}
+		}
+	}
+
+	sort.Slice(dashboard.Weeks, func(i, j int) bool {
+		return dashboard.Weeks[i].Week.Start().Before(dashboard.Weeks[j].Week.Start())
+	})
+
+	dashboard.Sparkline = sparklinePoints(dashboard.Weeks)
+	dashboard.TopGroups = topCounts(groupCounts, topGroupsLimit)
+	dashboard.OverloadedDays = topCounts(dayCounts, 0)
+	dashboard.Remediation = BuildRemediation(records)
+	return dashboard
+}
+
+// WeekTrend — показатели одной проверенной недели для графиков динамики за семестр
+type WeekTrend struct {
+	Week       domain.Week
+	Violations map[domain.ViolationKind]int // количество нарушений по видам
+	Total      int                          // всего нарушений
+	DailyLoad  float64                      // средняя дневная нагрузка студента, ак. ч; 0 — не сохранена
+	Complete   int                          // полнота данных проверки, %; 0 — не сохранена
+	Gaps       int                          // нарушений по окнам
+	GapHalves  int                          // сумма окон в этих нарушениях, в половинках пар
+}
+
+// BuildTrends возвращает показатели проверок по неделям в хронологическом порядке.
+// Учитываются недели от from до to включительно; нулевая неделя снимает ограничение.
+func BuildTrends(records []domain.CheckRecord, from, to domain.Week) []WeekTrend {
+	var trends []WeekTrend
+	for _, record := range records {
+		start := record.Week.Start()
+		if !from.IsZero() && start.Before(from.Start()) || !to.IsZero() && start.After(to.Start()) {
+			continue
+		}
+		trend := WeekTrend{
+			Week:       record.Week,
+			Violations: make(map[domain.ViolationKind]int),
+			Total:      len(record.Violations),
+			DailyLoad:  record.DailyLoad,
+			Complete:   record.Completeness,
+		}
+		for _, v := range record.Violations {
+			trend.Violations[v.Kind]++
+			if v.Kind == domain.ViolationGaps {
+				trend.Gaps++
+				trend.GapHalves += v.Hours
+			}
+		}
+		trends = append(trends, trend)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return trends[i].Week.Start().Before(trends[j].Week.Start())
+	})
+	return trends
+}
+
+// YearComparison — сравнение групп одного курса за период
+type YearComparison struct {
+	Year                 int
+	Groups               []GroupComparison // группы по убыванию нарушений на студента
+	DailyLoad            float64           // средняя дневная нагрузка студента курса, ак. ч
+	ViolationsPerStudent float64           // нарушений на студента курса за период
+}
+
+// GroupComparison — показатели группы за период
+type GroupComparison struct {
+	Group                string
+	Weeks                int     // проверенных недель с данными о нагрузке группы
+	Students             float64 // студентов в среднем за эти недели
+	DailyLoad            float64 // средняя дневная нагрузка студента, ак. ч
+	GapHalves            int     // окон за период, в половинках пар
+	GapsPerStudent       float64 // окон на студента за период, в половинках пар
+	Violations           int     // нарушений за период
+	ViolationsPerStudent float64
+	AboveLoad            bool // нагрузка выше средней по курсу
+	AboveViolations      bool // нарушений на студента больше, чем в среднем по курсу
+}
+
+// BuildYearComparison сравнивает группы внутри каждого курса по проверкам недель от from
+// до to включительно (нулевая неделя снимает ограничение). Учитываются только проверки,
+// в которых сохранена нагрузка по группам; возвращается и число пропущенных проверок
+// прежних версий, чтобы показать, что сравнение охватывает не весь период.
+func BuildYearComparison(records []domain.CheckRecord, from, to domain.Week) ([]YearComparison, int) {
+	type totals struct {
+		load       domain.GroupLoad
+		weeks      int
+		violations int
+	}
+	byGroup := make(map[string]*totals)
+	skipped := 0
+	for _, record := range records {
+		start := record.Week.Start()
+		if !from.IsZero() && start.Before(from.Start()) || !to.IsZero() && start.After(to.Start()) {
+			continue
+		}
+		if len(record.Groups) == 0 {
+			skipped++
+			continue
+		}
+		for _, g := range record.Groups {
+			t := byGroup[g.Group]
+			if t == nil {
+				t = &totals{load: domain.GroupLoad{Group: g.Group}}
+				byGroup[g.Group] = t
+			}
+			t.load.Year = g.Year
+			t.load.Students += g.Students
+			t.load.StudentDays += g.StudentDays
+			t.load.Hours += g.Hours
+			t.load.GapHalves += g.GapHalves
+			t.weeks++
+		}
+		for _, v := range record.Violations {
+			if t := byGroup[v.Group]; t != nil {
+				t.violations++
+			}
+		}
+	}
+
+	byYear := make(map[int]*YearComparison)
+	yearLoad := make(map[int]*domain.GroupLoad)
+	yearViolations := make(map[int]int)
+	yearStudents := make(map[int]float64)
+	for _, t := range byGroup {
+		students := float64(t.load.Students) / float64(t.weeks)
+		g := GroupComparison{
+			Group:      t.load.Group,
+			Weeks:      t.weeks,
+			Students:   students,
+			DailyLoad:  t.load.DailyLoad(),
+			GapHalves:  t.load.GapHalves,
+			Violations: t.violations,
+		}
+		if students > 0 {
+			g.GapsPerStudent = float64(t.load.GapHalves) / students
+			g.ViolationsPerStudent = float64(t.violations) / students
+		}
+
+		year := t.load.Year
+		if byYear[year] == nil {
+			byYear[year] = &YearComparison{Year: year}
+			yearLoad[year] = &domain.GroupLoad{}
+		}
+		byYear[year].Groups = append(byYear[year].Groups, g)
+		yearLoad[year].StudentDays += t.load.StudentDays
+		yearLoad[year].Hours += t.load.Hours
+		yearViolations[year] += t.violations
+		yearStudents[year] += students
+	}
+
+	result := make([]YearComparison, 0, len(byYear))
+	for year, comparison := range byYear {
+		comparison.DailyLoad = yearLoad[year].DailyLoad()
+		if yearStudents[year] > 0 {
+			comparison.ViolationsPerStudent = float64(yearViolations[year]) / yearStudents[year]
+		}
+		for i := range comparison.Groups {
+			g := &comparison.Groups[i]
+			g.AboveLoad = g.DailyLoad > comparison.DailyLoad
+			g.AboveViolations = g.ViolationsPerStudent > comparison.ViolationsPerStudent
+		}
+		sort.Slice(comparison.Groups, func(i, j int) bool {
+			a, b := comparison.Groups[i], comparison.Groups[j]
+			if a.ViolationsPerStudent != b.ViolationsPerStudent {
+				return a.ViolationsPerStudent > b.ViolationsPerStudent
+			}
+			return a.Group < b.Group
+		})
+		result = append(result, *comparison)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Year < result[j].Year
+	})
+	return result, skipped
+}
+
+// BuildRemediation считает по группам открытые, устранённые и повторяющиеся нарушения.
+// Повторяющимся считается открытое нарушение, если у того же студента нарушение того же
+// вида (и правила) найдено и в проверке предыдущей недели.
+func BuildRemediation(records []domain.CheckRecord) []GroupRemediation {
+	type recurrenceKey struct {
+		student string
+		kind    domain.ViolationKind
+		rule    string
+	}
+	byWeek := make(map[string]map[recurrenceKey]bool)
+	for _, record := range records {
+		keys := make(map[recurrenceKey]bool)
+		for _, v := range record.Violations {
+			keys[recurrenceKey{v.StudentName, v.Kind, v.Rule}] = true
+		}
+		byWeek[record.Week.String()] = keys
+	}
+
+	stats := make(map[string]*GroupRemediation)
+	group := func(name string) *GroupRemediation {
+		if stats[name] == nil {
+			stats[name] = &GroupRemediation{Group: name}
+		}
+		return stats[name]
+	}
+
+	for _, record := range records {
+		previous := byWeek[record.Week.Prev().String()]
+		for _, v := range record.Violations {
+			g := group(v.Group)
+			g.Open++
+			if previous[recurrenceKey{v.StudentName, v.Kind, v.Rule}] {
+				g.Recurring++
+			}
+		}
+		for _, v := range record.Resolved {
+			group(v.Group).Fixed++
+		}
+	}
+
+	result := make([]GroupRemediation, 0, len(stats))
+	for _, g := range stats {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Group < result[j].Group
+	})
+	return result
+}
+
+// sparklinePoints возвращает координаты точек ломаной для атрибута points элемента <polyline>
+func sparklinePoints(weeks []WeekStat) string {
+	if len(weeks) == 0 {
+		return ""
+	}
+
+	maxCount := 0
+	for _, w := range weeks {
+		if w.Violations > maxCount {
+			maxCount = w.Violations
+		}
+	}
+
+	points := make([]string, len(weeks))
+	for i, w := range weeks {
+		x := 0.0
+		if len(weeks) > 1 {
+			x = float64(i) * SparklineWidth / float64(len(weeks)-1)
+		}
+		y := float64(SparklineHeight)
+		if maxCount > 0 {
+			y = SparklineHeight - float64(w.Violations)*SparklineHeight/float64(maxCount)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(points, " ")
+}
+
+// topCounts сортирует счётчики по убыванию и возвращает не более limit первых (0 — все)
+func topCounts(counts map[string]int, limit int) []CountStat {
+	result := make([]CountStat, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, CountStat{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// StudentViolationEntry — нарушение студента с неделей, в которую оно найдено
+type StudentViolationEntry struct {
+	Week      domain.Week
+	Violation domain.Violation
+}
+
+// StudentViolations содержит все сохранённые нарушения студента
+type StudentViolations struct {
+	StudentName string
+	Entries     []StudentViolationEntry // нарушения в хронологическом порядке
+	Totals      []CountStat             // количество нарушений по видам
+	TotalHours  int                     // сумма часов по всем нарушениям
+}
+
+// BuildStudentViolations собирает нарушения студента по всем сохранённым проверкам
+func BuildStudentViolations(records []domain.CheckRecord, studentName string) StudentViolations {
+	result := StudentViolations{StudentName: studentName}
+	totals := make(map[string]int)
+
+	for _, record := range records {
+		for _, v := range record.Violations {
+			if v.StudentName != studentName {
+				continue
+			}
+			result.Entries = append(result.Entries, StudentViolationEntry{Week: record.Week, Violation: v})
+			totals[v.Title()]++
+			result.TotalHours += v.Hours
+		}
+	}
+
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		return result.Entries[i].Violation.Date.Before(result.Entries[j].Violation.Date)
+	})
+	result.Totals = topCounts(totals, 0)
+	return result
+}
+
+// FindViolation ищет нарушение по идентификатору в сохранённых проверках.
+// Если нарушение встречается в нескольких проверках, возвращается последнее.
+func FindViolation(records []domain.CheckRecord, id string) (StudentViolationEntry, bool) {
+	var found StudentViolationEntry
+	ok := false
+	for _, record := range records {
+		for _, v := range record.Violations {
+			if v.ID() == id {
+				found = StudentViolationEntry{Week: record.Week, Violation: v}
+				ok = true
+			}
+		}
+	}
+	return found, ok
+}
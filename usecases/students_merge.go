@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// StudentMerge — итог объединения повторяющихся записей студента
+type StudentMerge struct {
+	Kept       domain.Student // оставленная запись, дополненная данными удалённой
+	Removed    string         // имя удалённой записи
+	History    int            // перенесённые записи истории проверок
+	Exceptions int            // перенесённые исключения
+}
+
+// MergeStudents оставляет запись keep и удаляет запись duplicate того же студента.
+// История проверок и исключения duplicate переносятся на keep; history и exceptions
+// могут быть nil. Студент удаляется последним, чтобы при ошибке объединение можно было повторить.
+func MergeStudents(students StudentRepository, history HistoryRepository, exceptions ExceptionRepository, keep, duplicate string) (StudentMerge, error) {
+	if keep == duplicate {
+		return StudentMerge{}, fmt.Errorf("нельзя объединить студента %s с самим собой", keep)
+	}
+	kept, err := students.GetStudent(keep)
+	if err != nil {
+		return StudentMerge{}, err
+	}
+	removed, err := students.GetStudent(duplicate)
+	if err != nil {
+		return StudentMerge{}, err
+	}
+
+	result := StudentMerge{Kept: domain.MergeStudent(kept, removed), Removed: duplicate}
+	if history != nil {
+		if result.History, err = history.RenameStudent(duplicate, keep); err != nil {
+			return result, fmt.Errorf("ошибка переноса истории проверок: %w", err)
+		}
+	}
+	if exceptions != nil {
+		if result.Exceptions, err = exceptions.RenameStudent(duplicate, keep); err != nil {
+			return result, fmt.Errorf("ошибка переноса исключений: %w", err)
+		}
+	}
+	if err := students.UpdateStudent(keep, result.Kept); err != nil {
+		return result, err
+	}
+	if err := students.DeleteStudent(duplicate); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"sort"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// TeacherPortal — то, что программа увидела в расписании одного преподавателя: его занятия
+// последней проверки и нарушения студентов в дни, когда у них есть его занятия
+type TeacherPortal struct {
+	Teacher    string
+	Lessons    []domain.Lesson          // занятия преподавателя по времени
+	Violations []TeacherPortalViolation // нарушения с участием его занятий по датам
+}
+
+// TeacherPortalViolation — нарушение студента и занятия преподавателя у этого студента в день нарушения
+type TeacherPortalViolation struct {
+	Violation domain.Violation
+	Lessons   []domain.Lesson
+}
+
+// BuildTeacherPortal отбирает из занятий и нарушений проверки касающиеся преподавателя: занятия,
+// которые он ведёт под основным именем или псевдонимом, и нарушения студентов в те дни,
+// когда у студента (или его группы) есть занятие этого преподавателя
+func BuildTeacherPortal(teacher domain.Teacher, lessons []domain.Lesson, violations []domain.Violation) TeacherPortal {
+	portal := TeacherPortal{Teacher: teacher.Name}
+	for _, lesson := range lessons {
+		for _, t := range lesson.Teachers {
+			if teacher.Matches(t.Name) {
+				portal.Lessons = append(portal.Lessons, lesson)
+				break
+			}
+		}
+	}
+	sort.SliceStable(portal.Lessons, func(i, j int) bool {
+		a, b := portal.Lessons[i].Time, portal.Lessons[j].Time
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.Number != b.Number {
+			return a.Number < b.Number
+		}
+		return a.PairHalf < b.PairHalf
+	})
+
+	own := domain.Schedule(portal.Lessons)
+	for _, v := range violations {
+		date := v.Date.In(domain.Location()).Format("2006-01-02")
+		var involved []domain.Lesson
+		for _, lesson := range own.ForStudent(domain.Student{Name: v.StudentName, Group: v.Group}) {
+			if lesson.Time.Date.In(domain.Location()).Format("2006-01-02") == date {
+				involved = append(involved, lesson)
+			}
+		}
+		if len(involved) > 0 {
+			portal.Violations = append(portal.Violations, TeacherPortalViolation{Violation: v, Lessons: involved})
+		}
+	}
+	sort.SliceStable(portal.Violations, func(i, j int) bool {
+		return portal.Violations[i].Violation.Date.Before(portal.Violations[j].Violation.Date)
+	})
+	return portal
+}
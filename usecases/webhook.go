@@ -0,0 +1,201 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// WebhookPoster выполняет одну попытку доставки уведомления и возвращает код ответа получателя
+type WebhookPoster interface {
+	URL() string
+	Post(delivery domain.WebhookDelivery) (int, error)
+}
+
+// EventCheckCompleted — событие уведомления о завершённой проверке недели
+const EventCheckCompleted = "check.completed"
+
+// WebhookService доставляет уведомления о результатах проверок на внешний адрес: повторяет
+// неудачные попытки с растущей паузой и записывает каждую доставку в журнал
+type WebhookService struct {
+	poster   WebhookPoster
+	journal  WebhookLog
+	attempts int           // попыток на одну доставку
+	backoff  time.Duration // пауза перед второй попыткой, дальше удваивается
+	sleep    func(time.Duration)
+
+	mu       sync.Mutex      // журнал читается и дописывается из разных доставок
+	inFlight map[string]bool // доставки, попытки которых ещё идут
+}
+
+// NewWebhookService создаёт сервис доставки уведомлений. attempts меньше 1 считается одной попыткой
+func NewWebhookService(poster WebhookPoster, journal WebhookLog, attempts int, backoff time.Duration) *WebhookService {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &WebhookService{
+		poster:   poster,
+		journal:  journal,
+		attempts: attempts,
+		backoff:  backoff,
+		sleep:    time.Sleep,
+		inFlight: make(map[string]bool),
+	}
+}
+
+// WebhookCheckPayload — тело уведомления о завершённой проверке недели
+type WebhookCheckPayload struct {
+	Event        string             `json:"event"`
+	Week         string             `json:"week"`      // понедельник недели, 2006-01-02
+	CheckedAt    string             `json:"checkedAt"` // RFC 3339
+	Completeness int                `json:"completeness"`
+	Violations   []WebhookViolation `json:"violations"`
+}
+
+// WebhookViolation — нарушение в уведомлении; поля совпадают с нарушением JSON API
+type WebhookViolation struct {
+	ID          string `json:"id"`
+	StudentName string `json:"studentName"`
+	Group       string `json:"group"`
+	Year        int    `json:"year"`
+	Date        string `json:"date"` // 2006-01-02
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Hours       int    `json:"hours"`
+	Rule        string `json:"rule,omitempty"`
+}
+
+// CheckPayload собирает тело уведомления о проверке недели week
+func CheckPayload(week domain.Week, result ValidatingResult) WebhookCheckPayload {
+	payload := WebhookCheckPayload{
+		Event:        EventCheckCompleted,
+		Week:         week.Start().Format("2006-01-02"),
+		CheckedAt:    time.Now().In(domain.Location()).Format(time.RFC3339),
+		Completeness: result.Completeness.Score(),
+		Violations:   []WebhookViolation{},
+	}
+	for _, v := range result.Violations {
+		payload.Violations = append(payload.Violations, WebhookViolation{
+			ID:          v.ID(),
+			StudentName: v.StudentName,
+			Group:       v.Group,
+			Year:        v.Year,
+			Date:        v.Date.In(domain.Location()).Format("2006-01-02"),
+			Kind:        string(v.Kind),
+			Title:       v.Title(),
+			Hours:       v.Hours,
+			Rule:        v.Rule,
+		})
+	}
+	return payload
+}
+
+// Publish отправляет уведомление о событии event с телом payload и возвращает итог доставки.
+// Вызов блокируется на время всех попыток, поэтому после проверки его запускают в фоне.
+func (w *WebhookService) Publish(event string, payload any) (domain.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return domain.WebhookDelivery{}, fmt.Errorf("не удалось сформировать уведомление: %w", err)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return domain.WebhookDelivery{}, fmt.Errorf("не удалось создать идентификатор уведомления: %w", err)
+	}
+	delivery := domain.WebhookDelivery{
+		ID:        hex.EncodeToString(id),
+		Event:     event,
+		URL:       w.poster.URL(),
+		Payload:   string(body),
+		Status:    domain.WebhookPending,
+		CreatedAt: time.Now(),
+	}
+	return w.deliver(delivery)
+}
+
+// Redeliver повторно отправляет уведомление из журнала с тем же идентификатором и телом,
+// например после того, как получатель устранил свою ошибку. Счётчик попыток продолжается.
+func (w *WebhookService) Redeliver(id string) (domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	delivery, err := w.journal.GetDelivery(id)
+	w.mu.Unlock()
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	delivery.Status = domain.WebhookPending
+	delivery.Error = ""
+	delivery.URL = w.poster.URL()
+	return w.deliver(delivery)
+}
+
+// Deliveries возвращает журнал доставки, новые первыми
+func (w *WebhookService) Deliveries() ([]domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.journal.LoadDeliveries()
+}
+
+// deliver выполняет попытки доставки. Повторяются только попытки, после которых получатель
+// может принять уведомление: без ответа, с кодом 5xx или 429. Остальные коды 4xx означают,
+// что получатель отверг уведомление, и повтор ничего не изменит.
+func (w *WebhookService) deliver(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	if w.inFlight[delivery.ID] {
+		w.mu.Unlock()
+		return delivery, fmt.Errorf("уведомление %s ещё доставляется", delivery.ID)
+	}
+	w.inFlight[delivery.ID] = true
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		delete(w.inFlight, delivery.ID)
+		w.mu.Unlock()
+	}()
+
+	w.save(delivery)
+	pause := w.backoff
+	for attempt := 1; attempt <= w.attempts; attempt++ {
+		if attempt > 1 {
+			w.sleep(pause)
+			pause *= 2
+		}
+		code, err := w.poster.Post(delivery)
+		delivery.Attempts++
+		delivery.ResponseCode = code
+		delivery.FinishedAt = time.Now()
+		switch {
+		case err != nil:
+			delivery.Error = err.Error()
+		case code >= 200 && code < 300:
+			delivery.Status, delivery.Error = domain.WebhookDelivered, ""
+			w.save(delivery)
+			return delivery, nil
+		default:
+			delivery.Error = fmt.Sprintf("получатель ответил %d %s", code, http.StatusText(code))
+		}
+
+		retryable := err != nil || code >= 500 || code == http.StatusTooManyRequests
+		if !retryable || attempt == w.attempts {
+			break
+		}
+		w.save(delivery)
+	}
+	delivery.Status = domain.WebhookFailed
+	w.save(delivery)
+	return delivery, fmt.Errorf("уведомление %s не доставлено после %d попыток: %s", delivery.ID, delivery.Attempts, delivery.Error)
+}
+
+// save записывает состояние доставки в журнал; ошибка журнала не прерывает доставку
+func (w *WebhookService) save(delivery domain.WebhookDelivery) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.journal.SaveDelivery(delivery); err != nil {
+		log.Printf("Ошибка записи журнала уведомлений: %v", err)
+	}
+}
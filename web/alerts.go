@@ -0,0 +1,137 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// WithAlertCenter включает страницу «Оповещения»: центр подписывается на события проверок,
+// заданий очереди и проверки обновлений и сохраняет важные из них до прочтения
+func WithAlertCenter(center *usecases.AlertCenter) Option {
+	return func(s *Server) {
+		s.alerts = center
+		s.events.Subscribe(center)
+	}
+}
+
+// handleAlerts показывает оповещения, новые первыми
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	alerts, err := s.alerts.Alerts()
+	if err != nil {
+		log.Printf("Ошибка загрузки оповещений: %v", err)
+		http.Error(w, "Ошибка загрузки оповещений: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	unread := 0
+	for _, alert := range alerts {
+		if !alert.Read {
+			unread++
+		}
+	}
+
+	tmpl, err := s.parseTemplate("alerts.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	data := struct {
+		Alerts []domain.Alert
+		Unread int
+	}{alerts, unread}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleReadAlert отмечает прочитанным одно оповещение
+func (s *Server) handleReadAlert(w http.ResponseWriter, r *http.Request) {
+	s.markAlertsRead(w, r, func() error {
+		return s.alerts.MarkRead(strings.TrimPrefix(r.URL.Path, "/alerts/read/"))
+	})
+}
+
+// handleReadAllAlerts отмечает прочитанными все оповещения
+func (s *Server) handleReadAllAlerts(w http.ResponseWriter, r *http.Request) {
+	s.markAlertsRead(w, r, func() error {
+		return s.alerts.MarkAllRead()
+	})
+}
+
+// markAlertsRead выполняет отметку о прочтении и возвращает на страницу оповещений
+func (s *Server) markAlertsRead(w http.ResponseWriter, r *http.Request, mark func() error) {
+	if s.alerts == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := mark(); err != nil {
+		log.Printf("Ошибка отметки оповещений: %v", err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Redirect(w, r, "/alerts", http.StatusSeeOther)
+}
+
+// apiAlert — оповещение в JSON API
+type apiAlert struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Level     string    `json:"level"` // info, warning или error
+	Title     string    `json:"title"`
+	Text      string    `json:"text,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	Read      bool      `json:"read"`
+}
+
+// apiAlerts — оповещения и количество непрочитанных
+type apiAlerts struct {
+	Unread int        `json:"unread"`
+	Alerts []apiAlert `json:"alerts"`
+}
+
+func (s *Server) apiListAlerts(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeAPIError(w, http.StatusNotFound, "Оповещения выключены")
+		return
+	}
+	alerts, err := s.alerts.Alerts()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response := apiAlerts{Alerts: []apiAlert{}}
+	for _, alert := range alerts {
+		if !alert.Read {
+			response.Unread++
+		}
+		response.Alerts = append(response.Alerts, apiAlert{
+			ID:        alert.ID,
+			CreatedAt: alert.CreatedAt,
+			Level:     string(alert.Level),
+			Title:     alert.Title,
+			Text:      alert.Text,
+			Link:      alert.Link,
+			Read:      alert.Read,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, response)
+}
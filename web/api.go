@@ -0,0 +1,689 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// apiEndpoint описывает метод JSON API: обработчик и сведения для документа OpenAPI
+type apiEndpoint struct {
+	Method   string
+	Path     string
+	Summary  string
+	Query    []apiParam
+	Params   []apiParam // параметры пути, например {id} в Path
+	Request  any        // значение типа тела запроса, nil — без тела
+	Response any        // значение типа ответа
+	Status   int        // код успешного ответа
+	// Типы содержимого запроса и ответа, если они не application/json
+	RequestType  string
+	ResponseType string
+	handler      func(s *Server, w http.ResponseWriter, r *http.Request)
+}
+
+// apiParam описывает параметр строки запроса
+type apiParam struct {
+	Name        string
+	Description string
+}
+
+// apiError — тело ответа с ошибкой
+type apiError struct {
+	Error  string                       `json:"error"`
+	Errors []infrastructure.ImportError `json:"errors,omitempty"` // ошибки по файлам и записям загружаемых данных
+}
+
+// apiViolation — нарушение в JSON API
+type apiViolation struct {
+	ID          string `json:"id"`
+	StudentName string `json:"studentName"`
+	Group       string `json:"group"`
+	Year        int    `json:"year"`
+	Date        string `json:"date"` // 2006-01-02
+	Kind        string `json:"kind"` // overload, gaps, custom, clash, weekly_overload, consecutive, overlap
+	Title       string `json:"title"`
+	Hours       int    `json:"hours"`
+	Rule        string `json:"rule,omitempty"`
+}
+
+// apiLesson — занятие в JSON API
+type apiLesson struct {
+	ID         string   `json:"id"`
+	Date       string   `json:"date"` // 2006-01-02
+	Number     int      `json:"number"`
+	PairHalf   int      `json:"pairHalf"` // 0 — вся пара, 1 или 2 — половина
+	Hours      int      `json:"hours"`
+	StartTime  string   `json:"startTime,omitempty"` // 15:04
+	EndTime    string   `json:"endTime,omitempty"`
+	Discipline string   `json:"discipline"`
+	Teachers   []string `json:"teachers"`
+	Cabinet    string   `json:"cabinet,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Student    string   `json:"student,omitempty"`
+	Subgroup   string   `json:"subgroup,omitempty"`
+	Source     string   `json:"source"` // individual, group, imported
+}
+
+// apiStudent — студент в JSON API
+type apiStudent struct {
+	Name         string `json:"name"`
+	Group        string `json:"group"`
+	Department   string `json:"department,omitempty"`
+	Year         int    `json:"year"`
+	Email        string `json:"email,omitempty"`
+	Telegram     string `json:"telegram,omitempty"`
+	CuratorEmail string `json:"curatorEmail,omitempty"`
+}
+
+// apiJob — состояние проверки в JSON API
+type apiJob struct {
+	IsProcessing bool             `json:"isProcessing"`
+	ReportReady  bool             `json:"reportReady"`
+	Error        string           `json:"error,omitempty"`
+	Progress     string           `json:"progress,omitempty"`
+	Issues       []domain.Issue   `json:"issues,omitempty"`
+	FailedGroups []string         `json:"failedGroups,omitempty"` // группы, не загрузившиеся с сайта; см. POST /api/jobs/retry-failed
+	DryRun       bool             `json:"dryRun,omitempty"`       // пробная проверка: не сохранена в историю
+	Completeness *apiCompleteness `json:"completeness,omitempty"` // полнота данных готового отчета
+}
+
+// apiCompleteness — полнота данных проверки в JSON API: счётчики и итоговый процент
+type apiCompleteness struct {
+	Score            int      `json:"score"` // полнота данных, %
+	TeachersFound    int      `json:"teachersFound"`
+	TeachersExpected int      `json:"teachersExpected"`
+	GroupsLoaded     int      `json:"groupsLoaded"`
+	GroupsExpected   int      `json:"groupsExpected"`
+	StudentsMatched  int      `json:"studentsMatched"`
+	StudentsTracked  int      `json:"studentsTracked"`
+	MissingTeachers  []string `json:"missingTeachers,omitempty"` // преподаватели без занятий за неделю
+}
+
+// newAPICompleteness возвращает полноту данных проверки для JSON API
+func newAPICompleteness(c domain.Completeness) *apiCompleteness {
+	return &apiCompleteness{
+		Score:            c.Score(),
+		TeachersFound:    c.TeachersFound,
+		TeachersExpected: c.TeachersExpected,
+		GroupsLoaded:     c.GroupsLoaded,
+		GroupsExpected:   c.GroupsExpected,
+		StudentsMatched:  c.StudentsMatched,
+		StudentsTracked:  c.StudentsTracked,
+		MissingTeachers:  c.MissingTeachers,
+	}
+}
+
+// apiUploadCheckRequest — поля формы загрузки файлов с проверкой
+type apiUploadCheckRequest struct {
+	WeekStart  string   `json:"week_start"`            // неделя в любом поддерживаемом формате
+	Files      []string `json:"files" format:"binary"` // XLS-файлы или ZIP-архивы
+	FullReport bool     `json:"full_report,omitempty"`
+}
+
+// apiCheckProgress — строка хода проверки. Последняя строка имеет event "result" и содержит
+// состояние завершённой проверки
+type apiCheckProgress struct {
+	Event    string  `json:"event"` // check_started, source_parsed, violation_found, check_completed, result
+	Progress string  `json:"progress,omitempty"`
+	Count    int     `json:"count,omitempty"`
+	Job      *apiJob `json:"job,omitempty"`
+}
+
+// apiPreflight — результат предварительной проверки перед запуском проверки недели
+type apiPreflight struct {
+	Week   string              `json:"week"`  // 2006-01-02
+	Ready  bool                `json:"ready"` // все пункты пройдены
+	Checks []apiPreflightCheck `json:"checks"`
+}
+
+// apiPreflightCheck — пункт предварительной проверки
+type apiPreflightCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// apiJobBatchRequest — недели, которые ставятся в очередь одним пакетом
+type apiJobBatchRequest struct {
+	Weeks      []string `json:"weeks"` // недели в любом поддерживаемом формате
+	FullReport bool     `json:"fullReport"`
+}
+
+// apiJobBatch — пакет заданий в очереди
+type apiJobBatch struct {
+	Batch int64         `json:"batch"` // идентификатор пакета, 0 для одной недели; сводка — /jobs/batch/{batch}
+	Jobs  []apiQueueJob `json:"jobs"`
+}
+
+// apiQueueJob — задание очереди в JSON API
+type apiQueueJob struct {
+	ID     int64  `json:"id"`
+	Week   string `json:"week"`   // 2006-01-02
+	Status string `json:"status"` // queued, running, done, failed, cancelled
+}
+
+// apiPairTime — время пары в JSON API
+type apiPairTime struct {
+	Start string `json:"start"` // 15:04
+	End   string `json:"end"`
+}
+
+// apiBells — расписание звонков в JSON API. Ключи — номера пар, дни недели — monday, tuesday, ...
+type apiBells struct {
+	Default map[string]apiPairTime            `json:"default"`
+	Days    map[string]map[string]apiPairTime `json:"days,omitempty"`
+}
+
+// apiEndpoints — методы JSON API. По этому списку регистрируются обработчики и строится
+// документ OpenAPI, поэтому новые методы добавляются только сюда.
+var apiEndpoints = []apiEndpoint{
+	{
+		Method: http.MethodGet, Path: "/api/violations", Summary: "Нарушения последней проверки",
+		Query:    []apiParam{{"group", "Отбор по группе"}, {"student", "Отбор по студенту"}},
+		Response: []apiViolation{}, Status: http.StatusOK,
+		handler: (*Server).apiListViolations,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/lessons", Summary: "Занятия, загруженные при последней проверке",
+		Query:    []apiParam{{"group", "Отбор по группе"}, {"student", "Расписание студента: его индивидуальные занятия и пары его группы"}},
+		Response: []apiLesson{}, Status: http.StatusOK,
+		handler: (*Server).apiListLessons,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/students", Summary: "Список студентов",
+		Response: []apiStudent{}, Status: http.StatusOK,
+		handler: (*Server).apiListStudents,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/jobs", Summary: "Запуск проверки недели",
+		Request: CheckRequest{}, Response: apiJob{}, Status: http.StatusAccepted,
+		handler: (*Server).apiStartJob,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/jobs/retry-failed",
+		Summary: "Повторная загрузка только групп, не загрузившихся при последней проверке (failedGroups); " +
+			"их занятия добавляются к занятиям проверки, и отчет пересчитывается",
+		Response: apiJob{}, Status: http.StatusAccepted,
+		handler: (*Server).apiRetryFailed,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/jobs/batch",
+		Summary: "Постановка в очередь проверок нескольких недель; они выполняются по очереди, " +
+			"сводка результатов — на странице /jobs/batch/{batch}",
+		Request: apiJobBatchRequest{}, Response: apiJobBatch{}, Status: http.StatusAccepted,
+		handler: (*Server).apiEnqueueBatch,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/uploads/check",
+		Summary: "Загрузка XLS-файлов или ZIP-архива и проверка недели только по ним. Ход проверки передаётся " +
+			"построчно (JSON Lines) по мере выполнения, последняя строка содержит результат",
+		Request: apiUploadCheckRequest{}, RequestType: "multipart/form-data",
+		Response: apiCheckProgress{}, ResponseType: "application/x-ndjson", Status: http.StatusOK,
+		handler: (*Server).apiUploadAndCheck,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/preflight", Summary: "Готовность к проверке недели: сайт, XLS-файлы, студенты и настройки",
+		Query:    []apiParam{{"week", "Неделя в любом поддерживаемом формате; по умолчанию текущая"}},
+		Response: apiPreflight{}, Status: http.StatusOK,
+		handler: (*Server).apiPreflight,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/jobs/current", Summary: "Состояние последней проверки",
+		Response: apiJob{}, Status: http.StatusOK,
+		handler: (*Server).apiCurrentJob,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/settings/bells", Summary: "Расписание звонков",
+		Response: apiBells{}, Status: http.StatusOK,
+		handler: (*Server).apiGetBells,
+	},
+	{
+		Method: http.MethodPut, Path: "/api/settings/bells", Summary: "Изменение расписания звонков",
+		Request: apiBells{}, Response: apiBells{}, Status: http.StatusOK,
+		handler: (*Server).apiPutBells,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/update", Summary: "Новая версия программы",
+		Response: apiUpdate{}, Status: http.StatusOK,
+		handler: (*Server).apiGetUpdate,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/update/install", Summary: "Установка новой версии (Windows); она запустится после перезапуска",
+		Response: apiUpdate{}, Status: http.StatusOK,
+		handler: (*Server).apiInstallUpdate,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/alerts", Summary: "Оповещения о неудачных загрузках, устаревших файлах, выполненных заданиях и новых версиях, новые первыми",
+		Response: apiAlerts{}, Status: http.StatusOK,
+		handler: (*Server).apiListAlerts,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/departments", Summary: "Отделения на сайте расписания (кэшируются на site.cache_ttl)",
+		Response: []apiSiteDepartment{}, Status: http.StatusOK,
+		handler: (*Server).apiListSiteDepartments,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/departments/{id}/groups", Summary: "Группы отделения на сайте расписания",
+		Params:   []apiParam{{Name: "id", Description: "идентификатор отделения на сайте"}},
+		Response: []string{}, Status: http.StatusOK,
+		handler: (*Server).apiListSiteGroups,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/search",
+		Summary:  "Поиск студентов, преподавателей и дисциплин без учёта регистра и диакритических знаков (ё и е совпадают)",
+		Query:    []apiParam{{"q", "Строка поиска"}, {"limit", "Наибольшее количество результатов, по умолчанию 20"}},
+		Response: []apiSearchResult{}, Status: http.StatusOK,
+		handler: (*Server).apiSearch,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/stats/trends",
+		Summary: "Показатели проверенных недель для графиков: нарушения по видам, средняя дневная нагрузка и окна",
+		Query: []apiParam{
+			{"from", "Первая неделя (любая дата недели или «неделя N»), по умолчанию — с начала истории"},
+			{"to", "Последняя неделя, по умолчанию — до конца истории"},
+		},
+		Response: []apiWeekTrend{}, Status: http.StatusOK,
+		handler: (*Server).apiTrends,
+	},
+}
+
+// apiRoutes регистрирует методы JSON API, документ OpenAPI и страницу документации
+func (s *Server) apiRoutes() {
+	byPath := make(map[string][]apiEndpoint)
+	var paths []string
+	for _, endpoint := range apiEndpoints {
+		if _, exists := byPath[endpoint.Path]; !exists {
+			paths = append(paths, endpoint.Path)
+		}
+		byPath[endpoint.Path] = append(byPath[endpoint.Path], endpoint)
+	}
+
+	for _, path := range paths {
+		endpoints := byPath[path]
+		s.mux.HandleFunc(path, withRecover(func(w http.ResponseWriter, r *http.Request) {
+			for _, endpoint := range endpoints {
+				if endpoint.Method == r.Method {
+					endpoint.handler(s, w, r)
+					return
+				}
+			}
+			writeAPIError(w, http.StatusMethodNotAllowed, "Метод не разрешен")
+		}))
+	}
+
+	s.mux.HandleFunc("/api/openapi.json", withRecover(s.handleOpenAPI))
+	s.mux.HandleFunc("/api/docs", withRecover(s.handleAPIDocs))
+}
+
+// writeAPIJSON отправляет ответ JSON API
+func writeAPIJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Ошибка отправки ответа API: %v", err)
+	}
+}
+
+// writeAPIError отправляет ошибку JSON API
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, apiError{Error: message})
+}
+
+// writeImportError отправляет ошибку загружаемых данных; ошибки по файлам и записям
+// перечисляются в поле errors
+func writeImportError(w http.ResponseWriter, err error) {
+	response := apiError{Error: err.Error()}
+	if errs, ok := infrastructure.AsImportErrors(err); ok {
+		response.Error = fmt.Sprintf("Данные не загружены, ошибок: %d", len(errs))
+		response.Errors = errs
+	}
+	writeAPIJSON(w, http.StatusBadRequest, response)
+}
+
+func (s *Server) apiListViolations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ready := s.reportReady
+	violations := append([]domain.Violation(nil), s.violations...)
+	s.mu.Unlock()
+	if !ready {
+		writeAPIError(w, http.StatusConflict, "Сначала выполните проверку расписания")
+		return
+	}
+
+	group, student := r.URL.Query().Get("group"), r.URL.Query().Get("student")
+	result := []apiViolation{}
+	for _, v := range violations {
+		if (group != "" && v.Group != group) || (student != "" && v.StudentName != student) {
+			continue
+		}
+		result = append(result, apiViolation{
+			ID:          v.ID(),
+			StudentName: v.StudentName,
+			Group:       v.Group,
+			Year:        v.Year,
+			Date:        v.Date.In(domain.Location()).Format("2006-01-02"),
+			Kind:        string(v.Kind),
+			Title:       v.Title(),
+			Hours:       v.Hours,
+			Rule:        v.Rule,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) apiListLessons(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ready := s.reportReady
+	lessons := domain.Schedule(append([]domain.Lesson(nil), s.lessons...))
+	students := s.students
+	s.mu.Unlock()
+	if !ready {
+		writeAPIError(w, http.StatusConflict, "Сначала выполните проверку расписания")
+		return
+	}
+
+	if name := r.URL.Query().Get("student"); name != "" {
+		student := domain.Student{Name: name}
+		for _, st := range students {
+			if st.Name == name {
+				student = st
+				break
+			}
+		}
+		lessons = lessons.ForStudent(student)
+	}
+
+	group := r.URL.Query().Get("group")
+	result := []apiLesson{}
+	for _, lesson := range lessons {
+		if group != "" && lesson.Group != group {
+			continue
+		}
+		item := apiLesson{
+			ID:         lesson.ID,
+			Date:       lesson.Time.DateString(),
+			Number:     lesson.Time.Number,
+			PairHalf:   lesson.Time.PairHalf,
+			Hours:      lesson.Time.Hours,
+			Discipline: lesson.Discipline,
+			Teachers:   []string{},
+			Cabinet:    lesson.Cabinet,
+			Group:      lesson.Group,
+			Student:    lesson.Student,
+			Subgroup:   lesson.Subgroup,
+			Source:     string(lesson.Source),
+		}
+		if lessonTimeRange(lesson.Time) != "" {
+			item.StartTime = lesson.Time.StartTimeString()
+			item.EndTime = lesson.Time.EndTimeString()
+		}
+		for _, teacher := range lesson.Teachers {
+			item.Teachers = append(item.Teachers, teacher.Name)
+		}
+		result = append(result, item)
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) apiListStudents(w http.ResponseWriter, r *http.Request) {
+	students, err := s.studentRepo.LoadStudents()
+	if err != nil {
+		log.Printf("Ошибка загрузки студентов: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "Не удалось загрузить список студентов")
+		return
+	}
+
+	result := make([]apiStudent, 0, len(students))
+	for _, st := range students {
+		result = append(result, apiStudent{
+			Name:         st.Name,
+			Group:        st.Group,
+			Department:   st.Department,
+			Year:         st.Year,
+			Email:        st.Email,
+			Telegram:     st.Telegram,
+			CuratorEmail: st.CuratorEmail,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) apiStartJob(w http.ResponseWriter, r *http.Request) {
+	var req CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+	week, err := s.loadCalendar().ParseWeek(req.WeekStart)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !s.limitCheck(w, r, week, req.FullReport && !req.Month, req.DryRun) {
+		writeAPIError(w, http.StatusTooManyRequests, "Слишком много проверок подряд, попробуйте позже")
+		return
+	}
+	start := func() error { return s.startCheck(week, req.FullReport, req.DryRun) }
+	if req.Month {
+		start = func() error { return s.startMonthCheck(week, req.DryRun) }
+	}
+	if err := start(); err != nil {
+		if isRepeatedCheck(err) {
+			writeAPIJSON(w, http.StatusOK, s.currentJob())
+			return
+		}
+		if errors.Is(err, errCheckInProgress) {
+			writeAPIError(w, http.StatusConflict, "Обработка уже выполняется")
+			return
+		}
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, s.currentJob())
+}
+
+func (s *Server) apiEnqueueBatch(w http.ResponseWriter, r *http.Request) {
+	if s.jobRepo == nil {
+		writeAPIError(w, http.StatusNotFound, "Очередь заданий не настроена")
+		return
+	}
+	var req apiJobBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+	if len(req.Weeks) == 0 || len(req.Weeks) > 52 {
+		writeAPIError(w, http.StatusBadRequest, "Количество недель должно быть от 1 до 52")
+		return
+	}
+
+	calendar := s.loadCalendar()
+	seen := make(map[domain.Week]bool)
+	var jobs []domain.Job
+	for _, value := range req.Weeks {
+		week, err := calendar.ParseWeek(value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !seen[week] {
+			seen[week] = true
+			jobs = append(jobs, domain.Job{Week: week, FullReport: req.FullReport})
+		}
+	}
+
+	queued, err := s.jobRepo.EnqueueJobs(jobs)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	result := apiJobBatch{Batch: queued[0].Batch, Jobs: []apiQueueJob{}}
+	for _, job := range queued {
+		result.Jobs = append(result.Jobs, apiQueueJob{ID: job.ID, Week: job.Week.String(), Status: string(job.Status)})
+	}
+	writeAPIJSON(w, http.StatusAccepted, result)
+}
+
+func (s *Server) apiPreflight(w http.ResponseWriter, r *http.Request) {
+	week := domain.WeekOf(time.Now())
+	if value := r.URL.Query().Get("week"); value != "" {
+		var err error
+		week, err = s.loadCalendar().ParseWeek(value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	result := apiPreflight{Week: week.String(), Ready: true, Checks: []apiPreflightCheck{}}
+	for _, check := range usecases.NewScheduleService(week, s.studentRepo, s.serviceOpts...).Preflight() {
+		result.Ready = result.Ready && check.OK
+		result.Checks = append(result.Checks, apiPreflightCheck{Name: check.Name, OK: check.OK, Message: check.Message})
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) apiCurrentJob(w http.ResponseWriter, r *http.Request) {
+	writeAPIJSON(w, http.StatusOK, s.currentJob())
+}
+
+// currentJob возвращает состояние последней проверки
+func (s *Server) currentJob() apiJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := apiJob{
+		IsProcessing: s.isProcessing,
+		ReportReady:  s.reportReady,
+		Error:        s.lastError,
+		Progress:     s.progress,
+		Issues:       s.issues,
+		FailedGroups: s.failedGroups,
+		DryRun:       s.dryRun,
+	}
+	if s.reportReady {
+		job.Completeness = newAPICompleteness(s.completeness)
+	}
+	return job
+}
+
+func (s *Server) apiGetBells(w http.ResponseWriter, r *http.Request) {
+	if s.bellRepo == nil {
+		writeAPIError(w, http.StatusNotFound, "Расписание звонков не подключено")
+		return
+	}
+	bells, err := s.bellRepo.LoadBellSchedule()
+	if err != nil {
+		log.Printf("Ошибка загрузки расписания звонков: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, bellsToAPI(bells))
+}
+
+func (s *Server) apiPutBells(w http.ResponseWriter, r *http.Request) {
+	if s.bellRepo == nil {
+		writeAPIError(w, http.StatusNotFound, "Расписание звонков не подключено")
+		return
+	}
+	var req apiBells
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Неверный формат запроса")
+		return
+	}
+	bells, err := bellsFromAPI(req)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.bellRepo.SaveBellSchedule(bells); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	domain.SetPairsPerDay(bells.PairCount())
+	writeAPIJSON(w, http.StatusOK, bellsToAPI(bells))
+}
+
+// bellsToAPI переводит расписание звонков в представление JSON API
+func bellsToAPI(bells domain.BellSchedule) apiBells {
+	convert := func(pairs map[int]domain.PairTime) map[string]apiPairTime {
+		result := make(map[string]apiPairTime, len(pairs))
+		for number, pair := range pairs {
+			result[strconv.Itoa(number)] = apiPairTime{Start: pair.Start, End: pair.End}
+		}
+		return result
+	}
+
+	result := apiBells{Default: convert(bells.Default)}
+	for _, variant := range bellVariants {
+		if pairs := bells.Days[variant.Weekday]; variant.Key != "" && len(pairs) > 0 {
+			if result.Days == nil {
+				result.Days = make(map[string]map[string]apiPairTime)
+			}
+			result.Days[variant.Key] = convert(pairs)
+		}
+	}
+	return result
+}
+
+// bellsFromAPI разбирает расписание звонков из представления JSON API
+func bellsFromAPI(value apiBells) (domain.BellSchedule, error) {
+	convert := func(pairs map[string]apiPairTime) (map[int]domain.PairTime, error) {
+		result := make(map[int]domain.PairTime, len(pairs))
+		for key, pair := range pairs {
+			number, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, errors.New("номер пары должен быть числом: " + key)
+			}
+			result[number] = domain.PairTime{Start: pair.Start, End: pair.End}
+		}
+		return result, nil
+	}
+
+	def, err := convert(value.Default)
+	if err != nil {
+		return domain.BellSchedule{}, err
+	}
+	bells := domain.BellSchedule{Default: def}
+
+	keys := make([]string, 0, len(value.Days))
+	for key := range value.Days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		variant, ok := bellVariantByKey(key)
+		if !ok {
+			return domain.BellSchedule{}, errors.New("неизвестный день недели: " + key)
+		}
+		pairs, err := convert(value.Days[key])
+		if err != nil {
+			return domain.BellSchedule{}, err
+		}
+		if bells.Days == nil {
+			bells.Days = make(map[time.Weekday]map[int]domain.PairTime)
+		}
+		bells.Days[variant.Weekday] = pairs
+	}
+	return bells, nil
+}
+
+// bellVariantByKey ищет вариант расписания звонков для дня недели по ключу (monday, ...)
+func bellVariantByKey(key string) (bellVariant, bool) {
+	for _, variant := range bellVariants {
+		if variant.Key != "" && variant.Key == key {
+			return variant, true
+		}
+	}
+	return bellVariant{}, false
+}
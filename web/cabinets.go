@@ -0,0 +1,84 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// CabinetData — кабинет на странице календарей кабинетов
+type CabinetData struct {
+	Name    string
+	Lessons int    // занятий за проверенный период
+	Feed    string // адрес календаря .ics
+}
+
+// handleCabinets показывает кабинеты последней проверки со ссылками на календари,
+// а по адресу /cabinets/<кабинет>.ics отдаёт сам календарь
+func (s *Server) handleCabinets(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/cabinets"), "/")
+
+	s.mu.Lock()
+	ready := s.reportReady
+	lessons := domain.Schedule(append([]domain.Lesson(nil), s.lessons...))
+	s.mu.Unlock()
+
+	if name != "" {
+		s.serveCabinetICS(w, ready, strings.TrimSuffix(name, ".ics"), lessons)
+		return
+	}
+
+	data := struct {
+		Ready    bool
+		Cabinets []CabinetData
+	}{Ready: ready}
+	for _, cabinet := range lessons.Cabinets() {
+		data.Cabinets = append(data.Cabinets, CabinetData{
+			Name:    cabinet,
+			Lessons: len(lessons.ForCabinet(cabinet)),
+			Feed:    "/cabinets/" + url.PathEscape(cabinet) + ".ics",
+		})
+	}
+
+	tmpl, err := s.parseTemplate("cabinets.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// serveCabinetICS отдаёт занятия кабинета из последней проверки календарём iCalendar
+func (s *Server) serveCabinetICS(w http.ResponseWriter, ready bool, cabinet string, lessons domain.Schedule) {
+	if !ready {
+		http.Error(w, "Сначала выполните проверку расписания", http.StatusConflict)
+		return
+	}
+	cabinetLessons := lessons.ForCabinet(cabinet)
+	if len(cabinetLessons) == 0 {
+		http.Error(w, "Кабинет не найден в загруженном расписании", http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := infrastructure.ExportCabinetICS(&buf, cabinet, cabinetLessons, time.Now()); err != nil {
+		log.Printf("Ошибка формирования календаря: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "cabinet.ics"))
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// apiSiteDepartment — отделение на сайте расписания в JSON API
+type apiSiteDepartment struct {
+	ID     string `json:"id"` // идентификатор отделения на сайте
+	Name   string `json:"name"`
+	Groups int    `json:"groups"` // количество групп
+}
+
+// loadSiteDepartments возвращает отделения сайта или отправляет ошибку API
+func (s *Server) loadSiteDepartments(w http.ResponseWriter) ([]infrastructure.SiteDepartment, bool) {
+	if s.siteDepartments == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "Список отделений сайта не используется")
+		return nil, false
+	}
+	departments, err := s.siteDepartments()
+	if err != nil {
+		log.Printf("Ошибка загрузки отделений сайта: %v", err)
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return nil, false
+	}
+	return departments, true
+}
+
+func (s *Server) apiListSiteDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, ok := s.loadSiteDepartments(w)
+	if !ok {
+		return
+	}
+	result := []apiSiteDepartment{}
+	for _, department := range departments {
+		result = append(result, apiSiteDepartment{ID: department.ID, Name: department.Name, Groups: len(department.Groups)})
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) apiListSiteGroups(w http.ResponseWriter, r *http.Request) {
+	departments, ok := s.loadSiteDepartments(w)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	for _, department := range departments {
+		if department.ID == id {
+			writeAPIJSON(w, http.StatusOK, append([]string{}, department.Groups...))
+			return
+		}
+	}
+	writeAPIError(w, http.StatusNotFound, "Отделение "+id+" не найдено на сайте")
+}
@@ -0,0 +1,144 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// DigestGroupData — группа в сводке нарушений по группам
+type DigestGroupData struct {
+	Group    string
+	Total    int
+	Students []DigestStudentData
+}
+
+// DigestStudentData — студент группы с нарушениями за неделю
+type DigestStudentData struct {
+	Student    string
+	Year       int
+	Violations []DigestViolationData
+}
+
+// DigestViolationData — нарушение в сводке по группам
+type DigestViolationData struct {
+	ID    string // идентификатор для ссылки на обсуждение
+	Day   string // день недели и дата, например "вторник, 11.02"
+	Type  string
+	Hours int
+}
+
+// prepareDigestData подготавливает сводку по группам для шаблона
+func prepareDigestData(digest []domain.GroupDigest) []DigestGroupData {
+	var groups []DigestGroupData
+	for _, g := range digest {
+		group := DigestGroupData{Group: g.Group, Total: g.Total}
+		for _, st := range g.Students {
+			student := DigestStudentData{Student: st.Student, Year: st.Year}
+			for _, v := range st.Violations {
+				t := domain.LessonTime{Date: v.Date}
+				student.Violations = append(student.Violations, DigestViolationData{
+					ID:    v.ID(),
+					Day:   t.DayName() + ", " + v.Date.In(domain.Location()).Format("02.01"),
+					Type:  v.Title(),
+					Hours: v.Hours,
+				})
+			}
+			group.Students = append(group.Students, student)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// lastDigest возвращает сводку нарушений последней проверки по группам, первую и последнюю
+// проверенные недели (разные для отчета за месяц). Если указана group, в сводке остаётся только эта группа.
+func (s *Server) lastDigest(group string) (first, last domain.Week, digest []domain.GroupDigest, ready bool) {
+	s.mu.Lock()
+	ready = s.reportReady
+	violations := append([]domain.Violation(nil), s.violations...)
+	first, last = s.checkedWeek, s.checkedWeek
+	if n := len(s.month); n > 0 {
+		last = s.month[n-1].Week
+	}
+	s.mu.Unlock()
+	if !ready {
+		return first, last, nil, false
+	}
+
+	digest = domain.BuildGroupDigest(violations)
+	if group != "" {
+		var filtered []domain.GroupDigest
+		for _, g := range digest {
+			if g.Group == group {
+				filtered = append(filtered, g)
+			}
+		}
+		digest = filtered
+	}
+	return first, last, digest, true
+}
+
+// handleDigest показывает нарушения последней проверки по группам — для собраний групп
+func (s *Server) handleDigest(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("digest.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	first, last, digest, ready := s.lastDigest(group)
+	data := struct {
+		Ready         bool
+		Group         string
+		WeekDateStart string
+		WeekDateEnd   string
+		Groups        []DigestGroupData
+	}{Ready: ready, Group: group, Groups: prepareDigestData(digest)}
+	if ready {
+		data.WeekDateStart = first.Start().Format("2006-01-02")
+		data.WeekDateEnd = last.End().Format("2006-01-02")
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleDigestExport отдаёт сводку по группам файлом XLSX, по листу на группу, на языке
+// из параметра lang
+func (s *Server) handleDigestExport(w http.ResponseWriter, r *http.Request) {
+	lang, err := domain.ParseLang(r.URL.Query().Get("lang"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	group := r.URL.Query().Get("group")
+	first, last, digest, ready := s.lastDigest(group)
+	if !ready {
+		http.Error(w, "Сначала выполните проверку расписания", http.StatusConflict)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := infrastructure.ExportDigestXLSX(&buf, first, last, digest, lang); err != nil {
+		log.Printf("Ошибка формирования XLSX: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("groups-%s.xlsx", first)
+	if lang != domain.LangRU {
+		filename = fmt.Sprintf("groups-%s-%s.xlsx", first, lang)
+	}
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
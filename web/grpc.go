@@ -0,0 +1,181 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	lessoncounterv1 "github.com/Vaflel/lesson-counter/api/lessoncounter/v1"
+	"github.com/Vaflel/lesson-counter/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcService реализует gRPC API поверх состояния веб-сервера: проверки, запущенные через
+// API и через веб-интерфейс, общие
+type grpcService struct {
+	lessoncounterv1.UnimplementedLessonCounterServer
+	s *Server
+}
+
+// StartGRPC запускает gRPC API на указанном порту. Блокирует выполнение до остановки сервера.
+func (s *Server) StartGRPC(port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	lessoncounterv1.RegisterLessonCounterServer(server, &grpcService{s: s})
+
+	s.mu.Lock()
+	s.grpcServer = server
+	s.mu.Unlock()
+
+	log.Printf("gRPC API запущен на порту %d", port)
+	return server.Serve(listener)
+}
+
+// StartCheck запускает проверку недели
+func (g *grpcService) StartCheck(ctx context.Context, req *lessoncounterv1.StartCheckRequest) (*lessoncounterv1.StartCheckResponse, error) {
+	if g.s.readOnly {
+		return nil, status.Error(codes.PermissionDenied, readOnlyMessage)
+	}
+	week, err := g.s.loadCalendar().ParseWeek(req.GetWeekStart())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// Лимит общий с веб-интерфейсом и JSON API: клиент gRPC может запускать проверки в цикле
+	if wait, ok := g.s.allowCheck(grpcClientAddr(ctx), week, req.GetFullReport(), false); !ok {
+		return nil, status.Errorf(codes.ResourceExhausted, "слишком много проверок подряд, повторите через %d с", int(wait.Seconds())+1)
+	}
+
+	if err := g.s.startCheck(week, req.GetFullReport(), false); err != nil && !isRepeatedCheck(err) {
+		if errors.Is(err, errCheckInProgress) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &lessoncounterv1.StartCheckResponse{Week: week.String()}, nil
+}
+
+// grpcClientAddr возвращает адрес клиента gRPC без порта
+func grpcClientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// GetStatus возвращает состояние последней проверки
+func (g *grpcService) GetStatus(ctx context.Context, req *lessoncounterv1.GetStatusRequest) (*lessoncounterv1.GetStatusResponse, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	response := &lessoncounterv1.GetStatusResponse{
+		IsProcessing: g.s.isProcessing,
+		ReportReady:  g.s.reportReady,
+		Error:        g.s.lastError,
+		Progress:     g.s.progress,
+	}
+	for _, issue := range g.s.issues {
+		response.Issues = append(response.Issues, &lessoncounterv1.Issue{
+			Category: string(issue.Category),
+			Source:   issue.Source,
+			Message:  issue.Message,
+		})
+	}
+	return response, nil
+}
+
+// ListViolations возвращает нарушения последней проверки с отбором по группе и студенту
+func (g *grpcService) ListViolations(ctx context.Context, req *lessoncounterv1.ListViolationsRequest) (*lessoncounterv1.ListViolationsResponse, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	if !g.s.reportReady {
+		return nil, status.Error(codes.FailedPrecondition, "сначала выполните проверку расписания")
+	}
+
+	response := &lessoncounterv1.ListViolationsResponse{}
+	for _, v := range g.s.violations {
+		if req.GetGroup() != "" && v.Group != req.GetGroup() {
+			continue
+		}
+		if req.GetStudent() != "" && v.StudentName != req.GetStudent() {
+			continue
+		}
+		response.Violations = append(response.Violations, &lessoncounterv1.Violation{
+			Id:          v.ID(),
+			StudentName: v.StudentName,
+			Group:       v.Group,
+			Year:        int32(v.Year),
+			Date:        v.Date.In(domain.Location()).Format("2006-01-02"),
+			Kind:        string(v.Kind),
+			Title:       v.Title(),
+			Hours:       int32(v.Hours),
+			Rule:        v.Rule,
+		})
+	}
+	return response, nil
+}
+
+// ListLessons возвращает занятия последней проверки с отбором по группе и студенту
+func (g *grpcService) ListLessons(ctx context.Context, req *lessoncounterv1.ListLessonsRequest) (*lessoncounterv1.ListLessonsResponse, error) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	if !g.s.reportReady {
+		return nil, status.Error(codes.FailedPrecondition, "сначала выполните проверку расписания")
+	}
+
+	lessons := domain.Schedule(g.s.lessons)
+	if req.GetStudent() != "" {
+		student := domain.Student{Name: req.GetStudent()}
+		for _, st := range g.s.students {
+			if st.Name == req.GetStudent() {
+				student = st
+				break
+			}
+		}
+		lessons = lessons.ForStudent(student)
+	}
+
+	response := &lessoncounterv1.ListLessonsResponse{}
+	for _, lesson := range lessons {
+		if req.GetGroup() != "" && lesson.Group != req.GetGroup() {
+			continue
+		}
+		item := &lessoncounterv1.Lesson{
+			Id:         lesson.ID,
+			Date:       lesson.Time.DateString(),
+			Number:     int32(lesson.Time.Number),
+			PairHalf:   int32(lesson.Time.PairHalf),
+			Hours:      int32(lesson.Time.Hours),
+			Discipline: lesson.Discipline,
+			Cabinet:    lesson.Cabinet,
+			Group:      lesson.Group,
+			Student:    lesson.Student,
+			Subgroup:   lesson.Subgroup,
+			Source:     string(lesson.Source),
+		}
+		if lessonTimeRange(lesson.Time) != "" {
+			item.StartTime = lesson.Time.StartTimeString()
+			item.EndTime = lesson.Time.EndTimeString()
+		}
+		for _, teacher := range lesson.Teachers {
+			item.Teachers = append(item.Teachers, teacher.Name)
+		}
+		response.Lessons = append(response.Lessons, item)
+	}
+	return response, nil
+}
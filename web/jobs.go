@@ -0,0 +1,292 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// jobPollInterval — как часто обработчик очереди проверяет наступившие задания
+const jobPollInterval = 5 * time.Second
+
+// RunJobQueue выполняет задания из очереди по одному, когда наступает их время и сервер
+// свободен от других проверок. Блокирует выполнение до отмены ctx.
+func (s *Server) RunJobQueue(ctx context.Context) {
+	if s.jobRepo == nil {
+		return
+	}
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		for s.runNextJob(time.Now()) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNextJob выполняет одно наступившее задание. Возвращает true, если задание выполнено
+// и стоит сразу проверить следующее.
+func (s *Server) runNextJob(now time.Time) bool {
+	job, ok, err := s.jobRepo.NextDueJob(now)
+	if err != nil {
+		log.Printf("Ошибка чтения очереди заданий: %v", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := s.beginCheck(job.Week, job.FullReport, false); err != nil {
+		// проверка, запущенная из веб-интерфейса, ещё выполняется — задание подождёт
+		return false
+	}
+
+	job.Status = domain.JobRunning
+	job.Attempts++
+	job.Error = ""
+	job.FailedGroups = nil
+	job.StartedAt = time.Now()
+	job.FinishedAt = time.Time{}
+	if err := s.jobRepo.UpdateJob(job); err != nil {
+		log.Printf("Ошибка сохранения задания %d: %v", job.ID, err)
+	}
+
+	log.Printf("Задание %d: проверка недели %s", job.ID, job.Week)
+	checkErr := s.runCheck(job.Week)
+
+	job.Status = domain.JobDone
+	if checkErr != nil {
+		job.Status = domain.JobFailed
+		job.Error = checkErr.Error()
+	} else {
+		s.mu.Lock()
+		job.FailedGroups = append([]string(nil), s.failedGroups...)
+		s.mu.Unlock()
+	}
+	job.FinishedAt = time.Now()
+	if err := s.jobRepo.UpdateJob(job); err != nil {
+		log.Printf("Ошибка сохранения задания %d: %v", job.ID, err)
+	}
+	s.events.Publish(domain.Event{Type: domain.EventJobFinished, Week: job.Week, Job: &job})
+	return true
+}
+
+// handleJobs показывает очередь заданий и ставит в неё проверку одной или нескольких недель
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if s.jobRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+		week, err := s.loadCalendar().ParseWeek(r.FormValue("week_start"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		count := 1
+		if value := strings.TrimSpace(r.FormValue("weeks")); value != "" {
+			count, err = strconv.Atoi(value)
+			if err != nil || count < 1 || count > 52 {
+				http.Error(w, "Количество недель должно быть от 1 до 52", http.StatusBadRequest)
+				return
+			}
+		}
+		var scheduledAt time.Time
+		if value := r.FormValue("scheduled_at"); value != "" {
+			scheduledAt, err = time.ParseInLocation("2006-01-02T15:04", value, domain.Location())
+			if err != nil {
+				http.Error(w, "Неверный формат времени запуска", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Несколько недель ставятся в очередь одним пакетом со сводной страницей результатов
+		jobs := make([]domain.Job, count)
+		for i := range jobs {
+			jobs[i] = domain.Job{Week: week, FullReport: r.FormValue("full_report") != "", ScheduledAt: scheduledAt}
+			week = week.Next()
+		}
+		queued, err := s.jobRepo.EnqueueJobs(jobs)
+		if err != nil {
+			log.Printf("Ошибка добавления задания: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		if batch := queued[0].Batch; batch != 0 {
+			http.Redirect(w, r, fmt.Sprintf("/jobs/batch/%d", batch), http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, "/jobs", http.StatusSeeOther)
+		return
+	} else if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs, err := s.jobRepo.LoadJobs()
+	if err != nil {
+		log.Printf("Ошибка загрузки заданий: %v", err)
+		http.Error(w, "Ошибка загрузки заданий: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("jobs.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Jobs      []domain.Job
+		WeekStart string
+		Webhooks  bool // настроены уведомления о проверках
+		Retention bool // заданы сроки хранения данных
+	}{
+		Jobs:      jobs,
+		WeekStart: domain.WeekOf(time.Now()).String(),
+		Webhooks:  s.webhooks != nil,
+		Retention: s.retention != nil,
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// batchWeek — неделя пакета заданий на сводной странице
+type batchWeek struct {
+	Job    domain.Job
+	Record *domain.CheckRecord // сохранённая проверка недели, если задание выполнено
+}
+
+// handleJobBatch показывает сводку пакета заданий: состояние проверки каждой недели
+// и найденные нарушения по сохранённой истории проверок
+func (s *Server) handleJobBatch(w http.ResponseWriter, r *http.Request) {
+	if s.jobRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	batch, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/jobs/batch/"), 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный идентификатор пакета", http.StatusBadRequest)
+		return
+	}
+	jobs, err := s.jobRepo.LoadBatch(batch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	records := make(map[domain.Week]domain.CheckRecord)
+	if s.historyRepo != nil {
+		checks, err := s.historyRepo.LoadChecks()
+		if err != nil {
+			log.Printf("Ошибка загрузки истории проверок: %v", err)
+		}
+		for _, record := range checks {
+			records[record.Week] = record
+		}
+	}
+
+	data := struct {
+		Batch      int64
+		Weeks      []batchWeek
+		Done       int
+		Violations int
+	}{Batch: batch}
+	for _, job := range jobs {
+		item := batchWeek{Job: job}
+		if record, ok := records[job.Week]; ok && job.Status == domain.JobDone {
+			item.Record = &record
+			data.Violations += len(record.Violations)
+		}
+		if job.Status == domain.JobDone || job.Status == domain.JobFailed {
+			data.Done++
+		}
+		data.Weeks = append(data.Weeks, item)
+	}
+
+	tmpl, err := s.parseTemplate("job_batch.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleRetryJob повторно ставит в очередь задание, завершившееся ошибкой или отменённое
+func (s *Server) handleRetryJob(w http.ResponseWriter, r *http.Request) {
+	s.changeJob(w, r, "/jobs/retry/", func(job *domain.Job) error {
+		if !job.CanRetry() {
+			return errors.New("повторить можно только задание с ошибкой или отменённое")
+		}
+		job.Status = domain.JobQueued
+		job.ScheduledAt = time.Now()
+		return nil
+	})
+}
+
+// handleCancelJob отменяет задание, ещё не начавшее выполняться
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	s.changeJob(w, r, "/jobs/cancel/", func(job *domain.Job) error {
+		if !job.CanCancel() {
+			return errors.New("отменить можно только задание в очереди")
+		}
+		job.Status = domain.JobCancelled
+		job.FinishedAt = time.Now()
+		return nil
+	})
+}
+
+// changeJob загружает задание по идентификатору из пути, изменяет его и сохраняет
+func (s *Server) changeJob(w http.ResponseWriter, r *http.Request, prefix string, change func(job *domain.Job) error) {
+	if s.jobRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, prefix), 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный идентификатор задания", http.StatusBadRequest)
+		return
+	}
+	job, err := s.jobRepo.GetJob(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := change(&job); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err := s.jobRepo.UpdateJob(job); err != nil {
+		log.Printf("Ошибка сохранения задания %d: %v", job.ID, err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/jobs", http.StatusSeeOther)
+}
@@ -0,0 +1,156 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLogLines — сколько последних строк журнала проверки хранится для веб-интерфейса
+const maxLogLines = 2000
+
+// logKeepAlive — как часто поток журнала отправляет пустой комментарий, чтобы соединение
+// не закрывалось прокси и антивирусами
+const logKeepAlive = 15 * time.Second
+
+// logLine — строка журнала проверки
+type logLine struct {
+	Seq  int
+	Text string
+}
+
+// logBuffer собирает строки журнала, записанные во время проверки, чтобы показать их
+// на странице: при ошибке пользователь может скопировать нужные строки сам, без доступа
+// к консоли сервера. Строки между проверками не сохраняются.
+type logBuffer struct {
+	mu      sync.Mutex
+	active  bool
+	lines   []logLine
+	next    int
+	resets  int           // сколько раз журнал начинался заново
+	changed chan struct{} // закрывается при каждом изменении журнала
+	done    chan struct{} // закрывается при остановке сервера
+	closing sync.Once
+}
+
+func newLogBuffer() *logBuffer {
+	return &logBuffer{changed: make(chan struct{}), done: make(chan struct{})}
+}
+
+// Close завершает потоки журнала, чтобы остановка сервера не ждала их отключения
+func (b *logBuffer) Close() {
+	b.closing.Do(func() { close(b.done) })
+}
+
+// Write добавляет строки в журнал, если идёт проверка. Реализует io.Writer
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.active {
+		return len(p), nil
+	}
+	for _, text := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.next++
+		b.lines = append(b.lines, logLine{Seq: b.next, Text: text})
+	}
+	if len(b.lines) > maxLogLines {
+		b.lines = append([]logLine(nil), b.lines[len(b.lines)-maxLogLines:]...)
+	}
+	b.notify()
+	return len(p), nil
+}
+
+// Begin очищает журнал и начинает запись строк новой проверки
+func (b *logBuffer) Begin() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.active = true
+	b.lines = nil
+	b.resets++
+	b.notify()
+}
+
+// End прекращает запись; строки проверки остаются до начала следующей
+func (b *logBuffer) End() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.active = false
+}
+
+// notify будит ожидающих изменений журнала. Вызывается под мьютексом
+func (b *logBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Since возвращает строки после строки с номером after, номер текущего журнала
+// и канал, который закроется при следующем изменении
+func (b *logBuffer) Since(after int) ([]logLine, int, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lines []logLine
+	for _, line := range b.lines {
+		if line.Seq > after {
+			lines = append(lines, line)
+		}
+	}
+	return lines, b.resets, b.changed
+}
+
+// LogWriter возвращает приёмник журнала для страницы проверки. Чтобы строки журнала
+// попадали на страницу, вывод стандартного логгера нужно направить и сюда:
+//
+//	log.SetOutput(io.MultiWriter(os.Stderr, server.LogWriter()))
+func (s *Server) LogWriter() io.Writer {
+	return s.logs
+}
+
+// handleLogStream передаёт журнал текущей проверки как поток событий (Server-Sent Events).
+// При подключении и в начале каждой новой проверки отправляется событие reset, после
+// которого журнал передаётся с начала.
+func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(logKeepAlive)
+	defer keepAlive.Stop()
+
+	last, current := 0, -1
+	for {
+		lines, resets, changed := s.logs.Since(last)
+		if resets != current {
+			lines, _, _ = s.logs.Since(0)
+			current = resets
+			fmt.Fprint(w, "event: reset\ndata: \n\n")
+		}
+		for _, line := range lines {
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", line.Seq, line.Text)
+			last = line.Seq
+		}
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.logs.done:
+			return
+		}
+	}
+}
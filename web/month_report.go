@@ -0,0 +1,125 @@
+package web
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// monthWeeks — сколько недель подряд проверяется для отчета за месяц
+const monthWeeks = 4
+
+// MonthReportData содержит данные отчета за месяц: нарушения нескольких недель подряд
+// в одном документе, по неделям и студентам
+type MonthReportData struct {
+	DateStart  string           // Дата начала первой недели
+	DateEnd    string           // Дата окончания последней недели
+	Weeks      []MonthWeekData  // Недели отчета по порядку
+	Violations int              // Нарушений за все недели
+	Legend     []LegendItem     // Цвета видов нарушений, встречающихся в отчете
+	FirstYear  FirstYearSummary // Нарушения студентов 1-го курса за все недели
+	Summary    ReportSummary    // Итоги проверки за все недели
+}
+
+// MonthWeekData содержит нарушения одной недели отчета за месяц, сгруппированные по студентам
+type MonthWeekData struct {
+	DateStart string             // Дата начала недели
+	DateEnd   string             // Дата окончания недели
+	Students  []MonthStudentData // Студенты с нарушениями в порядке имён
+	Excepted  []ExceptedData     // Нарушения, подавленные допущенными исключениями
+	// CabinetConflicts — накладки кабинетов недели с предложениями свободных кабинетов
+	CabinetConflicts []CabinetConflictData
+}
+
+// MonthStudentData содержит нарушения студента за неделю отчета за месяц
+type MonthStudentData struct {
+	StudentName string          // Имя студента
+	Group       string          // Группа студента
+	Year        int             // Курс студента
+	Violations  []ViolationData // Нарушения студента за неделю
+	FirstYear   bool            // Студент 1-го курса: выделяется в отчете
+}
+
+// prepareMonthData подготавливает данные отчета за месяц по отчетам отдельных недель на языке lang
+func prepareMonthData(reports []Report, lang domain.Lang) MonthReportData {
+	var data MonthReportData
+	legend := make(map[string]bool)
+	var (
+		all          []domain.Violation
+		issues       []domain.Issue
+		excepted     int
+		dryRun       bool
+		completeness domain.Completeness
+	)
+	for _, report := range reports {
+		all = append(all, report.Violations...)
+		issues = append(issues, report.Issues...)
+		excepted += len(report.Excepted)
+		dryRun = dryRun || report.DryRun
+		completeness = completeness.Merge(report.Completeness)
+		report.Lang = lang
+		weekData := prepareTemplateData(report)
+		week := MonthWeekData{DateStart: weekData.WeekDateStart, DateEnd: weekData.WeekDateEnd, Excepted: weekData.Excepted, CabinetConflicts: weekData.CabinetConflicts}
+
+		byStudent := make(map[string]int)
+		for _, v := range weekData.Violations {
+			i, ok := byStudent[v.StudentName]
+			if !ok {
+				i = len(week.Students)
+				byStudent[v.StudentName] = i
+				week.Students = append(week.Students, MonthStudentData{StudentName: v.StudentName, Group: v.Group, Year: v.Year, FirstYear: v.FirstYear})
+			}
+			week.Students[i].Violations = append(week.Students[i].Violations, v)
+		}
+		sort.SliceStable(week.Students, func(i, j int) bool {
+			return week.Students[i].StudentName < week.Students[j].StudentName
+		})
+
+		data.Violations += len(weekData.Violations)
+		for _, item := range weekData.Legend {
+			legend[item.Kind] = true
+		}
+		data.Weeks = append(data.Weeks, week)
+	}
+
+	data.FirstYear = prepareFirstYear(all, lang)
+	data.Summary = prepareSummary(all, excepted, issues, lang)
+	data.Summary.DryRun = dryRun
+	data.Summary.Completeness = completeness
+	data.Summary.Score = completeness.Score()
+	if len(data.Weeks) > 0 {
+		data.DateStart = data.Weeks[0].DateStart
+		data.DateEnd = data.Weeks[len(data.Weeks)-1].DateEnd
+	}
+	for _, kind := range legendKinds {
+		if legend[string(kind)] {
+			data.Legend = append(data.Legend, LegendItem{Kind: string(kind), Name: lang.T(kind.DisplayName())})
+		}
+	}
+	return data
+}
+
+// renderMonthReport формирует отчет за месяц на языке lang. Сетка занятий и легенда берутся
+// из шаблона report.html, в том числе заменённого в каталоге шаблонов.
+func (s *Server) renderMonthReport(reports []Report, lang domain.Lang) (string, error) {
+	tmpl, err := s.parseTemplateIn("month_report.html", lang)
+	if err != nil {
+		return "", err
+	}
+	report, err := s.readOverride("report.html")
+	if err != nil {
+		if report, err = templates.ReadFile("templates/report.html"); err != nil {
+			return "", err
+		}
+	}
+	if _, err := tmpl.New("report.html").Parse(string(report)); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "month_report.html", prepareMonthData(reports, lang)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
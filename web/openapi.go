@@ -0,0 +1,210 @@
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPIDocument строит документ OpenAPI 3 по списку apiEndpoints. Схемы тел запросов
+// и ответов выводятся из Go-типов по тегам json, поэтому документ не расходится с обработчиками.
+func openAPIDocument() map[string]any {
+	components := make(map[string]any)
+	paths := make(map[string]any)
+
+	for _, endpoint := range apiEndpoints {
+		operation := map[string]any{
+			"summary": endpoint.Summary,
+			"responses": map[string]any{
+				strconv.Itoa(endpoint.Status): map[string]any{
+					"description": http.StatusText(endpoint.Status),
+					"content":     contentOf(endpoint.ResponseType, schemaOf(reflect.TypeOf(endpoint.Response), components)),
+				},
+				"default": map[string]any{
+					"description": "Ошибка",
+					"content":     jsonContent(schemaOf(reflect.TypeOf(apiError{}), components)),
+				},
+			},
+		}
+		var params []any
+		for _, p := range endpoint.Params {
+			params = append(params, map[string]any{
+				"name":        p.Name,
+				"in":          "path",
+				"required":    true,
+				"description": p.Description,
+				"schema":      map[string]any{"type": "string"},
+			})
+		}
+		for _, p := range endpoint.Query {
+			params = append(params, map[string]any{
+				"name":        p.Name,
+				"in":          "query",
+				"description": p.Description,
+				"schema":      map[string]any{"type": "string"},
+			})
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if endpoint.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  contentOf(endpoint.RequestType, schemaOf(reflect.TypeOf(endpoint.Request), components)),
+			}
+		}
+
+		item, _ := paths[endpoint.Path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[endpoint.Path] = item
+		}
+		item[strings.ToLower(endpoint.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Lesson Counter API",
+			"version": "1.0",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": components},
+	}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return contentOf("", schema)
+}
+
+// contentOf описывает содержимое с типом mediaType, по умолчанию application/json
+func contentOf(mediaType string, schema map[string]any) map[string]any {
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	return map[string]any{mediaType: map[string]any{"schema": schema}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf возвращает схему OpenAPI для типа. Именованные структуры помещаются
+// в components и подставляются ссылкой.
+func schemaOf(t reflect.Type, components map[string]any) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), components)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), components)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), components)}
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, exists := components[name]; !exists {
+			components[name] = nil // защита от рекурсии
+			components[name] = structSchema(t, components)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema строит схему структуры по экспортированным полям и тегам json
+func structSchema(t reflect.Type, components map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitempty := field.Name, false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				omitempty = omitempty || option == "omitempty"
+			}
+		}
+		property := schemaOf(field.Type, components)
+		// тег format уточняет формат строк, например binary для файлов формы
+		if format := field.Tag.Get("format"); format != "" {
+			if items, ok := property["items"].(map[string]any); ok {
+				items["format"] = format
+			} else {
+				property["format"] = format
+			}
+		}
+		properties[name] = property
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaName возвращает имя схемы для типа: "apiLesson" → "Lesson", "domain.Issue" → "Issue"
+func schemaName(t reflect.Type) string {
+	name := strings.TrimPrefix(t.Name(), "api")
+	if name == "" {
+		return "Object"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// handleOpenAPI отдаёт документ OpenAPI для JSON API
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeAPIJSON(w, http.StatusOK, openAPIDocument())
+}
+
+// handleAPIDocs показывает документацию JSON API в Swagger UI. Скрипты Swagger UI
+// загружаются из CDN, поэтому страница требует доступа в интернет; сам документ
+// доступен и без него по адресу /api/openapi.json.
+func (s *Server) handleAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(apiDocsPage))
+}
+
+const apiDocsPage = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+    <meta charset="UTF-8">
+    <title>Lesson Counter API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.onload = () => {
+            window.ui = SwaggerUIBundle({ url: '/api/openapi.json', dom_id: '#swagger-ui' });
+        };
+    </script>
+</body>
+</html>
+`
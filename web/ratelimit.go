@@ -0,0 +1,127 @@
+package web
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+const (
+	// checkRateLimit проверок может запустить один клиент за checkRateWindow
+	checkRateLimit  = 5
+	checkRateWindow = time.Minute
+	// checkDebounce — время после запуска проверки, в течение которого повторный запрос
+	// той же недели возвращает уже выполненную проверку, а не начинает новую
+	checkDebounce = 30 * time.Second
+)
+
+// errCheckRepeated и errCheckJustDone возвращает startCheck, если та же неделя ещё проверяется
+// или только что проверена: новая проверка не запускается, клиент получает состояние существующей
+var (
+	errCheckRepeated = errors.New("проверка этой недели уже запущена")
+	errCheckJustDone = errors.New("эта неделя только что проверена")
+)
+
+// isRepeatedCheck сообщает, что startCheck не запустил проверку, потому что запрос повторяет
+// выполняющуюся или только что выполненную
+func isRepeatedCheck(err error) bool {
+	return errors.Is(err, errCheckRepeated) || errors.Is(err, errCheckJustDone)
+}
+
+// rateLimiter ограничивает количество действий одного клиента за скользящее окно
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	starts map[string][]time.Time // время разрешённых действий по клиентам
+}
+
+// newRateLimiter создает ограничитель: не больше limit действий за window
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, starts: make(map[string][]time.Time)}
+}
+
+// allow учитывает действие клиента. Если лимит исчерпан, действие не учитывается
+// и возвращается время, через которое можно повторить.
+func (l *rateLimiter) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+	recent := l.starts[client]
+	if len(recent) >= l.limit {
+		return recent[0].Add(l.window).Sub(now), false
+	}
+	l.starts[client] = append(recent, now)
+	return 0, true
+}
+
+// prune убирает действия старше окна, а клиентов без действий в окне забывает, чтобы таблица
+// не росла с каждым новым адресом
+func (l *rateLimiter) prune(now time.Time) {
+	for client, starts := range l.starts {
+		recent := starts[:0]
+		for _, t := range starts {
+			if now.Sub(t) < l.window {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) == 0 {
+			delete(l.starts, client)
+			continue
+		}
+		l.starts[client] = recent
+	}
+}
+
+// clientAddr возвращает адрес клиента без порта
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limitCheck проверяет, может ли клиент запустить ещё одну проверку недели. Повторный запрос
+// уже запущенной недели лимитом не ограничивается: он вернёт существующую проверку.
+// Если лимит исчерпан, в ответ записывается заголовок Retry-After и возвращается false.
+func (s *Server) limitCheck(w http.ResponseWriter, r *http.Request, week domain.Week, fullReport, dryRun bool) bool {
+	wait, ok := s.allowCheck(clientAddr(r), week, fullReport, dryRun)
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+	}
+	return ok
+}
+
+// allowCheck учитывает запуск проверки клиентом client — для веб-интерфейса, JSON и gRPC API.
+// Если лимит исчерпан, возвращает время, через которое можно повторить.
+func (s *Server) allowCheck(client string, week domain.Week, fullReport, dryRun bool) (time.Duration, bool) {
+	if s.repeatedCheck(week, fullReport, dryRun) != nil {
+		return 0, true
+	}
+	return s.checkLimiter.allow(client, time.Now())
+}
+
+// repeatedCheck возвращает errCheckRepeated, если проверка той же недели с теми же параметрами
+// (полнота отчета, пробная или обычная) ещё выполняется, и errCheckJustDone, если она успешно
+// запущена менее checkDebounce назад и уже завершилась
+func (s *Server) repeatedCheck(week domain.Week, fullReport, dryRun bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkWeek != week || s.fullReport != fullReport || s.checkDryRun != dryRun || s.checkMonth {
+		return nil
+	}
+	switch {
+	case s.isProcessing:
+		return errCheckRepeated
+	case s.lastError == "" && time.Since(s.checkStarted) < checkDebounce:
+		return errCheckJustDone
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+)
+
+// readOnlyMessage — ответ на изменяющий запрос в режиме только для просмотра
+const readOnlyMessage = "Сервер работает в режиме только для просмотра"
+
+// readOnlyPages — страницы, которые в режиме только для просмотра закрыты и для чтения:
+// они нужны только для изменения данных
+var readOnlyPages = []string{"/students/edit/", "/departments/edit/", "/upload", "/setup"}
+
+// readOnlyCSS добавляется к styles.css в режиме только для просмотра и скрывает элементы
+// управления, которые всё равно не сработают
+const readOnlyCSS = `
+/* Режим только для просмотра */
+form[method="post"], form[method="POST"], #checkForm, #shutdownButton, #notifyButton, #notifyTeachersButton, #retryFailedButton,
+a[href^="/students/edit/"], a[href^="/students/delete/"],
+a[href^="/departments/edit/"], a[href^="/departments/delete/"],
+a[href="/upload"] {
+    display: none !important;
+}
+`
+
+// readOnlyGate в режиме только для просмотра отклоняет все запросы, кроме чтения,
+// и страницы изменения данных: редактирование студентов и отделений, загрузку файлов,
+// запуск проверок, настройки и завершение работы
+func (s *Server) readOnlyGate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.readOnly {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allowed := r.Method == http.MethodGet || r.Method == http.MethodHead
+		for _, prefix := range readOnlyPages {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				allowed = false
+			}
+		}
+		if allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeAPIError(w, http.StatusForbidden, readOnlyMessage)
+			return
+		}
+		http.Error(w, readOnlyMessage, http.StatusForbidden)
+	})
+}
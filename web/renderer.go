@@ -4,102 +4,250 @@ package web
 import (
 	"bytes"
 	"embed"
+	"fmt"
 	"html/template"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Vaflel/lesson-counter/domain"
 )
 
-//go:embed templates/index.html templates/students.html templates/edit_student.html static/*
+//go:embed templates/index.html templates/students.html templates/edit_student.html templates/departments.html templates/edit_department.html templates/dashboard.html templates/student_violations.html templates/tally.html templates/plan.html templates/bells.html templates/violation.html templates/exceptions.html templates/teachers.html templates/teacher_portal.html templates/report.html templates/month_report.html templates/report_export.html templates/group.html templates/cabinets.html templates/digest.html templates/jobs.html templates/job_batch.html templates/webhooks.html templates/retention.html templates/alerts.html templates/upload.html templates/setup.html templates/students_error.html static/*
 var templates embed.FS
 
-// Slot представляет временной слот в расписании, содержащий информацию о занятиях по разным дням недели
+// Slot представляет временной слот в расписании, содержащий информацию о занятиях по разным дням недели.
+// Если в паре есть занятия на половину пары, слот делится на две строки: Days — первая половина
+// (и целые пары на обе строки), Second — вторая половина.
 type Slot struct {
-	Number int   // Номер временного слота (например, 1-6)
+	Number int   // Номер временного слота (от 1 до количества пар в расписании звонков)
 	Days   []Day // Информация о занятиях по каждому дню недели для этого слота
+	Second []Day // Вторые половины пары по дням недели, если слот разделён
+	Split  bool  // В слоте есть занятия на половину пары
 }
 
 // Day представляет информацию о занятии в определенный день недели
 type Day struct {
 	Teacher     string // Преподаватель
 	Discipline  string // Дисциплина
+	Cabinet     string // Кабинет
 	Hours       string // Количество академических часов
+	Source      string // Источник занятия (индивидуальное/групповое расписание)
+	Time        string // Время занятия, например "08:30–10:00"
 	IsViolation bool   // Флаг, указывающий на наличие нарушения в расписании
+	Kind        string // Вид нарушения для выбора цвета выделения (класс violation-<вид>)
+	Half        int    // Половина пары: 0 — вся пара, 1 или 2
+	RowSpan     int    // В разделённом слоте 2, если ячейка занимает обе строки
+	Covered     bool   // Ячейка второй строки, занятая ячейкой первой строки
+}
+
+// add записывает занятие в ячейку. Если ячейка уже занята (накладка), данные занятий
+// перечисляются через " / ", чтобы были видны оба.
+func (d *Day) add(cell Day) {
+	if d.Discipline == "" {
+		*d = cell
+		return
+	}
+	d.Teacher += " / " + cell.Teacher
+	d.Discipline += " / " + cell.Discipline
+	d.Cabinet += " / " + cell.Cabinet
+	d.Hours += " / " + cell.Hours
+	if cell.IsViolation {
+		d.IsViolation, d.Kind = true, cell.Kind
+	}
 }
 
 // ViolationData содержит данные о нарушении расписания для конкретного студента
 type ViolationData struct {
+	ID          string           // Идентификатор нарушения для ссылки на обсуждение
+	StudentName string           // Имя студента
+	Group       string           // Группа студента
+	Year        int              // Курс студента
+	Type        string           // Тип нарушения
+	Hours       int              // Количество академических часов нарушения
+	Slots       []Slot           // Временные слоты с информацией о занятиях по дням недели
+	Suggestions []SuggestionData // Свободные слоты для переноса занятий дня с нарушением
+	FirstYear   bool             // Студент 1-го курса: выделяется в отчете
+	Footnote    FootnoteData     // Сноска с описанием нарушенного правила
+	Disciplines DisciplinesData  // Часы студента за неделю по дисциплинам
+}
+
+// DisciplinesData содержит часы студента за неделю по дисциплинам — для разбора
+// спорных превышений нагрузки
+type DisciplinesData struct {
+	Rows                     []domain.DisciplineHours
+	Individual, Group, Total int // Итоги по всем дисциплинам
+}
+
+// FootnoteData — пронумерованная сноска с описанием правила и его порогов
+type FootnoteData struct {
+	Number int    // Номер сноски в отчете, 0 — описания правила нет
+	Text   string // Описание правила
+}
+
+// FirstYearSummary содержит нарушения студентов 1-го курса: нормы для них строже,
+// и комиссия смотрит эти нарушения в первую очередь
+type FirstYearSummary struct {
+	Students   int                // Студентов 1-го курса с нарушениями
+	Violations []FirstYearRowData // Нарушения по студентам и датам
+}
+
+// FirstYearRowData — строка сводки нарушений 1-го курса
+type FirstYearRowData struct {
+	ID          string // Идентификатор нарушения для ссылки на его место в отчете
 	StudentName string // Имя студента
 	Group       string // Группа студента
-	Year        int    // Курс студента
+	Date        string // Дата нарушения
 	Type        string // Тип нарушения
 	Hours       int    // Количество академических часов нарушения
-	Slots       []Slot // Временные слоты с информацией о занятиях по дням недели
+}
+
+// SuggestionData содержит варианты переноса одного занятия
+type SuggestionData struct {
+	Lesson       string   // Описание занятия
+	Alternatives []string // Свободные слоты
+}
+
+// ExceptedData содержит нарушение, подавленное допущенным исключением
+type ExceptedData struct {
+	StudentName string // Имя студента
+	Group       string // Группа студента
+	Type        string // Тип нарушения
+	Date        string // Дата нарушения
+	Until       string // Срок действия исключения
+	Reason      string // Основание исключения
+	ApprovedBy  string // Кто допустил исключение
+}
+
+// CabinetConflictData содержит накладку кабинета: занятия в одном кабинете в одно время
+// и свободные кабинеты того же типа, куда можно перенести каждое из них
+type CabinetConflictData struct {
+	Cabinet     string           // Кабинет накладки
+	Slot        string           // День и пара накладки
+	Lessons     []string         // Описания занятий накладки
+	Suggestions []SuggestionData // Свободные кабинеты для каждого занятия
 }
 
 // TemplateData содержит все данные, необходимые для отображения отчета о нарушениях
 type TemplateData struct {
-	WeekDateStart string          // Дата начала недели для отчета
-	WeekDateEnd   string          // Дата окончания недели для отчета
-	Violations    []ViolationData // Список нарушений
+	WeekDateStart string                // Дата начала недели для отчета
+	WeekDateEnd   string                // Дата окончания недели для отчета
+	Violations    []ViolationData       // Список нарушений
+	Excepted      []ExceptedData        // Нарушения, подавленные допущенными исключениями
+	Schedules     []StudentScheduleData // Расписания всех студентов (полный отчет)
+	Legend        []LegendItem          // Цвета видов нарушений, встречающихся в отчете
+	FirstYear     FirstYearSummary      // Нарушения студентов 1-го курса
+	Summary       ReportSummary         // Итоги проверки в начале отчета
+	// CabinetConflicts — накладки кабинетов с предложениями свободных кабинетов
+	CabinetConflicts []CabinetConflictData
+}
+
+// ReportSummary содержит итоги проверки, которые выводятся в начале отчета до таблиц:
+// сколько нарушений и каких видов, сколько студентов и групп затронуто, полны ли данные
+type ReportSummary struct {
+	Total    int                // Нарушений всего
+	Kinds    []SummaryKindData  // Нарушений по видам в порядке легенды
+	Students int                // Студентов с нарушениями
+	Groups   int                // Групп, в которых есть студенты с нарушениями
+	Excepted int                // Нарушений, подавленных допущенными исключениями
+	Issues   []SummaryIssueData // Проблемы с данными по категориям; пусто — данные полные
+	Notices  []SummaryIssueData // Уведомления об изменениях в данных (расписание группы изменилось)
+	Failed   []string           // Группы, не загрузившиеся с сайта: их можно загрузить повторно
+	DryRun   bool               // Пробная проверка: результат не сохранён в историю и не рассылается
+	// Completeness — полнота данных: сколько ожидаемых преподавателей, групп и студентов попало
+	// в проверку; Score — она же в процентах
+	Completeness domain.Completeness
+	Score        int
+}
+
+// SummaryKindData — количество нарушений одного вида в итогах отчета
+type SummaryKindData struct {
+	Kind  string // Вид нарушения (класс violation-<вид>)
+	Name  string // Название вида нарушения
+	Count int
+}
+
+// SummaryIssueData — количество проблем с данными одной категории в итогах отчета
+type SummaryIssueData struct {
+	Name  string // Название категории проблемы
+	Count int
+}
+
+// LegendItem описывает цвет выделения вида нарушения в легенде отчета
+type LegendItem struct {
+	Kind string // Вид нарушения (класс violation-<вид>)
+	Name string // Название вида нарушения
+}
+
+// legendKinds — порядок видов нарушений в легенде отчета
+var legendKinds = []domain.ViolationKind{
+	domain.ViolationOverload,
+	domain.ViolationGaps,
+	domain.ViolationConsecutive,
+	domain.ViolationClash,
+	domain.ViolationOverlap,
+	domain.ViolationWeeklyOverload,
+	domain.ViolationCustom,
+}
+
+// StudentScheduleData содержит недельное расписание студента для полного отчета
+type StudentScheduleData struct {
+	StudentName string         // Имя студента
+	Group       string         // Группа студента
+	Year        int            // Курс студента
+	Violations  []string       // Нарушения студента за неделю
+	Slots       []Slot         // Временные слоты с информацией о занятиях по дням недели
+	FirstYear   bool           // Студент 1-го курса: выделяется в отчете
+	Footnotes   []FootnoteData // Описания правил, нарушенных студентом
+}
+
+// Report содержит результат проверки для отображения в отчете
+type Report struct {
+	Week       domain.Week // неделя отчета; если не задана, определяется по занятиям
+	Violations []domain.Violation
+	Excepted   []domain.ExceptedViolation // нарушения, подавленные допущенными исключениями
+	Lessons    []domain.Lesson
+	Students   []domain.Student         // студенты, чьё расписание выводится полностью; пусто — только нарушения
+	Rules      []domain.RuleDescription // описания правил для сносок под нарушениями
+	Issues     []domain.Issue           // некритичные проблемы проверки: по ним судят о полноте данных
+	// FailedGroups — группы, не загрузившиеся с сайта; в итогах отчета для них есть кнопка повтора
+	FailedGroups []string
+	DryRun       bool                // пробная проверка: отмечается в итогах отчета
+	Completeness domain.Completeness // полнота данных проверки для итогов отчета
+	// CabinetConflicts — накладки кабинетов; выводятся после нарушений с вариантами переноса
+	CabinetConflicts []domain.CabinetConflict
+	Lang             domain.Lang // язык отчета; пусто — русский
+	PairsPerDay      int         // количество пар в учебном дне по звонкам проверки; 0 — из настроек
 }
 
 // RenderViolations генерирует HTML-представление отчета о нарушениях расписания
-// Принимает список нарушений и список занятий, возвращает HTML-строку и ошибку
-func RenderViolations(violations []domain.Violation, lessons []domain.Lesson) (string, error) {
-	data := prepareTemplateData(violations, lessons)
-
-	// Константа reportTemplate содержит HTML-шаблон для отображения отчета о нарушениях расписания
-	// Шаблон включает таблицу с расписанием, где нарушения выделены цветом
-	const reportTemplate = `
-		<div style="text-align: center; margin-bottom: 20px;">
-			<p>Период: с {{.WeekDateStart}} по {{.WeekDateEnd}}</p>
-		</div>
-		{{if .Violations}}
-		{{range .Violations}}
-		<h2>Студент: {{.StudentName}} (Группа: {{.Group}}, Курс: {{.Year}})</h2>
-		<p><strong>Нарушение:</strong> {{.Type}} ({{.Hours}} ак.ч)</p>
-		<table class="schedule-table">
-			<tr>
-				<th>№</th>
-				<th colspan="3">Понедельник</th>
-				<th colspan="3">Вторник</th>
-				<th colspan="3">Среда</th>
-				<th colspan="3">Четверг</th>
-				<th colspan="3">Пятница</th>
-				<th colspan="3">Суббота</th>
-			</tr>
-			<tr>
-				<th></th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-				<th>Преподаватель</th><th>Дисциплина</th><th>Часы</th>
-			</tr>
-			{{range .Slots}}
-			<tr>
-				<td>{{.Number}}</td>
-				{{range .Days}}
-				<td {{if .IsViolation}}style="background-color: #ffcccc;"{{end}}>{{if .Teacher}}{{.Teacher}}{{else}}-{{end}}</td>
-				<td {{if .IsViolation}}style="background-color: #ffcccc;"{{end}}>{{if .Discipline}}{{.Discipline}}{{else}}-{{end}}</td>
-				<td {{if .IsViolation}}style="background-color: #ffcccc;"{{end}}>{{if .Hours}}{{.Hours}}{{else}}-{{end}}</td>
-				{{end}}
-			</tr>
-			{{end}}
-		</table>
-		{{end}}
-		{{else}}
-		<p style="text-align: center; font-size: 18px;">✅<br>Отлично!<br>Нарушений в расписании не найдено.</p>
-		{{end}}
-	`
-
-	tmpl, err := template.New("report").Parse(reportTemplate)
+// Принимает список нарушений, подавленные исключениями нарушения и список занятий,
+// возвращает HTML-строку и ошибку
+func RenderViolations(violations []domain.Violation, excepted []domain.ExceptedViolation, lessons []domain.Lesson) (string, error) {
+	return RenderReport(Report{Violations: violations, Excepted: excepted, Lessons: lessons})
+}
+
+// RenderReport генерирует HTML-представление отчета; при заданных Students отчет включает
+// недельное расписание каждого студента
+func RenderReport(report Report) (string, error) {
+	tmpl, err := template.New("report.html").Funcs(templateFuncs(report.lang())).ParseFS(templates, "templates/report.html")
 	if err != nil {
 		return "", err
 	}
+	return renderReport(tmpl, report)
+}
+
+// templateFuncs возвращает функции шаблонов: t переводит текст на язык lang по каталогу
+// сообщений, tf — строку формата с подстановками
+func templateFuncs(lang domain.Lang) template.FuncMap {
+	return template.FuncMap{"t": lang.T, "tf": lang.Tf}
+}
+
+// renderReport заполняет шаблон отчета о нарушениях данными проверки
+func renderReport(tmpl *template.Template, report Report) (string, error) {
+	data := prepareTemplateData(report)
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -110,89 +258,405 @@ func RenderViolations(violations []domain.Violation, lessons []domain.Lesson) (s
 }
 
 // prepareTemplateData подготавливает данные для отображения в шаблоне отчета о нарушениях
-// Принимает список нарушений и список занятий, возвращает структуру TemplateData
-func prepareTemplateData(violations []domain.Violation, lessons []domain.Lesson) TemplateData {
+// Принимает результат проверки, возвращает структуру TemplateData
+func prepareTemplateData(report Report) TemplateData {
 	data := TemplateData{
 		WeekDateStart: "Не указано",
 		WeekDateEnd:   "Не указано",
 	}
 
-	if len(lessons) > 0 {
-		data.WeekDateStart = lessons[0].Time.WeekStartString()
-		data.WeekDateEnd = lessons[0].Time.WeekEndString()
+	if !report.Week.IsZero() {
+		data.WeekDateStart = report.Week.Start().Format("2006-01-02")
+		data.WeekDateEnd = report.Week.End().Format("2006-01-02")
+	} else if len(report.Lessons) > 0 {
+		data.WeekDateStart = report.Lessons[0].Time.WeekStartString()
+		data.WeekDateEnd = report.Lessons[0].Time.WeekEndString()
 	}
 
-	// dayIndex сопоставляет названия дней недели с их индексами (0-5)
-	dayIndex := map[string]int{
-		"понедельник": 0,
-		"вторник":     1,
-		"среда":       2,
-		"четверг":     3,
-		"пятница":     4,
-		"суббота":     5,
+	schedule := domain.Schedule(report.Lessons)
+	days := gridDays(schedule)
+	lang := report.lang()
+	pairs := report.pairs()
+	notes := newFootnotes(report.Rules, lang)
+	disciplines := make(map[string]DisciplinesData)
+
+	for _, v := range report.Violations {
+		student := domain.Student{Name: v.StudentName, Group: v.Group}
+		slots := buildSlots(schedule, student, violationDates(v, schedule), days, pairs, lang)
+		if _, ok := disciplines[v.StudentName]; !ok {
+			disciplines[v.StudentName] = prepareDisciplines(schedule.ForStudent(student))
+		}
+
+		var suggestions []SuggestionData
+		for _, s := range domain.SuggestSlots(schedule, v, pairs) {
+			item := SuggestionData{
+				Lesson: fmt.Sprintf("%s, %s (%s)", s.Lesson.Discipline, s.Lesson.TeacherNames(), s.Lesson.Time.SlotStringIn(lang)),
+			}
+			for _, alt := range s.Alternatives {
+				item.Alternatives = append(item.Alternatives, alt.SlotStringIn(lang))
+			}
+			suggestions = append(suggestions, item)
+		}
+
+		data.Violations = append(data.Violations, ViolationData{
+			ID:          v.ID(),
+			StudentName: v.StudentName,
+			Group:       v.Group,
+			Year:        v.Year,
+			Type:        v.TitleIn(lang),
+			Hours:       v.Hours,
+			Slots:       slots,
+			Suggestions: suggestions,
+			FirstYear:   v.Year == 1,
+			Footnote:    notes.note(v),
+			Disciplines: disciplines[v.StudentName],
+		})
 	}
+	data.FirstYear = prepareFirstYear(report.Violations, lang)
+	data.Summary = prepareSummary(report.Violations, len(report.Excepted), report.Issues, lang)
+	data.Summary.Failed = report.FailedGroups
+	data.Summary.DryRun = report.DryRun
+	data.Summary.Completeness = report.Completeness
+	data.Summary.Score = report.Completeness.Score()
 
-	// lessonKey представляет ключ для группировки занятий по различным параметрам
-	type lessonKey struct {
-		Student    string // Имя студента
-		Group      string // Группа студента
-		Date       string // Дата занятия
-		DayName    string // Название дня недели
-		Number     int    // Номер временного слота
-		Discipline string // Дисциплина
-		Teacher    string // Преподаватель
+	for _, e := range report.Excepted {
+		data.Excepted = append(data.Excepted, ExceptedData{
+			StudentName: e.Violation.StudentName,
+			Group:       e.Violation.Group,
+			Type:        e.Violation.TitleIn(lang),
+			Date:        e.Violation.Date.In(domain.Location()).Format("02.01.2006"),
+			Until:       e.Exception.Until.In(domain.Location()).Format("02.01.2006"),
+			Reason:      e.Exception.Reason,
+			ApprovedBy:  e.Exception.ApprovedBy,
+		})
 	}
 
-	lessonMap := make(map[lessonKey][]domain.Lesson)
-	for _, lesson := range lessons {
-		key := lessonKey{
-			Student:    lesson.Student,
-			Group:      lesson.Group,
-			Date:       lesson.Time.DateString(),
-			DayName:    strings.ToLower(lesson.Time.DayName()),
-			Number:     lesson.Time.Number,
-			Discipline: lesson.Discipline,
-			Teacher:    lesson.Teacher,
+	data.CabinetConflicts = prepareCabinetConflicts(report.CabinetConflicts, lang)
+
+	// названия и даты нарушений каждого студента для полного отчета
+	titles := make(map[string][]string)
+	studentNotes := make(map[string][]FootnoteData)
+	dates := make(map[string]map[string]domain.ViolationKind)
+	for _, v := range report.Violations {
+		titles[v.StudentName] = append(titles[v.StudentName], v.TitleIn(lang))
+		if note := notes.note(v); note.Number != 0 && !slices.Contains(studentNotes[v.StudentName], note) {
+			studentNotes[v.StudentName] = append(studentNotes[v.StudentName], note)
+		}
+		if dates[v.StudentName] == nil {
+			dates[v.StudentName] = make(map[string]domain.ViolationKind)
+		}
+		for date, kind := range violationDates(v, schedule) {
+			if _, exists := dates[v.StudentName][date]; !exists {
+				dates[v.StudentName][date] = kind
+			}
+		}
+	}
+	for _, student := range report.Students {
+		data.Schedules = append(data.Schedules, StudentScheduleData{
+			StudentName: student.Name,
+			Group:       student.Group,
+			Year:        student.Year,
+			Violations:  titles[student.Name],
+			Slots:       buildSlots(schedule, student, dates[student.Name], days, pairs, lang),
+			FirstYear:   student.Year == 1,
+			Footnotes:   studentNotes[student.Name],
+		})
+	}
+
+	present := make(map[domain.ViolationKind]bool)
+	for _, v := range report.Violations {
+		present[v.Kind] = true
+	}
+	for _, kind := range legendKinds {
+		if present[kind] {
+			data.Legend = append(data.Legend, LegendItem{Kind: string(kind), Name: lang.T(kind.DisplayName())})
+		}
+	}
+
+	return data
+}
+
+// violationDates возвращает дни, занятия которых выделяются в сетке нарушения: день нарушения,
+// а для недельной нагрузки — все дни занятий студента
+func violationDates(v domain.Violation, schedule domain.Schedule) map[string]domain.ViolationKind {
+	if v.Kind != domain.ViolationWeeklyOverload {
+		return map[string]domain.ViolationKind{v.Date.In(domain.Location()).Format("2006-01-02"): v.Kind}
+	}
+	dates := make(map[string]domain.ViolationKind)
+	for date := range schedule.ForStudent(domain.Student{Name: v.StudentName, Group: v.Group}).ByDay() {
+		dates[date] = v.Kind
+	}
+	return dates
+}
+
+// lang возвращает язык отчета; по умолчанию — русский
+func (r Report) lang() domain.Lang {
+	if r.Lang == "" {
+		return domain.LangRU
+	}
+	return r.Lang
+}
+
+// pairs возвращает количество пар в учебном дне для сетки отчета и вариантов переноса
+func (r Report) pairs() int {
+	if r.PairsPerDay <= 0 {
+		return domain.PairsPerDay()
+	}
+	return r.PairsPerDay
+}
+
+// prepareDisciplines подсчитывает часы расписания студента по дисциплинам и итоги
+func prepareDisciplines(schedule domain.Schedule) DisciplinesData {
+	data := DisciplinesData{Rows: schedule.DisciplineHours()}
+	for _, row := range data.Rows {
+		data.Individual += row.Individual
+		data.Group += row.Group
+	}
+	data.Total = data.Individual + data.Group
+	return data
+}
+
+// footnotes нумерует описания правил в порядке первого упоминания в отчете
+type footnotes struct {
+	rules   []domain.RuleDescription
+	lang    domain.Lang // язык описаний правил
+	numbers map[int]int // номер сноски по индексу правила в rules
+}
+
+func newFootnotes(rules []domain.RuleDescription, lang domain.Lang) *footnotes {
+	return &footnotes{rules: rules, lang: lang, numbers: make(map[int]int)}
+}
+
+// note возвращает сноску правила, которым найдено нарушение v. Если описания
+// правила нет, возвращается сноска с нулевым номером.
+func (f *footnotes) note(v domain.Violation) FootnoteData {
+	for i, rule := range f.rules {
+		if !rule.Matches(v) {
+			continue
+		}
+		number, ok := f.numbers[i]
+		if !ok {
+			number = len(f.numbers) + 1
+			f.numbers[i] = number
 		}
-		lessonMap[key] = append(lessonMap[key], lesson)
+		return FootnoteData{Number: number, Text: rule.TextIn(f.lang)}
 	}
+	return FootnoteData{}
+}
+
+// prepareSummary подсчитывает итоги проверки для начала отчета на языке lang
+func prepareSummary(violations []domain.Violation, excepted int, issues []domain.Issue, lang domain.Lang) ReportSummary {
+	summary := ReportSummary{Total: len(violations), Excepted: excepted}
 
+	kinds := make(map[domain.ViolationKind]int)
+	students := make(map[string]bool)
+	groups := make(map[string]bool)
 	for _, v := range violations {
-		violationDate := v.Date.Format("2006-01-02")
-		slots := make([]Slot, 6)
-		for i := range slots {
-			slots[i] = Slot{
-				Number: i + 1,
-				Days:   make([]Day, 6),
-			}
+		kinds[v.Kind]++
+		students[v.StudentName] = true
+		if v.Group != "" {
+			groups[v.Group] = true
+		}
+	}
+	for _, kind := range legendKinds {
+		if n := kinds[kind]; n > 0 {
+			summary.Kinds = append(summary.Kinds, SummaryKindData{Kind: string(kind), Name: lang.T(kind.DisplayName()), Count: n})
 		}
+	}
+	summary.Students = len(students)
+	summary.Groups = len(groups)
 
-		for key, lessonGroup := range lessonMap {
-			if (key.Student == "" && key.Group == v.Group) || key.Student == v.StudentName {
-				if slotIdx := key.Number - 1; slotIdx >= 0 && slotIdx < 6 {
-					if dayIdx, exists := dayIndex[key.DayName]; exists {
-						// Используем LessonTime.Hours из первого урока в группе
-						hours := strconv.Itoa(lessonGroup[0].Time.Hours)
-						slots[slotIdx].Days[dayIdx] = Day{
-							Teacher:     key.Teacher,
-							Discipline:  key.Discipline,
-							Hours:       hours,
-							IsViolation: key.Date == violationDate,
-						}
-					}
-				}
-			}
+	// категории проблем в порядке первого появления; уведомления об изменениях считаются
+	// отдельно, полноты данных они не касаются
+	byCategory := make(map[domain.IssueCategory]int)
+	for _, issue := range issues {
+		list := &summary.Issues
+		if issue.Category.Informational() {
+			list = &summary.Notices
 		}
+		i, ok := byCategory[issue.Category]
+		if !ok {
+			i = len(*list)
+			byCategory[issue.Category] = i
+			*list = append(*list, SummaryIssueData{Name: lang.T(issue.Category.DisplayName())})
+		}
+		(*list)[i].Count++
+	}
+	return summary
+}
 
-		data.Violations = append(data.Violations, ViolationData{
+// prepareFirstYear собирает сводку нарушений студентов 1-го курса по студентам и датам
+func prepareFirstYear(violations []domain.Violation, lang domain.Lang) FirstYearSummary {
+	var firstYear []domain.Violation
+	for _, v := range violations {
+		if v.Year == 1 {
+			firstYear = append(firstYear, v)
+		}
+	}
+	sort.SliceStable(firstYear, func(i, j int) bool {
+		a, b := firstYear[i], firstYear[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.StudentName != b.StudentName {
+			return a.StudentName < b.StudentName
+		}
+		return a.Date.Before(b.Date)
+	})
+
+	var summary FirstYearSummary
+	students := make(map[string]bool)
+	for _, v := range firstYear {
+		students[v.StudentName] = true
+		summary.Violations = append(summary.Violations, FirstYearRowData{
+			ID:          v.ID(),
 			StudentName: v.StudentName,
 			Group:       v.Group,
-			Year:        v.Year,
-			Type:        v.Type,
+			Date:        v.Date.In(domain.Location()).Format("02.01.2006"),
+			Type:        v.TitleIn(lang),
 			Hours:       v.Hours,
-			Slots:       slots,
 		})
 	}
+	summary.Students = len(students)
+	return summary
+}
 
-	return data
+// dayIndex сопоставляет названия дней недели с их индексами (0-6)
+var dayIndex = map[string]int{
+	"понедельник": 0,
+	"вторник":     1,
+	"среда":       2,
+	"четверг":     3,
+	"пятница":     4,
+	"суббота":     5,
+	"воскресенье": 6,
+}
+
+// gridDays возвращает количество дней в сетке занятий: с понедельника по субботу или,
+// если хотя бы одно занятие выпало на воскресенье (например, репетиция), по воскресенье
+func gridDays(schedule domain.Schedule) int {
+	for _, lesson := range schedule {
+		if lesson.Time.Date.In(domain.Location()).Weekday() == time.Sunday {
+			return 7
+		}
+	}
+	return 6
+}
+
+// buildSlots раскладывает занятия студента за неделю по сетке пар и дней.
+// Занятия в даты из highlight ("2006-01-02") отмечаются как нарушения указанного вида.
+// Пара, в которой есть занятия на половину пары, делится на две строки по половинам,
+// чтобы было видно, в какой половине окно или накладка. days — количество дней в сетке (см. gridDays),
+// pairs — количество пар в расписании звонков, lang — язык названий источников занятий.
+func buildSlots(schedule domain.Schedule, student domain.Student, highlight map[string]domain.ViolationKind, days, pairs int, lang domain.Lang) []Slot {
+	lessons := schedule.ForStudent(student).MergeSubgroups()
+	// Пар столько, сколько в расписании звонков; занятия после последней пары не теряются
+	for _, lesson := range lessons {
+		pairs = max(pairs, lesson.Time.Number)
+	}
+	slots := make([]Slot, pairs)
+	for i := range slots {
+		slots[i] = Slot{
+			Number: i + 1,
+			Days:   make([]Day, days),
+			Second: make([]Day, days),
+		}
+	}
+
+	for _, lesson := range lessons {
+		slotIdx := lesson.Time.Number - 1
+		if slotIdx < 0 {
+			continue
+		}
+		dayIdx, exists := dayIndex[strings.ToLower(lesson.Time.DayName())]
+		if !exists || dayIdx >= days {
+			continue
+		}
+		cell := Day{
+			Teacher:    lesson.TeacherNames(),
+			Discipline: lesson.Discipline,
+			Cabinet:    lesson.Cabinet,
+			Hours:      strconv.Itoa(lesson.Time.Hours),
+			Source:     lang.T(lesson.Source.DisplayName()),
+			Time:       lessonTimeRange(lesson.Time),
+			Half:       lesson.Time.PairHalf,
+		}
+		if kind, ok := highlight[lesson.Time.DateString()]; ok {
+			cell.IsViolation = true
+			cell.Kind = string(kind)
+		}
+		slot := &slots[slotIdx]
+		switch lesson.Time.PairHalf {
+		case 1:
+			slot.Split = true
+			slot.Days[dayIdx].add(cell)
+		case 2:
+			slot.Split = true
+			slot.Second[dayIdx].add(cell)
+		default:
+			// Целая пара занимает обе половины
+			slot.Days[dayIdx].add(cell)
+			slot.Second[dayIdx].add(cell)
+		}
+	}
+
+	for i := range slots {
+		splitHalves(&slots[i])
+	}
+	return slots
+}
+
+// splitHalves объединяет половины пары в одну ячейку на две строки там, где они совпадают
+// (целая пара или пустая ячейка), а в неразделённом слоте убирает вторую строку
+func splitHalves(slot *Slot) {
+	if !slot.Split {
+		slot.Second = nil
+		return
+	}
+	for day := range slot.Days {
+		first, second := slot.Days[day], slot.Second[day]
+		if first.Half == 0 && second.Half == 0 && first.Discipline == second.Discipline {
+			slot.Days[day].RowSpan = 2
+			slot.Second[day] = Day{Covered: true}
+		}
+	}
+}
+
+// lessonTimeRange возвращает время занятия "08:30–10:00" или пустую строку, если время неизвестно
+func lessonTimeRange(t domain.LessonTime) string {
+	if t.StartTime.IsZero() || !t.EndTime.After(t.StartTime) {
+		return ""
+	}
+	return t.StartTimeString() + "–" + t.EndTimeString()
+}
+
+// prepareCabinetConflicts подготавливает накладки кабинетов для отчета на языке lang
+func prepareCabinetConflicts(conflicts []domain.CabinetConflict, lang domain.Lang) []CabinetConflictData {
+	var result []CabinetConflictData
+	for _, c := range conflicts {
+		item := CabinetConflictData{Cabinet: c.Cabinet, Slot: c.Time.SlotStringIn(lang)}
+		for _, lesson := range c.Lessons {
+			item.Lessons = append(item.Lessons, cabinetLessonString(lesson))
+		}
+		for _, s := range c.Suggestions {
+			item.Suggestions = append(item.Suggestions, SuggestionData{
+				Lesson:       cabinetLessonString(s.Lesson),
+				Alternatives: s.Cabinets,
+			})
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// cabinetLessonString описывает занятие накладки: дисциплина, преподаватели и кто занимается
+func cabinetLessonString(l domain.Lesson) string {
+	who := l.Student
+	if who == "" {
+		who = l.Group
+		if l.Subgroup != "" {
+			who += ", " + l.Subgroup
+		}
+	}
+	return fmt.Sprintf("%s, %s (%s)", l.Discipline, l.TeacherNames(), who)
 }
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// ReportExportData содержит отчет о нарушениях для выгрузки отдельным HTML-файлом
+type ReportExportData struct {
+	Lang   domain.Lang
+	Title  string
+	Styles template.CSS  // стили страницы программы, встроенные в файл
+	Report template.HTML // отчет, сформированный шаблоном report.html на языке Lang
+}
+
+// handleReportExport отдаёт отчет последней проверки отдельным HTML-файлом на языке из параметра
+// lang (ru или en) — например, для комиссии по аккредитации. Тексты отчета переводятся по тем же
+// каталогам сообщений, что и отчет на главной странице; имена, группы и дисциплины остаются как в данных.
+func (s *Server) handleReportExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	lang, err := domain.ParseLang(r.URL.Query().Get("lang"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	if !s.reportReady {
+		s.mu.Unlock()
+		http.Error(w, "Сначала выполните проверку расписания", http.StatusConflict)
+		return
+	}
+	week := s.checkedWeek
+	report, err := s.renderViolations(lang)
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("Ошибка рендеринга отчета: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	styles, err := templates.ReadFile("static/styles.css")
+	if err != nil {
+		log.Printf("Ошибка чтения стилей отчета: %v", err)
+	}
+	tmpl, err := s.parseTemplateIn("report_export.html", lang)
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	data := ReportExportData{
+		Lang:   lang,
+		Title:  lang.Tf("Отчет о нарушениях расписания за %s", week.Start().Format("02.01.2006")),
+		Styles: template.CSS(styles),
+		Report: template.HTML(report),
+	}
+
+	filename := fmt.Sprintf("report-%s-%s.html", week, lang)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// retentionInterval — как часто удаляются устаревшие данные
+const retentionInterval = 24 * time.Hour
+
+// retentionSchedule — очистка устаревших данных и итог последней очистки
+type retentionSchedule struct {
+	service   *usecases.RetentionService
+	scheduled bool                   // очищать при запуске и раз в сутки, а не только кнопкой
+	last      usecases.CleanupResult // итог последней очистки, защищён Server.mu
+}
+
+// WithRetention включает очистку данных старше сроков хранения: кнопкой на странице «Хранение
+// данных», а при scheduled — ещё и при запуске и раз в сутки (см. RunRetention)
+func WithRetention(service *usecases.RetentionService, scheduled bool) Option {
+	return func(s *Server) {
+		s.retention = &retentionSchedule{service: service, scheduled: scheduled}
+	}
+}
+
+// RunRetention удаляет устаревшие данные при запуске и затем раз в сутки, если очистка
+// по расписанию включена. Блокирует выполнение до отмены ctx.
+func (s *Server) RunRetention(ctx context.Context) {
+	if s.retention == nil || !s.retention.scheduled {
+		return
+	}
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		s.cleanup()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup удаляет устаревшие данные и запоминает итог для страницы
+func (s *Server) cleanup() usecases.CleanupResult {
+	result := s.retention.service.Cleanup(time.Now())
+	s.mu.Lock()
+	s.retention.last = result
+	s.mu.Unlock()
+	return result
+}
+
+// handleRetention показывает сроки хранения данных и итог последней очистки
+func (s *Server) handleRetention(w http.ResponseWriter, r *http.Request) {
+	if s.retention == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("retention.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	s.mu.Lock()
+	data := struct {
+		Policy    usecases.RetentionPolicy
+		Scheduled bool
+		Last      usecases.CleanupResult
+	}{s.retention.service.Policy(), s.retention.scheduled, s.retention.last}
+	s.mu.Unlock()
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleRetentionCleanup сразу удаляет устаревшие данные, не дожидаясь очистки по расписанию
+func (s *Server) handleRetentionCleanup(w http.ResponseWriter, r *http.Request) {
+	if s.retention == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	s.cleanup()
+	http.Redirect(w, r, "/retention", http.StatusSeeOther)
+}
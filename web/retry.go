@@ -0,0 +1,117 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// errNothingToRetry возвращается, если в последней проверке нет групп для повторной загрузки
+var errNothingToRetry = errors.New("в последней проверке нет групп, которые не загрузились")
+
+// startRetryFailed запускает в фоне повторную загрузку групп, не загрузившихся при последней
+// проверке. Занятия остальных групп и XLS-файлов берутся из последней проверки, отчет
+// пересчитывается по дополненному набору занятий. Для отчета за месяц повтор не поддерживается.
+func (s *Server) startRetryFailed() ([]string, error) {
+	s.mu.Lock()
+	if s.isProcessing {
+		s.mu.Unlock()
+		return nil, errCheckInProgress
+	}
+	if !s.reportReady || len(s.month) > 0 || len(s.failedGroups) == 0 {
+		s.mu.Unlock()
+		return nil, errNothingToRetry
+	}
+	week := s.checkedWeek
+	previous := usecases.ValidatingResult{
+		Violations:       s.violations,
+		Lessons:          s.lessons,
+		Students:         s.students,
+		Issues:           s.issues,
+		Excepted:         s.excepted,
+		CabinetConflicts: s.cabinetConflicts,
+		PairsPerDay:      s.pairsPerDay,
+		Rules:            s.rules,
+		FailedGroups:     s.failedGroups,
+		DryRun:           s.dryRun,
+		Completeness:     s.completeness,
+	}
+	s.isProcessing = true
+	s.checkMonth = false
+	s.checkWeek = week
+	s.checkDryRun = previous.DryRun
+	s.checkStarted = time.Now()
+	s.reportReady = false
+	s.lastError = ""
+	s.mu.Unlock()
+	s.logs.Begin()
+
+	go s.runRetryFailed(week, previous)
+	return previous.FailedGroups, nil
+}
+
+// runRetryFailed выполняет повторную загрузку, начатую startRetryFailed. Если повтор не удался,
+// остаётся отчет прежней проверки, а ошибка сохраняется как ошибка последней проверки.
+func (s *Server) runRetryFailed(week domain.Week, previous usecases.ValidatingResult) (err error) {
+	defer s.logs.End()
+	defer s.recoverCheck(&err)
+
+	opts := append([]usecases.Option{usecases.WithEventBus(s.events)}, s.serviceOpts...)
+	if previous.DryRun {
+		opts = append(opts, usecases.WithDryRun())
+	}
+	result, err := usecases.NewScheduleService(week, s.studentRepo, opts...).RetryFailedGroups(previous)
+	if err == nil && !result.DryRun {
+		s.saveHistory(week, result)
+		s.publishCheck(week, result)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isProcessing = false
+	s.reportReady = true
+	if err != nil {
+		log.Printf("Ошибка повторной загрузки групп: %v", err)
+		s.lastError = err.Error()
+		return err
+	}
+	s.setResult(week, result)
+	return nil
+}
+
+// handleRetryFailed запускает повторную загрузку групп, не загрузившихся при последней проверке
+// (кнопка «Повторить только неудачные» в отчете)
+func (s *Server) handleRetryFailed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	groups, err := s.startRetryFailed()
+	switch {
+	case errors.Is(err, errNothingToRetry):
+		writeCheckResponse(w, http.StatusConflict, false, err.Error())
+	case err != nil:
+		writeCheckResponse(w, http.StatusTooManyRequests, false, "Обработка уже выполняется")
+	default:
+		writeCheckResponse(w, http.StatusOK, true, fmt.Sprintf("Повторная загрузка групп: %s", strings.Join(groups, ", ")))
+	}
+}
+
+func (s *Server) apiRetryFailed(w http.ResponseWriter, r *http.Request) {
+	_, err := s.startRetryFailed()
+	switch {
+	case errors.Is(err, errNothingToRetry):
+		writeAPIError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeAPIError(w, http.StatusConflict, "Обработка уже выполняется")
+	default:
+		writeAPIJSON(w, http.StatusAccepted, s.currentJob())
+	}
+}
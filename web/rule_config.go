@@ -0,0 +1,106 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// maxRuleConfigSize — наибольший размер загружаемого файла настроек проверки
+const maxRuleConfigSize = 1 << 20
+
+// handleRuleConfigExport отдаёт файлом настройку проверки: пороги и пользовательские правила
+// из rules.yaml и допущенные исключения, чтобы перенести их в другую копию программы
+func (s *Server) handleRuleConfigExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rules, err := infrastructure.NewYAMLRulesRepository("rules.yaml").LoadRulesConfig()
+	if err != nil {
+		log.Printf("Ошибка загрузки правил: %v", err)
+		http.Error(w, "Ошибка загрузки правил: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var exceptions []domain.Exception
+	if s.exceptionRepo != nil {
+		exceptions, err = s.exceptionRepo.LoadExceptions()
+		if err != nil {
+			log.Printf("Ошибка загрузки исключений: %v", err)
+			http.Error(w, "Ошибка загрузки исключений: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := infrastructure.ExportRuleConfig(&buf, infrastructure.NewRuleConfig(rules, exceptions)); err != nil {
+		log.Printf("Ошибка выгрузки настроек проверки: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("rules-config-%s.yaml", time.Now().In(domain.Location()).Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
+
+// handleRuleConfigImport загружает файл настроек проверки, выгруженный handleRuleConfigExport.
+// Файл проверяется целиком и при любой ошибке не применяется. Пороги и пользовательские
+// правила заменяются загруженными, исключения добавляются к уже допущенным.
+func (s *Server) handleRuleConfigImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.exceptionRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRuleConfigSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		s.renderExceptions(w, http.StatusBadRequest, "", []string{"Не удалось прочитать файл настроек: " + err.Error()})
+		return
+	}
+	defer file.Close()
+
+	config, exceptions, err := infrastructure.ParseRuleConfig(file)
+	if err != nil {
+		var lines []string
+		if errs, ok := infrastructure.AsImportErrors(err); ok {
+			for _, item := range errs {
+				lines = append(lines, item.Error())
+			}
+		} else {
+			lines = []string{err.Error()}
+		}
+		s.renderExceptions(w, http.StatusBadRequest, "", lines)
+		return
+	}
+
+	if err := infrastructure.NewYAMLRulesRepository("rules.yaml").ReplaceRules(config.Limits, config.Custom); err != nil {
+		log.Printf("Ошибка сохранения правил: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	for _, exception := range exceptions {
+		if err := s.exceptionRepo.AddException(exception); err != nil {
+			log.Printf("Ошибка сохранения исключения: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	log.Printf("Загружены настройки проверки: правил %d, исключений %d", len(config.Custom), len(exceptions))
+	message := fmt.Sprintf("Настройки загружены: пороги, пользовательских правил — %d, исключений — %d. Они применятся со следующей проверки.",
+		len(config.Custom), len(exceptions))
+	s.renderExceptions(w, http.StatusOK, message, nil)
+}
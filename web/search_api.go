@@ -0,0 +1,61 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// searchLimit — количество результатов поиска по умолчанию
+const searchLimit = 20
+
+// apiSearchResult — результат быстрого поиска в JSON API
+type apiSearchResult struct {
+	Kind   string `json:"kind"` // student, teacher, discipline
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"` // страница объекта, если она есть
+}
+
+func (s *Server) apiSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := searchLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "limit должен быть положительным числом")
+			return
+		}
+		limit = n
+	}
+
+	students, err := s.studentRepo.LoadStudents()
+	if err != nil {
+		log.Printf("Ошибка загрузки студентов: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var plan []domain.PlanEntry
+	if s.planRepo != nil {
+		if _, plan, err = s.planRepo.LoadPlan(); err != nil {
+			log.Printf("Ошибка загрузки учебного плана: %v", err)
+		}
+	}
+	s.mu.Lock()
+	lessons := append([]domain.Lesson(nil), s.lessons...)
+	s.mu.Unlock()
+
+	result := []apiSearchResult{}
+	for _, found := range usecases.Search(query.Get("q"), students, lessons, plan, limit) {
+		item := apiSearchResult{Kind: string(found.Kind), Name: found.Name, Detail: found.Detail}
+		if found.Kind == usecases.SearchStudent {
+			item.URL = "/students/" + url.PathEscape(found.Name) + "/violations"
+		}
+		result = append(result, item)
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
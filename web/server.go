@@ -1,30 +1,225 @@
 package web
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
 	"github.com/Vaflel/lesson-counter/usecases"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
-	studentRepo  usecases.StudentRepository
-	mu           sync.Mutex
-	isProcessing bool
-	reportReady  bool
-	violations   []domain.Violation
-	lessons      []domain.Lesson
-	server       *http.Server
+	studentRepo      usecases.StudentRepository
+	deptRepo         usecases.DepartmentRepository
+	mu               sync.Mutex
+	isProcessing     bool
+	reportReady      bool
+	lastError        string              // ошибка последней проверки
+	issues           []domain.Issue      // некритичные проблемы последней проверки (неполные данные)
+	failedGroups     []string            // группы последней проверки, не загрузившиеся с сайта даже после повторов
+	dryRun           bool                // последняя проверка пробная: не сохранена в историю и не рассылается
+	completeness     domain.Completeness // полнота данных последней проверки
+	violations       []domain.Violation
+	excepted         []domain.ExceptedViolation // нарушения последней проверки, подавленные исключениями
+	cabinetConflicts []domain.CabinetConflict   // накладки кабинетов последней проверки
+	pairsPerDay      int                        // количество пар в учебном дне по звонкам последней проверки
+	rules            []domain.RuleDescription   // описания правил последней проверки для сносок отчета
+	lessons          []domain.Lesson
+	checkedWeek      domain.Week      // неделя последней успешной проверки
+	checkWeek        domain.Week      // неделя текущей или последней запущенной проверки
+	checkStarted     time.Time        // время запуска текущей или последней проверки
+	checkLimiter     *rateLimiter     // ограничение запусков проверок одним клиентом
+	checkMonth       bool             // текущая или последняя проверка — отчет за месяц
+	checkDryRun      bool             // текущая или последняя запущенная проверка — пробная
+	month            []Report         // недели отчета за месяц, если последняя проверка — отчет за месяц
+	students         []domain.Student // студенты последней проверки
+	fullReport       bool             // отчет последней проверки включает расписание всех студентов
+	server           *http.Server
+	listener         net.Listener                                    // порт веб-интерфейса, занятый Listen
+	readOnly         bool                                            // режим только для просмотра, см. WithReadOnly
+	siteDepartments  func() ([]infrastructure.SiteDepartment, error) // отделения и группы сайта, может быть nil
+	grpcServer       *grpc.Server                                    // gRPC API, если запущен через StartGRPC
+	mux              *http.ServeMux
+	onShutdown       func()                        // вызывается после остановки сервера через /shutdown
+	events           *domain.EventBus              // шина событий проверки
+	progress         string                        // описание текущего этапа проверки
+	logs             *logBuffer                    // журнал текущей проверки для страницы
+	updates          *infrastructure.UpdateChecker // проверка новых версий, может быть nil
+	update           updateState                   // результат последней проверки обновлений
+	warmup           *warmupSchedule               // ежедневный прогрев кэша расписания групп, может быть nil
+	setupConfig      string                        // файл настроек, создаваемый мастером первого запуска
+	setupPending     bool                          // мастер первого запуска ещё не пройден
+	serviceOpts      []usecases.Option
+	historyRepo      usecases.HistoryRepository      // история проверок, может быть nil
+	planRepo         usecases.PlanRepository         // учебный план часов, может быть nil
+	calendarRepo     usecases.CalendarRepository     // учебный календарь, может быть nil
+	bellRepo         usecases.BellRepository         // расписание звонков, может быть nil
+	exceptionRepo    usecases.ExceptionRepository    // допущенные исключения, может быть nil
+	teacherRepo      usecases.TeacherRepository      // справочник преподавателей, может быть nil
+	jobRepo          usecases.JobRepository          // очередь заданий на проверку, может быть nil
+	uploads          *infrastructure.ScheduleUploads // загруженные XLS-файлы, может быть nil
+	templatesDir     string                          // каталог шаблонов, заменяющих встроенные
+	notifier         *usecases.NotificationService   // рассылка студентам, может быть nil
+	webhooks         *usecases.WebhookService        // уведомления о проверках на внешний адрес, может быть nil
+	retention        *retentionSchedule              // очистка устаревших данных, может быть nil
+	alerts           *usecases.AlertCenter           // оповещения о важных событиях, может быть nil
+}
+
+// Option настраивает Server при создании
+type Option func(*Server)
+
+// WithOnShutdown задаёт действие, выполняемое после остановки сервера через /shutdown.
+// По умолчанию процесс завершается через os.Exit(0); при встраивании сервера в другую
+// программу или в тестах его стоит заменить.
+func WithOnShutdown(fn func()) Option {
+	return func(s *Server) {
+		s.onShutdown = fn
+	}
+}
+
+// WithServiceOptions задаёт настройки сервиса проверки, создаваемого для каждой проверки
+func WithServiceOptions(opts ...usecases.Option) Option {
+	return func(s *Server) {
+		s.serviceOpts = append(s.serviceOpts, opts...)
+	}
+}
+
+// WithHistoryRepository включает сохранение результатов проверок для статистики
+func WithHistoryRepository(repo usecases.HistoryRepository) Option {
+	return func(s *Server) {
+		s.historyRepo = repo
+	}
+}
+
+// WithPlanRepository подключает учебный план для сверки выданных часов с планом
+func WithPlanRepository(repo usecases.PlanRepository) Option {
+	return func(s *Server) {
+		s.planRepo = repo
+	}
+}
+
+// WithCalendarRepository подключает учебный календарь для выбора недели по номеру
+func WithCalendarRepository(repo usecases.CalendarRepository) Option {
+	return func(s *Server) {
+		s.calendarRepo = repo
+	}
+}
+
+// WithBellRepository включает страницу настройки расписания звонков
+func WithBellRepository(repo usecases.BellRepository) Option {
+	return func(s *Server) {
+		s.bellRepo = repo
+	}
+}
+
+// WithExceptionRepository включает допуск исключений для повторяющихся нарушений
+func WithExceptionRepository(repo usecases.ExceptionRepository) Option {
+	return func(s *Server) {
+		s.exceptionRepo = repo
+	}
+}
+
+// WithTeacherRepository подключает справочник преподавателей и страницу подтверждения
+// псевдонимов, найденных при разборе расписания
+func WithTeacherRepository(repo usecases.TeacherRepository) Option {
+	return func(s *Server) {
+		s.teacherRepo = repo
+	}
+}
+
+// WithJobRepository включает очередь заданий на проверку и страницу управления ею
+func WithJobRepository(repo usecases.JobRepository) Option {
+	return func(s *Server) {
+		s.jobRepo = repo
+	}
+}
+
+// WithUploads включает загрузку XLS-файлов и ZIP-архивов с ними через веб-интерфейс
+func WithUploads(uploads *infrastructure.ScheduleUploads) Option {
+	return func(s *Server) {
+		s.uploads = uploads
+	}
+}
+
+// WithUpdateChecker включает проверку новых версий программы и сообщение о них внизу страниц
+func WithUpdateChecker(checker *infrastructure.UpdateChecker) Option {
+	return func(s *Server) {
+		s.updates = checker
+	}
+}
+
+// WithSetup включает мастер первого запуска: пока он не пройден, страницы перенаправляются
+// на /setup. Файл настроек configPath создаётся мастером, если его ещё нет.
+func WithSetup(configPath string) Option {
+	return func(s *Server) {
+		s.setupConfig = configPath
+		s.setupPending = true
+	}
+}
+
+// WithSiteDepartments задаёт источник отделений и групп сайта для выпадающих списков
+// в формах студентов. Без него, или если источник вернул ошибку, поля вводятся вручную.
+func WithSiteDepartments(load func() ([]infrastructure.SiteDepartment, error)) Option {
+	return func(s *Server) {
+		s.siteDepartments = load
+	}
+}
+
+// WithReadOnly включает режим только для просмотра: отчёты и страницы доступны, а изменение
+// студентов, загрузка файлов, запуск проверок, настройки и завершение работы отключены
+func WithReadOnly() Option {
+	return func(s *Server) {
+		s.readOnly = true
+	}
+}
+
+// WithTemplatesDir задаёт каталог с шаблонами страниц и отчета, заменяющими встроенные.
+// Файл каталога с тем же именем, что и встроенный шаблон (например, report.html или
+// students.html), используется вместо него; файлы из подкаталога static — вместо
+// встроенных статических файлов. Отсутствующие файлы берутся из встроенных.
+func WithTemplatesDir(dir string) Option {
+	return func(s *Server) {
+		s.templatesDir = dir
+	}
+}
+
+// WithNotifier включает рассылку студентам их нарушений из последней проверки
+func WithNotifier(notifier *usecases.NotificationService) Option {
+	return func(s *Server) {
+		s.notifier = notifier
+	}
+}
+
+// WithWebhooks включает уведомления о завершённых проверках на внешний адрес и журнал их доставки
+func WithWebhooks(webhooks *usecases.WebhookService) Option {
+	return func(s *Server) {
+		s.webhooks = webhooks
+	}
+}
+
+// WithEventListener подписывает дополнительного слушателя на события проверки
+// (например, отправку уведомлений)
+func WithEventListener(listener domain.EventListener) Option {
+	return func(s *Server) {
+		s.events.Subscribe(listener)
+	}
 }
 
 type CheckResponse struct {
@@ -33,275 +228,1565 @@ type CheckResponse struct {
 }
 
 type CheckRequest struct {
-	WeekStart string `json:"weekStart"`
+	WeekStart  string `json:"weekStart"`
+	FullReport bool   `json:"fullReport"`      // включить в отчет расписание всех студентов, а не только нарушителей
+	Month      bool   `json:"month,omitempty"` // отчет за месяц: monthWeeks недель подряд, начиная с WeekStart
+	// DryRun — пробная проверка для опытов с правилами: без записи в историю, кэш и архив и без рассылки
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+type StatusResponse struct {
+	IsProcessing bool             `json:"isProcessing"`
+	ReportReady  bool             `json:"reportReady"`
+	Report       string           `json:"report,omitempty"`
+	Error        string           `json:"error,omitempty"`
+	Progress     string           `json:"progress,omitempty"`
+	Issues       []domain.Issue   `json:"issues,omitempty"`
+	FailedGroups []string         `json:"failedGroups,omitempty"` // группы, которые можно загрузить повторно
+	DryRun       bool             `json:"dryRun,omitempty"`       // результат пробной проверки
+	Completeness *apiCompleteness `json:"completeness,omitempty"` // полнота данных готового отчета
+}
+
+func NewServer(studentRepo usecases.StudentRepository, deptRepo usecases.DepartmentRepository, opts ...Option) *Server {
+	s := &Server{
+		studentRepo:  studentRepo,
+		deptRepo:     deptRepo,
+		mux:          http.NewServeMux(),
+		onShutdown:   func() { os.Exit(0) },
+		events:       domain.NewEventBus(infrastructure.NewLogListener()),
+		logs:         newLogBuffer(),
+		checkLimiter: newRateLimiter(checkRateLimit, checkRateWindow),
+	}
+	s.events.Subscribe(domain.EventListenerFunc(s.trackProgress))
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes()
+	return s
+}
+
+// routes регистрирует обработчики на собственном мультиплексоре сервера
+func (s *Server) routes() {
+	s.mux.HandleFunc("/", withRecover(s.handleIndex))
+	s.mux.HandleFunc("/check", withRecover(s.handleCheck))
+	s.mux.HandleFunc("/check/retry-failed", withRecover(s.handleRetryFailed))
+	s.mux.HandleFunc("/status", withRecover(s.handleStatus))
+	s.mux.HandleFunc("/logs/stream", withRecover(s.handleLogStream))
+	s.mux.HandleFunc("/students", withRecover(s.handleStudents))
+	s.mux.HandleFunc("/students/edit/", withRecover(s.handleEditStudent))
+	s.mux.HandleFunc("/students/delete/", withRecover(s.handleDeleteStudent))
+	s.mux.HandleFunc("/students/merge", withRecover(s.handleMergeStudents))
+	s.mux.HandleFunc("/students/", withRecover(s.handleStudentViolations))
+	s.mux.HandleFunc("/groups", withRecover(s.handleGroup))
+	s.mux.HandleFunc("/groups/", withRecover(s.handleGroup))
+	s.mux.HandleFunc("/cabinets", withRecover(s.handleCabinets))
+	s.mux.HandleFunc("/cabinets/", withRecover(s.handleCabinets))
+	s.mux.HandleFunc("/departments", withRecover(s.handleDepartments))
+	s.mux.HandleFunc("/departments/edit/", withRecover(s.handleEditDepartment))
+	s.mux.HandleFunc("/departments/delete/", withRecover(s.handleDeleteDepartment))
+	s.mux.HandleFunc("/violations/", withRecover(s.handleViolation))
+	s.mux.HandleFunc("/dashboard", withRecover(s.handleDashboard))
+	s.mux.HandleFunc("/plan", withRecover(s.handlePlan))
+	s.mux.HandleFunc("/tally", withRecover(s.handleTally))
+	s.mux.HandleFunc("/tally/export", withRecover(s.handleTallyExport))
+	s.mux.HandleFunc("/digest", withRecover(s.handleDigest))
+	s.mux.HandleFunc("/digest/export", withRecover(s.handleDigestExport))
+	s.mux.HandleFunc("/export/lessons.json", withRecover(s.handleLessonsExport))
+	s.mux.HandleFunc("/report/export", withRecover(s.handleReportExport))
+	s.mux.HandleFunc("/notify", withRecover(s.handleNotify))
+	s.mux.HandleFunc("/notify/teachers", withRecover(s.handleNotifyTeachers))
+	s.mux.HandleFunc("/settings/bells", withRecover(s.handleBells))
+	s.mux.HandleFunc("/settings/rules/export", withRecover(s.handleRuleConfigExport))
+	s.mux.HandleFunc("/settings/rules/import", withRecover(s.handleRuleConfigImport))
+	s.mux.HandleFunc("/exceptions", withRecover(s.handleExceptions))
+	s.mux.HandleFunc("/exceptions/delete/", withRecover(s.handleDeleteException))
+	s.mux.HandleFunc("/teachers", withRecover(s.handleTeachers))
+	s.mux.HandleFunc("/teacher", withRecover(s.handleTeacherPortal))
+	s.mux.HandleFunc("/teacher/logout", withRecover(s.handleTeacherLogout))
+	s.mux.HandleFunc("/jobs", withRecover(s.handleJobs))
+	s.mux.HandleFunc("/webhooks", withRecover(s.handleWebhooks))
+	s.mux.HandleFunc("/webhooks/redeliver/", withRecover(s.handleRedeliverWebhook))
+	s.mux.HandleFunc("/retention", withRecover(s.handleRetention))
+	s.mux.HandleFunc("/retention/cleanup", withRecover(s.handleRetentionCleanup))
+	s.mux.HandleFunc("/alerts", withRecover(s.handleAlerts))
+	s.mux.HandleFunc("/alerts/read/", withRecover(s.handleReadAlert))
+	s.mux.HandleFunc("/alerts/read-all", withRecover(s.handleReadAllAlerts))
+	s.mux.HandleFunc("/jobs/batch/", withRecover(s.handleJobBatch))
+	s.mux.HandleFunc("/jobs/retry/", withRecover(s.handleRetryJob))
+	s.mux.HandleFunc("/jobs/cancel/", withRecover(s.handleCancelJob))
+	s.mux.HandleFunc("/upload", withRecover(s.handleUpload))
+	s.mux.HandleFunc("/setup", withRecover(s.handleSetup))
+	s.mux.HandleFunc("/shutdown", withRecover(s.handleShutdown))
+	s.mux.HandleFunc("/static/", withRecover(s.handleStatic))
+	s.apiRoutes()
+}
+
+// Handler возвращает http.Handler сервера, пригодный для встраивания или httptest
+func (s *Server) Handler() http.Handler {
+	return s.readOnlyGate(s.setupGate(s.studentsGate(s.mux)))
+}
+
+// portFallbacks — сколько следующих портов пробуется, если заданный занят
+const portFallbacks = 10
+
+// Start занимает порт веб-интерфейса (см. Listen) и обслуживает запросы
+func (s *Server) Start(port int) error {
+	if _, err := s.Listen(port); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Listen занимает порт веб-интерфейса. Если порт занят, например другой копией программы,
+// по очереди пробуются следующие portFallbacks портов. Возвращает занятый порт.
+func (s *Server) Listen(port int) (int, error) {
+	var firstErr error
+	for p := port; p <= port+portFallbacks; p++ {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", p))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if p != port {
+			log.Printf("Порт %d занят, веб-интерфейс запущен на порту %d", port, p)
+		}
+		s.listener = listener
+		return p, nil
+	}
+	return 0, fmt.Errorf("порты %d–%d заняты: %w", port, port+portFallbacks, firstErr)
+}
+
+// Serve обслуживает запросы на порту, занятом Listen
+func (s *Server) Serve() error {
+	if s.listener == nil {
+		return errors.New("порт веб-интерфейса не занят: сначала вызовите Listen")
+	}
+	s.server = &http.Server{Handler: s.Handler()}
+	s.server.RegisterOnShutdown(s.logs.Close)
+	log.Printf("🚀 Сервер запущен на http://localhost:%d", s.listener.Addr().(*net.TCPAddr).Port)
+	return s.server.Serve(s.listener)
+}
+
+// trackProgress обновляет описание текущего этапа проверки по событиям сервиса
+func (s *Server) trackProgress(event domain.Event) {
+	progress := progressMessage(event)
+	s.mu.Lock()
+	s.progress = progress
+	s.mu.Unlock()
+}
+
+// progressMessage описывает этап проверки, на котором возникло событие
+func progressMessage(event domain.Event) string {
+	var progress string
+	switch event.Type {
+	case domain.EventCheckStarted:
+		progress = "Загрузка расписания..."
+	case domain.EventSourceParsed:
+		progress = fmt.Sprintf("Загружено: %s (%d занятий)", event.Source.DisplayName(), event.Count)
+	case domain.EventViolationFound:
+		progress = "Проверка нарушений..."
+	case domain.EventCheckCompleted:
+		progress = ""
+	}
+	return progress
+}
+
+// parseTemplate загружает шаблон страницы name из каталога WithTemplatesDir, если он там есть,
+// иначе встроенный шаблон
+func (s *Server) parseTemplate(name string) (*template.Template, error) {
+	return s.parseTemplateIn(name, domain.LangRU)
+}
+
+// parseTemplateIn загружает шаблон, как parseTemplate; функция t в шаблоне переводит текст на язык lang
+func (s *Server) parseTemplateIn(name string, lang domain.Lang) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(templateFuncs(lang))
+	if _, err := s.readOverride(name); err == nil {
+		return tmpl.ParseFiles(filepath.Join(s.templatesDir, name))
+	}
+	return tmpl.ParseFS(templates, "templates/"+name)
+}
+
+// readOverride читает файл name из каталога WithTemplatesDir. Если каталог не задан
+// или файла в нём нет, возвращается ошибка os.ErrNotExist.
+func (s *Server) readOverride(name string) ([]byte, error) {
+	if s.templatesDir == "" || !filepath.IsLocal(name) {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(s.templatesDir, filepath.FromSlash(name)))
+}
+
+// renderViolations формирует отчет о последней проверке на языке lang. Вызывается под s.mu.
+func (s *Server) renderViolations(lang domain.Lang) (string, error) {
+	tmpl, err := s.parseTemplateIn("report.html", lang)
+	if err != nil {
+		return "", err
+	}
+	if len(s.month) > 0 {
+		return s.renderMonthReport(s.month, lang)
+	}
+	report := Report{Violations: s.violations, Excepted: s.excepted, Lessons: s.lessons, Rules: s.rules, Issues: s.issues, FailedGroups: s.failedGroups, DryRun: s.dryRun, Completeness: s.completeness, CabinetConflicts: s.cabinetConflicts, Lang: lang, PairsPerDay: s.pairsPerDay}
+	if s.fullReport {
+		report.Students = s.students
+	}
+	return renderReport(tmpl, report)
+}
+
+// withRecover перехватывает панику в обработчике, логирует её и отвечает клиенту ошибкой 500,
+// не останавливая сервер
+func withRecover(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("Паника в обработчике %s: %v\n%s", r.URL.Path, rec, debug.Stack())
+				http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			}
+		}()
+		next(w, r)
+	}
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("index.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	data := struct {
+		IsProcessing bool
+		Report       template.HTML // Изменяем тип на template.HTML
+		Weeks        []domain.CalendarWeek
+		CurrentWeek  int
+	}{
+		IsProcessing: s.isProcessing,
+	}
+	if calendar := s.loadCalendar(); !calendar.IsZero() {
+		data.Weeks = calendar.Weeks()
+		data.CurrentWeek = calendar.WeekNumber(domain.WeekOf(time.Now()))
+	}
+	if s.reportReady {
+		report, err := s.renderViolations(domain.LangRU)
+		if err != nil {
+			log.Printf("Ошибка рендеринга отчета: %v", err)
+			s.mu.Unlock()
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		data.Report = template.HTML(report) // Преобразуем строку в template.HTML
+	}
+	s.mu.Unlock()
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// loadCalendar возвращает учебный календарь или пустой календарь, если он не подключён или не загрузился
+func (s *Server) loadCalendar() domain.AcademicCalendar {
+	if s.calendarRepo == nil {
+		return domain.AcademicCalendar{}
+	}
+	calendar, err := s.calendarRepo.LoadCalendar()
+	if err != nil {
+		log.Printf("Ошибка загрузки учебного календаря: %v", err)
+	}
+	return calendar
+}
+
+// errCheckInProgress возвращается при попытке запустить проверку, пока выполняется другая
+var errCheckInProgress = errors.New("обработка уже выполняется")
+
+// startCheck запускает проверку недели в фоне. Результат сохраняется в сервере и доступен
+// через /status, отчет и gRPC API.
+// Повторный запрос той же недели не запускает новую проверку и возвращает errCheckRepeated
+// или errCheckJustDone.
+// Дополнительные параметры extra передаются сервису после общих.
+func (s *Server) startCheck(week domain.Week, fullReport, dryRun bool) error {
+	if err := s.repeatedCheck(week, fullReport, dryRun); err != nil {
+		return err
+	}
+	if err := s.beginCheck(week, fullReport, dryRun); err != nil {
+		return err
+	}
+	go s.runCheck(week, dryRunOptions(dryRun)...)
+	return nil
+}
+
+// dryRunOptions возвращает параметры сервиса для пробной проверки
+func dryRunOptions(dryRun bool) []usecases.Option {
+	if dryRun {
+		return []usecases.Option{usecases.WithDryRun()}
+	}
+	return nil
+}
+
+// CheckCurrentWeek запускает в фоне проверку текущей недели, как кнопка на главной странице.
+// Если эта неделя уже проверяется, новая проверка не запускается и ошибки нет.
+func (s *Server) CheckCurrentWeek() error {
+	if err := s.startCheck(domain.WeekOf(time.Now()), false, false); err != nil && !isRepeatedCheck(err) {
+		return err
+	}
+	return nil
+}
+
+// beginCheck занимает сервер под новую проверку недели и сбрасывает результат предыдущей
+func (s *Server) beginCheck(week domain.Week, fullReport, dryRun bool) error {
+	s.mu.Lock()
+	if s.isProcessing {
+		repeated := s.checkWeek == week && s.fullReport == fullReport && s.checkDryRun == dryRun
+		s.mu.Unlock()
+		if repeated {
+			return errCheckRepeated
+		}
+		return errCheckInProgress
+	}
+	s.isProcessing = true
+	s.checkMonth = false
+	s.checkWeek = week
+	s.checkDryRun = dryRun
+	s.checkStarted = time.Now()
+	s.reportReady = false
+	s.lastError = ""
+	s.issues = nil
+	s.failedGroups = nil
+	s.dryRun = false
+	s.completeness = domain.Completeness{}
+	s.fullReport = fullReport
+	s.mu.Unlock()
+	s.logs.Begin()
+	return nil
+}
+
+// runCheck выполняет проверку, начатую beginCheck, и сохраняет её результат в сервере.
+// Дополнительные параметры extra передаются сервису после общих.
+// Возвращает ошибку проверки, в том числе после паники.
+func (s *Server) runCheck(week domain.Week, extra ...usecases.Option) (err error) {
+	defer s.logs.End()
+	defer s.recoverCheck(&err)
+
+	result, err := s.processWeek(week, extra...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isProcessing = false
+	if err != nil {
+		log.Printf("Ошибка обработки расписания: %v", err)
+		s.lastError = err.Error()
+		return err
+	}
+	s.setResult(week, result)
+	return nil
+}
+
+// setResult сохраняет в сервере результат проверки недели. Вызывается под s.mu.
+func (s *Server) setResult(week domain.Week, result usecases.ValidatingResult) {
+	s.violations = result.Violations
+	s.excepted = result.Excepted
+	s.cabinetConflicts = result.CabinetConflicts
+	s.pairsPerDay = result.PairsPerDay
+	s.rules = result.Rules
+	s.students = result.Students
+	s.lessons = result.Lessons
+	s.month = nil
+	s.checkedWeek = week
+	s.issues = result.Issues
+	s.failedGroups = result.FailedGroups
+	s.dryRun = result.DryRun
+	s.completeness = result.Completeness
+	s.reportReady = true
+}
+
+// recoverCheck перехватывает панику при проверке и записывает её как ошибку проверки
+func (s *Server) recoverCheck(err *error) {
+	if rec := recover(); rec != nil {
+		log.Printf("Паника при обработке расписания: %v\n%s", rec, debug.Stack())
+		*err = fmt.Errorf("внутренняя ошибка: %v", rec)
+		s.mu.Lock()
+		s.isProcessing = false
+		s.lastError = (*err).Error()
+		s.mu.Unlock()
+	}
+}
+
+// processWeek проверяет неделю и сохраняет успешную проверку в истории, если она не пробная.
+// Дополнительные параметры extra передаются сервису после общих.
+func (s *Server) processWeek(week domain.Week, extra ...usecases.Option) (usecases.ValidatingResult, error) {
+	opts := append([]usecases.Option{usecases.WithEventBus(s.events)}, s.serviceOpts...)
+	opts = append(opts, extra...)
+	result, err := usecases.NewScheduleService(week, s.studentRepo, opts...).ProcessSchedule()
+	if err == nil && !result.DryRun {
+		s.saveHistory(week, result)
+		s.publishCheck(week, result)
+	}
+	return result, err
+}
+
+// startMonthCheck запускает в фоне проверку monthWeeks недель подряд, начиная с first,
+// для отчета за месяц. Дополнительные параметры extra передаются сервису после общих.
+func (s *Server) startMonthCheck(first domain.Week, dryRun bool) error {
+	if err := s.beginCheck(first, false, dryRun); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.checkMonth = true
+	s.mu.Unlock()
+	go s.runMonthCheck(first, dryRunOptions(dryRun)...)
+	return nil
+}
+
+// runMonthCheck проверяет недели отчета за месяц по очереди. Ошибка любой недели прерывает
+// проверку: отчет за месяц с пропущенной неделей вводил бы в заблуждение.
+func (s *Server) runMonthCheck(first domain.Week, extra ...usecases.Option) (err error) {
+	defer s.logs.End()
+	defer s.recoverCheck(&err)
+
+	var (
+		reports  []Report
+		combined usecases.ValidatingResult
+	)
+	week := first
+	for i := 1; i <= monthWeeks; i++ {
+		log.Printf("Отчет за месяц: неделя %d из %d (%s)", i, monthWeeks, week.Start().Format("02.01.2006"))
+		result, err := s.processWeek(week, extra...)
+		if err != nil {
+			err = fmt.Errorf("неделя %s: %w", week.Start().Format("02.01.2006"), err)
+			log.Printf("Ошибка обработки расписания: %v", err)
+			s.mu.Lock()
+			s.isProcessing = false
+			s.lastError = err.Error()
+			s.mu.Unlock()
+			return err
+		}
+		reports = append(reports, Report{Week: week, Violations: result.Violations, Excepted: result.Excepted, Lessons: result.Lessons, Rules: result.Rules, Issues: result.Issues, DryRun: result.DryRun, Completeness: result.Completeness, CabinetConflicts: result.CabinetConflicts, PairsPerDay: result.PairsPerDay})
+		combined.Violations = append(combined.Violations, result.Violations...)
+		combined.Excepted = append(combined.Excepted, result.Excepted...)
+		combined.CabinetConflicts = append(combined.CabinetConflicts, result.CabinetConflicts...)
+		combined.Lessons = append(combined.Lessons, result.Lessons...)
+		combined.Issues = append(combined.Issues, result.Issues...)
+		combined.Students = result.Students
+		combined.Rules = result.Rules
+		combined.PairsPerDay = result.PairsPerDay
+		combined.DryRun = result.DryRun
+		combined.Completeness = combined.Completeness.Merge(result.Completeness)
+		week = week.Next()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.isProcessing = false
+	s.violations = combined.Violations
+	s.excepted = combined.Excepted
+	s.cabinetConflicts = combined.CabinetConflicts
+	s.pairsPerDay = combined.PairsPerDay
+	s.rules = combined.Rules
+	s.students = combined.Students
+	s.lessons = combined.Lessons
+	s.month = reports
+	s.checkedWeek = first
+	s.issues = combined.Issues
+	s.dryRun = combined.DryRun
+	s.completeness = combined.Completeness
+	s.reportReady = true
+	return nil
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqData CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		writeCheckResponse(w, http.StatusBadRequest, false, "Неверный формат запроса")
+		return
+	}
+
+	// Неделя принимается в любом поддерживаемом формате, в том числе «неделя N» по календарю
+	week, err := s.loadCalendar().ParseWeek(reqData.WeekStart)
+	if err != nil {
+		writeCheckResponse(w, http.StatusBadRequest, false, err.Error())
+		return
+	}
+
+	if !s.limitCheck(w, r, week, reqData.FullReport && !reqData.Month, reqData.DryRun) {
+		writeCheckResponse(w, http.StatusTooManyRequests, false, "Слишком много проверок подряд, попробуйте через минуту")
+		return
+	}
+
+	if reqData.Month {
+		if err := s.startMonthCheck(week, reqData.DryRun); err != nil {
+			writeCheckResponse(w, http.StatusTooManyRequests, false, "Обработка уже выполняется")
+			return
+		}
+		writeCheckResponse(w, http.StatusOK, true, fmt.Sprintf("Запущен отчет за %d недели с %s", monthWeeks, week.Start().Format("02.01.2006")))
+		return
+	}
+
+	switch err := s.startCheck(week, reqData.FullReport, reqData.DryRun); {
+	case errors.Is(err, errCheckRepeated):
+		// повторное нажатие кнопки: страница продолжит следить за уже запущенной проверкой
+		writeCheckResponse(w, http.StatusOK, true, "Проверка за неделю "+week.Start().Format("02.01.2006")+" уже запущена")
+		return
+	case errors.Is(err, errCheckJustDone):
+		writeCheckResponse(w, http.StatusOK, true, "Неделя "+week.Start().Format("02.01.2006")+" только что проверена, отчет готов")
+		return
+	case err != nil:
+		writeCheckResponse(w, http.StatusTooManyRequests, false, "Обработка уже выполняется")
+		return
+	}
+
+	writeCheckResponse(w, http.StatusOK, true, "Обработка запущена за неделю "+week.Start().Format("02.01.2006"))
+}
+
+// writeCheckResponse отвечает на запрос /check в формате CheckResponse, в том числе при ошибке,
+// чтобы страница могла показать причину
+func writeCheckResponse(w http.ResponseWriter, status int, success bool, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(CheckResponse{Success: success, Message: message})
+}
+
+// handleNotify рассылает студентам их нарушения из последней проверки
+func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	respond := func(status int, success bool, message string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(CheckResponse{Success: success, Message: message})
+	}
+
+	if s.notifier == nil {
+		respond(http.StatusServiceUnavailable, false, "Рассылка не настроена: задайте параметры почты или Telegram")
+		return
+	}
+
+	s.mu.Lock()
+	ready, dryRun := s.reportReady, s.dryRun
+	violations := append([]domain.Violation(nil), s.violations...)
+	s.mu.Unlock()
+	if !ready {
+		respond(http.StatusConflict, false, "Сначала выполните проверку расписания")
+		return
+	}
+	if dryRun {
+		respond(http.StatusConflict, false, "Результат пробной проверки не рассылается: выполните обычную проверку")
+		return
+	}
+
+	students, err := s.studentRepo.LoadStudents()
+	if err != nil {
+		log.Printf("Ошибка загрузки студентов: %v", err)
+		respond(http.StatusInternalServerError, false, "Не удалось загрузить список студентов")
+		return
+	}
+
+	result, err := s.notifier.NotifyStudents(students, violations)
+	if err != nil {
+		log.Printf("Ошибка рассылки уведомлений: %v", err)
+	}
+
+	message := fmt.Sprintf("Отправлено: %d", len(result.Sent))
+	if len(result.Skipped) > 0 {
+		message += fmt.Sprintf("; без контактов: %s", strings.Join(result.Skipped, ", "))
+	}
+	if len(result.Failed) > 0 {
+		message += fmt.Sprintf("; не удалось отправить: %s", strings.Join(result.Failed, ", "))
+	}
+	respond(http.StatusOK, len(result.Failed) == 0, message)
+}
+
+// handleViolation показывает нарушение из истории проверок с обсуждением и добавляет комментарии
+func (s *Server) handleViolation(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/violations/")
+	if s.historyRepo == nil || id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	records, err := s.historyRepo.LoadChecks()
+	if err != nil {
+		log.Printf("Ошибка загрузки истории проверок: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	entry, ok := usecases.FindViolation(records, id)
+	if !ok {
+		http.Error(w, "Нарушение не найдено в истории проверок", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+		comment := domain.Comment{
+			ViolationID: id,
+			Author:      strings.TrimSpace(r.FormValue("author")),
+			Text:        strings.TrimSpace(r.FormValue("text")),
+			CreatedAt:   time.Now(),
+		}
+		if comment.Author == "" || comment.Text == "" {
+			http.Error(w, "Укажите автора и текст комментария", http.StatusBadRequest)
+			return
+		}
+		if err := s.historyRepo.AddComment(comment); err != nil {
+			log.Printf("Ошибка сохранения комментария: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+		return
+	}
+
+	comments, err := s.historyRepo.LoadComments(id)
+	if err != nil {
+		log.Printf("Ошибка загрузки комментариев: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("violation.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	data := struct {
+		usecases.StudentViolationEntry
+		ID           string
+		Comments     []domain.Comment
+		CanApprove   bool
+		Exception    domain.Exception
+		DefaultUntil string
+	}{
+		StudentViolationEntry: entry,
+		ID:                    id,
+		Comments:              comments,
+		CanApprove:            s.exceptionRepo != nil,
+		Exception:             domain.ExceptionFor(entry.Violation, time.Time{}),
+		DefaultUntil:          time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleExceptions показывает допущенные исключения и допускает новое исключение
+// для нарушения из истории проверок
+func (s *Server) handleExceptions(w http.ResponseWriter, r *http.Request) {
+	if s.exceptionRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+		if s.historyRepo == nil {
+			http.Error(w, "История проверок не подключена", http.StatusNotFound)
+			return
+		}
+		records, err := s.historyRepo.LoadChecks()
+		if err != nil {
+			log.Printf("Ошибка загрузки истории проверок: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		entry, ok := usecases.FindViolation(records, r.FormValue("violation_id"))
+		if !ok {
+			http.Error(w, "Нарушение не найдено в истории проверок", http.StatusNotFound)
+			return
+		}
+		until, err := time.ParseInLocation("2006-01-02", r.FormValue("until"), domain.Location())
+		if err != nil {
+			http.Error(w, "Неверный формат даты окончания", http.StatusBadRequest)
+			return
+		}
+
+		exception := domain.ExceptionFor(entry.Violation, until)
+		exception.Reason = strings.TrimSpace(r.FormValue("reason"))
+		exception.ApprovedBy = strings.TrimSpace(r.FormValue("approved_by"))
+		if exception.Reason == "" {
+			http.Error(w, "Укажите основание исключения", http.StatusBadRequest)
+			return
+		}
+		if err := s.exceptionRepo.AddException(exception); err != nil {
+			log.Printf("Ошибка сохранения исключения: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/exceptions", http.StatusSeeOther)
+		return
+	} else if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.renderExceptions(w, http.StatusOK, "", nil)
+}
+
+// renderExceptions показывает страницу исключений с сообщением о загрузке настроек проверки
+// и ошибками загруженного файла, если они есть
+func (s *Server) renderExceptions(w http.ResponseWriter, status int, message string, importErrors []string) {
+	exceptions, err := s.exceptionRepo.LoadExceptions()
+	if err != nil {
+		log.Printf("Ошибка загрузки исключений: %v", err)
+		http.Error(w, "Ошибка загрузки исключений: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("exceptions.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	type row struct {
+		domain.Exception
+		Expired bool
+	}
+	var data struct {
+		Exceptions   []row
+		Message      string
+		ImportErrors []string
+	}
+	data.Message, data.ImportErrors = message, importErrors
+	now := time.Now()
+	for _, e := range exceptions {
+		data.Exceptions = append(data.Exceptions, row{Exception: e, Expired: e.Expired(now)})
+	}
+	w.WriteHeader(status)
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+	}
+}
+
+// handleDeleteException отменяет допущенное исключение
+func (s *Server) handleDeleteException(w http.ResponseWriter, r *http.Request) {
+	if s.exceptionRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/exceptions/delete/")
+	if err := s.exceptionRepo.DeleteException(id); err != nil {
+		log.Printf("Ошибка удаления исключения: %v", err)
+		http.Error(w, "Не удалось удалить исключение: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Redirect(w, r, "/exceptions", http.StatusSeeOther)
+}
+
+// saveHistory сохраняет результат проверки в историю, если она подключена
+func (s *Server) saveHistory(week domain.Week, result usecases.ValidatingResult) {
+	if s.historyRepo == nil {
+		return
+	}
+	record := domain.CheckRecord{
+		Week:         week,
+		CheckedAt:    time.Now(),
+		LessonsCount: len(result.Lessons),
+		DailyLoad:    domain.Schedule(result.Lessons).DailyLoad(result.Students),
+		Completeness: result.Completeness.Score(),
+		Violations:   result.Violations,
+		Hours:        domain.CountDeliveredHours(result.Lessons),
+		Groups:       domain.MeasureGroupLoads(result.Lessons, result.Students),
+	}
+	if err := s.historyRepo.SaveCheck(record); err != nil {
+		log.Printf("Ошибка сохранения истории проверок: %v", err)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := StatusResponse{
+		IsProcessing: s.isProcessing,
+		ReportReady:  s.reportReady,
+		Error:        s.lastError,
+		Progress:     s.progress,
+		Issues:       s.issues,
+		FailedGroups: s.failedGroups,
+		DryRun:       s.dryRun,
+	}
+	if s.reportReady {
+		response.Completeness = newAPICompleteness(s.completeness)
+		var err error
+		response.Report, err = s.renderViolations(domain.LangRU)
+		if err != nil {
+			log.Printf("Ошибка рендеринга отчета: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) handleStudents(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("students.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Неверные данные формы", http.StatusBadRequest)
+			return
+		}
+
+		name := r.FormValue("name")
+		group := r.FormValue("group")
+		department := r.FormValue("department")
+		year, err := strconv.Atoi(r.FormValue("year"))
+		if err != nil || name == "" {
+			http.Error(w, "Поле name и корректный year обязательны", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.studentRepo.AddStudent(domain.Student{
+			Name:         name,
+			Group:        group,
+			Department:   department,
+			Year:         year,
+			Email:        strings.TrimSpace(r.FormValue("email")),
+			Telegram:     strings.TrimSpace(r.FormValue("telegram")),
+			CuratorEmail: strings.TrimSpace(r.FormValue("curator_email")),
+		}); err != nil {
+			log.Printf("Ошибка добавления студента: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/students", http.StatusSeeOther)
+		return
+	}
+
+	students, err := s.studentRepo.LoadStudents()
+	if err != nil {
+		log.Printf("Ошибка загрузки студентов: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(students, func(i, j int) bool {
+		if students[i].Year != students[j].Year {
+			return students[i].Year < students[j].Year
+		}
+		return strings.ToLower(students[i].Name) < strings.ToLower(students[j].Name)
+	})
+
+	data := struct {
+		Students   []domain.Student
+		Duplicates [][]domain.Student
+		Form       studentForm
+	}{students, domain.DuplicateStudents(students), s.newStudentForm(domain.Student{})}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleEditStudent(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(r.URL.Path)
+	tmpl, err := s.parseTemplate("edit_student.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		student, err := s.studentRepo.GetStudent(name)
+		if err != nil {
+			log.Printf("Ошибка получения студента: %v", err)
+			http.Error(w, "Студент не найден", http.StatusNotFound)
+			return
+		}
+		if err := tmpl.Execute(w, s.newStudentForm(student)); err != nil {
+			log.Printf("Ошибка рендеринга шаблона: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+
+		newName := r.FormValue("name")
+		group := r.FormValue("group")
+		department := r.FormValue("department")
+		year, err := strconv.Atoi(r.FormValue("year"))
+		if err != nil || newName == "" || group == "" || department == "" || year <= 0 {
+			http.Error(w, "Все поля обязательны, курс должен быть > 0", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.studentRepo.UpdateStudent(name, domain.Student{
+			Name:         newName,
+			Group:        group,
+			Department:   department,
+			Year:         year,
+			Email:        strings.TrimSpace(r.FormValue("email")),
+			Telegram:     strings.TrimSpace(r.FormValue("telegram")),
+			CuratorEmail: strings.TrimSpace(r.FormValue("curator_email")),
+		}); err != nil {
+			log.Printf("Ошибка обновления студента: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/students", http.StatusSeeOther)
+		return
+	}
+
+	http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+}
+
+// handleStudentViolations показывает все сохранённые нарушения студента: /students/{имя}/violations
+func (s *Server) handleStudentViolations(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/students/"), "/violations")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("student_violations.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	var records []domain.CheckRecord
+	if s.historyRepo != nil {
+		records, err = s.historyRepo.LoadChecks()
+		if err != nil {
+			log.Printf("Ошибка загрузки истории проверок: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tmpl.Execute(w, usecases.BuildStudentViolations(records, name)); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleDeleteStudent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := s.studentRepo.DeleteStudent(filepath.Base(r.URL.Path)); err != nil {
+		log.Printf("Ошибка удаления студента: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/students", http.StatusSeeOther)
+}
+
+// handleMergeStudents объединяет повторяющиеся записи студента: запись keep остаётся,
+// остальные записи names удаляются, а их история проверок и исключения переносятся на keep
+func (s *Server) handleMergeStudents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Неверные данные формы", http.StatusBadRequest)
+		return
+	}
+
+	keep := r.FormValue("keep")
+	if keep == "" {
+		http.Error(w, "Выберите запись, которую нужно оставить", http.StatusBadRequest)
+		return
+	}
+	for _, name := range r.Form["names"] {
+		if name == keep {
+			continue
+		}
+		merge, err := usecases.MergeStudents(s.studentRepo, s.historyRepo, s.exceptionRepo, keep, name)
+		if err != nil {
+			log.Printf("Ошибка объединения студентов %s и %s: %v", keep, name, err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Студент %s объединён с %s: перенесено записей истории %d, исключений %d",
+			merge.Removed, keep, merge.History, merge.Exceptions)
+	}
+
+	http.Redirect(w, r, "/students", http.StatusSeeOther)
+}
+
+// departmentSources — варианты источника группового расписания для форм отделений
+var departmentSources = []domain.DepartmentSource{domain.DepartmentSourceSite, domain.DepartmentSourceNone}
+
+// departmentFromForm читает отделение из данных формы
+func departmentFromForm(r *http.Request) (domain.Department, error) {
+	dept := domain.Department{
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		SiteID:      strings.TrimSpace(r.FormValue("site_id")),
+		Source:      domain.DepartmentSource(r.FormValue("source")),
+		RuleProfile: strings.TrimSpace(r.FormValue("rule_profile")),
+	}
+	if dept.Name == "" {
+		return dept, fmt.Errorf("поле name обязательно")
+	}
+	if dept.Source != domain.DepartmentSourceSite && dept.Source != domain.DepartmentSourceNone {
+		return dept, fmt.Errorf("неизвестный источник расписания %q", dept.Source)
+	}
+	if value := strings.TrimSpace(r.FormValue("max_parallel")); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return dept, fmt.Errorf("одновременных запросов должно быть неотрицательным числом, указано %q", value)
+		}
+		dept.MaxParallel = n
+	}
+	return dept, nil
+}
+
+func (s *Server) handleDepartments(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("departments.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Неверные данные формы", http.StatusBadRequest)
+			return
+		}
+
+		dept, err := departmentFromForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.deptRepo.AddDepartment(dept); err != nil {
+			log.Printf("Ошибка добавления отделения: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/departments", http.StatusSeeOther)
+		return
+	}
+
+	departments, err := s.deptRepo.LoadDepartments()
+	if err != nil {
+		log.Printf("Ошибка загрузки отделений: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(departments, func(i, j int) bool {
+		return strings.ToLower(departments[i].Name) < strings.ToLower(departments[j].Name)
+	})
+
+	data := struct {
+		Departments []domain.Department
+		Sources     []domain.DepartmentSource
+	}{departments, departmentSources}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
 }
 
-type StatusResponse struct {
-	IsProcessing bool   `json:"isProcessing"`
-	ReportReady  bool   `json:"reportReady"`
-	Report       string `json:"report,omitempty"`
-}
+func (s *Server) handleEditDepartment(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(r.URL.Path)
+	tmpl, err := s.parseTemplate("edit_department.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
 
-func NewServer(studentRepo usecases.StudentRepository) *Server {
-	return &Server{
-		studentRepo: studentRepo,
+	if r.Method == http.MethodGet {
+		dept, err := s.deptRepo.GetDepartment(name)
+		if err != nil {
+			log.Printf("Ошибка получения отделения: %v", err)
+			http.Error(w, "Отделение не найдено", http.StatusNotFound)
+			return
+		}
+		data := struct {
+			Department domain.Department
+			Sources    []domain.DepartmentSource
+		}{dept, departmentSources}
+		if err := tmpl.Execute(w, data); err != nil {
+			log.Printf("Ошибка рендеринга шаблона: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		}
+		return
 	}
-}
 
-func (s *Server) Start(port int) error {
-	http.HandleFunc("/", s.handleIndex)
-	http.HandleFunc("/check", s.handleCheck)
-	http.HandleFunc("/status", s.handleStatus)
-	http.HandleFunc("/students", s.handleStudents)
-	http.HandleFunc("/students/edit/", s.handleEditStudent)
-	http.HandleFunc("/students/delete/", s.handleDeleteStudent)
-	http.HandleFunc("/shutdown", s.handleShutdown)
-	http.HandleFunc("/static/", s.handleStatic)
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+
+		dept, err := departmentFromForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.deptRepo.UpdateDepartment(name, dept); err != nil {
+			log.Printf("Ошибка обновления отделения: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/departments", http.StatusSeeOther)
+		return
+	}
 
-	addr := fmt.Sprintf(":%d", port)
-	s.server = &http.Server{Addr: addr}
-	log.Printf("🚀 Сервер запущен на http://localhost%s", addr)
-	return s.server.ListenAndServe()
+	http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
 }
 
-func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" {
-		http.NotFound(w, r)
+func (s *Server) handleDeleteDepartment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
 		return
 	}
 
-	tmpl, err := template.ParseFS(templates, "templates/index.html")
-	if err != nil {
-		log.Printf("Ошибка загрузки шаблона: %v", err)
+	if err := s.deptRepo.DeleteDepartment(filepath.Base(r.URL.Path)); err != nil {
+		log.Printf("Ошибка удаления отделения: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
+	http.Redirect(w, r, "/departments", http.StatusSeeOther)
+}
+
+// handleGroup показывает групповое расписание из последней проверки сеткой пар по дням недели,
+// чтобы сверить загруженные данные с сайтом. Без имени группы показывается список групп.
+func (s *Server) handleGroup(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/groups"), "/")
+
 	s.mu.Lock()
+	ready := s.reportReady
+	lessons := append([]domain.Lesson(nil), s.lessons...)
+	pairs := s.pairsPerDay
+	s.mu.Unlock()
+	if pairs <= 0 {
+		pairs = domain.PairsPerDay()
+	}
+
 	data := struct {
-		IsProcessing bool
-		Report       template.HTML // Изменяем тип на template.HTML
-	}{
-		IsProcessing: s.isProcessing,
+		Ready         bool
+		Groups        []string
+		Group         string
+		WeekDateStart string
+		WeekDateEnd   string
+		Slots         []Slot
+	}{Ready: ready, Group: name}
+
+	var groupLessons domain.Schedule
+	seen := make(map[string]bool)
+	for _, lesson := range lessons {
+		if lesson.Source != domain.SourceGroup || lesson.Group == "" {
+			continue
+		}
+		if !seen[lesson.Group] {
+			seen[lesson.Group] = true
+			data.Groups = append(data.Groups, lesson.Group)
+		}
+		groupLessons = append(groupLessons, lesson)
 	}
-	if s.reportReady {
-		report, err := RenderViolations(s.violations, s.lessons)
-		if err != nil {
-			log.Printf("Ошибка рендеринга отчета: %v", err)
-			s.mu.Unlock()
-			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	sort.Strings(data.Groups)
+
+	if name != "" {
+		if !seen[name] {
+			http.Error(w, "Группа не найдена в загруженном расписании", http.StatusNotFound)
 			return
 		}
-		data.Report = template.HTML(report) // Преобразуем строку в template.HTML
+		data.Slots = buildSlots(groupLessons, domain.Student{Group: name}, nil, gridDays(lessons), pairs, domain.LangRU)
+		data.WeekDateStart = lessons[0].Time.WeekStartString()
+		data.WeekDateEnd = lessons[0].Time.WeekEndString()
 	}
-	s.mu.Unlock()
 
+	tmpl, err := s.parseTemplate("group.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
 	if err := tmpl.Execute(w, data); err != nil {
 		log.Printf("Ошибка рендеринга шаблона: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 	}
 }
 
-func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("dashboard.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
-	var reqData CheckRequest
-	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
-		return
+	var records []domain.CheckRecord
+	if s.historyRepo != nil {
+		records, err = s.historyRepo.LoadChecks()
+		if err != nil {
+			log.Printf("Ошибка загрузки истории проверок: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
 	}
 
-	if reqData.WeekStart == "" {
-		http.Error(w, "Не указана дата начала недели", http.StatusBadRequest)
-		return
+	data := struct {
+		usecases.Dashboard
+		Width, Height         int
+		ViewWidth, ViewHeight int
+		Years                 []usecases.YearComparison // сравнение групп по курсам за период
+		Skipped               int                       // проверок периода без нагрузки по группам
+		From, To              string                    // период сравнения из формы
+		PeriodError           string
+	}{
+		Dashboard:  usecases.BuildDashboard(records),
+		Width:      usecases.SparklineWidth,
+		Height:     usecases.SparklineHeight,
+		ViewWidth:  usecases.SparklineWidth + 10,
+		ViewHeight: usecases.SparklineHeight + 10,
+		From:       r.URL.Query().Get("from"),
+		To:         r.URL.Query().Get("to"),
 	}
 
-	s.mu.Lock()
-	if s.isProcessing {
-		s.mu.Unlock()
-		http.Error(w, "Обработка уже выполняется", http.StatusTooManyRequests)
+	calendar := s.loadCalendar()
+	var from, to domain.Week
+	for _, bound := range []struct {
+		value string
+		week  *domain.Week
+	}{{data.From, &from}, {data.To, &to}} {
+		if bound.value == "" {
+			continue
+		}
+		week, err := calendar.ParseWeek(bound.value)
+		if err != nil {
+			data.PeriodError = err.Error()
+			break
+		}
+		*bound.week = week
+	}
+	if data.PeriodError == "" {
+		data.Years, data.Skipped = usecases.BuildYearComparison(records, from, to)
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handlePlan показывает выполнение учебного плана часов по сохранённым проверкам
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("plan.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
-	s.isProcessing = true
-	s.reportReady = false
-	s.mu.Unlock()
 
-	go func() {
-		service := usecases.NewScheduleService(reqData.WeekStart)
-		result, err := service.ProcessSchedule(reqData.WeekStart)
-		s.mu.Lock()
-		defer s.mu.Unlock()
+	data := struct {
+		Progress []domain.PlanProgress
+		Error    string
+	}{}
 
-		s.isProcessing = false
+	var semesters []domain.Semester
+	var plan []domain.PlanEntry
+	if s.planRepo != nil {
+		if semesters, plan, err = s.planRepo.LoadPlan(); err != nil {
+			log.Printf("Ошибка загрузки учебного плана: %v", err)
+			data.Error = err.Error()
+		}
+	}
+	if len(semesters) == 0 {
+		semesters = s.loadCalendar().Semesters
+	}
+
+	var records []domain.CheckRecord
+	if s.historyRepo != nil {
+		records, err = s.historyRepo.LoadChecks()
 		if err != nil {
-			log.Printf("Ошибка обработки расписания: %v", err)
+			log.Printf("Ошибка загрузки истории проверок: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 			return
 		}
+	}
+	data.Progress = domain.BuildPlanReport(semesters, plan, records, time.Now())
 
-		s.violations = result.Violations
-		s.lessons = result.Lessons
-		s.reportReady = true
-	}()
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(CheckResponse{
-		Success: true,
-		Message: "Обработка запущена",
-	})
+// bellVariant — вариант расписания звонков: по умолчанию (пустой Key) или для дня недели
+type bellVariant struct {
+	Key     string
+	Name    string
+	Weekday time.Weekday
 }
 
-func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// bellVariants — варианты расписания звонков на странице настроек: по умолчанию и по дням недели
+var bellVariants = []bellVariant{
+	{"", "По умолчанию", 0},
+	{"monday", "Понедельник", time.Monday},
+	{"tuesday", "Вторник", time.Tuesday},
+	{"wednesday", "Среда", time.Wednesday},
+	{"thursday", "Четверг", time.Thursday},
+	{"friday", "Пятница", time.Friday},
+	{"saturday", "Суббота", time.Saturday},
+}
 
-	response := StatusResponse{
-		IsProcessing: s.isProcessing,
-		ReportReady:  s.reportReady,
+// bellRows — количество строк пар в форме расписания звонков
+const bellRows = 8
+
+// handleBells показывает и сохраняет расписание звонков. Параметр day выбирает вариант
+// для дня недели; пустые поля варианта означают время по умолчанию.
+func (s *Server) handleBells(w http.ResponseWriter, r *http.Request) {
+	if s.bellRepo == nil {
+		http.NotFound(w, r)
+		return
 	}
-	if s.reportReady {
-		var err error
-		response.Report, err = RenderViolations(s.violations, s.lessons)
-		if err != nil {
-			log.Printf("Ошибка рендеринга отчета: %v", err)
-			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-			return
+
+	variant := bellVariants[0]
+	for _, v := range bellVariants {
+		if v.Key == r.URL.Query().Get("day") {
+			variant = v
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-func (s *Server) handleStudents(w http.ResponseWriter, r *http.Request) {
-	tmpl, err := template.ParseFS(templates, "templates/students.html")
+	bells, err := s.bellRepo.LoadBellSchedule()
 	if err != nil {
-		log.Printf("Ошибка загрузки шаблона: %v", err)
-		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		log.Printf("Ошибка загрузки расписания звонков: %v", err)
+		http.Error(w, "Ошибка загрузки расписания звонков: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	pairs := bells.Default
+	if variant.Key != "" {
+		pairs = bells.Days[variant.Weekday]
+	}
+
+	var formErr string
 	if r.Method == http.MethodPost {
 		if err := r.ParseForm(); err != nil {
-			http.Error(w, "Неверные данные формы", http.StatusBadRequest)
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
 			return
 		}
 
-		name := r.FormValue("name")
-		group := r.FormValue("group")
-		department := r.FormValue("department")
-		year, err := strconv.Atoi(r.FormValue("year"))
-		if err != nil || name == "" {
-			http.Error(w, "Поле name и корректный year обязательны", http.StatusBadRequest)
-			return
+		pairs = make(map[int]domain.PairTime)
+		for number := 1; number <= bellRows; number++ {
+			start := strings.TrimSpace(r.FormValue(fmt.Sprintf("start_%d", number)))
+			end := strings.TrimSpace(r.FormValue(fmt.Sprintf("end_%d", number)))
+			if start != "" || end != "" {
+				pairs[number] = domain.PairTime{Start: start, End: end}
+			}
+		}
+		if variant.Key == "" {
+			bells.Default = pairs
+		} else {
+			if bells.Days == nil {
+				bells.Days = make(map[time.Weekday]map[int]domain.PairTime)
+			}
+			bells.Days[variant.Weekday] = pairs
 		}
 
-		if err := s.studentRepo.AddStudent(domain.Student{
-			Name:       name,
-			Group:      group,
-			Department: department,
-			Year:       year,
-		}); err != nil {
-			log.Printf("Ошибка добавления студента: %v", err)
-			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		if err := s.bellRepo.SaveBellSchedule(bells); err != nil {
+			log.Printf("Ошибка сохранения расписания звонков: %v", err)
+			formErr = err.Error()
+		} else {
+			domain.SetPairsPerDay(bells.PairCount())
+			http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
 			return
 		}
-
-		http.Redirect(w, r, "/students", http.StatusSeeOther)
+	} else if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
 		return
 	}
 
-	students, err := s.studentRepo.LoadStudents()
+	tmpl, err := s.parseTemplate("bells.html")
 	if err != nil {
-		log.Printf("Ошибка загрузки студентов: %v", err)
+		log.Printf("Ошибка загрузки шаблона: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
-	sort.Slice(students, func(i, j int) bool {
-		if students[i].Year != students[j].Year {
-			return students[i].Year < students[j].Year
-		}
-		return strings.ToLower(students[i].Name) < strings.ToLower(students[j].Name)
-	})
+	type row struct {
+		Number     int
+		Start, End string
+		Default    domain.PairTime
+	}
+	data := struct {
+		Variants []bellVariant
+		Current  string
+		Name     string
+		Rows     []row
+		Error    string
+	}{Variants: bellVariants, Current: variant.Key, Name: variant.Name, Error: formErr}
+	for number := 1; number <= bellRows; number++ {
+		data.Rows = append(data.Rows, row{
+			Number:  number,
+			Start:   pairs[number].Start,
+			End:     pairs[number].End,
+			Default: bells.Default[number],
+		})
+	}
 
-	if err := tmpl.Execute(w, struct{ Students []domain.Student }{students}); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		log.Printf("Ошибка рендеринга шаблона: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 	}
 }
 
-func (s *Server) handleEditStudent(w http.ResponseWriter, r *http.Request) {
-	name := filepath.Base(r.URL.Path)
-	tmpl, err := template.ParseFS(templates, "templates/edit_student.html")
+// parseMonth разбирает месяц из параметра запроса в формате "2006-01"; пустое значение — текущий месяц
+func parseMonth(value string) (time.Time, error) {
+	if value == "" {
+		return domain.MonthStart(time.Now()), nil
+	}
+	month, err := time.ParseInLocation("2006-01", value, domain.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверный формат месяца: %q", value)
+	}
+	return month, nil
+}
+
+// buildTally строит табель за месяц из параметра month запроса
+func (s *Server) buildTally(r *http.Request) (domain.MonthlyTally, []domain.Issue, error) {
+	month, err := parseMonth(r.URL.Query().Get("month"))
+	if err != nil {
+		return domain.MonthlyTally{}, nil, err
+	}
+	service := usecases.NewScheduleService(domain.WeekOf(month), s.studentRepo, s.serviceOpts...)
+	return service.ProcessMonth(month)
+}
+
+// handleTally показывает табель часов индивидуальных занятий преподавателей за месяц
+func (s *Server) handleTally(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := s.parseTemplate("tally.html")
 	if err != nil {
 		log.Printf("Ошибка загрузки шаблона: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
-	if r.Method == http.MethodGet {
-		student, err := s.studentRepo.GetStudent(name)
-		if err != nil {
-			log.Printf("Ошибка получения студента: %v", err)
-			http.Error(w, "Студент не найден", http.StatusNotFound)
-			return
-		}
-		if err := tmpl.Execute(w, student); err != nil {
-			log.Printf("Ошибка рендеринга шаблона: %v", err)
-			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-		}
-		return
+	data := struct {
+		domain.MonthlyTally
+		MonthValue string
+		Issues     []domain.Issue
+		Error      string
+	}{}
+	data.MonthlyTally, data.Issues, err = s.buildTally(r)
+	if err != nil {
+		log.Printf("Ошибка построения табеля: %v", err)
+		data.Error = err.Error()
+	}
+	if !data.Month.IsZero() {
+		data.MonthValue = data.Month.Format("2006-01")
 	}
 
-	if r.Method == http.MethodPost {
-		if err := r.ParseForm(); err != nil {
-			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
-			return
-		}
-
-		newName := r.FormValue("name")
-		group := r.FormValue("group")
-		department := r.FormValue("department")
-		year, err := strconv.Atoi(r.FormValue("year"))
-		if err != nil || newName == "" || group == "" || department == "" || year <= 0 {
-			http.Error(w, "Все поля обязательны, курс должен быть > 0", http.StatusBadRequest)
-			return
-		}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
 
-		if err := s.studentRepo.UpdateStudent(name, domain.Student{
-			Name:       newName,
-			Group:      group,
-			Department: department,
-			Year:       year,
-		}); err != nil {
-			log.Printf("Ошибка обновления студента: %v", err)
-			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-			return
-		}
+// handleTallyExport отдаёт табель за месяц файлом XLSX на языке из параметра lang
+func (s *Server) handleTallyExport(w http.ResponseWriter, r *http.Request) {
+	lang, err := domain.ParseLang(r.URL.Query().Get("lang"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tally, _, err := s.buildTally(r)
+	if err != nil {
+		log.Printf("Ошибка построения табеля: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		http.Redirect(w, r, "/students", http.StatusSeeOther)
+	var buf bytes.Buffer
+	if err := infrastructure.ExportTallyXLSX(&buf, tally, lang); err != nil {
+		log.Printf("Ошибка формирования XLSX: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
-	http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	filename := fmt.Sprintf("tabel-%s.xlsx", tally.Month.Format("2006-01"))
+	if lang != domain.LangRU {
+		filename = fmt.Sprintf("tabel-%s-%s.xlsx", tally.Month.Format("2006-01"), lang)
+	}
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
 }
 
-func (s *Server) handleDeleteStudent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+// handleLessonsExport отдаёт файлом JSON занятия последней проверки — те, что видела проверка
+func (s *Server) handleLessonsExport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ready := s.reportReady
+	lessons := append([]domain.Lesson(nil), s.lessons...)
+	week := s.checkedWeek
+	s.mu.Unlock()
+	if !ready {
+		http.Error(w, "Сначала выполните проверку расписания", http.StatusConflict)
 		return
 	}
 
-	if err := s.studentRepo.DeleteStudent(filepath.Base(r.URL.Path)); err != nil {
-		log.Printf("Ошибка удаления студента: %v", err)
+	var buf bytes.Buffer
+	if err := infrastructure.ExportLessonsJSON(&buf, lessons); err != nil {
+		log.Printf("Ошибка формирования JSON: %v", err)
 		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
 		return
 	}
 
-	http.Redirect(w, r, "/students", http.StatusSeeOther)
+	filename := fmt.Sprintf("lessons-%s.json", week)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
 }
 
 func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
@@ -316,23 +1801,41 @@ func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
 		Message string `json:"message"`
 	}{true, "Сервер завершает работу"})
 
-	go func() {
-		log.Println("Завершение работы сервера...")
-		if err := s.server.Shutdown(r.Context()); err != nil {
+	go s.Shutdown()
+}
+
+// Shutdown останавливает веб-сервер и gRPC API и выполняет действие WithOnShutdown
+func (s *Server) Shutdown() {
+	log.Println("Завершение работы сервера...")
+	if s.server != nil {
+		if err := s.server.Shutdown(context.Background()); err != nil {
 			log.Printf("Ошибка при завершении работы: %v", err)
 		}
-		os.Exit(0)
-	}()
+	}
+	s.mu.Lock()
+	grpcServer := s.grpcServer
+	s.mu.Unlock()
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	s.onShutdown()
 }
 
 func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 	filePath := "static/" + strings.TrimPrefix(r.URL.Path, "/static/")
-	content, err := templates.ReadFile(filePath)
+	content, err := s.readOverride(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		content, err = templates.ReadFile(filePath)
+	}
 	if err != nil {
 		http.Error(w, "Файл не найден", http.StatusNotFound)
 		return
 	}
 
+	if s.readOnly && filePath == "static/styles.css" {
+		content = append(append([]byte(nil), content...), readOnlyCSS...)
+	}
+
 	if strings.HasSuffix(r.URL.Path, ".css") {
 		w.Header().Set("Content-Type", "text/css")
 	} else if strings.HasSuffix(r.URL.Path, ".js") {
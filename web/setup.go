@@ -0,0 +1,249 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// setupPage — данные страницы первоначальной настройки
+type setupPage struct {
+	ScheduleURL string
+	AliasURL    string
+	Timezone    string
+	Departments []infrastructure.SiteDepartment
+	Selected    map[string]bool // выбранные отделения по идентификатору на сайте
+	SiteError   string
+	Students    string
+	Limits      domain.Limits
+	Errors      []string
+}
+
+// setupGate перенаправляет страницы на мастер первоначальной настройки, пока он не пройден.
+// Статика, API и POST-запросы (например, завершение работы) проходят без перенаправления.
+func (s *Server) setupGate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		pending := s.setupPending
+		s.mu.Unlock()
+		if pending && r.Method == http.MethodGet && !strings.HasPrefix(r.URL.Path, "/setup") &&
+			!strings.HasPrefix(r.URL.Path, "/static/") && !strings.HasPrefix(r.URL.Path, "/api/") {
+			http.Redirect(w, r, "/setup", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleSetup показывает мастер первого запуска и сохраняет его результат: config.yaml,
+// выбранные на сайте отделения, начальный список студентов и пороги правил в rules.yaml
+func (s *Server) handleSetup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	pending := s.setupPending
+	s.mu.Unlock()
+	if !pending {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	defaults := infrastructure.DefaultConfig()
+	page := setupPage{
+		ScheduleURL: defaults.Site.ScheduleURL,
+		AliasURL:    defaults.Site.AliasURL,
+		Selected:    make(map[string]bool),
+		Limits:      domain.DefaultLimits(),
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if query.Get("load") != "" {
+			page.ScheduleURL = query.Get("schedule_url")
+			page.AliasURL = query.Get("alias_url")
+			page.Timezone = query.Get("timezone")
+			infrastructure.SetScheduleSite(page.ScheduleURL, page.AliasURL)
+			departments, err := infrastructure.LoadSiteDepartments()
+			if err != nil {
+				page.SiteError = err.Error()
+			}
+			page.Departments = departments
+		}
+	case http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+		s.readSetupForm(r, &page)
+		if len(page.Errors) == 0 {
+			if err := s.saveSetup(page); err != nil {
+				page.Errors = append(page.Errors, err.Error())
+			} else {
+				s.mu.Lock()
+				s.setupPending = false
+				s.mu.Unlock()
+				log.Printf("Первоначальная настройка завершена")
+				http.Redirect(w, r, "/", http.StatusSeeOther)
+				return
+			}
+		}
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("setup.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, page); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// readSetupForm заполняет страницу значениями формы и записывает в неё найденные ошибки
+func (s *Server) readSetupForm(r *http.Request, page *setupPage) {
+	page.ScheduleURL = strings.TrimSpace(r.FormValue("schedule_url"))
+	page.AliasURL = strings.TrimSpace(r.FormValue("alias_url"))
+	page.Timezone = strings.TrimSpace(r.FormValue("timezone"))
+	page.Students = r.FormValue("students")
+
+	// Отделения, загруженные с сайта, передаются скрытыми полями «id|название» и «groups:id»,
+	// чтобы не запрашивать сайт повторно
+	for _, value := range r.Form["site_department"] {
+		id, name, ok := strings.Cut(value, "|")
+		if !ok {
+			continue
+		}
+		department := infrastructure.SiteDepartment{ID: id, Name: name}
+		if groups := strings.TrimSpace(r.FormValue("groups:" + id)); groups != "" {
+			department.Groups = strings.Split(groups, "\n")
+		}
+		page.Departments = append(page.Departments, department)
+	}
+	for _, id := range r.Form["department"] {
+		page.Selected[id] = true
+	}
+
+	limits := []struct {
+		field  string
+		target *int
+	}{
+		{"max_daily_hours", &page.Limits.MaxDailyHours},
+		{"max_gaps", &page.Limits.MaxGaps},
+		{"max_gaps_first_year", &page.Limits.MaxGapsFirstYear},
+		{"max_weekly_hours", &page.Limits.MaxWeeklyHours},
+	}
+	for _, limit := range limits {
+		value, err := strconv.Atoi(strings.TrimSpace(r.FormValue(limit.field)))
+		if err != nil {
+			page.Errors = append(page.Errors, "Пороги правил должны быть целыми числами")
+			return
+		}
+		*limit.target = value
+	}
+	if err := page.Limits.Validate(); err != nil {
+		page.Errors = append(page.Errors, err.Error())
+	}
+
+	config := infrastructure.DefaultConfig()
+	config.Site.ScheduleURL, config.Site.AliasURL, config.Timezone = page.ScheduleURL, page.AliasURL, page.Timezone
+	if err := config.Validate(); err != nil {
+		page.Errors = append(page.Errors, strings.Split(err.Error(), "\n")...)
+	}
+
+	students, err := s.setupStudents(*page)
+	if err != nil {
+		page.Errors = append(page.Errors, strings.Split(err.Error(), "\n")...)
+	} else if len(students) == 0 {
+		page.Errors = append(page.Errors, "Добавьте хотя бы одного студента")
+	}
+}
+
+// selectedDepartments возвращает выбранные отделения
+func (p setupPage) selectedDepartments() []infrastructure.SiteDepartment {
+	var selected []infrastructure.SiteDepartment
+	for _, department := range p.Departments {
+		if p.Selected[department.ID] {
+			selected = append(selected, department)
+		}
+	}
+	return selected
+}
+
+// setupStudents разбирает список студентов и определяет отделение каждого по группе
+// среди выбранных отделений
+func (s *Server) setupStudents(page setupPage) ([]domain.Student, error) {
+	students, err := infrastructure.ParseStudentList(page.Students)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := page.selectedDepartments()
+	departmentOf := make(map[string]string)
+	for _, department := range selected {
+		for _, group := range department.Groups {
+			departmentOf[group] = department.Name
+		}
+	}
+
+	var errs []error
+	for i, student := range students {
+		switch name, ok := departmentOf[student.Group]; {
+		case ok:
+			students[i].Department = name
+		case len(selected) == 1:
+			students[i].Department = selected[0].Name
+		case len(selected) > 1:
+			errs = append(errs, fmt.Errorf("группа %s (%s) не найдена в выбранных отделениях", student.Group, student.Name))
+		}
+	}
+	return students, errors.Join(errs...)
+}
+
+// saveSetup сохраняет результат мастера. Существующий config.yaml не перезаписывается
+func (s *Server) saveSetup(page setupPage) error {
+	if _, err := os.Stat(s.setupConfig); errors.Is(err, os.ErrNotExist) {
+		config := infrastructure.DefaultConfig()
+		config.Site.ScheduleURL, config.Site.AliasURL, config.Timezone = page.ScheduleURL, page.AliasURL, page.Timezone
+		if err := infrastructure.SaveConfig(s.setupConfig, config); err != nil {
+			return err
+		}
+		config.Apply()
+		loc, _ := config.Location()
+		domain.SetLocation(loc)
+	}
+
+	for _, department := range page.selectedDepartments() {
+		if _, err := s.deptRepo.GetDepartment(department.Name); err == nil {
+			continue
+		}
+		err := s.deptRepo.AddDepartment(domain.Department{
+			Name:   department.Name,
+			SiteID: department.ID,
+			Source: domain.DepartmentSourceSite,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := infrastructure.NewYAMLRulesRepository("rules.yaml").SaveLimits(page.Limits); err != nil {
+		return err
+	}
+
+	students, err := s.setupStudents(page)
+	if err != nil {
+		return err
+	}
+	return s.studentRepo.SaveStudents(students)
+}
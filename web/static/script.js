@@ -1,69 +1,261 @@
+// escapeHtml экранирует текст перед вставкой в innerHTML
+function escapeHtml(text) {
+  const div = document.createElement('div');
+  div.textContent = text;
+  return div.innerHTML;
+}
+
+// showUpdateFooter добавляет внизу страницы сообщение о новой версии программы
+async function showUpdateFooter() {
+  let update;
+  try {
+    const response = await fetch('/api/update');
+    if (!response.ok) return;
+    update = await response.json();
+  } catch (error) {
+    return;
+  }
+  if (!update.available) return;
+
+  const footer = document.createElement('footer');
+  footer.className = 'update-footer';
+  if (update.installed) {
+    footer.textContent = `Версия ${update.latest} установлена и запустится после перезапуска программы.`;
+    document.body.appendChild(footer);
+    return;
+  }
+  footer.innerHTML = `Доступна новая версия ${escapeHtml(update.latest)} (у вас ${escapeHtml(update.current)}). ` +
+    `<a href="${escapeHtml(update.url)}" target="_blank" rel="noopener">Что нового</a>`;
+  if (update.canInstall) {
+    const button = document.createElement('button');
+    button.type = 'button';
+    button.textContent = 'Установить';
+    button.addEventListener('click', async () => {
+      button.disabled = true;
+      button.textContent = 'Установка...';
+      const response = await fetch('/api/update/install', { method: 'POST' });
+      const data = await response.json();
+      footer.textContent = response.ok
+        ? `Версия ${data.latest} установлена и запустится после перезапуска программы.`
+        : `Не удалось установить обновление: ${data.error}`;
+    });
+    footer.append(' ', button);
+  }
+  document.body.appendChild(footer);
+}
+
+// Кнопка «Оповещения» в меню с числом непрочитанных (если оповещения включены)
+async function showAlertsButton() {
+  const menu = document.querySelector('.button-container');
+  if (!menu) return;
+  let alerts;
+  try {
+    const response = await fetch('/api/alerts');
+    if (!response.ok) return;
+    alerts = await response.json();
+  } catch (error) {
+    return;
+  }
+
+  const button = document.createElement('a');
+  button.href = '/alerts';
+  button.className = 'button';
+  button.textContent = alerts.unread > 0 ? `Оповещения (${alerts.unread})` : 'Оповещения';
+  menu.insertBefore(button, document.getElementById('shutdownButton'));
+}
+
 document.addEventListener('DOMContentLoaded', () => {
+  const spinner = document.getElementById('spinner');
+  const resultDiv = document.getElementById('result');
+  const submitButton = document.querySelector('#checkForm button');
+
+  // Показывает, что проверка выполняется, или возвращает форму в исходное состояние
+  const setChecking = (checking) => {
+    if (spinner) spinner.style.display = checking ? 'block' : 'none';
+    if (submitButton) {
+      submitButton.disabled = checking;
+      submitButton.textContent = checking ? 'Проверка выполняется...' : 'Проверить расписание';
+    }
+  };
+
+  // Следит за запущенной проверкой и по её завершении выводит отчет или ошибку
+  const checkStatus = async () => {
+    const statusResponse = await fetch('/status');
+    const statusData = await statusResponse.json();
+
+    if (!statusData.isProcessing && statusData.reportReady) {
+      let warningsHtml = '';
+      if (statusData.error) {
+        warningsHtml = `<p style="color: red;">Ошибка: ${escapeHtml(statusData.error)}</p>`;
+      }
+      if (statusData.issues && statusData.issues.length) {
+        const items = statusData.issues
+          .map(issue => `<li>${issue.source ? `<strong>${escapeHtml(issue.source)}</strong>: ` : ''}${escapeHtml(issue.message)}</li>`)
+          .join('');
+        warningsHtml += `<div class="warnings"><p>Замечания по загруженным данным:</p><ul>${items}</ul></div>`;
+      }
+      if (resultDiv) resultDiv.innerHTML = warningsHtml + (statusData.report || '<p>Ошибка: отчет не получен.</p>');
+      setChecking(false);
+    } else if (!statusData.isProcessing && statusData.error) {
+      if (resultDiv) resultDiv.innerHTML = `<p style="color: red;">Ошибка: ${escapeHtml(statusData.error)}</p>`;
+      // при ошибке сразу показываем журнал, чтобы его можно было скопировать
+      const logPanel = document.getElementById('logPanel');
+      if (logPanel) logPanel.open = true;
+      setChecking(false);
+    } else {
+      if (resultDiv && statusData.progress) {
+        resultDiv.innerHTML = `<p style="text-align: center;">${escapeHtml(statusData.progress)}</p>`;
+      }
+      setTimeout(checkStatus, 1000);
+    }
+  };
+
+  // Запускает проверку запросом к url и следит за ней
+  const startCheck = async (url, body) => {
+    setChecking(true);
+    if (resultDiv) resultDiv.innerHTML = '';
+
+    try {
+      const response = await fetch(url, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: body ? JSON.stringify(body) : undefined,
+      });
+
+      const data = await response.json();
+
+      if (!data.success) {
+        if (resultDiv) resultDiv.innerHTML = `<p style="color: red;">Ошибка: ${escapeHtml(data.message)}</p>`;
+        setChecking(false);
+        return;
+      }
+
+      checkStatus();
+    } catch (error) {
+      if (resultDiv) resultDiv.innerHTML = `<p style="color: red;">Ошибка при выполнении запроса: ${error.message}</p>`;
+      setChecking(false);
+    }
+  };
+
   // Обработчик формы проверки расписания (если форма есть на странице)
   const checkForm = document.getElementById('checkForm');
   if (checkForm) {
-    checkForm.addEventListener('submit', async function(event) {
+    checkForm.addEventListener('submit', function(event) {
       event.preventDefault();
 
       const weekStart = document.getElementById('weekStart').value;
-      const spinner = document.getElementById('spinner');
-      const resultDiv = document.getElementById('result');
-      const submitButton = document.querySelector('#checkForm button');
-
-      if (spinner) spinner.style.display = 'block';
-      if (submitButton) {
-        submitButton.disabled = true;
-        submitButton.textContent = 'Проверка выполняется...';
-      }
-      if (resultDiv) resultDiv.innerHTML = '';
+      const fullReportInput = document.getElementById('fullReport');
+      const fullReport = fullReportInput ? fullReportInput.checked : false;
+      const monthReportInput = document.getElementById('monthReport');
+      const month = monthReportInput ? monthReportInput.checked : false;
+      const dryRunInput = document.getElementById('dryRun');
+      const dryRun = dryRunInput ? dryRunInput.checked : false;
+
+      startCheck('/check', { weekStart, fullReport, month, dryRun });
+    });
+  }
+
+  // Повторная загрузка групп, не загрузившихся с сайта; кнопка появляется в итогах отчета
+  document.addEventListener('click', (event) => {
+    const button = event.target.closest('#retryFailedButton');
+    if (!button) return;
+    button.disabled = true;
+    startCheck('/check/retry-failed');
+  });
 
+  // Журнал текущей проверки приходит с сервера потоком событий
+  const logLines = document.getElementById('logLines');
+  if (logLines && window.EventSource) {
+    const source = new EventSource('/logs/stream');
+    source.addEventListener('reset', () => {
+      logLines.textContent = '';
+    });
+    source.onmessage = (event) => {
+      logLines.textContent += event.data + '\n';
+      logLines.scrollTop = logLines.scrollHeight;
+    };
+  }
+  const copyLog = document.getElementById('copyLog');
+  if (copyLog && logLines) {
+    copyLog.addEventListener('click', async () => {
       try {
-        const response = await fetch('/check', {
-          method: 'POST',
-          headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({ weekStart }),
-        });
+        await navigator.clipboard.writeText(logLines.textContent);
+        copyLog.textContent = 'Скопировано';
+      } catch (error) {
+        // буфер обмена недоступен — выделяем текст, чтобы скопировать вручную
+        const range = document.createRange();
+        range.selectNodeContents(logLines);
+        window.getSelection().removeAllRanges();
+        window.getSelection().addRange(range);
+      }
+    });
+  }
 
-        const data = await response.json();
+  // Сообщение о новой версии внизу страницы (если проверка обновлений включена)
+  showUpdateFooter();
 
-        if (!data.success) {
-          if (resultDiv) resultDiv.innerHTML = `<p style="color: red;">Ошибка: ${data.message}</p>`;
-          if (spinner) spinner.style.display = 'none';
-          if (submitButton) {
-            submitButton.disabled = false;
-            submitButton.textContent = 'Проверить расписание';
-          }
-          return;
-        }
+  // Оповещения о неудачных загрузках, выполненных заданиях и новых версиях
+  showAlertsButton();
 
-        const checkStatus = async () => {
-          const statusResponse = await fetch('/status');
-          const statusData = await statusResponse.json();
-
-          if (!statusData.isProcessing && statusData.reportReady) {
-            if (resultDiv) resultDiv.innerHTML = statusData.report || '<p>Ошибка: отчет не получен.</p>';
-            if (spinner) spinner.style.display = 'none';
-            if (submitButton) {
-              submitButton.disabled = false;
-              submitButton.textContent = 'Проверить расписание';
-            }
-          } else {
-            setTimeout(checkStatus, 1000);
-          }
-        };
-
-        checkStatus();
-      } catch (error) {
-        if (resultDiv) resultDiv.innerHTML = `<p style="color: red;">Ошибка при выполнении запроса: ${error.message}</p>`;
-        if (spinner) spinner.style.display = 'none';
-        if (submitButton) {
-          submitButton.disabled = false;
-          submitButton.textContent = 'Проверить расписание';
-        }
+  // Выбор недели по учебному календарю подставляет дату её начала
+  const weekNumber = document.getElementById('weekNumber');
+  const weekStartInput = document.getElementById('weekStart');
+  if (weekNumber && weekStartInput) {
+    const syncWeek = () => {
+      if (weekNumber.value) weekStartInput.value = weekNumber.value;
+    };
+    weekNumber.addEventListener('change', syncWeek);
+    syncWeek();
+  }
+
+  // Выбор группы из списка сайта подставляет её отделение
+  const siteGroup = document.querySelector('select.site-group');
+  const departmentSelect = document.querySelector('select#department');
+  if (siteGroup && departmentSelect) {
+    siteGroup.addEventListener('change', () => {
+      const option = siteGroup.selectedOptions[0];
+      if (option && option.parentElement.tagName === 'OPTGROUP') {
+        departmentSelect.value = option.parentElement.label;
       }
     });
   }
 
+  // Рассылка студентам их нарушений; кнопка появляется в отчете после проверки
+  document.addEventListener('click', async (event) => {
+    const button = event.target.closest('#notifyButton');
+    if (!button) return;
+    if (!confirm('Отправить каждому студенту с нарушениями его часть отчета?')) return;
+
+    button.disabled = true;
+    try {
+      const response = await fetch('/notify', { method: 'POST' });
+      const data = await response.json();
+      alert(data.success ? data.message : ('Ошибка: ' + data.message));
+    } catch (error) {
+      alert('Ошибка при выполнении запроса: ' + error.message);
+    } finally {
+      button.disabled = false;
+    }
+  });
+
+  // Напоминание преподавателям индивидуальных занятий, чьих занятий нет в проверенной неделе
+  document.addEventListener('click', async (event) => {
+    const button = event.target.closest('#notifyTeachersButton');
+    if (!button) return;
+    if (!confirm('Отправить преподавателям без занятий на неделе напоминание о файле расписания?')) return;
+
+    button.disabled = true;
+    try {
+      const response = await fetch('/notify/teachers', { method: 'POST' });
+      const data = await response.json();
+      alert(data.success ? data.message : ('Ошибка: ' + data.message));
+    } catch (error) {
+      alert('Ошибка при выполнении запроса: ' + error.message);
+    } finally {
+      button.disabled = false;
+    }
+  });
+
   // Обработчик кнопки завершения (если кнопка есть на странице)
   const shutdownButton = document.getElementById('shutdownButton');
   if (shutdownButton) {
@@ -83,15 +275,59 @@ document.addEventListener('DOMContentLoaded', () => {
     });
   }
 
-  // Обработчик кнопок удаления студентов
-  const deleteButtons = document.querySelectorAll('.delete-student');
+  // Быстрый поиск студентов, преподавателей и дисциплин на главной странице
+  const quickSearch = document.getElementById('quickSearch');
+  const quickSearchResults = document.getElementById('quickSearchResults');
+  if (quickSearch && quickSearchResults) {
+    const kindNames = { student: 'Студент', teacher: 'Преподаватель', discipline: 'Дисциплина' };
+    let searchTimer;
+    quickSearch.addEventListener('input', () => {
+      clearTimeout(searchTimer);
+      searchTimer = setTimeout(async () => {
+        const q = quickSearch.value.trim();
+        quickSearchResults.innerHTML = '';
+        if (!q) return;
+        try {
+          const response = await fetch('/api/search?limit=10&q=' + encodeURIComponent(q));
+          const results = await response.json();
+          if (!response.ok) return;
+          results.forEach(item => {
+            const li = document.createElement('li');
+            const title = document.createElement(item.url ? 'a' : 'span');
+            title.textContent = item.name;
+            if (item.url) title.href = item.url;
+            li.append(`${kindNames[item.kind] || item.kind}: `, title);
+            if (item.detail) li.append(` — ${item.detail}`);
+            quickSearchResults.appendChild(li);
+          });
+        } catch (error) {
+          console.error('Ошибка поиска:', error);
+        }
+      }, 200);
+    });
+  }
+
+  // Объединение повторяющихся записей студента удаляет остальные записи, поэтому требует подтверждения
+  document.querySelectorAll('form.merge-students').forEach(form => {
+    form.addEventListener('submit', (event) => {
+      const keep = form.querySelector('input[name="keep"]:checked');
+      if (!keep || !confirm(`Оставить запись ${keep.value} и удалить остальные?`)) {
+        event.preventDefault();
+      }
+    });
+  });
+
+  // Обработчик кнопок удаления студентов и отделений
+  const deleteButtons = document.querySelectorAll('.delete-student, .delete-department');
   deleteButtons.forEach(button => {
     button.addEventListener('click', async (event) => {
       event.preventDefault();
       const url = button.getAttribute('href');
       const studentName = url.split('/').pop();
+      const isDepartment = button.classList.contains('delete-department');
+      const what = isDepartment ? 'отделение' : 'студента';
       
-      if (!confirm(`Вы уверены, что хотите удалить студента ${decodeURIComponent(studentName)}?`)) {
+      if (!confirm(`Вы уверены, что хотите удалить ${what} ${decodeURIComponent(studentName)}?`)) {
         return;
       }
 
@@ -102,7 +338,7 @@ document.addEventListener('DOMContentLoaded', () => {
         });
 
         if (response.ok) {
-          window.location.href = '/students'; // Перенаправление после успешного удаления
+          window.location.href = isDepartment ? '/departments' : '/students'; // Перенаправление после успешного удаления
         } else {
           const data = await response.json();
           alert(`Ошибка: ${data.message || 'Не удалось удалить студента'}`);
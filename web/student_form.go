@@ -0,0 +1,43 @@
+package web
+
+import (
+	"log"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// studentForm — данные формы студента: сам студент и отделения с группами с сайта
+// для выпадающих списков
+type studentForm struct {
+	domain.Student
+	SiteDepartments  []infrastructure.SiteDepartment // пусто — сайт недоступен, поля вводятся вручную
+	DepartmentListed bool                            // отделение студента есть на сайте
+	GroupListed      bool                            // группа студента есть на сайте
+}
+
+// newStudentForm заполняет форму студента списками с сайта, если они заданы WithSiteDepartments
+// и загружаются. Отделение и группа студента, которых нет на сайте, в форме сохраняются.
+func (s *Server) newStudentForm(student domain.Student) studentForm {
+	form := studentForm{Student: student}
+	if s.siteDepartments == nil {
+		return form
+	}
+	departments, err := s.siteDepartments()
+	if err != nil {
+		log.Printf("Списки отделений и групп недоступны, поля вводятся вручную: %v", err)
+		return form
+	}
+	form.SiteDepartments = departments
+	for _, department := range departments {
+		if department.Name == student.Department {
+			form.DepartmentListed = true
+		}
+		for _, group := range department.Groups {
+			if group == student.Group {
+				form.GroupListed = true
+			}
+		}
+	}
+	return form
+}
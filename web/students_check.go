@@ -0,0 +1,51 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// validatingStudentRepository — хранилище студентов в файле, который можно проверить целиком
+type validatingStudentRepository interface {
+	Validate() error
+	Filename() string
+}
+
+// studentsErrorPage — данные страницы ошибок в файле студентов
+type studentsErrorPage struct {
+	File   string
+	Errors []string
+}
+
+// studentsGate показывает вместо страниц ошибки файла студентов, пока он не исправлен.
+// Файл проверяется при каждом открытии страницы, поэтому после исправления достаточно
+// обновить её. Статика, API, мастер настройки и POST-запросы проходят без проверки.
+func (s *Server) studentsGate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, ok := s.studentRepo.(validatingStudentRepository)
+		if !ok || r.Method != http.MethodGet || strings.HasPrefix(r.URL.Path, "/setup") ||
+			strings.HasPrefix(r.URL.Path, "/static/") || strings.HasPrefix(r.URL.Path, "/api/") ||
+			strings.HasPrefix(r.URL.Path, "/logs/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		err := repo.Validate()
+		if err == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tmpl, tmplErr := s.parseTemplate("students_error.html")
+		if tmplErr != nil {
+			log.Printf("Ошибка загрузки шаблона: %v", tmplErr)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		page := studentsErrorPage{File: repo.Filename(), Errors: strings.Split(err.Error(), "\n")}
+		if err := tmpl.Execute(w, page); err != nil {
+			log.Printf("Ошибка рендеринга шаблона: %v", err)
+		}
+	})
+}
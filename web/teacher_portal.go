@@ -0,0 +1,113 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// teacherKeyCookie — cookie с ключом страницы преподавателя, чтобы ключ не оставался в адресе
+const teacherKeyCookie = "teacher_key"
+
+// teacherKeyMaxAge — сколько браузер преподавателя помнит ключ
+const teacherKeyMaxAge = 120 * 24 * time.Hour
+
+// teacherPortalData — данные страницы преподавателя
+type teacherPortalData struct {
+	Login     bool   // ключа нет или он не подходит: показывается форма ввода ключа
+	Error     string // ошибка ввода ключа
+	Ready     bool   // есть результат проверки
+	DateStart string // начало проверенного периода
+	DateEnd   string // конец проверенного периода
+	Portal    usecases.TeacherPortal
+}
+
+// handleTeacherPortal показывает преподавателю только его занятия из последней проверки
+// и нарушения с их участием. Доступ — по ключу, выданному на странице «Преподаватели»: ссылка
+// /teacher?key=… запоминает ключ в cookie и открывает страницу без ключа в адресе.
+// Ключ проверяется только здесь: полный отчёт, страницы студентов и API им не закрыты.
+func (s *Server) handleTeacherPortal(w http.ResponseWriter, r *http.Request) {
+	if s.teacherRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	registry, err := s.teacherRepo.LoadRegistry()
+	if err != nil {
+		log.Printf("Ошибка загрузки справочника преподавателей: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	var data teacherPortalData
+	status := http.StatusOK
+	if key := r.URL.Query().Get("key"); key != "" {
+		if _, ok := registry.ByAccessKey(key); ok {
+			http.SetCookie(w, &http.Cookie{
+				Name:     teacherKeyCookie,
+				Value:    key,
+				Path:     "/teacher",
+				MaxAge:   int(teacherKeyMaxAge.Seconds()),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+			http.Redirect(w, r, "/teacher", http.StatusSeeOther)
+			return
+		}
+		data.Login, data.Error = true, "Ключ не подходит: возможно, он отозван. Попросите у диспетчера новую ссылку."
+		status = http.StatusForbidden
+	} else if cookie, err := r.Cookie(teacherKeyCookie); err != nil {
+		data.Login = true
+	} else if teacher, ok := registry.ByAccessKey(cookie.Value); !ok {
+		data.Login, data.Error = true, "Ключ больше не действует. Попросите у диспетчера новую ссылку."
+	} else {
+		s.mu.Lock()
+		data.Ready = s.reportReady
+		week, month := s.checkedWeek, len(s.month) > 0
+		lessons, violations := s.lessons, s.violations
+		s.mu.Unlock()
+		if data.Ready {
+			end := week
+			if month {
+				for i := 1; i < monthWeeks; i++ {
+					end = end.Next()
+				}
+			}
+			data.DateStart = week.Start().Format("02.01.2006")
+			data.DateEnd = end.End().Format("02.01.2006")
+		}
+		data.Portal = usecases.BuildTeacherPortal(teacher, lessons, violations)
+	}
+
+	tmpl, err := s.parseTemplate("teacher_portal.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+	}
+}
+
+// handleTeacherLogout забывает ключ страницы преподавателя в этом браузере
+func (s *Server) handleTeacherLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: teacherKeyCookie, Path: "/teacher", MaxAge: -1, HttpOnly: true})
+	http.Redirect(w, r, "/teacher", http.StatusSeeOther)
+}
+
+// teacherPortalURL возвращает ссылку на страницу преподавателя с ключом key для отправки преподавателю
+func teacherPortalURL(r *http.Request, key string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/teacher?key=" + key
+}
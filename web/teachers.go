@@ -0,0 +1,179 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+)
+
+// handleTeachers показывает справочник преподавателей: кандидатов в псевдонимы, найденных при
+// разборе расписания, подтверждённые псевдонимы и преподавателей, ведущих индивидуальные занятия.
+// Кандидат подтверждается или отклоняется одной кнопкой (action=confirm или dismiss), псевдоним
+// убирается кнопкой action=remove. Преподаватель индивидуальных занятий добавляется
+// (action=expect, с контактами email и telegram) и убирается (action=unexpect) по имени; ему же
+// выдаётся ссылка на страницу преподавателя (action=grant) и отзывается (action=revoke).
+// Ссылка с ключом показывается один раз — в ответе на её выдачу: страница открыта без пароля,
+// и ключи, видные на ней постоянно, мог бы скопировать любой преподаватель.
+func (s *Server) handleTeachers(w http.ResponseWriter, r *http.Request) {
+	if s.teacherRepo == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Ошибка обработки формы", http.StatusBadRequest)
+			return
+		}
+		name := strings.TrimSpace(r.FormValue("name"))
+		alias := strings.TrimSpace(r.FormValue("alias"))
+		action := r.FormValue("action")
+		if name == "" || alias == "" && action != "expect" && action != "unexpect" && action != "grant" && action != "revoke" {
+			http.Error(w, "Не указано имя преподавателя или псевдоним", http.StatusBadRequest)
+			return
+		}
+		var (
+			err     error
+			granted string // ключ новой ссылки, показывается в ответе один раз
+		)
+		switch action {
+		case "expect":
+			err = s.teacherRepo.ExpectTeacher(name, strings.TrimSpace(r.FormValue("email")), strings.TrimSpace(r.FormValue("telegram")))
+		case "unexpect":
+			err = s.teacherRepo.UnexpectTeacher(name)
+		case "grant":
+			granted, err = s.teacherRepo.GrantAccess(name)
+		case "revoke":
+			err = s.teacherRepo.RevokeAccess(name)
+		case "confirm":
+			err = s.teacherRepo.ConfirmAlias(name, alias)
+		case "dismiss":
+			err = s.teacherRepo.DismissAlias(name, alias)
+		case "remove":
+			err = s.teacherRepo.RemoveAlias(name, alias)
+		default:
+			http.Error(w, "Неизвестное действие", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка сохранения справочника преподавателей: %v", err)
+			http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+			return
+		}
+		if granted != "" {
+			s.renderTeachers(w, r, &grantedLink{Name: name, URL: teacherPortalURL(r, granted)})
+			return
+		}
+		http.Redirect(w, r, "/teachers", http.StatusSeeOther)
+		return
+	} else if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	s.renderTeachers(w, r, nil)
+}
+
+// grantedLink — только что выданная ссылка на страницу преподавателя
+type grantedLink struct {
+	Name string // преподаватель
+	URL  string // ссылка с ключом
+}
+
+// renderTeachers показывает страницу справочника преподавателей; granted — только что
+// выданная ссылка, nil — ссылка не выдавалась
+func (s *Server) renderTeachers(w http.ResponseWriter, r *http.Request, granted *grantedLink) {
+	registry, err := s.teacherRepo.LoadRegistry()
+	if err != nil {
+		log.Printf("Ошибка загрузки справочника преподавателей: %v", err)
+		http.Error(w, "Ошибка загрузки справочника преподавателей: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("teachers.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Candidates []domain.TeacherAliasCandidate
+		Teachers   []domain.Teacher
+		Expected   []domain.Teacher
+		Granted    *grantedLink
+	}{Candidates: registry.Pending(), Teachers: registry.Teachers, Expected: registry.Expected(), Granted: granted}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleNotifyTeachers напоминает преподавателям индивидуальных занятий, занятий которых нет
+// в последней проверке недели, прислать файл расписания (кнопка в итогах отчета)
+func (s *Server) handleNotifyTeachers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.notifier == nil {
+		writeCheckResponse(w, http.StatusServiceUnavailable, false, "Рассылка не настроена: задайте параметры почты или Telegram")
+		return
+	}
+	if s.teacherRepo == nil {
+		writeCheckResponse(w, http.StatusServiceUnavailable, false, "Справочник преподавателей не подключён")
+		return
+	}
+
+	s.mu.Lock()
+	ready, dryRun, month, week := s.reportReady, s.dryRun, len(s.month) > 0, s.checkedWeek
+	missing := append([]string(nil), s.completeness.MissingTeachers...)
+	s.mu.Unlock()
+	switch {
+	case !ready:
+		writeCheckResponse(w, http.StatusConflict, false, "Сначала выполните проверку расписания")
+		return
+	case dryRun:
+		writeCheckResponse(w, http.StatusConflict, false, "По пробной проверке напоминания не отправляются: выполните обычную проверку")
+		return
+	case month:
+		writeCheckResponse(w, http.StatusConflict, false, "Напоминания отправляются по проверке одной недели")
+		return
+	}
+
+	registry, err := s.teacherRepo.LoadRegistry()
+	if err != nil {
+		log.Printf("Ошибка загрузки справочника преподавателей: %v", err)
+		writeCheckResponse(w, http.StatusInternalServerError, false, "Не удалось загрузить справочник преподавателей")
+		return
+	}
+	absent := make(map[string]bool, len(missing))
+	for _, name := range missing {
+		absent[name] = true
+	}
+	var teachers []domain.Teacher
+	for _, teacher := range registry.Expected() {
+		if absent[teacher.Name] {
+			teachers = append(teachers, teacher)
+		}
+	}
+	if len(teachers) == 0 {
+		writeCheckResponse(w, http.StatusConflict, false, "В последней проверке нет преподавателей индивидуальных занятий без занятий на неделе")
+		return
+	}
+
+	result, err := s.notifier.NotifyAbsentTeachers(teachers, week)
+	if err != nil {
+		log.Printf("Ошибка отправки напоминаний преподавателям: %v", err)
+	}
+	message := fmt.Sprintf("Напоминаний отправлено: %d", len(result.Sent))
+	if len(result.Skipped) > 0 {
+		message += fmt.Sprintf("; без контактов: %s", strings.Join(result.Skipped, ", "))
+	}
+	if len(result.Failed) > 0 {
+		message += fmt.Sprintf("; не удалось отправить: %s", strings.Join(result.Failed, ", "))
+	}
+	writeCheckResponse(w, http.StatusOK, len(result.Failed) == 0, message)
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// apiWeekTrend — показатели недели для графиков в JSON API
+type apiWeekTrend struct {
+	Week       string         `json:"week"`
+	Start      string         `json:"start"` // 2006-01-02
+	End        string         `json:"end"`
+	Violations map[string]int `json:"violations"` // по видам: overload, gaps, clash, custom
+	Total      int            `json:"total"`
+	DailyLoad  float64        `json:"dailyLoad,omitempty"`    // ак. ч; нет у проверок прежних версий
+	Complete   int            `json:"completeness,omitempty"` // полнота данных, %; нет у проверок прежних версий
+	Gaps       int            `json:"gaps"`                   // нарушений по окнам
+	GapHalves  int            `json:"gapHalves"`              // окон в этих нарушениях, в половинках пар
+}
+
+func (s *Server) apiTrends(w http.ResponseWriter, r *http.Request) {
+	if s.historyRepo == nil {
+		writeAPIError(w, http.StatusNotFound, "История проверок не ведётся")
+		return
+	}
+	calendar := s.loadCalendar()
+	var from, to domain.Week
+	for _, bound := range []struct {
+		name string
+		week *domain.Week
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(bound.name)
+		if value == "" {
+			continue
+		}
+		week, err := calendar.ParseWeek(value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, bound.name+": "+err.Error())
+			return
+		}
+		*bound.week = week
+	}
+
+	records, err := s.historyRepo.LoadChecks()
+	if err != nil {
+		log.Printf("Ошибка загрузки истории проверок: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := []apiWeekTrend{}
+	for _, trend := range usecases.BuildTrends(records, from, to) {
+		item := apiWeekTrend{
+			Week:       trend.Week.String(),
+			Start:      trend.Week.Start().Format("2006-01-02"),
+			End:        trend.Week.End().Format("2006-01-02"),
+			Violations: make(map[string]int),
+			Total:      trend.Total,
+			DailyLoad:  math.Round(trend.DailyLoad*100) / 100,
+			Complete:   trend.Complete,
+			Gaps:       trend.Gaps,
+			GapHalves:  trend.GapHalves,
+		}
+		// Все виды присутствуют в каждой неделе, чтобы ряды графика были одинаковой длины
+		for _, kind := range legendKinds {
+			item.Violations[string(kind)] = trend.Violations[kind]
+		}
+		result = append(result, item)
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+)
+
+// updateCheckInterval — как часто проверяется выход новой версии
+const updateCheckInterval = 24 * time.Hour
+
+// updateState — результат последней проверки обновлений
+type updateState struct {
+	release   infrastructure.Release
+	available bool
+	installed bool // новая версия установлена и запустится после перезапуска
+	err       string
+}
+
+// RunUpdateCheck проверяет выход новой версии при запуске и затем раз в сутки.
+// Блокирует выполнение до отмены ctx.
+func (s *Server) RunUpdateCheck(ctx context.Context) {
+	if s.updates == nil {
+		return
+	}
+	ticker := time.NewTicker(updateCheckInterval)
+	defer ticker.Stop()
+	for {
+		release, available, err := s.updates.Latest(ctx)
+		s.mu.Lock()
+		announce := false
+		if err != nil {
+			log.Printf("Ошибка проверки обновлений: %v", err)
+			s.update.err = err.Error()
+		} else {
+			if available && !s.update.available {
+				log.Printf("Доступна новая версия %s (запущена %s): %s", release.Version, s.updates.Current(), release.URL)
+				announce = true
+			}
+			s.update.release, s.update.available, s.update.err = release, available, ""
+		}
+		s.mu.Unlock()
+		// слушатели событий сами блокируют s.mu, поэтому событие публикуется после Unlock
+		if announce {
+			s.events.Publish(domain.Event{Type: domain.EventUpdateAvailable, Version: release.Version, URL: release.URL})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apiUpdate — сведения о новой версии программы
+type apiUpdate struct {
+	Current    string `json:"current"`
+	Latest     string `json:"latest,omitempty"`
+	Available  bool   `json:"available"`
+	URL        string `json:"url,omitempty"`   // страница выпуска
+	CanInstall bool   `json:"canInstall"`      // программа может установить обновление сама (Windows)
+	Installed  bool   `json:"installed"`       // обновление установлено, нужен перезапуск
+	Error      string `json:"error,omitempty"` // ошибка последней проверки
+}
+
+func (s *Server) apiGetUpdate(w http.ResponseWriter, r *http.Request) {
+	if s.updates == nil {
+		writeAPIError(w, http.StatusNotFound, "Проверка обновлений выключена")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, s.currentUpdate())
+}
+
+func (s *Server) apiInstallUpdate(w http.ResponseWriter, r *http.Request) {
+	if s.updates == nil {
+		writeAPIError(w, http.StatusNotFound, "Проверка обновлений выключена")
+		return
+	}
+	s.mu.Lock()
+	state := s.update
+	s.mu.Unlock()
+	if !state.available {
+		writeAPIError(w, http.StatusConflict, "Новых версий нет")
+		return
+	}
+	if state.installed {
+		writeAPIJSON(w, http.StatusOK, s.currentUpdate())
+		return
+	}
+
+	if err := s.updates.Install(r.Context(), state.release); err != nil {
+		log.Printf("Ошибка установки обновления %s: %v", state.release.Version, err)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Установлена версия %s, она запустится после перезапуска программы", state.release.Version)
+	s.mu.Lock()
+	s.update.installed = true
+	s.mu.Unlock()
+	writeAPIJSON(w, http.StatusOK, s.currentUpdate())
+}
+
+// currentUpdate возвращает результат последней проверки обновлений
+func (s *Server) currentUpdate() apiUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := apiUpdate{
+		Current:   s.updates.Current(),
+		Available: s.update.available,
+		Installed: s.update.installed,
+		Error:     s.update.err,
+	}
+	if s.update.available {
+		result.Latest = s.update.release.Version
+		result.URL = s.update.release.URL
+		result.CanInstall = s.updates.CanInstall(s.update.release)
+	}
+	return result
+}
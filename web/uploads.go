@@ -0,0 +1,200 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/infrastructure"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// maxUploadRequest — наибольший размер запроса с загружаемыми файлами
+const maxUploadRequest = 100 << 20
+
+// uploadPage — данные страницы загрузки файлов
+type uploadPage struct {
+	WeekStart string
+	Saved     []string // имена сохранённых файлов последней загрузки
+	Week      string   // неделя последней загрузки
+	Error     string
+	Errors    []infrastructure.ImportError // ошибки по файлам; если есть, Error не выводится
+	Batches   []infrastructure.UploadBatch
+}
+
+// handleUpload показывает загруженные по неделям файлы и принимает новые: XLS-файлы
+// или ZIP-архив со всеми файлами преподавателей за неделю
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if s.uploads == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := uploadPage{WeekStart: domain.WeekOf(time.Now()).String()}
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		week, files, err := s.readUploadForm(w, r)
+		if err == nil {
+			page.WeekStart = week.String()
+			var saved []string
+			saved, err = s.uploads.Save(week, files)
+			for _, path := range saved {
+				page.Saved = append(page.Saved, filepath.Base(path))
+			}
+		}
+		if errs, ok := infrastructure.AsImportErrors(err); ok {
+			page.Errors = errs
+			status = http.StatusBadRequest
+		} else if err != nil {
+			page.Error = err.Error()
+			status = http.StatusBadRequest
+		} else {
+			page.Week = page.WeekStart
+			log.Printf("Загружено файлов для недели %s: %d", page.Week, len(page.Saved))
+		}
+	} else if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	batches, err := s.uploads.Batches()
+	if err != nil {
+		log.Printf("Ошибка чтения загрузок: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	page.Batches = batches
+
+	tmpl, err := s.parseTemplate("upload.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	if err := tmpl.Execute(w, page); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+	}
+}
+
+// readUploadForm читает из формы неделю и загруженные файлы
+func (s *Server) readUploadForm(w http.ResponseWriter, r *http.Request) (domain.Week, []infrastructure.UploadedFile, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadRequest)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return domain.Week{}, nil, fmt.Errorf("не удалось прочитать загруженные файлы (не больше %d МБ за раз): %w", maxUploadRequest>>20, err)
+	}
+	week, err := s.loadCalendar().ParseWeek(r.FormValue("week_start"))
+	if err != nil {
+		return domain.Week{}, nil, err
+	}
+
+	var files []infrastructure.UploadedFile
+	for _, header := range r.MultipartForm.File["files"] {
+		file, err := header.Open()
+		if err != nil {
+			return week, nil, fmt.Errorf("%s: %w", header.Filename, err)
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return week, nil, fmt.Errorf("%s: %w", header.Filename, err)
+		}
+		files = append(files, infrastructure.UploadedFile{Name: header.Filename, Data: data})
+	}
+	return week, files, nil
+}
+
+// checkStream передаёт события проверки в канал обработчика потокового ответа.
+// События, которые клиент не успел забрать, пропускаются, чтобы не задерживать проверку.
+type checkStream struct {
+	events chan domain.Event
+}
+
+// HandleEvent реализует domain.EventListener
+func (c *checkStream) HandleEvent(event domain.Event) {
+	select {
+	case c.events <- event:
+	default:
+	}
+}
+
+// apiUploadAndCheck сохраняет загруженные файлы и сразу проверяет неделю только по ним,
+// без поиска остальных XLS-файлов рабочей папки. Ход проверки отправляется клиенту
+// строками JSON по мере выполнения.
+func (s *Server) apiUploadAndCheck(w http.ResponseWriter, r *http.Request) {
+	if s.uploads == nil {
+		writeAPIError(w, http.StatusNotFound, "Загрузка файлов не настроена")
+		return
+	}
+	week, files, err := s.readUploadForm(w, r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	saved, err := s.uploads.Save(week, files)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	if err := s.beginCheck(week, r.FormValue("full_report") == "true", false); err != nil {
+		writeAPIError(w, http.StatusConflict, "Обработка уже выполняется")
+		return
+	}
+	log.Printf("Проверка недели %s по загруженным файлам: %d", week, len(saved))
+
+	stream := &checkStream{events: make(chan domain.Event, 64)}
+	s.events.Subscribe(stream)
+	defer s.events.Unsubscribe(stream)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.runCheck(week, usecases.WithScheduleFiles(saved))
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	send := func(line apiCheckProgress) {
+		if err := encoder.Encode(line); err != nil {
+			log.Printf("Ошибка отправки хода проверки: %v", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	lastProgress := ""
+	sendEvent := func(event domain.Event) {
+		// о каждом найденном нарушении не сообщается, только о смене этапа
+		progress := progressMessage(event)
+		if event.Type == domain.EventViolationFound && progress == lastProgress {
+			return
+		}
+		lastProgress = progress
+		send(apiCheckProgress{Event: string(event.Type), Progress: progress, Count: event.Count})
+	}
+	for {
+		select {
+		case event := <-stream.events:
+			sendEvent(event)
+		case <-done:
+			for len(stream.events) > 0 {
+				sendEvent(<-stream.events)
+			}
+			job := s.currentJob()
+			send(apiCheckProgress{Event: "result", Job: &job})
+			return
+		case <-r.Context().Done():
+			// клиент отключился; проверка продолжается, её результат доступен через /api/jobs/current
+			return
+		}
+	}
+}
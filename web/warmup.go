@@ -0,0 +1,74 @@
+package web
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// warmupSchedule — время ежедневного прогрева кэша расписания групп
+type warmupSchedule struct {
+	at  time.Duration // смещение от полуночи в часовом поясе расписания
+	ttl time.Duration // сколько хранить прогретое расписание
+}
+
+// WithWarmup включает ежедневный прогрев кэша расписания групп: в момент at после полуночи
+// (в часовом поясе расписания) расписание групп предстоящей недели загружается с сайта
+// и хранится ttl, чтобы утренняя проверка не ждала сайт. Запускается через RunWarmup.
+func WithWarmup(at, ttl time.Duration) Option {
+	return func(s *Server) {
+		s.warmup = &warmupSchedule{at: at, ttl: ttl}
+	}
+}
+
+// nextRun возвращает ближайший после now момент прогрева
+func (w warmupSchedule) nextRun(now time.Time) time.Time {
+	now = now.In(domain.Location())
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, domain.Location())
+	next := midnight.Add(w.at)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(w.at)
+	}
+	return next
+}
+
+// warmupWeek возвращает неделю, которую стоит прогреть в момент now: в выходные — следующую,
+// в остальные дни — текущую
+func warmupWeek(now time.Time) domain.Week {
+	week := domain.WeekOf(now)
+	switch now.In(domain.Location()).Weekday() {
+	case time.Saturday, time.Sunday:
+		return week.Next()
+	}
+	return week
+}
+
+// RunWarmup прогревает кэш расписания групп каждый день во время, заданное WithWarmup.
+// Блокирует выполнение до отмены ctx.
+func (s *Server) RunWarmup(ctx context.Context) {
+	if s.warmup == nil {
+		return
+	}
+	for {
+		next := s.warmup.nextRun(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		week := warmupWeek(time.Now())
+		started := time.Now()
+		n, err := usecases.NewScheduleService(week, s.studentRepo, s.serviceOpts...).WarmUp(s.warmup.ttl)
+		if err != nil {
+			log.Printf("Прогрев кэша расписания групп на неделю %s: %v", week, err)
+		}
+		log.Printf("Прогрев кэша расписания групп на неделю %s: загружено занятий %d за %s",
+			week, n, time.Since(started).Round(time.Second))
+	}
+}
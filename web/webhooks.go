@@ -0,0 +1,82 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Vaflel/lesson-counter/domain"
+	"github.com/Vaflel/lesson-counter/usecases"
+)
+
+// publishCheck отправляет в фоне уведомление о завершённой проверке недели, если
+// уведомления настроены. Ошибки доставки видны в журнале на странице «Уведомления».
+func (s *Server) publishCheck(week domain.Week, result usecases.ValidatingResult) {
+	if s.webhooks == nil {
+		return
+	}
+	payload := usecases.CheckPayload(week, result)
+	go func() {
+		delivery, err := s.webhooks.Publish(usecases.EventCheckCompleted, payload)
+		if err != nil {
+			log.Printf("Ошибка отправки уведомления о проверке недели %s: %v", week, err)
+			return
+		}
+		log.Printf("Уведомление %s о проверке недели %s доставлено", delivery.ID, week)
+	}()
+}
+
+// handleWebhooks показывает журнал доставки уведомлений: когда и что отправлено,
+// сколько было попыток и что ответил получатель
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	deliveries, err := s.webhooks.Deliveries()
+	if err != nil {
+		log.Printf("Ошибка загрузки журнала уведомлений: %v", err)
+		http.Error(w, "Ошибка загрузки журнала уведомлений: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := s.parseTemplate("webhooks.html")
+	if err != nil {
+		log.Printf("Ошибка загрузки шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+	data := struct {
+		Deliveries []domain.WebhookDelivery
+	}{deliveries}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Ошибка рендеринга шаблона: %v", err)
+		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
+	}
+}
+
+// handleRedeliverWebhook повторно отправляет уведомление из журнала. Попытки выполняются
+// в фоне, итог появится в журнале.
+func (s *Server) handleRedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/webhooks/redeliver/")
+	go func() {
+		if _, err := s.webhooks.Redeliver(id); err != nil {
+			log.Printf("Ошибка повторной отправки уведомления: %v", err)
+		}
+	}()
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+}